@@ -29,8 +29,31 @@ const (
 	LoadBalancerDeleteFailedReason = "LoadBalancerDeleteFailed"
 	// LoadBalancerServiceSyncFailedReason used when an error occurs while syncing services of load balancer.
 	LoadBalancerServiceSyncFailedReason = "LoadBalancerServiceSyncFailed"
+	// LoadBalancerTargetSyncFailedReason used when an error occurs while syncing the label selector target of load balancer.
+	LoadBalancerTargetSyncFailedReason = "LoadBalancerTargetSyncFailed"
 	// LoadBalancerFailedToOwnReason used when no owned label could be set on a load balancer.
 	LoadBalancerFailedToOwnReason = "LoadBalancerFailedToOwn"
+	// LoadBalancerMigratedCondition reports on the migration of the control plane load balancer to another region.
+	LoadBalancerMigratedCondition clusterv1.ConditionType = "LoadBalancerMigrated"
+	// LoadBalancerMigrationWaitingForHealthChecksReason used while the new load balancer waits for healthy targets.
+	LoadBalancerMigrationWaitingForHealthChecksReason = "LoadBalancerMigrationWaitingForHealthChecks"
+	// LoadBalancerMigrationDrainingReason used while the old load balancer is drained after the new one took over.
+	LoadBalancerMigrationDrainingReason = "LoadBalancerMigrationDraining"
+	// LoadBalancerMigrationNotApprovedReason used when the load balancer has to be migrated, but the migration has not been approved.
+	LoadBalancerMigrationNotApprovedReason = "LoadBalancerMigrationNotApproved"
+	// LoadBalancerMigrationBlockedReason used when the load balancer cannot be migrated, because the control plane endpoint is its IP.
+	LoadBalancerMigrationBlockedReason = "LoadBalancerMigrationBlocked"
+	// LoadBalancerMigrationWaitingForDNSReason used while the control plane endpoint still resolves to the old load balancer.
+	LoadBalancerMigrationWaitingForDNSReason = "LoadBalancerMigrationWaitingForDNS"
+	// LoadBalancerMigrationFailedReason used when an error occurs during the migration of the load balancer.
+	LoadBalancerMigrationFailedReason = "LoadBalancerMigrationFailed"
+)
+
+const (
+	// AdditionalLoadBalancersReadyCondition reports on whether the additional load balancers of the cluster are ready.
+	AdditionalLoadBalancersReadyCondition clusterv1.ConditionType = "AdditionalLoadBalancersReady"
+	// AdditionalLoadBalancersNotReadyReason used when one or more additional load balancers are not ready.
+	AdditionalLoadBalancersNotReadyReason = "AdditionalLoadBalancersNotReady"
 )
 
 const (
@@ -38,6 +61,8 @@ const (
 	ServerCreateSucceededCondition clusterv1.ConditionType = "ServerCreateSucceeded"
 	// InstanceHasNonExistingPlacementGroupReason instance has a placement group name that does not exist.
 	InstanceHasNonExistingPlacementGroupReason = "InstanceHasNonExistingPlacementGroup"
+	// InstanceHasFullPlacementGroupReason instance has a sharded placement group of which all shards are full.
+	InstanceHasFullPlacementGroupReason = "InstanceHasFullPlacementGroup"
 	// SSHKeyNotFoundReason indicates that ssh key could not be found.
 	SSHKeyNotFoundReason = "SSHKeyNotFound"
 	// ImageNotFoundReason indicates that the image could not be found.
@@ -48,6 +73,13 @@ const (
 	ServerTypeNotFoundReason = "ServerTypeNotFound"
 	// ServerCreateFailedReason indicates that server could not get created.
 	ServerCreateFailedReason = "ServerCreateFailedReason"
+	// ServerTypeUnavailableReason indicates that none of the server types is available in any of the locations.
+	ServerTypeUnavailableReason = "ServerTypeUnavailable"
+	// BootstrapDataTooLargeReason indicates that the bootstrap data exceeds the limit of the user data even after
+	// compression and no bootstrap data storage is configured.
+	BootstrapDataTooLargeReason = "BootstrapDataTooLarge"
+	// BootstrapDataUploadFailedReason indicates that the bootstrap data could not be uploaded to the bootstrap data storage.
+	BootstrapDataUploadFailedReason = "BootstrapDataUploadFailed"
 )
 
 const (
@@ -61,11 +93,38 @@ const (
 	ServerOffReason = "ServerOff"
 )
 
+const (
+	// ServerShutdownCondition reports the shutdown of the server before it is deleted. It is true once the server is off.
+	ServerShutdownCondition clusterv1.ConditionType = "ServerShutdown"
+	// GracefulShutdownRequestedReason indicates that the operating system has been asked to shut down and the
+	// controller waits until the graceful shutdown timeout is reached.
+	GracefulShutdownRequestedReason = "GracefulShutdownRequested"
+	// ServerPoweredOffReason indicates that the server has been powered off, because the graceful shutdown timed out
+	// or has been disabled.
+	ServerPoweredOffReason = "ServerPoweredOff"
+)
+
+const (
+	// ServerRebuildCondition reports the in-place rebuild of the server with a new image. It is true once the server
+	// runs the image of the spec.
+	ServerRebuildCondition clusterv1.ConditionType = "ServerRebuild"
+	// RebuildInProgressReason indicates that the server is being rebuilt with the new image.
+	RebuildInProgressReason = "RebuildInProgress"
+	// RebuildFailedReason indicates that the server could not be rebuilt, e.g. because the image does not exist.
+	RebuildFailedReason = "RebuildFailed"
+	// RebuildBlockedReason indicates that the server is not rebuilt, because its user data cannot be run again.
+	RebuildBlockedReason = "RebuildBlocked"
+)
+
 const (
 	// NetworkAttachFailedReason is used when server could not be attached to network.
 	NetworkAttachFailedReason = "NetworkAttachFailed"
+	// NetworkNotReadyReason is used when a private-only server waits for the network of the cluster.
+	NetworkNotReadyReason = "NetworkNotReady"
 	// LoadBalancerAttachFailedReason is used when server could not be attached to network.
 	LoadBalancerAttachFailedReason = "LoadBalancerAttachFailed"
+	// VolumeAttachFailedReason is used when the volumes of a server could not be created or attached.
+	VolumeAttachFailedReason = "VolumeAttachFailed"
 )
 
 const (
@@ -82,6 +141,15 @@ const (
 	NetworkReconcileFailedReason = "NetworkReconcileFailed"
 )
 
+const (
+	// NATGatewayReadyCondition reports on whether the NAT gateway of the network is ready.
+	NATGatewayReadyCondition clusterv1.ConditionType = "NATGatewayReady"
+	// NATGatewayReconcileFailedReason indicates that reconciling the NAT gateway failed.
+	NATGatewayReconcileFailedReason = "NATGatewayReconcileFailed"
+	// NATGatewayNotReadyReason indicates that the NAT gateway has no IP address in the network yet.
+	NATGatewayNotReadyReason = "NATGatewayNotReady"
+)
+
 const (
 	// PlacementGroupsSyncedCondition reports on whether the placement groups are successfully synced.
 	PlacementGroupsSyncedCondition clusterv1.ConditionType = "PlacementGroupsSynced"
@@ -89,6 +157,42 @@ const (
 	PlacementGroupsSyncFailedReason = "PlacementGroupsSyncFailed"
 )
 
+const (
+	// ControlPlaneIPReadyCondition reports on whether the Floating IP of the control plane endpoint is ready.
+	ControlPlaneIPReadyCondition clusterv1.ConditionType = "ControlPlaneIPReady"
+	// ControlPlaneIPCreateFailedReason indicates that the control plane IP could not be created.
+	ControlPlaneIPCreateFailedReason = "ControlPlaneIPCreateFailed"
+	// ControlPlaneIPAssignFailedReason indicates that the control plane IP could not be assigned to a server.
+	ControlPlaneIPAssignFailedReason = "ControlPlaneIPAssignFailed"
+	// ControlPlaneIPNotAssignedReason indicates that the control plane IP is not assigned to any server.
+	ControlPlaneIPNotAssignedReason = "ControlPlaneIPNotAssigned"
+	// ControlPlaneIPTypeUnsupportedReason indicates that the cluster has been created with a control plane endpoint
+	// type that is no longer supported.
+	ControlPlaneIPTypeUnsupportedReason = "ControlPlaneIPTypeUnsupported"
+)
+
+const (
+	// ControlPlaneDNSReadyCondition reports on whether the DNS records of the control plane endpoint are in sync.
+	ControlPlaneDNSReadyCondition clusterv1.ConditionType = "ControlPlaneDNSReady"
+	// ControlPlaneDNSSyncFailedReason indicates that syncing the DNS records failed.
+	ControlPlaneDNSSyncFailedReason = "ControlPlaneDNSSyncFailed"
+	// ControlPlaneDNSIPNotAvailableReason indicates that the control plane endpoint has no IP the records could point to yet.
+	ControlPlaneDNSIPNotAvailableReason = "ControlPlaneDNSIPNotAvailable"
+	// ControlPlaneDNSRecordConflictReason indicates that records of the name exist already that have not been created
+	// for the cluster.
+	ControlPlaneDNSRecordConflictReason = "ControlPlaneDNSRecordConflict"
+	// ControlPlaneDNSDeleteFailedReason indicates that the DNS records could not be deleted because the DNS provider
+	// is not available, e.g. because the DNS token is missing.
+	ControlPlaneDNSDeleteFailedReason = "ControlPlaneDNSDeleteFailed"
+)
+
+const (
+	// FirewallsSyncedCondition reports on whether the firewalls are successfully synced.
+	FirewallsSyncedCondition clusterv1.ConditionType = "FirewallsSynced"
+	// FirewallsSyncFailedReason indicates that syncing the firewalls failed.
+	FirewallsSyncFailedReason = "FirewallsSyncFailed"
+)
+
 const (
 	// HCloudTokenAvailableCondition reports on whether the HCloud Token is available.
 	HCloudTokenAvailableCondition clusterv1.ConditionType = "HCloudTokenAvailable"
@@ -17,7 +17,10 @@ limitations under the License.
 package v1beta1
 
 import (
+	"time"
+
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
@@ -31,6 +34,24 @@ const (
 	// DeprecatedHCloudMachineFinalizer contains the old string.
 	// The controller will automatically update to the new string.
 	DeprecatedHCloudMachineFinalizer = "hcloudmachine.infrastructure.cluster.x-k8s.io"
+
+	// DiagnosticsAnnotation requests to collect diagnostics of the server of an HCloudMachine in the rescue system.
+	// The controller removes the annotation once the diagnostics have been collected.
+	DiagnosticsAnnotation = "capi.syself.com/diagnostics"
+
+	// RebuildApprovedAnnotation approves to rebuild the server of an HCloudMachine in place with the image of the
+	// spec. While it is set, the image of the spec may be changed. The node has to be drained before. The controller
+	// removes the annotation once the server runs the new image.
+	RebuildApprovedAnnotation = "capi.syself.com/rebuild-approved"
+
+	// SnapshotAnnotation requests a snapshot of the server of an HCloudMachine. The value is the name of the snapshot,
+	// which can be used as imageName. If it is empty, the name of the machine and the current time are used. The
+	// controller removes the annotation once the snapshot has been requested.
+	SnapshotAnnotation = "capi.syself.com/snapshot"
+
+	// DefaultGracefulShutdownTimeout is the time the operating system of a server gets to shut down before the server
+	// is powered off, if the deletion policy does not specify it.
+	DefaultGracefulShutdownTimeout = 2 * time.Minute
 )
 
 // HCloudMachineSpec defines the desired state of HCloudMachine.
@@ -43,10 +64,27 @@ type HCloudMachineSpec struct {
 	// +kubebuilder:validation:Enum=cpx11;cx21;cpx21;cx31;cpx31;cx41;cpx41;cx51;cpx51;ccx11;ccx12;ccx13;ccx21;ccx22;ccx23;ccx31;ccx32;ccx33;ccx41;ccx42;ccx43;ccx51;ccx52;ccx53;ccx62;ccx63;cax11;cax21;cax31;cax41;cx22;cx32;cx42;cx52
 	Type HCloudMachineType `json:"type"`
 
+	// FallbackTypes are server types that are tried in the given order if HCloud has no capacity for the server type of Type.
+	// Server types of a different architecture than Type require an image of that architecture.
+	// +optional
+	// +kubebuilder:validation:items:Enum=cpx11;cx21;cpx21;cx31;cpx31;cx41;cpx41;cx51;cpx51;ccx11;ccx12;ccx13;ccx21;ccx22;ccx23;ccx31;ccx32;ccx33;ccx41;ccx42;ccx43;ccx51;ccx52;ccx53;ccx62;ccx63;cax11;cax21;cax31;cax41;cx22;cx32;cx42;cx52
+	FallbackTypes []HCloudMachineType `json:"fallbackTypes,omitempty"`
+
+	// FallbackLocations are locations that are tried in the given order if none of the server types is available in the
+	// location of the failure domain. Locations outside of the network zone of the failure domain are ignored.
+	// +optional
+	FallbackLocations []Region `json:"fallbackLocations,omitempty"`
+
 	// ImageName is the reference to the Machine Image from which to create the machine instance.
 	// It can reference an image uploaded to Hetzner API in two ways: either directly as the name of an image or as the label of an image.
+	// Either ImageName or Image has to be specified.
+	// +optional
 	// +kubebuilder:validation:MinLength=1
-	ImageName string `json:"imageName"`
+	ImageName string `json:"imageName,omitempty"`
+
+	// Image references the Machine Image by its ID or by a label selector. Either ImageName or Image has to be specified.
+	// +optional
+	Image *HCloudImageSpec `json:"image,omitempty"`
 
 	// SSHKeys define machine-specific SSH keys and override cluster-wide SSH keys.
 	// +optional
@@ -60,6 +98,59 @@ type HCloudMachineSpec struct {
 	// the primary IP address of the server. If both IPv4 and IPv6 are disabled, then the private network has to be enabled.
 	// +optional
 	PublicNetwork *PublicNetworkSpec `json:"publicNetwork,omitempty"`
+
+	// Volumes are HCloud Volumes that are created in the location of the server and attached to it.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Volumes []HCloudVolumeSpec `json:"volumes,omitempty"`
+
+	// Labels are added to the labels of the server. Changes are applied to existing servers as well.
+	// Labels that CAPH manages itself cannot be set.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// PropagateMachineLabels are keys of labels of the Machine that are copied to the server, e.g.
+	// cluster.x-k8s.io/deployment-name. Labels takes precedence over the labels of the Machine.
+	// +optional
+	PropagateMachineLabels []string `json:"propagateMachineLabels,omitempty"`
+
+	// DeletionPolicy defines how the server is shut down before it is deleted.
+	// +optional
+	DeletionPolicy *HCloudDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Backups enables the automatic backups of HCloud for the server. HCloud keeps the last seven backups, which are
+	// deleted together with the server.
+	// +optional
+	Backups bool `json:"backups,omitempty"`
+
+	// ReplayableBootstrapData declares that the bootstrap data of the machine can be run again on the same server,
+	// e.g. because it does not contain join tokens that expire. The HCloud rebuild reruns the user data of the
+	// creation, so servers are only rebuilt if it is set. Defaults to false.
+	// +optional
+	ReplayableBootstrapData bool `json:"replayableBootstrapData,omitempty"`
+}
+
+// HCloudDeletionPolicy defines how the server of an HCloudMachine is shut down before it is deleted.
+type HCloudDeletionPolicy struct {
+	// GracefulShutdownTimeout is the time the operating system gets to shut down after the shutdown request. Once it
+	// has passed, the server is powered off. Zero powers the server off without a graceful shutdown. Defaults to 2m.
+	// +optional
+	GracefulShutdownTimeout *metav1.Duration `json:"gracefulShutdownTimeout,omitempty"`
+}
+
+// GracefulShutdownTimeout returns the graceful shutdown timeout of the deletion policy or the default.
+func (s *HCloudMachineSpec) GracefulShutdownTimeout() time.Duration {
+	if s.DeletionPolicy == nil || s.DeletionPolicy.GracefulShutdownTimeout == nil {
+		return DefaultGracefulShutdownTimeout
+	}
+	return s.DeletionPolicy.GracefulShutdownTimeout.Duration
+}
+
+// IsPrivateOnly returns true if the server has neither a public IPv4 nor a public IPv6 address. Such servers reach
+// the internet only through the NAT gateway of the network.
+func (s *HCloudMachineSpec) IsPrivateOnly() bool {
+	return s.PublicNetwork != nil && !s.PublicNetwork.EnableIPv4 && !s.PublicNetwork.EnableIPv6
 }
 
 // HCloudMachineStatus defines the observed state of HCloudMachine.
@@ -71,12 +162,52 @@ type HCloudMachineStatus struct {
 	// Addresses contain the server's associated addresses.
 	Addresses []clusterv1.MachineAddress `json:"addresses,omitempty"`
 
-	// Region contains the name of the HCloud location the server is running.
+	// Region contains the name of the HCloud location the server is running. It differs from the failure domain if one
+	// of the fallback locations has been used.
 	Region Region `json:"region,omitempty"`
 
+	// ServerType is the server type the server has been created with. It differs from the type in the spec if one of
+	// the fallback types has been used.
+	// +optional
+	ServerType HCloudMachineType `json:"serverType,omitempty"`
+
+	// ImageID is the ID of the image the server has been created from. If the server has to be created again,
+	// the same image is used as long as it exists.
+	// +optional
+	ImageID int64 `json:"imageID,omitempty"`
+
 	// SSHKeys specifies the ssh keys that were used for provisioning the server.
 	SSHKeys []SSHKey `json:"sshKeys,omitempty"`
 
+	// AppliedLabels are the keys of the labels that have been added to the server because of Labels or
+	// PropagateMachineLabels. They are removed from the server once they are not desired anymore.
+	// +optional
+	AppliedLabels []string `json:"appliedLabels,omitempty"`
+
+	// Volumes are the HCloud Volumes of the machine.
+	// +optional
+	Volumes []HCloudVolumeStatus `json:"volumes,omitempty"`
+
+	// Diagnostics is the state of the collection of diagnostics that has been requested with the diagnostics annotation.
+	// +optional
+	Diagnostics *HCloudDiagnosticsStatus `json:"diagnostics,omitempty"`
+
+	// Snapshots are the snapshots that have been taken of the server with the snapshot annotation. They are not
+	// deleted together with the machine.
+	// +optional
+	Snapshots []HCloudSnapshotStatus `json:"snapshots,omitempty"`
+
+	// BootstrapDataObject is the object in the bootstrap data storage of the cluster that serves the bootstrap
+	// data of the server, because it has been too large for the user data.
+	// +optional
+	BootstrapDataObject *BootstrapDataObjectStatus `json:"bootstrapDataObject,omitempty"`
+
+	// BootstrapDataNotReplayable is the reason why the user data of the server cannot be run again by a rebuild of
+	// the server, because the HCloud rebuild reruns the user data of the creation. An empty value does not mean that
+	// the user data is replayable, which only spec.replayableBootstrapData declares.
+	// +optional
+	BootstrapDataNotReplayable string `json:"bootstrapDataNotReplayable,omitempty"`
+
 	// InstanceState is the state of the server for this machine.
 	// +optional
 	InstanceState *hcloud.ServerStatus `json:"instanceState,omitempty"`
@@ -98,6 +229,49 @@ type HCloudMachineStatus struct {
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
+// HCloudDiagnosticsPhase is the phase of the collection of diagnostics of a server.
+type HCloudDiagnosticsPhase string
+
+const (
+	// HCloudDiagnosticsPhaseRescueBooting means that the server is rebooting into the rescue system.
+	HCloudDiagnosticsPhaseRescueBooting HCloudDiagnosticsPhase = "RescueBooting"
+	// HCloudDiagnosticsPhaseCompleted means that the diagnostics have been collected and the server has been rebooted
+	// into its operating system.
+	HCloudDiagnosticsPhaseCompleted HCloudDiagnosticsPhase = "Completed"
+	// HCloudDiagnosticsPhaseFailed means that the diagnostics could not be collected.
+	HCloudDiagnosticsPhaseFailed HCloudDiagnosticsPhase = "Failed"
+)
+
+// HCloudDiagnosticsStatus is the state of the collection of diagnostics of a server.
+type HCloudDiagnosticsStatus struct {
+	// Phase is the current phase of the collection.
+	Phase HCloudDiagnosticsPhase `json:"phase"`
+
+	// StartTime is the time when the collection started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// ConfigMapName is the name of the ConfigMap that contains the collected logs.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// Message describes the result of the collection.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// HCloudSnapshotStatus is a snapshot of a server.
+type HCloudSnapshotStatus struct {
+	// Name of the snapshot. It is stored in the label caph-image-name of the image, so that it can be used as imageName.
+	Name string `json:"name"`
+
+	// ID of the image of the snapshot.
+	ID int64 `json:"id"`
+
+	// Created is the time when the snapshot has been requested.
+	Created metav1.Time `json:"created"`
+}
+
 // HCloudMachine is the Schema for the hcloudmachines API.
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=hcloudmachines,scope=Namespaced,categories=cluster-api,shortName=hcma
@@ -133,6 +307,25 @@ func (r *HCloudMachine) SetConditions(conditions clusterv1.Conditions) {
 	r.Status.Conditions = conditions
 }
 
+// BootstrapDataNotReplayableReason returns why the server must not be rebuilt, because its user data cannot be run
+// again. It is empty only if the bootstrap data has been declared replayable and nothing is known that prevents it.
+func (r *HCloudMachine) BootstrapDataNotReplayableReason() string {
+	if !r.Spec.ReplayableBootstrapData {
+		return "the bootstrap data has not been declared replayable in spec.replayableBootstrapData"
+	}
+	return r.Status.BootstrapDataNotReplayable
+}
+
+// rebuildInProgress returns whether the server is rebuilt with the image of the spec.
+func (r *HCloudMachine) rebuildInProgress() bool {
+	for _, c := range r.Status.Conditions {
+		if c.Type == ServerRebuildCondition {
+			return c.Status == corev1.ConditionFalse && c.Reason == RebuildInProgressReason
+		}
+	}
+	return false
+}
+
 //+kubebuilder:object:root=true
 
 // HCloudMachineList contains a list of HCloudMachine.
@@ -18,7 +18,10 @@ package v1beta1
 
 import (
 	"reflect"
+	"strings"
 
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -31,6 +34,20 @@ func validateHCloudMachineSpec(oldSpec, newSpec HCloudMachineSpec) field.ErrorLi
 		)
 	}
 
+	// FallbackTypes are immutable
+	if !reflect.DeepEqual(oldSpec.FallbackTypes, newSpec.FallbackTypes) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "fallbackTypes"), newSpec.FallbackTypes, "field is immutable"),
+		)
+	}
+
+	// FallbackLocations are immutable
+	if !reflect.DeepEqual(oldSpec.FallbackLocations, newSpec.FallbackLocations) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "fallbackLocations"), newSpec.FallbackLocations, "field is immutable"),
+		)
+	}
+
 	// ImageName is immutable
 	if !reflect.DeepEqual(oldSpec.ImageName, newSpec.ImageName) {
 		allErrs = append(allErrs,
@@ -38,6 +55,13 @@ func validateHCloudMachineSpec(oldSpec, newSpec HCloudMachineSpec) field.ErrorLi
 		)
 	}
 
+	// Image is immutable
+	if !reflect.DeepEqual(oldSpec.Image, newSpec.Image) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "image"), newSpec.Image, "field is immutable"),
+		)
+	}
+
 	// SSHKeys is immutable
 	if !reflect.DeepEqual(oldSpec.SSHKeys, newSpec.SSHKeys) {
 		allErrs = append(allErrs,
@@ -52,5 +76,158 @@ func validateHCloudMachineSpec(oldSpec, newSpec HCloudMachineSpec) field.ErrorLi
 		)
 	}
 
+	// Volumes are immutable
+	if !reflect.DeepEqual(oldSpec.Volumes, newSpec.Volumes) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "volumes"), newSpec.Volumes, "field is immutable"),
+		)
+	}
+
+	return allErrs
+}
+
+// validateHCloudRebuildImage checks that the image is not changed again while the server is rebuilt, as the
+// controller would not notice that the server runs an outdated image.
+func validateHCloudRebuildImage(fldPath *field.Path, oldSpec, newSpec HCloudMachineSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if oldSpec.ImageName != newSpec.ImageName || !reflect.DeepEqual(oldSpec.Image, newSpec.Image) {
+		allErrs = append(allErrs,
+			field.Forbidden(fldPath.Child("image"), "image cannot be changed while the server is rebuilt"),
+		)
+	}
+
+	return allErrs
+}
+
+// validateHCloudVolumes checks that only formatted volumes are mounted automatically.
+func validateHCloudVolumes(fldPath *field.Path, volumes []HCloudVolumeSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, volume := range volumes {
+		if volume.Automount && volume.Format == "" {
+			allErrs = append(allErrs,
+				field.Invalid(fldPath.Index(i).Child("automount"), volume.Automount, "automount requires a format"),
+			)
+		}
+	}
+
+	return allErrs
+}
+
+// validateHCloudFallbacks checks that fallback types and locations are unique and that all fallback locations
+// are in the same network zone.
+func validateHCloudFallbacks(fldPath *field.Path, spec HCloudMachineSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	types := map[HCloudMachineType]struct{}{spec.Type: {}}
+	for i, t := range spec.FallbackTypes {
+		if _, found := types[t]; found {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("fallbackTypes").Index(i), t))
+		}
+		types[t] = struct{}{}
+	}
+
+	locations := make(map[Region]struct{}, len(spec.FallbackLocations))
+	for i, location := range spec.FallbackLocations {
+		if _, found := locations[location]; found {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("fallbackLocations").Index(i), location))
+		}
+		locations[location] = struct{}{}
+
+		if location.NetworkZone() != spec.FallbackLocations[0].NetworkZone() {
+			allErrs = append(allErrs,
+				field.Invalid(fldPath.Child("fallbackLocations").Index(i), location, "all fallback locations must be in the same network zone"),
+			)
+		}
+	}
+
+	return allErrs
+}
+
+// validateHCloudImage checks that the image is referenced in exactly one way.
+func validateHCloudImage(fldPath *field.Path, spec HCloudMachineSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.Image == nil {
+		if spec.ImageName == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("imageName"), "either imageName or image is required"))
+		}
+		return allErrs
+	}
+
+	imagePath := fldPath.Child("image")
+	if spec.ImageName != "" {
+		allErrs = append(allErrs, field.Forbidden(imagePath, "image cannot be combined with imageName"))
+	}
+
+	switch {
+	case spec.Image.ID != nil && len(spec.Image.LabelSelector) > 0:
+		allErrs = append(allErrs, field.Forbidden(imagePath.Child("labelSelector"), "labelSelector cannot be combined with id"))
+	case spec.Image.ID == nil && len(spec.Image.LabelSelector) == 0:
+		allErrs = append(allErrs, field.Required(imagePath, "either id or labelSelector is required"))
+	}
+
+	if spec.Image.SelectionPolicy != "" && len(spec.Image.LabelSelector) == 0 {
+		allErrs = append(allErrs, field.Forbidden(imagePath.Child("selectionPolicy"), "selectionPolicy requires a labelSelector"))
+	}
+
+	return allErrs
+}
+
+// validateHCloudLabels checks that the labels of the server are valid and that they do not overwrite the labels that
+// CAPH manages.
+func validateHCloudLabels(fldPath *field.Path, spec HCloudMachineSpec) field.ErrorList {
+	labelsPath := fldPath.Child("labels")
+	allErrs := metav1validation.ValidateLabels(spec.Labels, labelsPath)
+	for key := range spec.Labels {
+		if isReservedLabelKey(key) {
+			allErrs = append(allErrs, field.Forbidden(labelsPath.Key(key), "label is managed by CAPH"))
+		}
+	}
+
+	propagatePath := fldPath.Child("propagateMachineLabels")
+	for i, key := range spec.PropagateMachineLabels {
+		for _, msg := range validation.IsQualifiedName(key) {
+			allErrs = append(allErrs, field.Invalid(propagatePath.Index(i), key, msg))
+		}
+		if isReservedLabelKey(key) {
+			allErrs = append(allErrs, field.Forbidden(propagatePath.Index(i), "label is managed by CAPH"))
+		}
+	}
+
+	return allErrs
+}
+
+// isReservedLabelKey returns true if CAPH manages the label of the key on HCloud resources.
+func isReservedLabelKey(key string) bool {
+	return key == MachineNameTagKey || key == MachineTypeTagKey || strings.HasPrefix(key, NameHetznerProviderPrefix)
+}
+
+// validateHCloudDeletionPolicy checks that the graceful shutdown timeout is not negative.
+func validateHCloudDeletionPolicy(fldPath *field.Path, spec HCloudMachineSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if policy := spec.DeletionPolicy; policy != nil && policy.GracefulShutdownTimeout != nil && policy.GracefulShutdownTimeout.Duration < 0 {
+		allErrs = append(allErrs,
+			field.Invalid(fldPath.Child("deletionPolicy", "gracefulShutdownTimeout"), policy.GracefulShutdownTimeout.Duration.String(), "must not be negative"),
+		)
+	}
+
+	return allErrs
+}
+
+func validateHCloudSnapshotAnnotation(annotations map[string]string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// the name of the snapshot is stored in a label of the image
+	if name := annotations[SnapshotAnnotation]; name != "" {
+		for _, msg := range validation.IsValidLabelValue(name) {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("metadata", "annotations").Key(SnapshotAnnotation), name, msg),
+			)
+		}
+	}
+
 	return allErrs
 }
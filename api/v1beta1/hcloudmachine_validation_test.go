@@ -18,9 +18,12 @@ package v1beta1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 )
 
 type args struct {
@@ -46,6 +49,18 @@ func TestValidateHCloudMachineSpec(t *testing.T) {
 			},
 			want: field.Invalid(field.NewPath("spec", "type"), "cx21", "field is immutable"),
 		},
+		{
+			name: "Immutable FallbackTypes",
+			args: args{
+				oldSpec: HCloudMachineSpec{
+					FallbackTypes: []HCloudMachineType{"cpx41"},
+				},
+				newSpec: HCloudMachineSpec{
+					FallbackTypes: []HCloudMachineType{"cpx51"},
+				},
+			},
+			want: field.Invalid(field.NewPath("spec", "fallbackTypes"), []HCloudMachineType{"cpx51"}, "field is immutable"),
+		},
 		{
 			name: "Immutable ImageName",
 			args: args{
@@ -105,6 +120,18 @@ func TestValidateHCloudMachineSpec(t *testing.T) {
 			},
 			want: field.Invalid(field.NewPath("spec", "placementGroupName"), "placement-group-2", "field is immutable"),
 		},
+		{
+			name: "Immutable Volumes",
+			args: args{
+				oldSpec: HCloudMachineSpec{
+					Volumes: []HCloudVolumeSpec{{Name: "data", Size: 10}},
+				},
+				newSpec: HCloudMachineSpec{
+					Volumes: []HCloudVolumeSpec{{Name: "data", Size: 20}},
+				},
+			},
+			want: field.Invalid(field.NewPath("spec", "volumes"), []HCloudVolumeSpec{{Name: "data", Size: 20}}, "field is immutable"),
+		},
 		{
 			name: "No Errors",
 			args: args{
@@ -149,3 +176,289 @@ func TestValidateHCloudMachineSpec(t *testing.T) {
 func createPlacementGroupName(name string) *string {
 	return &name
 }
+
+func TestValidateHCloudVolumes(t *testing.T) {
+	volumesPath := field.NewPath("spec", "volumes")
+
+	tests := []struct {
+		name    string
+		volumes []HCloudVolumeSpec
+		want    *field.Error
+	}{
+		{
+			name:    "Automount without format",
+			volumes: []HCloudVolumeSpec{{Name: "data", Size: 10, Format: "ext4", Automount: true}, {Name: "raw", Size: 10, Automount: true}},
+			want:    field.Invalid(volumesPath.Index(1).Child("automount"), true, "automount requires a format"),
+		},
+		{
+			name:    "No Errors",
+			volumes: []HCloudVolumeSpec{{Name: "data", Size: 10, Format: "xfs", Automount: true}, {Name: "raw", Size: 10}},
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateHCloudVolumes(volumesPath, tt.volumes)
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
+
+func TestValidateHCloudFallbacks(t *testing.T) {
+	specPath := field.NewPath("spec")
+
+	tests := []struct {
+		name string
+		spec HCloudMachineSpec
+		want *field.Error
+	}{
+		{
+			name: "Fallback type equals type",
+			spec: HCloudMachineSpec{Type: "cpx31", FallbackTypes: []HCloudMachineType{"cpx41", "cpx31"}},
+			want: field.Duplicate(specPath.Child("fallbackTypes").Index(1), HCloudMachineType("cpx31")),
+		},
+		{
+			name: "Duplicate fallback location",
+			spec: HCloudMachineSpec{Type: "cpx31", FallbackLocations: []Region{"nbg1", "nbg1"}},
+			want: field.Duplicate(specPath.Child("fallbackLocations").Index(1), Region("nbg1")),
+		},
+		{
+			name: "Fallback locations in different network zones",
+			spec: HCloudMachineSpec{Type: "cpx31", FallbackLocations: []Region{"nbg1", "ash"}},
+			want: field.Invalid(specPath.Child("fallbackLocations").Index(1), Region("ash"), "all fallback locations must be in the same network zone"),
+		},
+		{
+			name: "No Errors",
+			spec: HCloudMachineSpec{Type: "cpx31", FallbackTypes: []HCloudMachineType{"cpx41", "cax31"}, FallbackLocations: []Region{"nbg1", "hel1"}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateHCloudFallbacks(specPath, tt.spec)
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
+
+func TestValidateHCloudImage(t *testing.T) {
+	specPath := field.NewPath("spec")
+	imagePath := specPath.Child("image")
+
+	tests := []struct {
+		name string
+		spec HCloudMachineSpec
+		want *field.Error
+	}{
+		{
+			name: "Neither image name nor image",
+			spec: HCloudMachineSpec{},
+			want: field.Required(specPath.Child("imageName"), "either imageName or image is required"),
+		},
+		{
+			name: "Image name and image",
+			spec: HCloudMachineSpec{ImageName: "my-image", Image: &HCloudImageSpec{ID: ptr.To[int64](42)}},
+			want: field.Forbidden(imagePath, "image cannot be combined with imageName"),
+		},
+		{
+			name: "ID and label selector",
+			spec: HCloudMachineSpec{Image: &HCloudImageSpec{ID: ptr.To[int64](42), LabelSelector: map[string]string{"release": "v1"}}},
+			want: field.Forbidden(imagePath.Child("labelSelector"), "labelSelector cannot be combined with id"),
+		},
+		{
+			name: "Neither ID nor label selector",
+			spec: HCloudMachineSpec{Image: &HCloudImageSpec{}},
+			want: field.Required(imagePath, "either id or labelSelector is required"),
+		},
+		{
+			name: "Selection policy without label selector",
+			spec: HCloudMachineSpec{Image: &HCloudImageSpec{ID: ptr.To[int64](42), SelectionPolicy: ImageSelectionPolicyNewest}},
+			want: field.Forbidden(imagePath.Child("selectionPolicy"), "selectionPolicy requires a labelSelector"),
+		},
+		{
+			name: "No Errors",
+			spec: HCloudMachineSpec{Image: &HCloudImageSpec{LabelSelector: map[string]string{"release": "v1"}, SelectionPolicy: ImageSelectionPolicyNewest}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateHCloudImage(specPath, tt.spec)
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
+
+func TestValidateHCloudRebuildImage(t *testing.T) {
+	specPath := field.NewPath("spec")
+
+	tests := []struct {
+		name    string
+		oldSpec HCloudMachineSpec
+		newSpec HCloudMachineSpec
+		want    *field.Error
+	}{
+		{
+			name:    "Image name changed",
+			oldSpec: HCloudMachineSpec{ImageName: "ubuntu-24.04"},
+			newSpec: HCloudMachineSpec{ImageName: "ubuntu-24.10"},
+			want:    field.Forbidden(specPath.Child("image"), "image cannot be changed while the server is rebuilt"),
+		},
+		{
+			name:    "Image changed",
+			oldSpec: HCloudMachineSpec{Image: &HCloudImageSpec{ID: ptr.To[int64](1)}},
+			newSpec: HCloudMachineSpec{Image: &HCloudImageSpec{ID: ptr.To[int64](2)}},
+			want:    field.Forbidden(specPath.Child("image"), "image cannot be changed while the server is rebuilt"),
+		},
+		{
+			name:    "No Errors",
+			oldSpec: HCloudMachineSpec{ImageName: "ubuntu-24.04"},
+			newSpec: HCloudMachineSpec{ImageName: "ubuntu-24.04"},
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateHCloudRebuildImage(specPath, tt.oldSpec, tt.newSpec)
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
+
+func TestValidateHCloudLabels(t *testing.T) {
+	specPath := field.NewPath("spec")
+
+	tests := []struct {
+		name string
+		spec HCloudMachineSpec
+		want *field.Error
+	}{
+		{
+			name: "Label managed by CAPH",
+			spec: HCloudMachineSpec{Labels: map[string]string{MachineTypeTagKey: "worker"}},
+			want: field.Forbidden(specPath.Child("labels").Key(MachineTypeTagKey), "label is managed by CAPH"),
+		},
+		{
+			name: "Propagated label managed by CAPH",
+			spec: HCloudMachineSpec{PropagateMachineLabels: []string{"cluster.x-k8s.io/deployment-name", MachineNameTagKey}},
+			want: field.Forbidden(specPath.Child("propagateMachineLabels").Index(1), "label is managed by CAPH"),
+		},
+		{
+			name: "No Errors",
+			spec: HCloudMachineSpec{Labels: map[string]string{"team": "platform"}, PropagateMachineLabels: []string{"cluster.x-k8s.io/deployment-name"}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateHCloudLabels(specPath, tt.spec)
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
+
+func TestValidateHCloudDeletionPolicy(t *testing.T) {
+	specPath := field.NewPath("spec")
+
+	tests := []struct {
+		name string
+		spec HCloudMachineSpec
+		want *field.Error
+	}{
+		{
+			name: "Negative graceful shutdown timeout",
+			spec: HCloudMachineSpec{DeletionPolicy: &HCloudDeletionPolicy{GracefulShutdownTimeout: &metav1.Duration{Duration: -time.Minute}}},
+			want: field.Invalid(specPath.Child("deletionPolicy", "gracefulShutdownTimeout"), "-1m0s", "must not be negative"),
+		},
+		{
+			name: "No Errors",
+			spec: HCloudMachineSpec{DeletionPolicy: &HCloudDeletionPolicy{GracefulShutdownTimeout: &metav1.Duration{}}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateHCloudDeletionPolicy(specPath, tt.spec)
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
+
+func TestValidateHCloudSnapshotAnnotation(t *testing.T) {
+	annotationPath := field.NewPath("metadata", "annotations").Key(SnapshotAnnotation)
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{
+			name:        "Invalid snapshot name",
+			annotations: map[string]string{SnapshotAnnotation: "before upgrade"},
+			wantErr:     true,
+		},
+		{
+			name:        "Empty snapshot name",
+			annotations: map[string]string{SnapshotAnnotation: ""},
+		},
+		{
+			name:        "No Errors",
+			annotations: map[string]string{SnapshotAnnotation: "my-node-before-upgrade"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateHCloudSnapshotAnnotation(tt.annotations)
+
+			if !tt.wantErr {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, annotationPath.String(), got[0].Field)
+		})
+	}
+}
@@ -17,12 +17,15 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	"fmt"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -32,10 +35,20 @@ import (
 // log is for logging in this package.
 var hcloudmachinelog = utils.GetDefaultLogger("info").WithName("hcloudmachine-resource")
 
+// HCloudMachineWebhook implements the validating webhook for HCloudMachine. Besides the checks of the
+// HCloudMachine itself, it validates the HCloudMachine against the HetznerCluster of its cluster.
+// +k8s:deepcopy-gen=false
+type HCloudMachineWebhook struct {
+	c client.Client
+}
+
 // SetupWebhookWithManager initializes webhook manager for HCloudMachine.
-func (r *HCloudMachine) SetupWebhookWithManager(mgr ctrl.Manager) error {
+func (hw *HCloudMachineWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	hw.c = mgr.GetClient()
+
 	return ctrl.NewWebhookManagedBy(mgr).
-		For(r).
+		For(&HCloudMachine{}).
+		WithValidator(hw).
 		Complete()
 }
 
@@ -69,6 +82,13 @@ func (r *HCloudMachine) ValidateCreate() (admission.Warnings, error) {
 	hcloudmachinelog.V(1).Info("validate create", "name", r.Name)
 	var allErrs field.ErrorList
 
+	allErrs = append(allErrs, validateHCloudVolumes(field.NewPath("spec", "volumes"), r.Spec.Volumes)...)
+	allErrs = append(allErrs, validateHCloudFallbacks(field.NewPath("spec"), r.Spec)...)
+	allErrs = append(allErrs, validateHCloudImage(field.NewPath("spec"), r.Spec)...)
+	allErrs = append(allErrs, validateHCloudLabels(field.NewPath("spec"), r.Spec)...)
+	allErrs = append(allErrs, validateHCloudDeletionPolicy(field.NewPath("spec"), r.Spec)...)
+	allErrs = append(allErrs, validateHCloudSnapshotAnnotation(r.Annotations)...)
+
 	return nil, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
@@ -81,7 +101,23 @@ func (r *HCloudMachine) ValidateUpdate(old runtime.Object) (admission.Warnings,
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an HCloudMachine but got a %T", old))
 	}
 
-	allErrs := validateHCloudMachineSpec(oldM.Spec, r.Spec)
+	oldSpec := oldM.Spec
+	var allErrs field.ErrorList
+	if _, found := r.Annotations[RebuildApprovedAnnotation]; found {
+		if oldM.rebuildInProgress() {
+			allErrs = append(allErrs, validateHCloudRebuildImage(field.NewPath("spec"), oldSpec, r.Spec)...)
+		}
+
+		// the server is rebuilt in place with the new image
+		oldSpec.ImageName = r.Spec.ImageName
+		oldSpec.Image = r.Spec.Image
+		allErrs = append(allErrs, validateHCloudImage(field.NewPath("spec"), r.Spec)...)
+	}
+
+	allErrs = append(allErrs, validateHCloudMachineSpec(oldSpec, r.Spec)...)
+	allErrs = append(allErrs, validateHCloudLabels(field.NewPath("spec"), r.Spec)...)
+	allErrs = append(allErrs, validateHCloudDeletionPolicy(field.NewPath("spec"), r.Spec)...)
+	allErrs = append(allErrs, validateHCloudSnapshotAnnotation(r.Annotations)...)
 
 	return nil, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
@@ -91,3 +127,79 @@ func (r *HCloudMachine) ValidateDelete() (admission.Warnings, error) {
 	hcloudmachinelog.V(1).Info("validate delete", "name", r.Name)
 	return nil, nil
 }
+
+var _ webhook.CustomValidator = &HCloudMachineWebhook{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (hw *HCloudMachineWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	hcloudMachine, ok := obj.(*HCloudMachine)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an HCloudMachine but got a %T", obj))
+	}
+
+	if warnings, err := hcloudMachine.ValidateCreate(); err != nil {
+		return warnings, err
+	}
+
+	if !hcloudMachine.Spec.IsPrivateOnly() {
+		return nil, nil
+	}
+
+	hetznerCluster, err := hw.getHetznerCluster(ctx, hcloudMachine)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("could not verify that the network of the cluster is enabled: %s", err.Error())}, nil
+	}
+	if hetznerCluster == nil {
+		return nil, nil
+	}
+
+	var allErrs field.ErrorList
+	if !hetznerCluster.Spec.HCloudNetwork.Enabled {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "publicNetwork"),
+			fmt.Sprintf("servers without public IPv4 and IPv6 require an enabled network, but the network of HetznerCluster %q is disabled", hetznerCluster.Name)))
+	}
+
+	return nil, aggregateObjErrors(hcloudMachine.GroupVersionKind().GroupKind(), hcloudMachine.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (hw *HCloudMachineWebhook) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	hcloudMachine, ok := newObj.(*HCloudMachine)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an HCloudMachine but got a %T", newObj))
+	}
+	return hcloudMachine.ValidateUpdate(oldObj)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (hw *HCloudMachineWebhook) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	hcloudMachine, ok := obj.(*HCloudMachine)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an HCloudMachine but got a %T", obj))
+	}
+	return hcloudMachine.ValidateDelete()
+}
+
+// getHetznerCluster returns the HetznerCluster of the cluster that the HCloudMachine belongs to. It returns nil
+// if the HCloudMachine has no cluster label or the cluster has no infrastructure yet.
+func (hw *HCloudMachineWebhook) getHetznerCluster(ctx context.Context, hcloudMachine *HCloudMachine) (*HetznerCluster, error) {
+	clusterName, ok := hcloudMachine.Labels[clusterv1.ClusterNameLabel]
+	if !ok {
+		return nil, nil
+	}
+
+	cluster := &clusterv1.Cluster{}
+	if err := hw.c.Get(ctx, client.ObjectKey{Namespace: hcloudMachine.Namespace, Name: clusterName}, cluster); err != nil {
+		return nil, fmt.Errorf("failed to get Cluster %q: %w", clusterName, err)
+	}
+	ref := cluster.Spec.InfrastructureRef
+	if ref == nil || ref.Kind != "HetznerCluster" {
+		return nil, nil
+	}
+
+	hetznerCluster := &HetznerCluster{}
+	if err := hw.c.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: ref.Name}, hetznerCluster); err != nil {
+		return nil, fmt.Errorf("failed to get HetznerCluster %q: %w", ref.Name, err)
+	}
+	return hetznerCluster, nil
+}
@@ -22,6 +22,20 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+const (
+	// NodeLabelsAnnotation declares labels of the nodes of machines of an HCloudMachineTemplate in the format
+	// "key1=value1,key2=value2". They are added to status.nodeLabels.
+	NodeLabelsAnnotation = "capacity.cluster-autoscaler.kubernetes.io/labels"
+
+	// NodeTaintsAnnotation declares taints of the nodes of machines of an HCloudMachineTemplate in the format
+	// "key1=value1:NoSchedule,key2:NoExecute". They are added to status.nodeTaints.
+	NodeTaintsAnnotation = "capacity.cluster-autoscaler.kubernetes.io/taints"
+
+	// GPUCountAnnotation declares the number of GPUs of machines of an HCloudMachineTemplate, as HCloud does not report
+	// them. It is added to status.capacity as nvidia.com/gpu.
+	GPUCountAnnotation = "capacity.cluster-autoscaler.kubernetes.io/gpu-count"
+)
+
 // HCloudMachineTemplateSpec defines the desired state of HCloudMachineTemplate.
 type HCloudMachineTemplateSpec struct {
 	Template HCloudMachineTemplateResource `json:"template"`
@@ -35,6 +49,33 @@ type HCloudMachineTemplateStatus struct {
 	// +optional
 	Capacity corev1.ResourceList `json:"capacity,omitempty"`
 
+	// NodeInfo contains the architecture and operating system of the nodes of machines of this template. It is used
+	// for autoscaling from zero as well.
+	// +optional
+	NodeInfo *NodeInfo `json:"nodeInfo,omitempty"`
+
+	// NodeLabels are the labels that the nodes of machines of this template are expected to have. They consist of
+	// well-known labels of the server type and of the labels of the annotation capacity.cluster-autoscaler.kubernetes.io/labels.
+	// +optional
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+
+	// NodeTaints are the taints of the annotation capacity.cluster-autoscaler.kubernetes.io/taints.
+	// +optional
+	NodeTaints []corev1.Taint `json:"nodeTaints,omitempty"`
+
+	// ServerType is the server type that the capacity has been evaluated for.
+	// +optional
+	ServerType HCloudMachineType `json:"serverType,omitempty"`
+
+	// CPUType is the type of the CPU of the server type, either shared or dedicated.
+	// +optional
+	CPUType string `json:"cpuType,omitempty"`
+
+	// LastCapacityUpdate is the time when the capacity has been evaluated. It is evaluated again periodically and
+	// when the server type changes.
+	// +optional
+	LastCapacityUpdate *metav1.Time `json:"lastCapacityUpdate,omitempty"`
+
 	// Conditions defines current service state of the HCloudMachineTemplate.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
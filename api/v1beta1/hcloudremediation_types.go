@@ -43,6 +43,15 @@ type HCloudRemediationStatus struct {
 	// +optional
 	LastRemediated *metav1.Time `json:"lastRemediated,omitempty"`
 
+	// CurrentStep is the index of the current step of an escalating remediation. RetryCount counts the executions
+	// of this step.
+	// +optional
+	CurrentStep int `json:"currentStep,omitempty"`
+
+	// Steps records the steps of an escalating remediation that have been executed.
+	// +optional
+	Steps []RemediationStepStatus `json:"steps,omitempty"`
+
 	// Conditions defines current service state of the HCloudRemediation.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
@@ -55,6 +64,7 @@ type HCloudRemediationStatus struct {
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Timeout",type=string,JSONPath=".spec.strategy.timeout",description="Timeout for the remediation"
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=".status.phase",description="Phase of the remediation"
+// +kubebuilder:printcolumn:name="Step",type=string,JSONPath=".status.steps[-1:].type",description="Last executed step of an escalating remediation"
 // +kubebuilder:printcolumn:name="Last Remediated",type=string,JSONPath=".status.lastRemediated",description="Timestamp of the last remediation attempt"
 // +kubebuilder:printcolumn:name="Retry count",type=string,JSONPath=".status.retryCount",description="How many times remediation controller has tried to remediate the node"
 // +kubebuilder:printcolumn:name="Retry limit",type=string,JSONPath=".spec.strategy.retryLimit",description="How many times remediation controller should attempt to remediate the node"
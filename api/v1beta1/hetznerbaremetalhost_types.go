@@ -211,6 +211,10 @@ func VerboseRebootType(rebootType RebootType) string {
 // RebootAnnotationArguments defines the arguments of the RebootAnnotation type.
 type RebootAnnotationArguments struct {
 	Type RebootType `json:"type"`
+
+	// Robot reboots the host with a software, hardware or power reset of the type via the Robot API. Without it,
+	// the host is rebooted via SSH, whatever the type is.
+	Robot bool `json:"robot,omitempty"`
 }
 
 // HetznerBareMetalHostSpec defines the desired state of HetznerBareMetalHost.
@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
@@ -98,6 +99,28 @@ type HostSelector struct {
 	MatchExpressions []HostSelectorRequirement `json:"matchExpressions,omitempty"`
 }
 
+// LabelSelector returns the selector of HetznerBareMetalHosts. Invalid requirements are ignored.
+func (hs HostSelector) LabelSelector() labels.Selector {
+	labelSelector := labels.NewSelector()
+	var reqs labels.Requirements
+
+	for labelKey, labelVal := range hs.MatchLabels {
+		r, err := labels.NewRequirement(labelKey, selection.Equals, []string{labelVal})
+		if err == nil { // ignore invalid host selector
+			reqs = append(reqs, *r)
+		}
+	}
+	for _, req := range hs.MatchExpressions {
+		lowercaseOperator := selection.Operator(strings.ToLower(string(req.Operator)))
+		r, err := labels.NewRequirement(req.Key, lowercaseOperator, req.Values)
+		if err == nil { // ignore invalid host selector
+			reqs = append(reqs, *r)
+		}
+	}
+
+	return labelSelector.Add(reqs...)
+}
+
 // HostSelectorRequirement defines a requirement used for MatchExpressions to select host machines.
 type HostSelectorRequirement struct {
 	// Key defines the key of the label that should be matched in the host object.
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,9 +26,41 @@ type HetznerBareMetalMachineTemplateSpec struct {
 	Template HetznerBareMetalMachineTemplateResource `json:"template"`
 }
 
+// HetznerBareMetalMachineTemplateStatus defines the observed state of HetznerBareMetalMachineTemplate.
+type HetznerBareMetalMachineTemplateStatus struct {
+	// Capacity is the minimum capacity of the HetznerBareMetalHosts that match the host selector of the template.
+	// Only hosts with hardware details are considered. It contains the CPU threads, the RAM and the size of the
+	// root disk as ephemeral storage.
+	// This value is used for autoscaling from zero operations as defined in:
+	// https://github.com/kubernetes-sigs/cluster-api/blob/main/docs/proposals/20210310-opt-in-autoscaling-from-zero.md
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+
+	// NodeInfo contains the architecture and operating system of the nodes of machines of this template. It is used
+	// for autoscaling from zero as well.
+	// +optional
+	NodeInfo *NodeInfo `json:"nodeInfo,omitempty"`
+
+	// MatchingHosts is the number of HetznerBareMetalHosts that match the host selector of the template.
+	// +optional
+	MatchingHosts int `json:"matchingHosts"`
+
+	// AvailableHosts is the number of matching HetznerBareMetalHosts that are neither in use nor in maintenance mode
+	// nor have an error, i.e. the number of machines that can still be created with this template.
+	// +optional
+	AvailableHosts int `json:"availableHosts"`
+
+	// LastCapacityUpdate is the time when the capacity or the number of hosts changed the last time.
+	// +optional
+	LastCapacityUpdate *metav1.Time `json:"lastCapacityUpdate,omitempty"`
+}
+
 // HetznerBareMetalMachineTemplate is the Schema for the hetznerbaremetalmachinetemplates API.
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Available",type="integer",JSONPath=".status.availableHosts",description="Number of matching hosts that are not in use"
+// +kubebuilder:printcolumn:name="Matching",type="integer",JSONPath=".status.matchingHosts",description="Number of hosts that match the host selector"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of HetznerBareMetalMachineTemplate"
 // +kubebuilder:resource:path=hetznerbaremetalmachinetemplates,scope=Namespaced,categories=cluster-api,shortName=hbmmt
 // +kubebuilder:storageversion
@@ -38,6 +71,9 @@ type HetznerBareMetalMachineTemplate struct {
 
 	// +optional
 	Spec HetznerBareMetalMachineTemplateSpec `json:"spec,omitempty"`
+
+	// +optional
+	Status HetznerBareMetalMachineTemplateStatus `json:"status,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -21,9 +21,14 @@ import (
 )
 
 const (
-	// RebootAnnotation indicates that a bare metal host object should be rebooted.
+	// RebootAnnotation indicates that a bare metal host object should be rebooted. The value can contain
+	// RebootAnnotationArguments to reboot via the Robot API. Without them, the host is rebooted via SSH.
 	RebootAnnotation = "capi.syself.com/reboot"
 
+	// ReprovisionAnnotation indicates that a provisioned bare metal host should be provisioned again with the same
+	// InstallImage. The controller removes the annotation once it starts provisioning.
+	ReprovisionAnnotation = "capi.syself.com/reprovision"
+
 	// PermanentErrorAnnotation indicates that the bare metal host has an error which needs to be resolved manually.
 	// After the permanent error the annotation got removed (usually by a human), the controller removes
 	// ErrorType, ErrorCount and ErrorMessages, so that the hbmh will be usable again.
@@ -51,6 +56,15 @@ type HetznerBareMetalRemediationStatus struct {
 	// LastRemediated identifies when the host was last remediated
 	// +optional
 	LastRemediated *metav1.Time `json:"lastRemediated,omitempty"`
+
+	// CurrentStep is the index of the current step of an escalating remediation. RetryCount counts the executions
+	// of this step.
+	// +optional
+	CurrentStep int `json:"currentStep,omitempty"`
+
+	// Steps records the steps of an escalating remediation that have been executed.
+	// +optional
+	Steps []RemediationStepStatus `json:"steps,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -62,6 +76,7 @@ type HetznerBareMetalRemediationStatus struct {
 // +kubebuilder:printcolumn:name="Retry limit",type=string,JSONPath=".spec.strategy.retryLimit",description="How many times remediation controller should attempt to remediate the host"
 // +kubebuilder:printcolumn:name="Timeout",type=string,JSONPath=".spec.strategy.timeout",description="Timeout for the remediation"
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=".status.phase",description="Phase of the remediation"
+// +kubebuilder:printcolumn:name="Step",type=string,JSONPath=".status.steps[-1:].type",description="Last executed step of an escalating remediation"
 // +kubebuilder:printcolumn:name="Last Remediated",type=string,JSONPath=".status.lastRemediated",description="Timestamp of the last remediation attempt"
 // +kubebuilder:printcolumn:name="Retry count",type=string,JSONPath=".status.retryCount",description="How many times remediation controller has tried to remediate the node"
 
@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -44,12 +45,14 @@ var _ webhook.Validator = &HetznerBareMetalRemediation{}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
 func (r *HetznerBareMetalRemediation) ValidateCreate() (admission.Warnings, error) {
-	return nil, nil
+	allErrs := validateRemediationStrategy(field.NewPath("spec", "strategy"), r.Spec.Strategy, bareMetalRemediationStepTypes)
+	return nil, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
 func (r *HetznerBareMetalRemediation) ValidateUpdate(runtime.Object) (admission.Warnings, error) {
-	return nil, nil
+	allErrs := validateRemediationStrategy(field.NewPath("spec", "strategy"), r.Spec.Strategy, bareMetalRemediationStepTypes)
+	return nil, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -36,6 +38,15 @@ const (
 	AllowEmptyControlPlaneAddressAnnotation = "capi.syself.com/allow-empty-control-plane-address"
 	// ConstantBareMetalHostnameAnnotation makes hostnames of bare metal servers constant.
 	ConstantBareMetalHostnameAnnotation = "capi.syself.com/constant-bare-metal-hostname"
+	// LoadBalancerMigrationApprovedAnnotation approves the migration of the control plane load balancer to another
+	// region or network. It is removed once the migration has finished.
+	LoadBalancerMigrationApprovedAnnotation = "capi.syself.com/load-balancer-migration-approved"
+
+	// DefaultBootstrapDataURLExpiry is the time after which the URL of bootstrap data in the bootstrap data storage
+	// expires if no expiry is configured.
+	DefaultBootstrapDataURLExpiry = 15 * time.Minute
+	// MaxBootstrapDataURLExpiry is the longest expiry of URLs of bootstrap data that object storages accept.
+	MaxBootstrapDataURLExpiry = 7 * 24 * time.Hour
 )
 
 // HetznerClusterSpec defines the desired state of HetznerCluster.
@@ -58,9 +69,36 @@ type HetznerClusterSpec struct {
 	// ControlPlaneLoadBalancer is an optional configuration for customizing control plane behavior.
 	ControlPlaneLoadBalancer LoadBalancerSpec `json:"controlPlaneLoadBalancer,omitempty"`
 
+	// AdditionalLoadBalancers defines load balancers that are owned by the cluster in addition to the control plane
+	// load balancer, e.g. for ingress traffic. They are deleted together with the cluster.
+	// +optional
+	AdditionalLoadBalancers []AdditionalLoadBalancerSpec `json:"additionalLoadBalancers,omitempty"`
+
+	// ControlPlaneEndpointType defines how the control plane endpoint is provided. With LoadBalancer, the endpoint is
+	// taken from the control plane load balancer or has to be provided by the user. With FloatingIP, an HCloud
+	// Floating IP is created, owned by the cluster and assigned to a control plane server. This requires the control
+	// plane load balancer to be disabled. If omitted, the default value is "LoadBalancer".
+	// +optional
+	ControlPlaneEndpointType ControlPlaneEndpointType `json:"controlPlaneEndpointType,omitempty"`
+
+	// ControlPlaneDNS defines DNS records that are created for the control plane endpoint and kept in sync with the
+	// IPs of the control plane load balancer or the control plane IP. The records are deleted together with the cluster.
+	// +optional
+	ControlPlaneDNS *ControlPlaneDNSSpec `json:"controlPlaneDNS,omitempty"`
+
+	// BootstrapDataStorage is an S3 compatible object storage, e.g. Hetzner Object Storage, that serves the bootstrap
+	// data of HCloud servers whose user data exceeds the limit of HCloud even after compression. If it is not set,
+	// such servers cannot be created.
+	// +optional
+	BootstrapDataStorage *BootstrapDataStorageSpec `json:"bootstrapDataStorage,omitempty"`
+
 	// +optional
 	HCloudPlacementGroups []HCloudPlacementGroupSpec `json:"hcloudPlacementGroups,omitempty"`
 
+	// HCloudFirewalls defines the HCloud firewalls that are managed for the servers of the cluster.
+	// +optional
+	HCloudFirewalls []HCloudFirewallSpec `json:"hcloudFirewalls,omitempty"`
+
 	// HetznerSecretRef is a reference to a token to be used when reconciling this cluster.
 	// This is generated in the security section under API TOKENS. Read & write is necessary.
 	HetznerSecret HetznerSecretRef `json:"hetznerSecretRef"`
@@ -74,11 +112,23 @@ type HetznerClusterStatus struct {
 	// +optional
 	Network *NetworkStatus `json:"networkStatus,omitempty"`
 
+	// NATGateway is the observed state of the NAT gateway of the network.
+	// +optional
+	NATGateway *NATGatewayStatus `json:"natGateway,omitempty"`
+
 	ControlPlaneLoadBalancer *LoadBalancerStatus `json:"controlPlaneLoadBalancer,omitempty"`
 	// +optional
+	AdditionalLoadBalancers []AdditionalLoadBalancerStatus `json:"additionalLoadBalancers,omitempty"`
+	// +optional
+	ControlPlaneIP *ControlPlaneIPStatus `json:"controlPlaneIP,omitempty"`
+	// +optional
+	ControlPlaneDNS *ControlPlaneDNSStatus `json:"controlPlaneDNS,omitempty"`
+	// +optional
 	HCloudPlacementGroups []HCloudPlacementGroupStatus `json:"hcloudPlacementGroups,omitempty"`
-	FailureDomains        clusterv1.FailureDomains     `json:"failureDomains,omitempty"`
-	Conditions            clusterv1.Conditions         `json:"conditions,omitempty"`
+	// +optional
+	HCloudFirewalls []HCloudFirewallStatus   `json:"hcloudFirewalls,omitempty"`
+	FailureDomains  clusterv1.FailureDomains `json:"failureDomains,omitempty"`
+	Conditions      clusterv1.Conditions     `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -122,6 +172,11 @@ func (r *HetznerCluster) SetConditions(conditions clusterv1.Conditions) {
 	r.Status.Conditions = conditions
 }
 
+// UsesControlPlaneIP returns true if the control plane endpoint is provided by an HCloud Floating IP.
+func (r *HetznerCluster) UsesControlPlaneIP() bool {
+	return r.Spec.ControlPlaneEndpointType == ControlPlaneEndpointTypeFloatingIP
+}
+
 // ClusterTagKey generates the key for resources associated with a cluster.
 func (r *HetznerCluster) ClusterTagKey() string {
 	return NameHetznerProviderOwned + r.Name
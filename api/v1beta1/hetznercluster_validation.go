@@ -0,0 +1,387 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func validateHCloudFirewalls(firewalls []HCloudFirewallSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	firewallsPath := field.NewPath("spec", "hcloudFirewalls")
+
+	names := make(map[string]struct{}, len(firewalls))
+	for i, fw := range firewalls {
+		// Names have to be unique as they identify the firewalls in HCloud
+		if _, ok := names[fw.Name]; ok {
+			allErrs = append(allErrs,
+				field.Duplicate(firewallsPath.Index(i).Child("name"), fw.Name),
+			)
+		}
+		names[fw.Name] = struct{}{}
+
+		for j, rule := range fw.Rules {
+			rulePath := firewallsPath.Index(i).Child("rules").Index(j)
+
+			// Ports are only supported for tcp and udp
+			if rule.Port != nil && rule.Protocol != "tcp" && rule.Protocol != "udp" {
+				allErrs = append(allErrs,
+					field.Invalid(rulePath.Child("port"), *rule.Port, "port is only supported for protocols tcp and udp"),
+				)
+			}
+			if rule.Port == nil && (rule.Protocol == "tcp" || rule.Protocol == "udp") {
+				allErrs = append(allErrs,
+					field.Required(rulePath.Child("port"), "port is required for protocols tcp and udp"),
+				)
+			}
+
+			allErrs = append(allErrs, validateCIDRs(rulePath.Child("sourceIPs"), rule.SourceIPs)...)
+			allErrs = append(allErrs, validateCIDRs(rulePath.Child("destinationIPs"), rule.DestinationIPs)...)
+		}
+	}
+
+	return allErrs
+}
+
+func validateCIDRs(fldPath *field.Path, cidrs []string) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, cidr := range cidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), cidr, "invalid CIDR"))
+		}
+	}
+	return allErrs
+}
+
+func validateHCloudNetwork(network HCloudNetworkSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	networkPath := field.NewPath("spec", "hcloudNetwork")
+
+	// An adopted network is not modified by the controller
+	if network.ID != nil && len(network.LabelSelector) > 0 {
+		allErrs = append(allErrs,
+			field.Forbidden(networkPath.Child("labelSelector"), "labelSelector cannot be combined with id"),
+		)
+	}
+	if network.IsAdopted() && len(network.Subnets) > 0 {
+		allErrs = append(allErrs,
+			field.Forbidden(networkPath.Child("subnets"), "subnets cannot be specified for an existing network"),
+		)
+	}
+	if network.IsAdopted() && len(network.Routes) > 0 {
+		allErrs = append(allErrs,
+			field.Forbidden(networkPath.Child("routes"), "routes cannot be specified for an existing network"),
+		)
+	}
+
+	// The NAT gateway adds a default route to the network, so it needs a network that is managed by the controller
+	if network.NATGateway != nil && !network.Enabled {
+		allErrs = append(allErrs,
+			field.Forbidden(networkPath.Child("natGateway"), "natGateway requires an enabled network"),
+		)
+	}
+	if network.NATGateway != nil && network.IsAdopted() {
+		allErrs = append(allErrs,
+			field.Forbidden(networkPath.Child("natGateway"), "natGateway cannot be combined with an existing network"),
+		)
+	}
+
+	ipRanges := make(map[string]struct{}, len(network.Subnets))
+	for i, subnet := range network.Subnets {
+		subnetPath := networkPath.Child("subnets").Index(i)
+
+		if _, _, err := net.ParseCIDR(subnet.IPRange); err != nil {
+			allErrs = append(allErrs, field.Invalid(subnetPath.Child("ipRange"), subnet.IPRange, "invalid CIDR"))
+		}
+
+		// The IP range identifies the subnet in HCloud
+		if _, ok := ipRanges[subnet.IPRange]; ok {
+			allErrs = append(allErrs, field.Duplicate(subnetPath.Child("ipRange"), subnet.IPRange))
+		}
+		ipRanges[subnet.IPRange] = struct{}{}
+
+		if subnet.Type == HCloudNetworkSubnetTypeVSwitch && subnet.VSwitchID == nil {
+			allErrs = append(allErrs,
+				field.Required(subnetPath.Child("vSwitchID"), "vSwitchID is required for subnets of type vswitch"),
+			)
+		}
+		if subnet.Type != HCloudNetworkSubnetTypeVSwitch && subnet.VSwitchID != nil {
+			allErrs = append(allErrs,
+				field.Invalid(subnetPath.Child("vSwitchID"), *subnet.VSwitchID, "vSwitchID is only supported for subnets of type vswitch"),
+			)
+		}
+	}
+
+	for i, route := range network.Routes {
+		routePath := networkPath.Child("routes").Index(i)
+
+		if _, _, err := net.ParseCIDR(route.Destination); err != nil {
+			allErrs = append(allErrs, field.Invalid(routePath.Child("destination"), route.Destination, "invalid CIDR"))
+		}
+		if net.ParseIP(route.Gateway) == nil {
+			allErrs = append(allErrs, field.Invalid(routePath.Child("gateway"), route.Gateway, "invalid IP address"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateHCloudNetworkUpdate only allows adding subnets and routes, as they are never removed from an existing network,
+// and changing the NAT gateway.
+func validateHCloudNetworkUpdate(oldNetwork, newNetwork HCloudNetworkSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	networkPath := field.NewPath("spec", "hcloudNetwork")
+
+	oldFixed, newFixed := oldNetwork, newNetwork
+	oldFixed.Subnets, oldFixed.Routes, oldFixed.NATGateway = nil, nil, nil
+	newFixed.Subnets, newFixed.Routes, newFixed.NATGateway = nil, nil, nil
+	if !reflect.DeepEqual(oldFixed, newFixed) {
+		allErrs = append(allErrs,
+			field.Invalid(networkPath, newNetwork, "field is immutable except for adding subnets and routes and the NAT gateway"),
+		)
+	}
+
+	for _, oldSubnet := range oldNetwork.Subnets {
+		if !slices.ContainsFunc(newNetwork.Subnets, func(s HCloudNetworkSubnetSpec) bool {
+			return reflect.DeepEqual(s, oldSubnet)
+		}) {
+			allErrs = append(allErrs,
+				field.Forbidden(networkPath.Child("subnets"), fmt.Sprintf("subnet %s must not be changed or removed", oldSubnet.IPRange)),
+			)
+		}
+	}
+
+	for _, oldRoute := range oldNetwork.Routes {
+		if !slices.Contains(newNetwork.Routes, oldRoute) {
+			allErrs = append(allErrs,
+				field.Forbidden(networkPath.Child("routes"), fmt.Sprintf("route %s via %s must not be changed or removed", oldRoute.Destination, oldRoute.Gateway)),
+			)
+		}
+	}
+
+	return allErrs
+}
+
+// hasCloudSubnetForAllRegions checks that servers in all regions can be attached to the network.
+func hasCloudSubnetForAllRegions(regions []Region, subnets []HCloudNetworkSubnetSpec) *field.Error {
+	for _, region := range regions {
+		if !slices.ContainsFunc(subnets, func(s HCloudNetworkSubnetSpec) bool {
+			return s.Type != HCloudNetworkSubnetTypeVSwitch && string(s.NetworkZone) == regionNetworkZoneMap[string(region)]
+		}) {
+			return field.Invalid(field.NewPath("spec", "controlPlaneRegions"), regions,
+				fmt.Sprintf("no cloud subnet in the network zone of region %s", region))
+		}
+	}
+	return nil
+}
+
+func validateLoadBalancerServices(lb LoadBalancerSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	lbPath := field.NewPath("spec", "controlPlaneLoadBalancer")
+
+	if lb.HealthCheck != nil {
+		allErrs = append(allErrs, validateLoadBalancerHealthCheck(lbPath.Child("healthCheck"), *lb.HealthCheck)...)
+	}
+
+	for i, service := range lb.ExtraServices {
+		allErrs = append(allErrs, validateLoadBalancerService(lbPath.Child("extraServices").Index(i), service)...)
+	}
+
+	return allErrs
+}
+
+func validateLoadBalancerService(servicePath *field.Path, service LoadBalancerServiceSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if service.HTTP != nil && service.Protocol == "tcp" {
+		allErrs = append(allErrs,
+			field.Invalid(servicePath.Child("http"), service.Protocol, "http settings are only supported for protocols http and https"),
+		)
+	}
+	if service.Protocol == "https" && (service.HTTP == nil || len(service.HTTP.CertificateIDs) == 0) {
+		allErrs = append(allErrs,
+			field.Required(servicePath.Child("http", "certificateIDs"), "certificates are required for protocol https"),
+		)
+	}
+	if service.HTTP != nil && service.HTTP.RedirectHTTP && service.Protocol != "https" {
+		allErrs = append(allErrs,
+			field.Invalid(servicePath.Child("http", "redirectHTTP"), service.HTTP.RedirectHTTP, "redirectHTTP is only supported for protocol https"),
+		)
+	}
+
+	if service.HealthCheck != nil {
+		allErrs = append(allErrs, validateLoadBalancerHealthCheck(servicePath.Child("healthCheck"), *service.HealthCheck)...)
+	}
+
+	return allErrs
+}
+
+func validateAdditionalLoadBalancers(loadBalancers []AdditionalLoadBalancerSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	names := make(map[string]struct{}, len(loadBalancers))
+
+	for i, lb := range loadBalancers {
+		lbPath := field.NewPath("spec", "additionalLoadBalancers").Index(i)
+
+		if _, ok := names[lb.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(lbPath.Child("name"), lb.Name))
+		}
+		names[lb.Name] = struct{}{}
+
+		listenPorts := make(map[int]struct{}, len(lb.Services))
+		for j, service := range lb.Services {
+			servicePath := lbPath.Child("services").Index(j)
+			if _, ok := listenPorts[service.ListenPort]; ok {
+				allErrs = append(allErrs, field.Duplicate(servicePath.Child("listenPort"), service.ListenPort))
+			}
+			listenPorts[service.ListenPort] = struct{}{}
+
+			allErrs = append(allErrs, validateLoadBalancerService(servicePath, service)...)
+		}
+	}
+
+	return allErrs
+}
+
+// validateAdditionalLoadBalancersUpdate checks that the region of existing additional load balancers is not changed.
+func validateAdditionalLoadBalancersUpdate(oldLoadBalancers, newLoadBalancers []AdditionalLoadBalancerSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, lb := range newLoadBalancers {
+		for _, oldLB := range oldLoadBalancers {
+			if oldLB.Name == lb.Name && oldLB.Region != lb.Region {
+				allErrs = append(allErrs,
+					field.Invalid(field.NewPath("spec", "additionalLoadBalancers").Index(i).Child("region"), lb.Region, "field is immutable"),
+				)
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateLoadBalancerHealthCheck(fldPath *field.Path, healthCheck LoadBalancerHealthCheckSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if healthCheck.HTTP != nil && healthCheck.Protocol != "http" {
+		allErrs = append(allErrs,
+			field.Invalid(fldPath.Child("http"), healthCheck.Protocol, "http settings are only supported for protocol http"),
+		)
+	}
+
+	if healthCheck.Interval != nil && healthCheck.Interval.Duration < time.Second {
+		allErrs = append(allErrs,
+			field.Invalid(fldPath.Child("interval"), healthCheck.Interval.Duration.String(), "interval must be at least 1s"),
+		)
+	}
+	if healthCheck.Timeout != nil && healthCheck.Timeout.Duration < time.Second {
+		allErrs = append(allErrs,
+			field.Invalid(fldPath.Child("timeout"), healthCheck.Timeout.Duration.String(), "timeout must be at least 1s"),
+		)
+	}
+
+	return allErrs
+}
+
+// validateHCloudPlacementGroups checks that the names of the placement groups and of the shards of sharded
+// placement groups do not collide.
+func validateHCloudPlacementGroups(placementGroups []HCloudPlacementGroupSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	names := make(map[string]struct{}, len(placementGroups))
+
+	for i, pg := range placementGroups {
+		namePath := field.NewPath("spec", "hcloudPlacementGroups").Index(i).Child("name")
+
+		if _, ok := names[pg.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(namePath, pg.Name))
+		}
+		names[pg.Name] = struct{}{}
+
+		for _, sharded := range placementGroups {
+			if !sharded.Sharded || sharded.Name == pg.Name {
+				continue
+			}
+			if index, ok := strings.CutPrefix(pg.Name, sharded.Name+"-"); ok && isNumeric(index) {
+				allErrs = append(allErrs,
+					field.Invalid(namePath, pg.Name, fmt.Sprintf("name collides with the shards of placement group %s", sharded.Name)),
+				)
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// validateControlPlaneDNS checks that the DNS records of the control plane endpoint have an IP to point to and
+// that their name is relative to the zone.
+func validateControlPlaneDNS(r *HetznerCluster) field.ErrorList {
+	var allErrs field.ErrorList
+	dns := r.Spec.ControlPlaneDNS
+	if dns == nil {
+		return nil
+	}
+	fldPath := field.NewPath("spec", "controlPlaneDNS")
+
+	if !r.Spec.ControlPlaneLoadBalancer.Enabled && !r.UsesControlPlaneIP() {
+		allErrs = append(allErrs,
+			field.Invalid(fldPath, dns.Name, "requires the control plane load balancer or a control plane IP"),
+		)
+	}
+
+	zone := strings.TrimSuffix(dns.Zone, ".")
+	if name := strings.TrimSuffix(dns.Name, "."); name == zone || strings.HasSuffix(name, "."+zone) {
+		allErrs = append(allErrs,
+			field.Invalid(fldPath.Child("name"), dns.Name, "name must be relative to the zone, use @ for the apex of the zone"),
+		)
+	}
+
+	return allErrs
+}
+
+func validateBootstrapDataStorage(spec *BootstrapDataStorageSpec) field.ErrorList {
+	if spec == nil {
+		return nil
+	}
+
+	if expiry := spec.URLExpiry.Duration; expiry < 0 || expiry > MaxBootstrapDataURLExpiry {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec", "bootstrapDataStorage", "urlExpiry"),
+			spec.URLExpiry.Duration.String(),
+			fmt.Sprintf("must be between 0s and %s", MaxBootstrapDataURLExpiry),
+		)}
+	}
+	return nil
+}
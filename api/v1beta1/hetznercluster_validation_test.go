@@ -0,0 +1,527 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateHCloudFirewalls(t *testing.T) {
+	tests := []struct {
+		name      string
+		firewalls []HCloudFirewallSpec
+		want      *field.Error
+	}{
+		{
+			name: "Duplicate name",
+			firewalls: []HCloudFirewallSpec{
+				{Name: "fw"},
+				{Name: "fw"},
+			},
+			want: field.Duplicate(field.NewPath("spec", "hcloudFirewalls").Index(1).Child("name"), "fw"),
+		},
+		{
+			name: "Port with icmp",
+			firewalls: []HCloudFirewallSpec{
+				{Name: "fw", Rules: []HCloudFirewallRuleSpec{{Direction: "in", Protocol: "icmp", Port: ptr.To("22")}}},
+			},
+			want: field.Invalid(field.NewPath("spec", "hcloudFirewalls").Index(0).Child("rules").Index(0).Child("port"), "22", "port is only supported for protocols tcp and udp"),
+		},
+		{
+			name: "Missing port with tcp",
+			firewalls: []HCloudFirewallSpec{
+				{Name: "fw", Rules: []HCloudFirewallRuleSpec{{Direction: "in", Protocol: "tcp"}}},
+			},
+			want: field.Required(field.NewPath("spec", "hcloudFirewalls").Index(0).Child("rules").Index(0).Child("port"), "port is required for protocols tcp and udp"),
+		},
+		{
+			name: "Invalid CIDR",
+			firewalls: []HCloudFirewallSpec{
+				{Name: "fw", Rules: []HCloudFirewallRuleSpec{{Direction: "in", Protocol: "tcp", Port: ptr.To("22"), SourceIPs: []string{"10.0.0.1"}}}},
+			},
+			want: field.Invalid(field.NewPath("spec", "hcloudFirewalls").Index(0).Child("rules").Index(0).Child("sourceIPs").Index(0), "10.0.0.1", "invalid CIDR"),
+		},
+		{
+			name: "No Errors",
+			firewalls: []HCloudFirewallSpec{
+				{Name: "fw", Rules: []HCloudFirewallRuleSpec{{Direction: "in", Protocol: "tcp", Port: ptr.To("80-85"), SourceIPs: []string{"0.0.0.0/0", "::/0"}}}},
+				{Name: "fw2", Rules: []HCloudFirewallRuleSpec{{Direction: "out", Protocol: "icmp", DestinationIPs: []string{"10.0.0.0/8"}}}},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateHCloudFirewalls(tt.firewalls)
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
+
+func TestValidateHCloudNetwork(t *testing.T) {
+	subnetsPath := field.NewPath("spec", "hcloudNetwork", "subnets")
+	tests := []struct {
+		name    string
+		network HCloudNetworkSpec
+		want    *field.Error
+	}{
+		{
+			name: "Invalid subnet CIDR",
+			network: HCloudNetworkSpec{Subnets: []HCloudNetworkSubnetSpec{
+				{Type: "cloud", NetworkZone: "eu-central", IPRange: "10.0.0.0"},
+			}},
+			want: field.Invalid(subnetsPath.Index(0).Child("ipRange"), "10.0.0.0", "invalid CIDR"),
+		},
+		{
+			name: "Duplicate subnet",
+			network: HCloudNetworkSpec{Subnets: []HCloudNetworkSubnetSpec{
+				{Type: "cloud", NetworkZone: "eu-central", IPRange: "10.0.0.0/24"},
+				{Type: "cloud", NetworkZone: "us-east", IPRange: "10.0.0.0/24"},
+			}},
+			want: field.Duplicate(subnetsPath.Index(1).Child("ipRange"), "10.0.0.0/24"),
+		},
+		{
+			name: "Missing vSwitchID",
+			network: HCloudNetworkSpec{Subnets: []HCloudNetworkSubnetSpec{
+				{Type: "vswitch", NetworkZone: "eu-central", IPRange: "10.0.1.0/24"},
+			}},
+			want: field.Required(subnetsPath.Index(0).Child("vSwitchID"), "vSwitchID is required for subnets of type vswitch"),
+		},
+		{
+			name: "Invalid route gateway",
+			network: HCloudNetworkSpec{Routes: []HCloudNetworkRouteSpec{
+				{Destination: "10.100.0.0/16", Gateway: "10.0.0"},
+			}},
+			want: field.Invalid(field.NewPath("spec", "hcloudNetwork", "routes").Index(0).Child("gateway"), "10.0.0", "invalid IP address"),
+		},
+		{
+			name: "ID with label selector",
+			network: HCloudNetworkSpec{
+				ID:            ptr.To[int64](1),
+				LabelSelector: map[string]string{"network": "shared"},
+			},
+			want: field.Forbidden(field.NewPath("spec", "hcloudNetwork", "labelSelector"), "labelSelector cannot be combined with id"),
+		},
+		{
+			name: "Existing network with subnets",
+			network: HCloudNetworkSpec{
+				ID: ptr.To[int64](1),
+				Subnets: []HCloudNetworkSubnetSpec{
+					{Type: "cloud", NetworkZone: "eu-central", IPRange: "10.0.0.0/24"},
+				},
+			},
+			want: field.Forbidden(subnetsPath, "subnets cannot be specified for an existing network"),
+		},
+		{
+			name:    "NAT gateway without network",
+			network: HCloudNetworkSpec{NATGateway: &NATGatewaySpec{}},
+			want:    field.Forbidden(field.NewPath("spec", "hcloudNetwork", "natGateway"), "natGateway requires an enabled network"),
+		},
+		{
+			name:    "NAT gateway with existing network",
+			network: HCloudNetworkSpec{Enabled: true, ID: ptr.To[int64](1), NATGateway: &NATGatewaySpec{}},
+			want:    field.Forbidden(field.NewPath("spec", "hcloudNetwork", "natGateway"), "natGateway cannot be combined with an existing network"),
+		},
+		{
+			name: "No Errors",
+			network: HCloudNetworkSpec{
+				Subnets: []HCloudNetworkSubnetSpec{
+					{Type: "cloud", NetworkZone: "eu-central", IPRange: "10.0.0.0/24"},
+					{Type: "vswitch", NetworkZone: "eu-central", IPRange: "10.0.1.0/24", VSwitchID: ptr.To[int64](42)},
+				},
+				Routes: []HCloudNetworkRouteSpec{{Destination: "10.100.0.0/16", Gateway: "10.0.0.2"}},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateHCloudNetwork(tt.network)
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
+
+func TestValidateHCloudNetworkUpdate(t *testing.T) {
+	oldNetwork := HCloudNetworkSpec{
+		Enabled:   true,
+		CIDRBlock: "10.0.0.0/16",
+		Subnets: []HCloudNetworkSubnetSpec{
+			{Type: "cloud", NetworkZone: "eu-central", IPRange: "10.0.0.0/24"},
+		},
+		Routes: []HCloudNetworkRouteSpec{{Destination: "10.100.0.0/16", Gateway: "10.0.0.2"}},
+	}
+
+	t.Run("Adding subnets and routes is allowed", func(t *testing.T) {
+		newNetwork := *oldNetwork.DeepCopy()
+		newNetwork.Subnets = append(newNetwork.Subnets, HCloudNetworkSubnetSpec{Type: "cloud", NetworkZone: "us-east", IPRange: "10.0.1.0/24"})
+		newNetwork.Routes = append(newNetwork.Routes, HCloudNetworkRouteSpec{Destination: "10.101.0.0/16", Gateway: "10.0.0.3"})
+		assert.Empty(t, validateHCloudNetworkUpdate(oldNetwork, newNetwork))
+	})
+
+	t.Run("Adding and removing the NAT gateway is allowed", func(t *testing.T) {
+		newNetwork := *oldNetwork.DeepCopy()
+		newNetwork.NATGateway = &NATGatewaySpec{Type: "cx22", ImageName: "ubuntu-24.04"}
+		assert.Empty(t, validateHCloudNetworkUpdate(oldNetwork, newNetwork))
+		assert.Empty(t, validateHCloudNetworkUpdate(newNetwork, oldNetwork))
+	})
+
+	t.Run("Removing a subnet is forbidden", func(t *testing.T) {
+		newNetwork := *oldNetwork.DeepCopy()
+		newNetwork.Subnets = nil
+		got := validateHCloudNetworkUpdate(oldNetwork, newNetwork)
+		assert.Len(t, got, 1)
+		assert.Equal(t, field.ErrorTypeForbidden, got[0].Type)
+	})
+
+	t.Run("Changing a route is forbidden", func(t *testing.T) {
+		newNetwork := *oldNetwork.DeepCopy()
+		newNetwork.Routes[0].Gateway = "10.0.0.3"
+		got := validateHCloudNetworkUpdate(oldNetwork, newNetwork)
+		assert.Len(t, got, 1)
+		assert.Equal(t, field.ErrorTypeForbidden, got[0].Type)
+	})
+
+	t.Run("Changing the CIDR block is forbidden", func(t *testing.T) {
+		newNetwork := *oldNetwork.DeepCopy()
+		newNetwork.CIDRBlock = "10.1.0.0/16"
+		got := validateHCloudNetworkUpdate(oldNetwork, newNetwork)
+		assert.Len(t, got, 1)
+		assert.Equal(t, field.ErrorTypeInvalid, got[0].Type)
+	})
+}
+
+func TestHasCloudSubnetForAllRegions(t *testing.T) {
+	subnets := []HCloudNetworkSubnetSpec{
+		{Type: "cloud", NetworkZone: "eu-central", IPRange: "10.0.0.0/24"},
+		{Type: "vswitch", NetworkZone: "us-east", IPRange: "10.0.1.0/24", VSwitchID: ptr.To[int64](42)},
+	}
+
+	assert.Nil(t, hasCloudSubnetForAllRegions([]Region{"fsn1", "nbg1"}, subnets))
+	assert.NotNil(t, hasCloudSubnetForAllRegions([]Region{"fsn1", "ash"}, subnets))
+}
+
+func TestValidateLoadBalancerServices(t *testing.T) {
+	lbPath := field.NewPath("spec", "controlPlaneLoadBalancer")
+	tests := []struct {
+		name string
+		lb   LoadBalancerSpec
+		want *field.Error
+	}{
+		{
+			name: "HTTP settings with tcp",
+			lb: LoadBalancerSpec{ExtraServices: []LoadBalancerServiceSpec{
+				{Protocol: "tcp", ListenPort: 80, DestinationPort: 80, HTTP: &LoadBalancerServiceHTTPSpec{StickySessions: true}},
+			}},
+			want: field.Invalid(lbPath.Child("extraServices").Index(0).Child("http"), "tcp", "http settings are only supported for protocols http and https"),
+		},
+		{
+			name: "HTTPS without certificates",
+			lb: LoadBalancerSpec{ExtraServices: []LoadBalancerServiceSpec{
+				{Protocol: "https", ListenPort: 443, DestinationPort: 80},
+			}},
+			want: field.Required(lbPath.Child("extraServices").Index(0).Child("http", "certificateIDs"), "certificates are required for protocol https"),
+		},
+		{
+			name: "Redirect with http",
+			lb: LoadBalancerSpec{ExtraServices: []LoadBalancerServiceSpec{
+				{Protocol: "http", ListenPort: 80, DestinationPort: 80, HTTP: &LoadBalancerServiceHTTPSpec{RedirectHTTP: true}},
+			}},
+			want: field.Invalid(lbPath.Child("extraServices").Index(0).Child("http", "redirectHTTP"), true, "redirectHTTP is only supported for protocol https"),
+		},
+		{
+			name: "HTTP health check settings with tcp",
+			lb: LoadBalancerSpec{HealthCheck: &LoadBalancerHealthCheckSpec{
+				Protocol: "tcp",
+				HTTP:     &LoadBalancerHealthCheckHTTPSpec{Path: "/readyz"},
+			}},
+			want: field.Invalid(lbPath.Child("healthCheck", "http"), "tcp", "http settings are only supported for protocol http"),
+		},
+		{
+			name: "Health check interval too short",
+			lb: LoadBalancerSpec{HealthCheck: &LoadBalancerHealthCheckSpec{
+				Protocol: "tcp",
+				Interval: &metav1.Duration{Duration: 500 * time.Millisecond},
+			}},
+			want: field.Invalid(lbPath.Child("healthCheck", "interval"), "500ms", "interval must be at least 1s"),
+		},
+		{
+			name: "No Errors",
+			lb: LoadBalancerSpec{
+				HealthCheck: &LoadBalancerHealthCheckSpec{
+					Protocol: "http",
+					HTTP:     &LoadBalancerHealthCheckHTTPSpec{Path: "/readyz", TLS: true},
+				},
+				ExtraServices: []LoadBalancerServiceSpec{
+					{Protocol: "https", ListenPort: 443, DestinationPort: 80, HTTP: &LoadBalancerServiceHTTPSpec{CertificateIDs: []int64{1}, RedirectHTTP: true}},
+				},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateLoadBalancerServices(tt.lb)
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
+
+func TestValidateAdditionalLoadBalancers(t *testing.T) {
+	lbsPath := field.NewPath("spec", "additionalLoadBalancers")
+
+	tests := []struct {
+		name string
+		lbs  []AdditionalLoadBalancerSpec
+		want *field.Error
+	}{
+		{
+			name: "Duplicate name",
+			lbs: []AdditionalLoadBalancerSpec{
+				{Name: "ingress", Region: "fsn1"},
+				{Name: "ingress", Region: "nbg1"},
+			},
+			want: field.Duplicate(lbsPath.Index(1).Child("name"), "ingress"),
+		},
+		{
+			name: "Duplicate listen port",
+			lbs: []AdditionalLoadBalancerSpec{
+				{Name: "ingress", Region: "fsn1", Services: []LoadBalancerServiceSpec{
+					{Protocol: "tcp", ListenPort: 80, DestinationPort: 30080},
+					{Protocol: "tcp", ListenPort: 80, DestinationPort: 30081},
+				}},
+			},
+			want: field.Duplicate(lbsPath.Index(0).Child("services").Index(1).Child("listenPort"), 80),
+		},
+		{
+			name: "Invalid service",
+			lbs: []AdditionalLoadBalancerSpec{
+				{Name: "ingress", Region: "fsn1", Services: []LoadBalancerServiceSpec{
+					{Protocol: "https", ListenPort: 443, DestinationPort: 30080},
+				}},
+			},
+			want: field.Required(lbsPath.Index(0).Child("services").Index(0).Child("http", "certificateIDs"), "certificates are required for protocol https"),
+		},
+		{
+			name: "No Errors",
+			lbs: []AdditionalLoadBalancerSpec{
+				{Name: "ingress", Region: "fsn1", Services: []LoadBalancerServiceSpec{
+					{Protocol: "tcp", ListenPort: 80, DestinationPort: 30080},
+					{Protocol: "tcp", ListenPort: 443, DestinationPort: 30443},
+				}},
+				{Name: "internal", Region: "nbg1"},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateAdditionalLoadBalancers(tt.lbs)
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
+
+func TestValidateAdditionalLoadBalancersUpdate(t *testing.T) {
+	oldLBs := []AdditionalLoadBalancerSpec{{Name: "ingress", Region: "fsn1"}}
+
+	t.Run("Region changed", func(t *testing.T) {
+		got := validateAdditionalLoadBalancersUpdate(oldLBs, []AdditionalLoadBalancerSpec{
+			{Name: "internal", Region: "fsn1"},
+			{Name: "ingress", Region: "nbg1"},
+		})
+		assert.Len(t, got, 1)
+		assert.Equal(t, field.Invalid(field.NewPath("spec", "additionalLoadBalancers").Index(1).Child("region"), Region("nbg1"), "field is immutable"), got[0])
+	})
+
+	t.Run("Load balancer replaced", func(t *testing.T) {
+		got := validateAdditionalLoadBalancersUpdate(oldLBs, []AdditionalLoadBalancerSpec{{Name: "other", Region: "nbg1"}})
+		assert.Empty(t, got)
+	})
+}
+
+func TestValidateHCloudPlacementGroups(t *testing.T) {
+	pgsPath := field.NewPath("spec", "hcloudPlacementGroups")
+
+	tests := []struct {
+		name string
+		pgs  []HCloudPlacementGroupSpec
+		want *field.Error
+	}{
+		{
+			name: "Duplicate name",
+			pgs:  []HCloudPlacementGroupSpec{{Name: "workers"}, {Name: "workers", Sharded: true}},
+			want: field.Duplicate(pgsPath.Index(1).Child("name"), "workers"),
+		},
+		{
+			name: "Name of a shard",
+			pgs:  []HCloudPlacementGroupSpec{{Name: "workers", Sharded: true}, {Name: "workers-2"}},
+			want: field.Invalid(pgsPath.Index(1).Child("name"), "workers-2", "name collides with the shards of placement group workers"),
+		},
+		{
+			name: "No Errors",
+			pgs:  []HCloudPlacementGroupSpec{{Name: "workers", Sharded: true}, {Name: "workers-big"}, {Name: "control-plane"}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateHCloudPlacementGroups(tt.pgs)
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
+
+func TestValidateControlPlaneDNS(t *testing.T) {
+	dnsPath := field.NewPath("spec", "controlPlaneDNS")
+
+	tests := []struct {
+		name    string
+		cluster HetznerClusterSpec
+		want    *field.Error
+	}{
+		{
+			name: "Without endpoint IP",
+			cluster: HetznerClusterSpec{
+				ControlPlaneDNS: &ControlPlaneDNSSpec{Zone: "example.com", Name: "api"},
+			},
+			want: field.Invalid(dnsPath, "api", "requires the control plane load balancer or a control plane IP"),
+		},
+		{
+			name: "Fully qualified name",
+			cluster: HetznerClusterSpec{
+				ControlPlaneLoadBalancer: LoadBalancerSpec{Enabled: true},
+				ControlPlaneDNS:          &ControlPlaneDNSSpec{Zone: "example.com", Name: "api.example.com."},
+			},
+			want: field.Invalid(dnsPath.Child("name"), "api.example.com.", "name must be relative to the zone, use @ for the apex of the zone"),
+		},
+		{
+			name: "No Errors with load balancer",
+			cluster: HetznerClusterSpec{
+				ControlPlaneLoadBalancer: LoadBalancerSpec{Enabled: true},
+				ControlPlaneDNS:          &ControlPlaneDNSSpec{Zone: "example.com", Name: "api.example"},
+			},
+			want: nil,
+		},
+		{
+			name: "No Errors with control plane IP",
+			cluster: HetznerClusterSpec{
+				ControlPlaneEndpointType: ControlPlaneEndpointTypeFloatingIP,
+				ControlPlaneDNS:          &ControlPlaneDNSSpec{Zone: "example.com", Name: "@"},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateControlPlaneDNS(&HetznerCluster{Spec: tt.cluster})
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
+
+func TestValidateBootstrapDataStorage(t *testing.T) {
+	expiryPath := field.NewPath("spec", "bootstrapDataStorage", "urlExpiry")
+
+	tests := []struct {
+		name   string
+		expiry time.Duration
+		want   *field.Error
+	}{
+		{
+			name:   "Negative expiry",
+			expiry: -time.Minute,
+			want:   field.Invalid(expiryPath, "-1m0s", "must be between 0s and 168h0m0s"),
+		},
+		{
+			name:   "Expiry longer than a week",
+			expiry: 8 * 24 * time.Hour,
+			want:   field.Invalid(expiryPath, "192h0m0s", "must be between 0s and 168h0m0s"),
+		},
+		{
+			name:   "No Errors",
+			expiry: 15 * time.Minute,
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateBootstrapDataStorage(&BootstrapDataStorageSpec{
+				Endpoint:  "https://fsn1.your-objectstorage.com",
+				Region:    "fsn1",
+				Bucket:    "bootstrap",
+				URLExpiry: metav1.Duration{Duration: tt.expiry},
+			})
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
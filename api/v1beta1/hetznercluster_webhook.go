@@ -111,9 +111,27 @@ func (r *HetznerCluster) ValidateCreate() (admission.Warnings, error) {
 		}
 	}
 
+	// With explicit subnets, every control plane region needs a subnet in its network zone
+	if r.Spec.HCloudNetwork.Enabled && len(r.Spec.HCloudNetwork.Subnets) > 0 {
+		if err := hasCloudSubnetForAllRegions(r.Spec.ControlPlaneRegions, r.Spec.HCloudNetwork.Subnets); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	allErrs = append(allErrs, validateHCloudNetwork(r.Spec.HCloudNetwork)...)
+
+	// A Floating IP replaces the load balancer
+	if r.UsesControlPlaneIP() && r.Spec.ControlPlaneLoadBalancer.Enabled {
+		allErrs = append(allErrs, field.Invalid(
+			field.NewPath("spec", "controlPlaneLoadBalancer", "enabled"),
+			r.Spec.ControlPlaneLoadBalancer.Enabled,
+			fmt.Sprintf("load balancer must be disabled if controlPlaneEndpointType is %s", r.Spec.ControlPlaneEndpointType),
+		))
+	}
+
 	// Check whether controlPlaneEndpoint is specified if allow empty is not set or false
 
-	if !allowEmptyControlPlaneAddress && !r.Spec.ControlPlaneLoadBalancer.Enabled {
+	if !allowEmptyControlPlaneAddress && !r.Spec.ControlPlaneLoadBalancer.Enabled && !r.UsesControlPlaneIP() {
 		if r.Spec.ControlPlaneEndpoint == nil ||
 			r.Spec.ControlPlaneEndpoint.Host == "" ||
 			r.Spec.ControlPlaneEndpoint.Port == 0 {
@@ -131,6 +149,13 @@ func (r *HetznerCluster) ValidateCreate() (admission.Warnings, error) {
 		allErrs = append(allErrs, err)
 	}
 
+	allErrs = append(allErrs, validateHCloudFirewalls(r.Spec.HCloudFirewalls)...)
+	allErrs = append(allErrs, validateLoadBalancerServices(r.Spec.ControlPlaneLoadBalancer)...)
+	allErrs = append(allErrs, validateAdditionalLoadBalancers(r.Spec.AdditionalLoadBalancers)...)
+	allErrs = append(allErrs, validateHCloudPlacementGroups(r.Spec.HCloudPlacementGroups)...)
+	allErrs = append(allErrs, validateControlPlaneDNS(r)...)
+	allErrs = append(allErrs, validateBootstrapDataStorage(r.Spec.BootstrapDataStorage)...)
+
 	return nil, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
@@ -161,15 +186,17 @@ func (r *HetznerCluster) ValidateUpdate(old runtime.Object) (admission.Warnings,
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an HetznerCluster but got a %T", old))
 	}
 
-	// Network settings are immutable
-	if !reflect.DeepEqual(oldC.Spec.HCloudNetwork, r.Spec.HCloudNetwork) {
-		allErrs = append(allErrs,
-			field.Invalid(field.NewPath("spec", "hcloudNetwork"), r.Spec.HCloudNetwork, "field is immutable"),
-		)
-	}
+	// Network settings are immutable, only subnets and routes can be added
+	allErrs = append(allErrs, validateHCloudNetworkUpdate(oldC.Spec.HCloudNetwork, r.Spec.HCloudNetwork)...)
+	allErrs = append(allErrs, validateHCloudNetwork(r.Spec.HCloudNetwork)...)
 
-	// Check if all regions are in the same network zone if a private network is enabled
-	if oldC.Spec.HCloudNetwork.Enabled {
+	// Check if all regions are in the same network zone if a private network is enabled.
+	// With explicit subnets, every control plane region needs a subnet in its network zone instead.
+	if oldC.Spec.HCloudNetwork.Enabled && len(r.Spec.HCloudNetwork.Subnets) > 0 {
+		if err := hasCloudSubnetForAllRegions(r.Spec.ControlPlaneRegions, r.Spec.HCloudNetwork.Subnets); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	} else if oldC.Spec.HCloudNetwork.Enabled {
 		var defaultNetworkZone *string
 		if len(oldC.Spec.ControlPlaneRegions) > 0 {
 			str := regionNetworkZoneMap[string(oldC.Spec.ControlPlaneRegions[0])]
@@ -188,15 +215,25 @@ func (r *HetznerCluster) ValidateUpdate(old runtime.Object) (admission.Warnings,
 		)
 	}
 
-	// Load balancer region and port are immutable
+	// Control plane endpoint type is immutable
+	if !reflect.DeepEqual(oldC.Spec.ControlPlaneEndpointType, r.Spec.ControlPlaneEndpointType) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "controlPlaneEndpointType"), r.Spec.ControlPlaneEndpointType, "field is immutable"),
+		)
+	}
+
+	// Load balancer port is immutable
 	if !reflect.DeepEqual(oldC.Spec.ControlPlaneLoadBalancer.Port, r.Spec.ControlPlaneLoadBalancer.Port) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "controlPlaneLoadBalancer", "port"), r.Spec.ControlPlaneLoadBalancer.Port, "field is immutable"),
 		)
 	}
-	if !reflect.DeepEqual(oldC.Spec.ControlPlaneLoadBalancer.Region, r.Spec.ControlPlaneLoadBalancer.Region) {
+
+	// Load balancer region can only be changed if the load balancer is created by the controller, which migrates it
+	if !reflect.DeepEqual(oldC.Spec.ControlPlaneLoadBalancer.Region, r.Spec.ControlPlaneLoadBalancer.Region) &&
+		r.Spec.ControlPlaneLoadBalancer.Name != nil {
 		allErrs = append(allErrs,
-			field.Invalid(field.NewPath("spec", "controlPlaneLoadBalancer", "region"), r.Spec.ControlPlaneLoadBalancer.Region, "field is immutable"),
+			field.Invalid(field.NewPath("spec", "controlPlaneLoadBalancer", "region"), r.Spec.ControlPlaneLoadBalancer.Region, "field is immutable if name is set"),
 		)
 	}
 
@@ -204,6 +241,14 @@ func (r *HetznerCluster) ValidateUpdate(old runtime.Object) (admission.Warnings,
 		allErrs = append(allErrs, err)
 	}
 
+	allErrs = append(allErrs, validateHCloudFirewalls(r.Spec.HCloudFirewalls)...)
+	allErrs = append(allErrs, validateLoadBalancerServices(r.Spec.ControlPlaneLoadBalancer)...)
+	allErrs = append(allErrs, validateAdditionalLoadBalancers(r.Spec.AdditionalLoadBalancers)...)
+	allErrs = append(allErrs, validateHCloudPlacementGroups(r.Spec.HCloudPlacementGroups)...)
+	allErrs = append(allErrs, validateControlPlaneDNS(r)...)
+	allErrs = append(allErrs, validateBootstrapDataStorage(r.Spec.BootstrapDataStorage)...)
+	allErrs = append(allErrs, validateAdditionalLoadBalancersUpdate(oldC.Spec.AdditionalLoadBalancers, r.Spec.AdditionalLoadBalancers)...)
+
 	return nil, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
@@ -16,7 +16,18 @@ limitations under the License.
 
 package v1beta1
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemediationSkippedAnnotation is set on a remediation whose action has been skipped because the health probe found
+// the machine reachable from the provider side. The value describes the result of the probe.
+const RemediationSkippedAnnotation = "capi.syself.com/remediation-skipped"
+
+// DefaultHealthProbeTimeout is the default timeout of a TCP connection of a health probe.
+const DefaultHealthProbeTimeout = 5 * time.Second
 
 // RemediationType defines the type of remediation.
 type RemediationType string
@@ -24,6 +35,51 @@ type RemediationType string
 const (
 	// RemediationTypeReboot sets RemediationType to Reboot.
 	RemediationTypeReboot RemediationType = "Reboot"
+
+	// RemediationTypeEscalate sets RemediationType to Escalate. The steps of the strategy are executed one after
+	// another until the machine is healthy again or all steps failed.
+	RemediationTypeEscalate RemediationType = "Escalate"
+
+	// RemediationTypeReplace sets RemediationType to Replace. The machine is handed back to Cluster API right away,
+	// which replaces it.
+	RemediationTypeReplace RemediationType = "Replace"
+)
+
+// RemediationStepType defines the type of a step of an escalating remediation.
+// +kubebuilder:validation:Enum=Reboot;PowerCycle;SoftwareReset;HardwareReset;Rebuild
+type RemediationStepType string
+
+const (
+	// RemediationStepTypeReboot reboots the operating system. Bare metal servers are rebooted via SSH.
+	RemediationStepTypeReboot RemediationStepType = "Reboot"
+
+	// RemediationStepTypePowerCycle shuts the machine down and powers it on again. Only supported for HCloud.
+	RemediationStepTypePowerCycle RemediationStepType = "PowerCycle"
+
+	// RemediationStepTypeSoftwareReset sends CTRL+ALT+DEL to the server via the Robot API. Only supported for bare metal.
+	RemediationStepTypeSoftwareReset RemediationStepType = "SoftwareReset"
+
+	// RemediationStepTypeHardwareReset executes an automatic hardware reset via the Robot API. Only supported for bare metal.
+	RemediationStepTypeHardwareReset RemediationStepType = "HardwareReset"
+
+	// RemediationStepTypeRebuild installs the image of the machine again. Bare metal hosts are provisioned again
+	// with the same InstallImage.
+	RemediationStepTypeRebuild RemediationStepType = "Rebuild"
+)
+
+var (
+	// DefaultHCloudRemediationSteps are the steps of an escalating remediation of HCloud machines without steps.
+	DefaultHCloudRemediationSteps = []RemediationStepType{
+		RemediationStepTypeReboot,
+		RemediationStepTypePowerCycle,
+	}
+
+	// DefaultBareMetalRemediationSteps are the steps of an escalating remediation of bare metal machines without steps.
+	DefaultBareMetalRemediationSteps = []RemediationStepType{
+		RemediationStepTypeReboot,
+		RemediationStepTypeSoftwareReset,
+		RemediationStepTypeHardwareReset,
+	}
 )
 
 const (
@@ -39,7 +95,8 @@ const (
 
 // RemediationStrategy describes how to remediate machines.
 type RemediationStrategy struct {
-	// Type represents the type of the remediation strategy. At the moment, only "Reboot" is supported.
+	// Type represents the type of the remediation strategy. "Reboot" reboots the machine up to retryLimit times,
+	// "Escalate" executes the steps one after another and "Replace" hands the machine back to Cluster API right away.
 	// +kubebuilder:default=Reboot
 	// +optional
 	Type RemediationType `json:"type,omitempty"`
@@ -50,4 +107,103 @@ type RemediationStrategy struct {
 
 	// Timeout sets the timeout between remediation retries. It should be of the form "10m", or "40s".
 	Timeout *metav1.Duration `json:"timeout"`
+
+	// Steps are the steps of an escalating remediation. They are only supported for the type "Escalate". If no steps
+	// are given, HCloud machines are rebooted and power cycled once each, and bare metal machines are rebooted, reset
+	// by software and reset by hardware once each. If the machine is still unhealthy after the last step, it is handed
+	// back to Cluster API for replacement.
+	// +optional
+	Steps []RemediationStep `json:"steps,omitempty"`
+
+	// HealthProbe verifies that the machine is unreachable from the provider side before any action of the
+	// remediation. If the server is powered on and all TCP ports of the probe are reachable, the action is skipped
+	// and the remediation is annotated. This protects healthy machines during network partitions between the
+	// management and the workload cluster.
+	// +optional
+	HealthProbe *RemediationHealthProbe `json:"healthProbe,omitempty"`
+}
+
+// RemediationHealthProbe defines how a machine is probed before it is remediated.
+type RemediationHealthProbe struct {
+	// TCPPorts are the ports of the server that are probed, e.g. 10250 for the kubelet and 22 for SSH. At least one
+	// port is required, as a powered on server alone does not show that the node works.
+	// +kubebuilder:validation:MinItems=1
+	TCPPorts []int `json:"tcpPorts"`
+
+	// Timeout is the timeout of each TCP connection. Defaults to 5s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ConnectTimeout returns the timeout of each TCP connection of the probe.
+func (p *RemediationHealthProbe) ConnectTimeout() time.Duration {
+	if p.Timeout != nil && p.Timeout.Duration > 0 {
+		return p.Timeout.Duration
+	}
+	return DefaultHealthProbeTimeout
+}
+
+// EscalationSteps returns the steps of an escalating remediation or the given default steps.
+func (s *RemediationStrategy) EscalationSteps(defaultSteps []RemediationStepType) []RemediationStep {
+	if len(s.Steps) > 0 {
+		return s.Steps
+	}
+	steps := make([]RemediationStep, 0, len(defaultSteps))
+	for _, stepType := range defaultSteps {
+		steps = append(steps, RemediationStep{Type: stepType})
+	}
+	return steps
+}
+
+// StepTimeout returns the timeout of the step or the timeout of the strategy.
+func (s *RemediationStrategy) StepTimeout(step RemediationStep) time.Duration {
+	if step.Timeout != nil {
+		return step.Timeout.Duration
+	}
+	if s.Timeout != nil {
+		return s.Timeout.Duration
+	}
+	return 0
+}
+
+// RemediationStep describes a step of an escalating remediation.
+type RemediationStep struct {
+	// Type is the action of the step.
+	Type RemediationStepType `json:"type"`
+
+	// RetryLimit is the number of times the step is executed before the remediation escalates to the next step.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RetryLimit int `json:"retryLimit,omitempty"`
+
+	// Timeout is the time the machine gets to become healthy after the step before the step is retried or the next
+	// step is executed. Defaults to the timeout of the strategy.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// Attempts returns how often the step is executed.
+func (s RemediationStep) Attempts() int {
+	if s.RetryLimit < 1 {
+		return 1
+	}
+	return s.RetryLimit
+}
+
+// RemediationStepStatus records a step of an escalating remediation that has been executed.
+type RemediationStepStatus struct {
+	// Type is the action of the step.
+	Type RemediationStepType `json:"type"`
+
+	// Attempt is the number of the execution of the step, starting with 1.
+	Attempt int `json:"attempt"`
+
+	// Started is the time when the step has been executed.
+	Started metav1.Time `json:"started"`
+
+	// Finished is the time when the step has been completed, e.g. when the machine has been powered on again after
+	// a power cycle.
+	// +optional
+	Finished *metav1.Time `json:"finished,omitempty"`
 }
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var supportedRemediationTypes = []string{
+	string(RemediationTypeReboot),
+	string(RemediationTypeEscalate),
+	string(RemediationTypeReplace),
+}
+
+// hcloudRemediationStepTypes are the steps of escalating remediations that are supported for HCloud machines.
+var hcloudRemediationStepTypes = []RemediationStepType{
+	RemediationStepTypeReboot,
+	RemediationStepTypePowerCycle,
+	RemediationStepTypeRebuild,
+}
+
+// bareMetalRemediationStepTypes are the steps of escalating remediations that are supported for bare metal machines.
+var bareMetalRemediationStepTypes = []RemediationStepType{
+	RemediationStepTypeReboot,
+	RemediationStepTypeSoftwareReset,
+	RemediationStepTypeHardwareReset,
+	RemediationStepTypeRebuild,
+}
+
+func validateRemediationStrategy(fldPath *field.Path, strategy *RemediationStrategy, supportedSteps []RemediationStepType) field.ErrorList {
+	if strategy == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	switch strategy.Type {
+	case "", RemediationTypeReboot, RemediationTypeEscalate, RemediationTypeReplace:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), strategy.Type, supportedRemediationTypes))
+	}
+
+	if strategy.Timeout != nil && strategy.Timeout.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), strategy.Timeout.Duration.String(), "must not be negative"))
+	}
+
+	if len(strategy.Steps) > 0 && strategy.Type != RemediationTypeEscalate {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("steps"), "steps are only supported for the type Escalate"))
+	}
+
+	for i, step := range strategy.Steps {
+		stepPath := fldPath.Child("steps").Index(i)
+		if !slices.Contains(supportedSteps, step.Type) {
+			supported := make([]string, 0, len(supportedSteps))
+			for _, stepType := range supportedSteps {
+				supported = append(supported, string(stepType))
+			}
+			allErrs = append(allErrs, field.NotSupported(stepPath.Child("type"), step.Type, supported))
+		}
+		if step.Timeout != nil && step.Timeout.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(stepPath.Child("timeout"), step.Timeout.Duration.String(), "must not be negative"))
+		}
+		if step.RetryLimit < 0 {
+			allErrs = append(allErrs, field.Invalid(stepPath.Child("retryLimit"), step.RetryLimit, "must not be negative"))
+		}
+	}
+
+	if probe := strategy.HealthProbe; probe != nil {
+		probePath := fldPath.Child("healthProbe")
+		if len(probe.TCPPorts) == 0 {
+			allErrs = append(allErrs, field.Required(probePath.Child("tcpPorts"), "at least one port has to be probed"))
+		}
+		for i, port := range probe.TCPPorts {
+			if port < 1 || port > 65535 {
+				allErrs = append(allErrs, field.Invalid(probePath.Child("tcpPorts").Index(i), port, "must be between 1 and 65535"))
+			}
+		}
+		if probe.Timeout != nil && probe.Timeout.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(probePath.Child("timeout"), probe.Timeout.Duration.String(), "must not be negative"))
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateRemediationStrategy(t *testing.T) {
+	strategyPath := field.NewPath("spec", "strategy")
+	timeout := &metav1.Duration{Duration: 5 * time.Minute}
+	tests := []struct {
+		name     string
+		strategy *RemediationStrategy
+		want     *field.Error
+	}{
+		{
+			name:     "No strategy",
+			strategy: nil,
+			want:     nil,
+		},
+		{
+			name:     "Unsupported type",
+			strategy: &RemediationStrategy{Type: "Unknown", Timeout: timeout},
+			want:     field.NotSupported(strategyPath.Child("type"), RemediationType("Unknown"), supportedRemediationTypes),
+		},
+		{
+			name:     "Steps without escalation",
+			strategy: &RemediationStrategy{Type: RemediationTypeReboot, Timeout: timeout, Steps: []RemediationStep{{Type: RemediationStepTypeReboot}}},
+			want:     field.Forbidden(strategyPath.Child("steps"), "steps are only supported for the type Escalate"),
+		},
+		{
+			name: "Unsupported step",
+			strategy: &RemediationStrategy{Type: RemediationTypeEscalate, Timeout: timeout, Steps: []RemediationStep{
+				{Type: RemediationStepTypeHardwareReset},
+			}},
+			want: field.NotSupported(strategyPath.Child("steps").Index(0).Child("type"), RemediationStepTypeHardwareReset, []string{"Reboot", "PowerCycle", "Rebuild"}),
+		},
+		{
+			name: "Negative step timeout",
+			strategy: &RemediationStrategy{Type: RemediationTypeEscalate, Timeout: timeout, Steps: []RemediationStep{
+				{Type: RemediationStepTypeReboot},
+				{Type: RemediationStepTypePowerCycle, Timeout: &metav1.Duration{Duration: -time.Minute}},
+			}},
+			want: field.Invalid(strategyPath.Child("steps").Index(1).Child("timeout"), "-1m0s", "must not be negative"),
+		},
+		{
+			name: "Negative retry limit",
+			strategy: &RemediationStrategy{Type: RemediationTypeEscalate, Timeout: timeout, Steps: []RemediationStep{
+				{Type: RemediationStepTypeRebuild, RetryLimit: -1},
+			}},
+			want: field.Invalid(strategyPath.Child("steps").Index(0).Child("retryLimit"), -1, "must not be negative"),
+		},
+		{
+			name: "Invalid health probe port",
+			strategy: &RemediationStrategy{Type: RemediationTypeReboot, Timeout: timeout, HealthProbe: &RemediationHealthProbe{
+				TCPPorts: []int{10250, 0},
+			}},
+			want: field.Invalid(strategyPath.Child("healthProbe", "tcpPorts").Index(1), 0, "must be between 1 and 65535"),
+		},
+		{
+			name:     "Health probe without ports",
+			strategy: &RemediationStrategy{Type: RemediationTypeReboot, Timeout: timeout, HealthProbe: &RemediationHealthProbe{}},
+			want:     field.Required(strategyPath.Child("healthProbe", "tcpPorts"), "at least one port has to be probed"),
+		},
+		{
+			name: "Negative health probe timeout",
+			strategy: &RemediationStrategy{Type: RemediationTypeReboot, Timeout: timeout, HealthProbe: &RemediationHealthProbe{
+				TCPPorts: []int{22},
+				Timeout:  &metav1.Duration{Duration: -time.Second},
+			}},
+			want: field.Invalid(strategyPath.Child("healthProbe", "timeout"), "-1s", "must not be negative"),
+		},
+		{
+			name: "No Errors",
+			strategy: &RemediationStrategy{Type: RemediationTypeEscalate, Timeout: timeout, Steps: []RemediationStep{
+				{Type: RemediationStepTypeReboot, RetryLimit: 2},
+				{Type: RemediationStepTypePowerCycle, Timeout: &metav1.Duration{Duration: 10 * time.Minute}},
+				{Type: RemediationStepTypeRebuild},
+			}},
+			want: nil,
+		},
+		{
+			name:     "Replace",
+			strategy: &RemediationStrategy{Type: RemediationTypeReplace, Timeout: timeout},
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateRemediationStrategy(strategyPath, tt.strategy, hcloudRemediationStepTypes)
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			assert.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
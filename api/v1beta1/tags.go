@@ -49,6 +49,41 @@ const (
 
 	// MachineNameTagKey tags related MachineNameTag.
 	MachineNameTagKey = "machine." + NameHetznerProviderPrefix + "name"
+
+	// LoadBalancerNameTagKey is the tag that contains the name of an additional load balancer in the spec.
+	LoadBalancerNameTagKey = NameHetznerProviderPrefix + "load-balancer-name"
+
+	// PlacementGroupShardTagKey is the tag that contains the index of a shard of a sharded placement group.
+	PlacementGroupShardTagKey = NameHetznerProviderPrefix + "placement-group-shard"
+
+	// VolumeNameTagKey is the tag that contains the name of a volume in the spec of its HCloudMachine.
+	VolumeNameTagKey = NameHetznerProviderPrefix + "volume-name"
+
+	// VolumeReclaimPolicyTagKey is the tag that contains the reclaim policy of a volume at the time of its creation.
+	VolumeReclaimPolicyTagKey = NameHetznerProviderPrefix + "volume-reclaim-policy"
+
+	// NATGatewayTagKey is the tag that marks the server of the NAT gateway of a cluster.
+	NATGatewayTagKey = NameHetznerProviderPrefix + "nat-gateway"
+
+	// LoadBalancerMigrationTagKey is the tag that marks the load balancers of an ongoing migration of the
+	// control plane load balancer to another region.
+	LoadBalancerMigrationTagKey = NameHetznerProviderPrefix + "load-balancer-migration"
+
+	// LoadBalancerMigrationTarget is the value of LoadBalancerMigrationTagKey for the new load balancer.
+	LoadBalancerMigrationTarget = "target"
+
+	// LoadBalancerMigrationDraining is the value of LoadBalancerMigrationTagKey for the old load balancer
+	// after the new one has taken over.
+	LoadBalancerMigrationDraining = "draining"
+
+	// MachineTypeTagKey is the tag that differentiates control plane and worker servers.
+	MachineTypeTagKey = "machine_type"
+
+	// MachineTypeControlPlane is the value of MachineTypeTagKey for control plane servers.
+	MachineTypeControlPlane = "control_plane"
+
+	// MachineTypeWorker is the value of MachineTypeTagKey for worker servers.
+	MachineTypeWorker = "worker"
 )
 
 // ClusterHetznerCloudProviderTagKey generates the key for resources associated a cluster's HCloud cloud provider.
@@ -18,6 +18,8 @@ package v1beta1
 
 import (
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // LoadBalancerAlgorithmType defines the Algorithm type.
@@ -33,8 +35,21 @@ const (
 	LoadBalancerAlgorithmTypeLeastConnections = LoadBalancerAlgorithmType("least_connections")
 )
 
+// ControlPlaneEndpointType defines how the control plane endpoint of the cluster is provided.
+// +kubebuilder:validation:Enum=LoadBalancer;FloatingIP
+type ControlPlaneEndpointType string
+
+const (
+
+	// ControlPlaneEndpointTypeLoadBalancer uses the control plane load balancer or a user-provided endpoint.
+	ControlPlaneEndpointTypeLoadBalancer = ControlPlaneEndpointType("LoadBalancer")
+
+	// ControlPlaneEndpointTypeFloatingIP uses an HCloud Floating IP that is assigned to a healthy control plane server.
+	ControlPlaneEndpointTypeFloatingIP = ControlPlaneEndpointType("FloatingIP")
+)
+
 // LoadBalancerTargetType defines the target type.
-// +kubebuilder:validation:Enum=server;ip
+// +kubebuilder:validation:Enum=server;ip;label_selector
 type LoadBalancerTargetType string
 
 const (
@@ -44,6 +59,9 @@ const (
 
 	// LoadBalancerTargetTypeIP default for load balancer.
 	LoadBalancerTargetTypeIP = LoadBalancerTargetType("ip")
+
+	// LoadBalancerTargetTypeLabelSelector targets all servers that match a label selector.
+	LoadBalancerTargetTypeLabelSelector = LoadBalancerTargetType("label_selector")
 )
 
 // HCloudAlgorithmType converts LoadBalancerAlgorithmType to hcloud type.
@@ -64,6 +82,10 @@ type HetznerSSHKeys struct {
 	HCloud []SSHKey `json:"hcloud,omitempty"`
 	// RobotRescueSecretRef defines the reference to the secret where the SSH key for the rescue system is stored.
 	RobotRescueSecretRef SSHSecretRef `json:"robotRescueSecretRef,omitempty"`
+	// HCloudRescueSecretRef defines the reference to the secret with the private SSH key that is used to log into the
+	// rescue system of HCloud servers to collect diagnostics. The key has to belong to one of the HCloud SSH keys.
+	// +optional
+	HCloudRescueSecretRef *SSHSecretRef `json:"hcloudRescueSecretRef,omitempty"`
 }
 
 // SSHKey defines the SSHKey for HCloud.
@@ -82,6 +104,9 @@ type HCloudMachineType string
 // ResourceLifecycle configures the lifecycle of a resource.
 type ResourceLifecycle string
 
+// MaxServersPerPlacementGroup is the maximum number of servers in a spread placement group of HCloud.
+const MaxServersPerPlacementGroup = 10
+
 // HCloudPlacementGroupSpec defines a PlacementGroup.
 type HCloudPlacementGroupSpec struct {
 	// +kubebuilder:validation:MinLength=1
@@ -89,6 +114,12 @@ type HCloudPlacementGroupSpec struct {
 	// +kubebuilder:validation:Enum=spread
 	// +kubebuilder:default=spread
 	Type string `json:"type,omitempty"`
+
+	// Sharded makes the placement group a template for placement groups named <name>-N. As a spread
+	// placement group holds at most 10 servers, a new shard is created when all shards are full.
+	// Machines that reference the name are assigned to the least-filled shard, and empty shards are deleted.
+	// +optional
+	Sharded bool `json:"sharded,omitempty"`
 }
 
 // HCloudPlacementGroupStatus returns the status of a Placementgroup.
@@ -97,6 +128,67 @@ type HCloudPlacementGroupStatus struct {
 	Server []int64 `json:"servers,omitempty"`
 	Name   string  `json:"name,omitempty"`
 	Type   string  `json:"type,omitempty"`
+
+	// ShardOf is the name of the sharded placement group that this placement group is a shard of.
+	// +optional
+	ShardOf string `json:"shardOf,omitempty"`
+}
+
+// IsFull returns whether no more servers can be added to the placement group.
+func (pg HCloudPlacementGroupStatus) IsFull() bool {
+	return len(pg.Server) >= MaxServersPerPlacementGroup
+}
+
+// HCloudFirewallSpec defines an HCloud firewall that is applied to servers of the cluster.
+type HCloudFirewallSpec struct {
+	// Name defines the name of the firewall. In HCloud, the name of the HetznerCluster is used as prefix.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Rules defines the rules of the firewall.
+	// +optional
+	Rules []HCloudFirewallRuleSpec `json:"rules,omitempty"`
+
+	// LabelSelector restricts the servers of the cluster that the firewall is applied to. Only servers that
+	// carry all of the given labels are selected, e.g. "machine_type: control_plane". If empty, the firewall
+	// is applied to all servers of the cluster.
+	// +optional
+	LabelSelector map[string]string `json:"labelSelector,omitempty"`
+}
+
+// HCloudFirewallRuleSpec defines a rule of an HCloud firewall.
+type HCloudFirewallRuleSpec struct {
+	// Direction defines whether the rule applies to incoming or outgoing traffic.
+	// +kubebuilder:validation:Enum=in;out
+	Direction string `json:"direction"`
+
+	// Protocol defines the protocol of the rule. It could be one of tcp, udp, icmp, esp, or gre.
+	// +kubebuilder:validation:Enum=tcp;udp;icmp;esp;gre
+	Protocol string `json:"protocol"`
+
+	// Port defines a port or a port range, e.g. "80" or "30000-32767". It is required for tcp and udp.
+	// +optional
+	Port *string `json:"port,omitempty"`
+
+	// SourceIPs defines the CIDR blocks from which incoming traffic is allowed.
+	// +optional
+	SourceIPs []string `json:"sourceIPs,omitempty"`
+
+	// DestinationIPs defines the CIDR blocks to which outgoing traffic is allowed.
+	// +optional
+	DestinationIPs []string `json:"destinationIPs,omitempty"`
+
+	// Description defines an optional description of the rule.
+	// +optional
+	Description *string `json:"description,omitempty"`
+}
+
+// HCloudFirewallStatus returns the status of a firewall.
+type HCloudFirewallStatus struct {
+	ID   int64  `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	// AppliedTo contains the HCloud label selectors the firewall is applied to.
+	AppliedTo []string `json:"appliedTo,omitempty"`
 }
 
 // HetznerSecretRef defines all the names of the secret and the relevant keys needed to access Hetzner API.
@@ -128,6 +220,21 @@ type HetznerSecretKeyRef struct {
 	// +optional
 	// +kubebuilder:default=hcloud-ssh-key-name
 	SSHKey string `json:"sshKey"`
+	// HetznerDNSToken defines the name of the key where the token for the Hetzner DNS API is stored.
+	// It is only needed if the DNS records of the control plane endpoint are managed.
+	// +optional
+	// +kubebuilder:default=hetzner-dns-token
+	HetznerDNSToken string `json:"hetznerDNSToken"`
+	// ObjectStorageAccessKey defines the name of the key where the access key of the object storage is stored.
+	// It is only needed if a bootstrap data storage is configured.
+	// +optional
+	// +kubebuilder:default=object-storage-access-key
+	ObjectStorageAccessKey string `json:"objectStorageAccessKey"`
+	// ObjectStorageSecretKey defines the name of the key where the secret key of the object storage is stored.
+	// It is only needed if a bootstrap data storage is configured.
+	// +optional
+	// +kubebuilder:default=object-storage-secret-key
+	ObjectStorageSecretKey string `json:"objectStorageSecretKey"`
 }
 
 // PublicNetworkSpec contains specs about the public network spec of an HCloud server.
@@ -143,6 +250,86 @@ type PublicNetworkSpec struct {
 	EnableIPv6 bool `json:"enableIPv6"`
 }
 
+// ImageSelectionPolicy defines which image is used if several images match a label selector.
+type ImageSelectionPolicy string
+
+const (
+	// ImageSelectionPolicyNewest uses the image that has been created last.
+	ImageSelectionPolicyNewest ImageSelectionPolicy = "Newest"
+)
+
+// HCloudImageSpec references an HCloud image by its ID or by a label selector.
+type HCloudImageSpec struct {
+	// ID is the ID of the image. It cannot be combined with LabelSelector.
+	// +optional
+	ID *int64 `json:"id,omitempty"`
+
+	// LabelSelector selects the image by its labels. Only images with the architecture of the server type are considered.
+	// It cannot be combined with ID.
+	// +optional
+	LabelSelector map[string]string `json:"labelSelector,omitempty"`
+
+	// SelectionPolicy defines which image is used if several images match the label selector. If it is not set,
+	// exactly one image has to match.
+	// +optional
+	// +kubebuilder:validation:Enum=Newest
+	SelectionPolicy ImageSelectionPolicy `json:"selectionPolicy,omitempty"`
+}
+
+// VolumeReclaimPolicy defines what happens to a volume when its machine is deleted.
+type VolumeReclaimPolicy string
+
+const (
+	// VolumeReclaimPolicyDelete deletes the volume together with the machine.
+	VolumeReclaimPolicyDelete VolumeReclaimPolicy = "Delete"
+	// VolumeReclaimPolicyRetain detaches the volume and keeps it when the machine is deleted. The labels that mark it
+	// as owned by the cluster and the machine are removed.
+	VolumeReclaimPolicyRetain VolumeReclaimPolicy = "Retain"
+)
+
+// HCloudVolumeSpec defines an HCloud Volume that is created for a machine and attached to its server.
+type HCloudVolumeSpec struct {
+	// Name of the volume. The HCloud Volume is named <machine-name>-<name>.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Size of the volume in GB.
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=10240
+	Size int `json:"size"`
+
+	// Format is the filesystem the volume is formatted with. If omitted, the volume is not formatted.
+	// +optional
+	// +kubebuilder:validation:Enum=ext4;xfs
+	Format string `json:"format,omitempty"`
+
+	// Automount mounts the volume in the server. Requires a format.
+	// +optional
+	Automount bool `json:"automount,omitempty"`
+
+	// Labels are added to the HCloud Volume in addition to the labels set by the controller.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// ReclaimPolicy defines whether the volume is deleted or retained when the machine is deleted.
+	// +optional
+	// +kubebuilder:default=Delete
+	// +kubebuilder:validation:Enum=Delete;Retain
+	ReclaimPolicy VolumeReclaimPolicy `json:"reclaimPolicy,omitempty"`
+}
+
+// HCloudVolumeStatus defines the observed state of a volume of a machine.
+type HCloudVolumeStatus struct {
+	Name string `json:"name"`
+	ID   int64  `json:"id"`
+	// LinuxDevice is the path of the volume in the server, e.g. /dev/disk/by-id/scsi-0HC_Volume_123.
+	LinuxDevice string `json:"linuxDevice,omitempty"`
+	// Attached is true if the volume is attached to the server of the machine.
+	Attached bool `json:"attached"`
+}
+
 // LoadBalancerSpec defines the desired state of the Control Plane load balancer.
 type LoadBalancerSpec struct {
 	// Enabled specifies if a load balancer should be created.
@@ -177,10 +364,77 @@ type LoadBalancerSpec struct {
 	// +optional
 	ExtraServices []LoadBalancerServiceSpec `json:"extraServices,omitempty"`
 
+	// HealthCheck defines the health check of the kube-apiserver service. If omitted, HCloud checks
+	// the TCP connection to the API Server port.
+	// +optional
+	HealthCheck *LoadBalancerHealthCheckSpec `json:"healthCheck,omitempty"`
+
+	// UseLabelSelectorTarget defines whether the control plane servers are targeted with a single label selector
+	// instead of adding each server as a target. HCloud then adds and removes servers automatically, and
+	// only the health check decides whether a new server receives traffic. The label machine_type is removed from
+	// servers that are deleted, so that they are not targeted anymore before their node is drained.
+	// +optional
+	UseLabelSelectorTarget bool `json:"useLabelSelectorTarget,omitempty"`
+
 	// Region contains the name of the HCloud location where the load balancer is running.
+	// If it is changed, a load balancer that has been created by the controller is migrated to the new region.
+	// The old load balancer is deleted once the new one is healthy. Only the region can be migrated, and only if
+	// the control plane endpoint is a DNS name or an external endpoint that is not the IP of the load balancer.
 	Region Region `json:"region,omitempty"`
 }
 
+// AdditionalLoadBalancerSpec defines a load balancer that is owned by the cluster and targets servers of the cluster.
+type AdditionalLoadBalancerSpec struct {
+	// Name identifies the load balancer in the cluster. The HCloud load balancer is named "<cluster name>-<name>".
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=30
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Algorithm defines the type of load balancer algorithm. It could be round_robin or least_connection. The default value is "round_robin".
+	// +optional
+	// +kubebuilder:validation:Enum=round_robin;least_connections
+	// +kubebuilder:default=round_robin
+	Algorithm LoadBalancerAlgorithmType `json:"algorithm,omitempty"`
+
+	// Type defines the type of load balancer. It could be one of lb11, lb21, or lb31.
+	// +optional
+	// +kubebuilder:validation:Enum=lb11;lb21;lb31
+	// +kubebuilder:default=lb11
+	Type string `json:"type,omitempty"`
+
+	// Region contains the name of the HCloud location where the load balancer is running. It is immutable.
+	Region Region `json:"region"`
+
+	// Services defines how traffic will be routed from the load balancer to the target servers.
+	// +optional
+	Services []LoadBalancerServiceSpec `json:"services,omitempty"`
+
+	// AttachToNetwork defines whether the load balancer is attached to the private network of the cluster.
+	// Then the private IPs of the servers are targeted. Otherwise, the load balancer is detached from its networks.
+	// +optional
+	// +kubebuilder:default=true
+	AttachToNetwork bool `json:"attachToNetwork"`
+
+	// MachineSelector selects the servers of the cluster that are targeted by their labels, e.g.
+	// "machine_type: worker". If empty, all servers of the cluster are targeted. Servers of machines that are
+	// being deleted are not targeted anymore.
+	// +optional
+	MachineSelector map[string]string `json:"machineSelector,omitempty"`
+}
+
+// AdditionalLoadBalancerStatus defines the observed state of an additional load balancer.
+type AdditionalLoadBalancerStatus struct {
+	// Name is the name of the load balancer in the spec.
+	Name string `json:"name"`
+
+	LoadBalancerStatus `json:",inline"`
+
+	// Conditions define the current state of the load balancer.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
 // LoadBalancerServiceSpec defines a load balancer Target.
 type LoadBalancerServiceSpec struct {
 	// Protocol specifies the supported load balancer Protocol. It could be one of the https, http, or tcp.
@@ -196,6 +450,97 @@ type LoadBalancerServiceSpec struct {
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=65535
 	DestinationPort int `json:"destinationPort,omitempty"`
+
+	// ProxyProtocol enables the PROXY protocol for the connections to the target servers.
+	// +optional
+	ProxyProtocol bool `json:"proxyProtocol,omitempty"`
+
+	// HealthCheck defines the health check of the service. If omitted, the health check is not changed
+	// and HCloud uses a TCP health check on the destination port.
+	// +optional
+	HealthCheck *LoadBalancerHealthCheckSpec `json:"healthCheck,omitempty"`
+
+	// HTTP defines the settings of services with protocol http or https.
+	// +optional
+	HTTP *LoadBalancerServiceHTTPSpec `json:"http,omitempty"`
+}
+
+// LoadBalancerServiceHTTPSpec defines the settings of http and https load balancer services.
+type LoadBalancerServiceHTTPSpec struct {
+	// CertificateIDs are the IDs of the HCloud certificates that are used to terminate TLS.
+	// They are required for services with protocol https.
+	// +optional
+	CertificateIDs []int64 `json:"certificateIDs,omitempty"`
+
+	// RedirectHTTP redirects traffic from port 80 to port 443. Only for services with protocol https.
+	// +optional
+	RedirectHTTP bool `json:"redirectHTTP,omitempty"`
+
+	// StickySessions enables sticky sessions based on a cookie.
+	// +optional
+	StickySessions bool `json:"stickySessions,omitempty"`
+
+	// CookieName is the name of the cookie that is used for sticky sessions. HCloud defaults to "HCLBSTICKY".
+	// +optional
+	CookieName string `json:"cookieName,omitempty"`
+
+	// CookieLifetime is the lifetime of the cookie that is used for sticky sessions. HCloud defaults to 300s.
+	// +optional
+	CookieLifetime *metav1.Duration `json:"cookieLifetime,omitempty"`
+}
+
+// LoadBalancerHealthCheckSpec defines the health check of a load balancer service.
+type LoadBalancerHealthCheckSpec struct {
+	// Protocol defines the protocol of the health check. It could be http or tcp.
+	// +kubebuilder:validation:Enum=http;tcp
+	Protocol string `json:"protocol"`
+
+	// Port defines the port on the server that is checked. If omitted, the destination port of the service is used.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int `json:"port,omitempty"`
+
+	// Interval defines the time between two health checks. It should be of the form "15s". The default is 15s.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Timeout defines the time after which a health check fails. It should be of the form "10s". The default is 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Retries defines the number of failed health checks before a target is marked unhealthy. The default is 3.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Retries *int `json:"retries,omitempty"`
+
+	// HTTP defines the settings of health checks with protocol http.
+	// +optional
+	HTTP *LoadBalancerHealthCheckHTTPSpec `json:"http,omitempty"`
+}
+
+// LoadBalancerHealthCheckHTTPSpec defines the settings of http health checks.
+type LoadBalancerHealthCheckHTTPSpec struct {
+	// Domain defines the host header that is sent with the request.
+	// +optional
+	Domain string `json:"domain,omitempty"`
+
+	// Path defines the path of the request, e.g. "/readyz". HCloud defaults to "/".
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Response defines a string that the response body has to contain.
+	// +optional
+	Response string `json:"response,omitempty"`
+
+	// StatusCodes defines the accepted status codes, e.g. "2??". HCloud defaults to 2?? and 3??.
+	// +optional
+	StatusCodes []string `json:"statusCodes,omitempty"`
+
+	// TLS enables HTTPS for the health check, e.g. to check "/readyz" of the kube-apiserver.
+	// TLS certificates are not verified.
+	// +optional
+	TLS bool `json:"tls,omitempty"`
 }
 
 // LoadBalancerStatus defines the observed state of the control plane load balancer.
@@ -210,9 +555,114 @@ type LoadBalancerStatus struct {
 
 // LoadBalancerTarget defines the target of a load balancer.
 type LoadBalancerTarget struct {
-	Type     LoadBalancerTargetType `json:"type"`
-	ServerID int64                  `json:"serverID,omitempty"`
-	IP       string                 `json:"ip,omitempty"`
+	Type          LoadBalancerTargetType `json:"type"`
+	ServerID      int64                  `json:"serverID,omitempty"`
+	IP            string                 `json:"ip,omitempty"`
+	LabelSelector string                 `json:"labelSelector,omitempty"`
+}
+
+// ControlPlaneIPStatus defines the observed state of the Floating IP that is used as control plane endpoint.
+type ControlPlaneIPStatus struct {
+	Type ControlPlaneEndpointType `json:"type"`
+	ID   int64                    `json:"id,omitempty"`
+	IPv4 string                   `json:"ipv4,omitempty"`
+	// Location is the HCloud home location of the IP.
+	Location Region `json:"location,omitempty"`
+	// ServerID is the ID of the server the IP is currently assigned to. It is zero if the IP is not assigned.
+	ServerID int64 `json:"serverID,omitempty"`
+}
+
+// DNSProvider is the provider of the DNS zone of the control plane records.
+type DNSProvider string
+
+const (
+	// DNSProviderHetzner manages the records with the Hetzner DNS API.
+	DNSProviderHetzner DNSProvider = "hetzner"
+)
+
+// ControlPlaneDNSSpec defines the DNS records that point to the control plane endpoint.
+type ControlPlaneDNSSpec struct {
+	// Provider is the DNS provider of the zone. The API token is read from the Hetzner secret of the cluster.
+	// +optional
+	// +kubebuilder:default=hetzner
+	// +kubebuilder:validation:Enum=hetzner
+	Provider DNSProvider `json:"provider,omitempty"`
+
+	// Zone is the name of the DNS zone, e.g. "example.com". The zone has to exist already.
+	// +kubebuilder:validation:MinLength=1
+	Zone string `json:"zone"`
+
+	// Name of the records relative to the zone, e.g. "api.my-cluster". Use "@" for the apex of the zone.
+	// Existing A and AAAA records of this name are taken over and kept in sync with the control plane endpoint.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// TTL of the records in seconds.
+	// +optional
+	// +kubebuilder:default=300
+	// +kubebuilder:validation:Minimum=60
+	TTL int `json:"ttl,omitempty"`
+}
+
+// NodeInfo contains information about the nodes of machines, as defined by the proposal for autoscaling from zero.
+type NodeInfo struct {
+	// Architecture is the CPU architecture of the node.
+	// +kubebuilder:validation:Enum=amd64;arm64
+	// +optional
+	Architecture string `json:"architecture,omitempty"`
+
+	// OperatingSystem is the operating system of the node.
+	// +kubebuilder:validation:Enum=linux;windows
+	// +optional
+	OperatingSystem string `json:"operatingSystem,omitempty"`
+}
+
+// BootstrapDataStorageSpec defines the object storage that serves bootstrap data which is too large for the user
+// data of HCloud servers. The server only receives a short-lived presigned URL of the object. The credentials are read
+// from the Hetzner secret.
+type BootstrapDataStorageSpec struct {
+	// Endpoint is the URL of the object storage, e.g. "https://fsn1.your-objectstorage.com".
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint"`
+
+	// Region of the object storage that is used to sign requests, e.g. "fsn1".
+	// +kubebuilder:validation:MinLength=1
+	Region string `json:"region"`
+
+	// Bucket is the name of the bucket that stores the bootstrap data. It must not be public.
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+
+	// URLExpiry is the time after which the URL of the bootstrap data expires and the object is deleted. The server
+	// has to download its bootstrap data within this time.
+	// +optional
+	// +kubebuilder:default="15m"
+	URLExpiry metav1.Duration `json:"urlExpiry,omitempty"`
+}
+
+// BootstrapDataObjectStatus is an object in the bootstrap data storage that holds the bootstrap data of a server.
+type BootstrapDataObjectStatus struct {
+	// Key of the object in the bucket.
+	Key string `json:"key"`
+
+	// ExpiresAt is the time when the URL of the object expires. The object is deleted afterwards.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+}
+
+// ControlPlaneDNSStatus defines the observed state of the DNS records of the control plane endpoint.
+type ControlPlaneDNSStatus struct {
+	// ZoneID is the ID of the zone that contains the records.
+	ZoneID string `json:"zoneID,omitempty"`
+	// +optional
+	Records []DNSRecordStatus `json:"records,omitempty"`
+}
+
+// DNSRecordStatus defines the observed state of a DNS record.
+type DNSRecordStatus struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 // HCloudNetworkSpec defines the desired state of the HCloud Private Network.
@@ -237,22 +687,164 @@ type HCloudNetworkSpec struct {
 	// +kubebuilder:default=eu-central
 	// +optional
 	NetworkZone HCloudNetworkZone `json:"networkZone,omitempty"`
+
+	// Subnets defines the subnets of the HCloud Network. If set, SubnetCIDRBlock and NetworkZone are ignored.
+	// Subnets are added to an existing network, but are never removed from it.
+	// +optional
+	Subnets []HCloudNetworkSubnetSpec `json:"subnets,omitempty"`
+
+	// Routes defines static routes of the HCloud Network.
+	// Routes are added to an existing network, but are never removed from it.
+	// +optional
+	Routes []HCloudNetworkRouteSpec `json:"routes,omitempty"`
+
+	// NATGateway defines a server that routes the traffic of servers without public IP addresses to the internet.
+	// The controller creates the server in the network and adds a default route via the server to the network.
+	// It cannot be combined with an existing network.
+	// +optional
+	NATGateway *NATGatewaySpec `json:"natGateway,omitempty"`
+
+	// ID is the ID of an existing HCloud Network that is used instead of creating a new one.
+	// The network is not managed by the controller: it is neither modified nor deleted with the cluster.
+	// It cannot be combined with LabelSelector, Subnets or Routes.
+	// +optional
+	ID *int64 `json:"id,omitempty"`
+
+	// LabelSelector selects an existing HCloud Network by its labels that is used instead of creating a new one.
+	// Exactly one network has to match. The network is not managed by the controller: it is neither modified
+	// nor deleted with the cluster. It cannot be combined with ID, Subnets or Routes.
+	// +optional
+	LabelSelector map[string]string `json:"labelSelector,omitempty"`
 }
 
+// HCloudNetworkSubnetSpec defines a subnet of the HCloud Network.
+type HCloudNetworkSubnetSpec struct {
+	// Type defines the type of the subnet. It could be cloud or vswitch. The default value is "cloud".
+	// +kubebuilder:validation:Enum=cloud;vswitch
+	// +kubebuilder:default=cloud
+	// +optional
+	Type HCloudNetworkSubnetType `json:"type,omitempty"`
+
+	// NetworkZone specifies the HCloud network zone of the subnet.
+	// +kubebuilder:validation:Enum=eu-central;us-east;us-west;ap-southeast
+	NetworkZone HCloudNetworkZone `json:"networkZone"`
+
+	// IPRange defines the cidrBlock of the subnet. It has to be part of the cidrBlock of the network.
+	IPRange string `json:"ipRange"`
+
+	// VSwitchID defines the ID of the Robot vSwitch. It is required for subnets of type vswitch.
+	// +optional
+	VSwitchID *int64 `json:"vSwitchID,omitempty"`
+}
+
+// HCloudNetworkRouteSpec defines a static route of the HCloud Network.
+type HCloudNetworkRouteSpec struct {
+	// Destination defines the cidrBlock of the destination of the route.
+	Destination string `json:"destination"`
+
+	// Gateway defines the IP address of the gateway of the route. It has to be part of the network.
+	Gateway string `json:"gateway"`
+}
+
+// NATGatewaySpec defines the server that routes the traffic of private-only servers to the internet.
+type NATGatewaySpec struct {
+	// Type is the HCloud server type of the NAT gateway.
+	// +kubebuilder:validation:Enum=cpx11;cx21;cpx21;cx31;cpx31;cx41;cpx41;cx51;cpx51;ccx11;ccx12;ccx13;ccx21;ccx22;ccx23;ccx31;ccx32;ccx33;ccx41;ccx42;ccx43;ccx51;ccx52;ccx53;ccx62;ccx63;cax11;cax21;cax31;cax41;cx22;cx32;cx42;cx52
+	// +kubebuilder:default=cx22
+	// +optional
+	Type HCloudMachineType `json:"type,omitempty"`
+
+	// ImageName is the name of the image of the NAT gateway. The image has to support cloud-init and iptables.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:default=ubuntu-24.04
+	// +optional
+	ImageName string `json:"imageName,omitempty"`
+
+	// Location is the location of the NAT gateway. It defaults to the first control plane region.
+	// +optional
+	Location Region `json:"location,omitempty"`
+}
+
+// NATGatewayStatus defines the observed state of the NAT gateway.
+type NATGatewayStatus struct {
+	// ServerID is the ID of the server of the NAT gateway.
+	ServerID int64 `json:"serverID"`
+
+	// PrivateIP is the IP address of the NAT gateway in the network. It is the gateway of the default route.
+	// +optional
+	PrivateIP string `json:"privateIP,omitempty"`
+
+	// PublicIPv4 is the public IPv4 address through which the traffic of private-only servers leaves the network.
+	// +optional
+	PublicIPv4 string `json:"publicIPv4,omitempty"`
+}
+
+// HCloudNetworkSubnetType describes the type of a subnet.
+type HCloudNetworkSubnetType string
+
+const (
+	// HCloudNetworkSubnetTypeCloud is a subnet for HCloud servers and load balancers.
+	HCloudNetworkSubnetTypeCloud = HCloudNetworkSubnetType("cloud")
+
+	// HCloudNetworkSubnetTypeVSwitch is a subnet that connects a Robot vSwitch.
+	HCloudNetworkSubnetTypeVSwitch = HCloudNetworkSubnetType("vswitch")
+)
+
 // NetworkStatus defines the observed state of the HCloud Private Network.
 type NetworkStatus struct {
 	ID              int64             `json:"id,omitempty"`
 	Labels          map[string]string `json:"-"`
 	AttachedServers []int64           `json:"attachedServers,omitempty"`
+	// +optional
+	Subnets []NetworkSubnetStatus `json:"subnets,omitempty"`
+	// +optional
+	Routes []HCloudNetworkRouteSpec `json:"routes,omitempty"`
+	// Unmanaged is true if an existing network has been adopted. It is not deleted with the cluster.
+	// +optional
+	Unmanaged bool `json:"unmanaged,omitempty"`
+}
+
+// NetworkSubnetStatus defines the observed state of a subnet of the HCloud Private Network.
+type NetworkSubnetStatus struct {
+	Type        HCloudNetworkSubnetType `json:"type"`
+	NetworkZone HCloudNetworkZone       `json:"networkZone"`
+	IPRange     string                  `json:"ipRange"`
+	Gateway     string                  `json:"gateway,omitempty"`
+	VSwitchID   int64                   `json:"vSwitchID,omitempty"`
 }
 
 // Region is a Hetzner Location.
 // +kubebuilder:validation:Enum=fsn1;hel1;nbg1;ash;hil;sin
 type Region string
 
+// NetworkZone returns the network zone of the location or an empty string if the location is unknown.
+func (r Region) NetworkZone() HCloudNetworkZone {
+	return HCloudNetworkZone(regionNetworkZoneMap[string(r)])
+}
+
 // HCloudNetworkZone describes the Network zone.
 type HCloudNetworkZone string
 
+// SubnetSpecs returns the desired subnets of the network. If no subnets are specified,
+// a single cloud subnet is built from SubnetCIDRBlock and NetworkZone.
+func (s *HCloudNetworkSpec) SubnetSpecs() []HCloudNetworkSubnetSpec {
+	if len(s.Subnets) > 0 {
+		return s.Subnets
+	}
+	return []HCloudNetworkSubnetSpec{
+		{
+			Type:        HCloudNetworkSubnetTypeCloud,
+			NetworkZone: s.NetworkZone,
+			IPRange:     s.SubnetCIDRBlock,
+		},
+	}
+}
+
+// IsAdopted returns true if an existing network should be used instead of creating one.
+func (s *HCloudNetworkSpec) IsAdopted() bool {
+	return s.ID != nil || len(s.LabelSelector) > 0
+}
+
 // IsZero returns true if a private Network is set.
 func (s *HCloudNetworkSpec) IsZero() bool {
 	if s.CIDRBlock != "" {
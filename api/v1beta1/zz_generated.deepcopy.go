@@ -22,13 +22,65 @@ package v1beta1
 
 import (
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
-	"k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalLoadBalancerSpec) DeepCopyInto(out *AdditionalLoadBalancerSpec) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]LoadBalancerServiceSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MachineSelector != nil {
+		in, out := &in.MachineSelector, &out.MachineSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalLoadBalancerSpec.
+func (in *AdditionalLoadBalancerSpec) DeepCopy() *AdditionalLoadBalancerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalLoadBalancerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalLoadBalancerStatus) DeepCopyInto(out *AdditionalLoadBalancerStatus) {
+	*out = *in
+	in.LoadBalancerStatus.DeepCopyInto(&out.LoadBalancerStatus)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalLoadBalancerStatus.
+func (in *AdditionalLoadBalancerStatus) DeepCopy() *AdditionalLoadBalancerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalLoadBalancerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BTRFSDefinition) DeepCopyInto(out *BTRFSDefinition) {
 	*out = *in
@@ -44,6 +96,38 @@ func (in *BTRFSDefinition) DeepCopy() *BTRFSDefinition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapDataObjectStatus) DeepCopyInto(out *BootstrapDataObjectStatus) {
+	*out = *in
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapDataObjectStatus.
+func (in *BootstrapDataObjectStatus) DeepCopy() *BootstrapDataObjectStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapDataObjectStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapDataStorageSpec) DeepCopyInto(out *BootstrapDataStorageSpec) {
+	*out = *in
+	out.URLExpiry = in.URLExpiry
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapDataStorageSpec.
+func (in *BootstrapDataStorageSpec) DeepCopy() *BootstrapDataStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapDataStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CPU) DeepCopyInto(out *CPU) {
 	*out = *in
@@ -64,12 +148,62 @@ func (in *CPU) DeepCopy() *CPU {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneDNSSpec) DeepCopyInto(out *ControlPlaneDNSSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneDNSSpec.
+func (in *ControlPlaneDNSSpec) DeepCopy() *ControlPlaneDNSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneDNSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneDNSStatus) DeepCopyInto(out *ControlPlaneDNSStatus) {
+	*out = *in
+	if in.Records != nil {
+		in, out := &in.Records, &out.Records
+		*out = make([]DNSRecordStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneDNSStatus.
+func (in *ControlPlaneDNSStatus) DeepCopy() *ControlPlaneDNSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneDNSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneIPStatus) DeepCopyInto(out *ControlPlaneIPStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneIPStatus.
+func (in *ControlPlaneIPStatus) DeepCopy() *ControlPlaneIPStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneIPStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerGeneratedStatus) DeepCopyInto(out *ControllerGeneratedStatus) {
 	*out = *in
 	if in.UserData != nil {
 		in, out := &in.UserData, &out.UserData
-		*out = new(v1.SecretReference)
+		*out = new(corev1.SecretReference)
 		**out = **in
 	}
 	if in.InstallImage != nil {
@@ -116,6 +250,171 @@ func (in *ControllerGeneratedStatus) DeepCopy() *ControllerGeneratedStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSRecordStatus) DeepCopyInto(out *DNSRecordStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSRecordStatus.
+func (in *DNSRecordStatus) DeepCopy() *DNSRecordStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSRecordStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudDeletionPolicy) DeepCopyInto(out *HCloudDeletionPolicy) {
+	*out = *in
+	if in.GracefulShutdownTimeout != nil {
+		in, out := &in.GracefulShutdownTimeout, &out.GracefulShutdownTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudDeletionPolicy.
+func (in *HCloudDeletionPolicy) DeepCopy() *HCloudDeletionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudDeletionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudDiagnosticsStatus) DeepCopyInto(out *HCloudDiagnosticsStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudDiagnosticsStatus.
+func (in *HCloudDiagnosticsStatus) DeepCopy() *HCloudDiagnosticsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudDiagnosticsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudFirewallRuleSpec) DeepCopyInto(out *HCloudFirewallRuleSpec) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(string)
+		**out = **in
+	}
+	if in.SourceIPs != nil {
+		in, out := &in.SourceIPs, &out.SourceIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DestinationIPs != nil {
+		in, out := &in.DestinationIPs, &out.DestinationIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudFirewallRuleSpec.
+func (in *HCloudFirewallRuleSpec) DeepCopy() *HCloudFirewallRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudFirewallRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudFirewallSpec) DeepCopyInto(out *HCloudFirewallSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]HCloudFirewallRuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudFirewallSpec.
+func (in *HCloudFirewallSpec) DeepCopy() *HCloudFirewallSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudFirewallSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudFirewallStatus) DeepCopyInto(out *HCloudFirewallStatus) {
+	*out = *in
+	if in.AppliedTo != nil {
+		in, out := &in.AppliedTo, &out.AppliedTo
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudFirewallStatus.
+func (in *HCloudFirewallStatus) DeepCopy() *HCloudFirewallStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudFirewallStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudImageSpec) DeepCopyInto(out *HCloudImageSpec) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudImageSpec.
+func (in *HCloudImageSpec) DeepCopy() *HCloudImageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudImageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HCloudMachine) DeepCopyInto(out *HCloudMachine) {
 	*out = *in
@@ -183,6 +482,21 @@ func (in *HCloudMachineSpec) DeepCopyInto(out *HCloudMachineSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.FallbackTypes != nil {
+		in, out := &in.FallbackTypes, &out.FallbackTypes
+		*out = make([]HCloudMachineType, len(*in))
+		copy(*out, *in)
+	}
+	if in.FallbackLocations != nil {
+		in, out := &in.FallbackLocations, &out.FallbackLocations
+		*out = make([]Region, len(*in))
+		copy(*out, *in)
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(HCloudImageSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SSHKeys != nil {
 		in, out := &in.SSHKeys, &out.SSHKeys
 		*out = make([]SSHKey, len(*in))
@@ -198,6 +512,30 @@ func (in *HCloudMachineSpec) DeepCopyInto(out *HCloudMachineSpec) {
 		*out = new(PublicNetworkSpec)
 		**out = **in
 	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]HCloudVolumeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PropagateMachineLabels != nil {
+		in, out := &in.PropagateMachineLabels, &out.PropagateMachineLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(HCloudDeletionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudMachineSpec.
@@ -223,6 +561,33 @@ func (in *HCloudMachineStatus) DeepCopyInto(out *HCloudMachineStatus) {
 		*out = make([]SSHKey, len(*in))
 		copy(*out, *in)
 	}
+	if in.AppliedLabels != nil {
+		in, out := &in.AppliedLabels, &out.AppliedLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]HCloudVolumeStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Diagnostics != nil {
+		in, out := &in.Diagnostics, &out.Diagnostics
+		*out = new(HCloudDiagnosticsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = make([]HCloudSnapshotStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BootstrapDataObject != nil {
+		in, out := &in.BootstrapDataObject, &out.BootstrapDataObject
+		*out = new(BootstrapDataObjectStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InstanceState != nil {
 		in, out := &in.InstanceState, &out.InstanceState
 		*out = new(hcloud.ServerStatus)
@@ -354,11 +719,34 @@ func (in *HCloudMachineTemplateStatus) DeepCopyInto(out *HCloudMachineTemplateSt
 	*out = *in
 	if in.Capacity != nil {
 		in, out := &in.Capacity, &out.Capacity
-		*out = make(v1.ResourceList, len(*in))
+		*out = make(corev1.ResourceList, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.NodeInfo != nil {
+		in, out := &in.NodeInfo, &out.NodeInfo
+		*out = new(NodeInfo)
+		**out = **in
+	}
+	if in.NodeLabels != nil {
+		in, out := &in.NodeLabels, &out.NodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeTaints != nil {
+		in, out := &in.NodeTaints, &out.NodeTaints
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastCapacityUpdate != nil {
+		in, out := &in.LastCapacityUpdate, &out.LastCapacityUpdate
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(apiv1beta1.Conditions, len(*in))
@@ -378,9 +766,53 @@ func (in *HCloudMachineTemplateStatus) DeepCopy() *HCloudMachineTemplateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudNetworkRouteSpec) DeepCopyInto(out *HCloudNetworkRouteSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudNetworkRouteSpec.
+func (in *HCloudNetworkRouteSpec) DeepCopy() *HCloudNetworkRouteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudNetworkRouteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HCloudNetworkSpec) DeepCopyInto(out *HCloudNetworkSpec) {
 	*out = *in
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]HCloudNetworkSubnetSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]HCloudNetworkRouteSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.NATGateway != nil {
+		in, out := &in.NATGateway, &out.NATGateway
+		*out = new(NATGatewaySpec)
+		**out = **in
+	}
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudNetworkSpec.
@@ -393,6 +825,26 @@ func (in *HCloudNetworkSpec) DeepCopy() *HCloudNetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudNetworkSubnetSpec) DeepCopyInto(out *HCloudNetworkSubnetSpec) {
+	*out = *in
+	if in.VSwitchID != nil {
+		in, out := &in.VSwitchID, &out.VSwitchID
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudNetworkSubnetSpec.
+func (in *HCloudNetworkSubnetSpec) DeepCopy() *HCloudNetworkSubnetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudNetworkSubnetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HCloudPlacementGroupSpec) DeepCopyInto(out *HCloudPlacementGroupSpec) {
 	*out = *in
@@ -514,6 +966,13 @@ func (in *HCloudRemediationStatus) DeepCopyInto(out *HCloudRemediationStatus) {
 		in, out := &in.LastRemediated, &out.LastRemediated
 		*out = (*in).DeepCopy()
 	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]RemediationStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(apiv1beta1.Conditions, len(*in))
@@ -640,6 +1099,59 @@ func (in *HCloudRemediationTemplateStatus) DeepCopy() *HCloudRemediationTemplate
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudSnapshotStatus) DeepCopyInto(out *HCloudSnapshotStatus) {
+	*out = *in
+	in.Created.DeepCopyInto(&out.Created)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudSnapshotStatus.
+func (in *HCloudSnapshotStatus) DeepCopy() *HCloudSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudVolumeSpec) DeepCopyInto(out *HCloudVolumeSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudVolumeSpec.
+func (in *HCloudVolumeSpec) DeepCopy() *HCloudVolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudVolumeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudVolumeStatus) DeepCopyInto(out *HCloudVolumeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudVolumeStatus.
+func (in *HCloudVolumeStatus) DeepCopy() *HCloudVolumeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudVolumeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HardwareDetails) DeepCopyInto(out *HardwareDetails) {
 	*out = *in
@@ -735,7 +1247,7 @@ func (in *HetznerBareMetalHostSpec) DeepCopyInto(out *HetznerBareMetalHostSpec)
 	}
 	if in.ConsumerRef != nil {
 		in, out := &in.ConsumerRef, &out.ConsumerRef
-		*out = new(v1.ObjectReference)
+		*out = new(corev1.ObjectReference)
 		**out = **in
 	}
 	if in.MaintenanceMode != nil {
@@ -900,6 +1412,7 @@ func (in *HetznerBareMetalMachineTemplate) DeepCopyInto(out *HetznerBareMetalMac
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HetznerBareMetalMachineTemplate.
@@ -984,6 +1497,37 @@ func (in *HetznerBareMetalMachineTemplateSpec) DeepCopy() *HetznerBareMetalMachi
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HetznerBareMetalMachineTemplateStatus) DeepCopyInto(out *HetznerBareMetalMachineTemplateStatus) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.NodeInfo != nil {
+		in, out := &in.NodeInfo, &out.NodeInfo
+		*out = new(NodeInfo)
+		**out = **in
+	}
+	if in.LastCapacityUpdate != nil {
+		in, out := &in.LastCapacityUpdate, &out.LastCapacityUpdate
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HetznerBareMetalMachineTemplateStatus.
+func (in *HetznerBareMetalMachineTemplateStatus) DeepCopy() *HetznerBareMetalMachineTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HetznerBareMetalMachineTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HetznerBareMetalRemediation) DeepCopyInto(out *HetznerBareMetalRemediation) {
 	*out = *in
@@ -1070,6 +1614,13 @@ func (in *HetznerBareMetalRemediationStatus) DeepCopyInto(out *HetznerBareMetalR
 		in, out := &in.LastRemediated, &out.LastRemediated
 		*out = (*in).DeepCopy()
 	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]RemediationStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HetznerBareMetalRemediationStatus.
@@ -1251,7 +1802,7 @@ func (in *HetznerClusterList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HetznerClusterSpec) DeepCopyInto(out *HetznerClusterSpec) {
 	*out = *in
-	out.HCloudNetwork = in.HCloudNetwork
+	in.HCloudNetwork.DeepCopyInto(&out.HCloudNetwork)
 	if in.ControlPlaneRegions != nil {
 		in, out := &in.ControlPlaneRegions, &out.ControlPlaneRegions
 		*out = make([]Region, len(*in))
@@ -1264,11 +1815,35 @@ func (in *HetznerClusterSpec) DeepCopyInto(out *HetznerClusterSpec) {
 		**out = **in
 	}
 	in.ControlPlaneLoadBalancer.DeepCopyInto(&out.ControlPlaneLoadBalancer)
+	if in.AdditionalLoadBalancers != nil {
+		in, out := &in.AdditionalLoadBalancers, &out.AdditionalLoadBalancers
+		*out = make([]AdditionalLoadBalancerSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ControlPlaneDNS != nil {
+		in, out := &in.ControlPlaneDNS, &out.ControlPlaneDNS
+		*out = new(ControlPlaneDNSSpec)
+		**out = **in
+	}
+	if in.BootstrapDataStorage != nil {
+		in, out := &in.BootstrapDataStorage, &out.BootstrapDataStorage
+		*out = new(BootstrapDataStorageSpec)
+		**out = **in
+	}
 	if in.HCloudPlacementGroups != nil {
 		in, out := &in.HCloudPlacementGroups, &out.HCloudPlacementGroups
 		*out = make([]HCloudPlacementGroupSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.HCloudFirewalls != nil {
+		in, out := &in.HCloudFirewalls, &out.HCloudFirewalls
+		*out = make([]HCloudFirewallSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.HetznerSecret = in.HetznerSecret
 }
 
@@ -1290,11 +1865,33 @@ func (in *HetznerClusterStatus) DeepCopyInto(out *HetznerClusterStatus) {
 		*out = new(NetworkStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NATGateway != nil {
+		in, out := &in.NATGateway, &out.NATGateway
+		*out = new(NATGatewayStatus)
+		**out = **in
+	}
 	if in.ControlPlaneLoadBalancer != nil {
 		in, out := &in.ControlPlaneLoadBalancer, &out.ControlPlaneLoadBalancer
 		*out = new(LoadBalancerStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AdditionalLoadBalancers != nil {
+		in, out := &in.AdditionalLoadBalancers, &out.AdditionalLoadBalancers
+		*out = make([]AdditionalLoadBalancerStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ControlPlaneIP != nil {
+		in, out := &in.ControlPlaneIP, &out.ControlPlaneIP
+		*out = new(ControlPlaneIPStatus)
+		**out = **in
+	}
+	if in.ControlPlaneDNS != nil {
+		in, out := &in.ControlPlaneDNS, &out.ControlPlaneDNS
+		*out = new(ControlPlaneDNSStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.HCloudPlacementGroups != nil {
 		in, out := &in.HCloudPlacementGroups, &out.HCloudPlacementGroups
 		*out = make([]HCloudPlacementGroupStatus, len(*in))
@@ -1302,6 +1899,13 @@ func (in *HetznerClusterStatus) DeepCopyInto(out *HetznerClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HCloudFirewalls != nil {
+		in, out := &in.HCloudFirewalls, &out.HCloudFirewalls
+		*out = make([]HCloudFirewallStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.FailureDomains != nil {
 		in, out := &in.FailureDomains, &out.FailureDomains
 		*out = make(apiv1beta1.FailureDomains, len(*in))
@@ -1428,6 +2032,11 @@ func (in *HetznerSSHKeys) DeepCopyInto(out *HetznerSSHKeys) {
 		copy(*out, *in)
 	}
 	out.RobotRescueSecretRef = in.RobotRescueSecretRef
+	if in.HCloudRescueSecretRef != nil {
+		in, out := &in.HCloudRescueSecretRef, &out.HCloudRescueSecretRef
+		*out = new(SSHSecretRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HetznerSSHKeys.
@@ -1581,9 +2190,104 @@ func (in *LVMDefinition) DeepCopy() *LVMDefinition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerHealthCheckHTTPSpec) DeepCopyInto(out *LoadBalancerHealthCheckHTTPSpec) {
+	*out = *in
+	if in.StatusCodes != nil {
+		in, out := &in.StatusCodes, &out.StatusCodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerHealthCheckHTTPSpec.
+func (in *LoadBalancerHealthCheckHTTPSpec) DeepCopy() *LoadBalancerHealthCheckHTTPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerHealthCheckHTTPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerHealthCheckSpec) DeepCopyInto(out *LoadBalancerHealthCheckSpec) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(LoadBalancerHealthCheckHTTPSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerHealthCheckSpec.
+func (in *LoadBalancerHealthCheckSpec) DeepCopy() *LoadBalancerHealthCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerHealthCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerServiceHTTPSpec) DeepCopyInto(out *LoadBalancerServiceHTTPSpec) {
+	*out = *in
+	if in.CertificateIDs != nil {
+		in, out := &in.CertificateIDs, &out.CertificateIDs
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.CookieLifetime != nil {
+		in, out := &in.CookieLifetime, &out.CookieLifetime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerServiceHTTPSpec.
+func (in *LoadBalancerServiceHTTPSpec) DeepCopy() *LoadBalancerServiceHTTPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerServiceHTTPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadBalancerServiceSpec) DeepCopyInto(out *LoadBalancerServiceSpec) {
 	*out = *in
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(LoadBalancerHealthCheckSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(LoadBalancerServiceHTTPSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerServiceSpec.
@@ -1607,7 +2311,14 @@ func (in *LoadBalancerSpec) DeepCopyInto(out *LoadBalancerSpec) {
 	if in.ExtraServices != nil {
 		in, out := &in.ExtraServices, &out.ExtraServices
 		*out = make([]LoadBalancerServiceSpec, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(LoadBalancerHealthCheckSpec)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -1656,6 +2367,36 @@ func (in *LoadBalancerTarget) DeepCopy() *LoadBalancerTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATGatewaySpec) DeepCopyInto(out *NATGatewaySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATGatewaySpec.
+func (in *NATGatewaySpec) DeepCopy() *NATGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NATGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATGatewayStatus) DeepCopyInto(out *NATGatewayStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATGatewayStatus.
+func (in *NATGatewayStatus) DeepCopy() *NATGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NATGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NIC) DeepCopyInto(out *NIC) {
 	*out = *in
@@ -1686,6 +2427,16 @@ func (in *NetworkStatus) DeepCopyInto(out *NetworkStatus) {
 		*out = make([]int64, len(*in))
 		copy(*out, *in)
 	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]NetworkSubnetStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]HCloudNetworkRouteSpec, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkStatus.
@@ -1698,6 +2449,36 @@ func (in *NetworkStatus) DeepCopy() *NetworkStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkSubnetStatus) DeepCopyInto(out *NetworkSubnetStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSubnetStatus.
+func (in *NetworkSubnetStatus) DeepCopy() *NetworkSubnetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkSubnetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeInfo) DeepCopyInto(out *NodeInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeInfo.
+func (in *NodeInfo) DeepCopy() *NodeInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Partition) DeepCopyInto(out *Partition) {
 	*out = *in
@@ -1763,14 +2544,91 @@ func (in *RebootAnnotationArguments) DeepCopy() *RebootAnnotationArguments {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationHealthProbe) DeepCopyInto(out *RemediationHealthProbe) {
+	*out = *in
+	if in.TCPPorts != nil {
+		in, out := &in.TCPPorts, &out.TCPPorts
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationHealthProbe.
+func (in *RemediationHealthProbe) DeepCopy() *RemediationHealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationHealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationStep) DeepCopyInto(out *RemediationStep) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationStep.
+func (in *RemediationStep) DeepCopy() *RemediationStep {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationStepStatus) DeepCopyInto(out *RemediationStepStatus) {
+	*out = *in
+	in.Started.DeepCopyInto(&out.Started)
+	if in.Finished != nil {
+		in, out := &in.Finished, &out.Finished
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationStepStatus.
+func (in *RemediationStepStatus) DeepCopy() *RemediationStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RemediationStrategy) DeepCopyInto(out *RemediationStrategy) {
 	*out = *in
 	if in.Timeout != nil {
 		in, out := &in.Timeout, &out.Timeout
-		*out = new(metav1.Duration)
+		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]RemediationStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HealthProbe != nil {
+		in, out := &in.HealthProbe, &out.HealthProbe
+		*out = new(RemediationHealthProbe)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationStrategy.
@@ -1901,7 +2759,7 @@ func (in *SecretStatus) DeepCopyInto(out *SecretStatus) {
 	*out = *in
 	if in.Reference != nil {
 		in, out := &in.Reference, &out.Reference
-		*out = new(v1.SecretReference)
+		*out = new(corev1.SecretReference)
 		**out = **in
 	}
 	if in.DataHash != nil {
@@ -822,6 +822,45 @@ var _ = Describe("HCloudMachine validation", func() {
 		Expect(testEnv.Create(ctx, hcloudMachine)).To(Succeed())
 	})
 
+	It("should fail without public IPs if the network of the cluster is disabled", func() {
+		hetznerCluster := &infrav1.HetznerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "hetzner-validation", Namespace: testNs.Name},
+			Spec:       getDefaultHetznerClusterSpec(),
+		}
+		hetznerCluster.Spec.HCloudNetwork.Enabled = false
+		Expect(testEnv.Create(ctx, hetznerCluster)).To(Succeed())
+
+		capiCluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "validation", Namespace: testNs.Name},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{
+					APIVersion: infrav1.GroupVersion.String(),
+					Kind:       "HetznerCluster",
+					Name:       hetznerCluster.Name,
+					Namespace:  testNs.Name,
+				},
+			},
+		}
+		Expect(testEnv.Create(ctx, capiCluster)).To(Succeed())
+		defer func() {
+			Expect(testEnv.Cleanup(ctx, capiCluster, hetznerCluster)).To(Succeed())
+		}()
+
+		hcloudMachine.Labels = map[string]string{clusterv1.ClusterNameLabel: capiCluster.Name}
+		hcloudMachine.Spec.PublicNetwork = &infrav1.PublicNetworkSpec{EnableIPv4: false, EnableIPv6: false}
+
+		Eventually(func() error {
+			if err := testEnv.Client.Get(ctx, client.ObjectKeyFromObject(capiCluster), &clusterv1.Cluster{}); err != nil {
+				return err
+			}
+			return testEnv.Client.Get(ctx, client.ObjectKeyFromObject(hetznerCluster), &infrav1.HetznerCluster{})
+		}, timeout, interval).Should(Succeed())
+		Expect(testEnv.Create(ctx, hcloudMachine)).ToNot(Succeed())
+
+		hcloudMachine.Spec.PublicNetwork.EnableIPv6 = true
+		Expect(testEnv.Create(ctx, hcloudMachine)).To(Succeed())
+	})
+
 	It("should prevent updating immutable fields", func() {
 		Expect(testEnv.Create(ctx, hcloudMachine)).To(Succeed())
 
@@ -834,6 +873,21 @@ var _ = Describe("HCloudMachine validation", func() {
 		hcloudMachine.Spec.ImageName = "my-control-plane"
 		Expect(testEnv.Update(ctx, hcloudMachine)).ToNot(Succeed())
 	})
+
+	It("should allow updating the image if the rebuild is approved", func() {
+		Expect(testEnv.Create(ctx, hcloudMachine)).To(Succeed())
+
+		Eventually(func() error {
+			key := client.ObjectKey{Namespace: testNs.Name, Name: hcloudMachine.Name}
+			return testEnv.Client.Get(ctx, key, hcloudMachine)
+		}, timeout, interval).Should(BeNil())
+
+		hcloudMachine.Spec.ImageName = "my-control-plane"
+		Expect(testEnv.Update(ctx, hcloudMachine)).ToNot(Succeed())
+
+		hcloudMachine.Annotations = map[string]string{infrav1.RebuildApprovedAnnotation: ""}
+		Expect(testEnv.Update(ctx, hcloudMachine)).To(Succeed())
+	})
 })
 
 var _ = Describe("IgnoreInsignificantHetznerClusterUpdates Predicate", func() {
@@ -148,7 +148,12 @@ func (r *HCloudMachineTemplateReconciler) Reconcile(ctx context.Context, req rec
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
-	return reconcile.Result{}, r.reconcile(ctx, machineTemplateScope)
+	if err := r.reconcile(ctx, machineTemplateScope); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// evaluate the capacity again periodically
+	return reconcile.Result{RequeueAfter: machinetemplate.CapacityRefreshInterval}, nil
 }
 
 func (r *HCloudMachineTemplateReconciler) reconcile(ctx context.Context, machineTemplateScope *scope.HCloudMachineTemplateScope) error {
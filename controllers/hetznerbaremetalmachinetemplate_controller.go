@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/machinetemplate"
+)
+
+// HetznerBareMetalMachineTemplateReconciler reconciles a HetznerBareMetalMachineTemplate object.
+type HetznerBareMetalMachineTemplateReconciler struct {
+	client.Client
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=hetznerbaremetalmachinetemplates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=hetznerbaremetalmachinetemplates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=hetznerbaremetalhosts,verbs=get;list;watch
+
+// Reconcile reports the capacity of the HetznerBareMetalHosts that match a HetznerBareMetalMachineTemplate.
+func (r *HetznerBareMetalMachineTemplateReconciler) Reconcile(ctx context.Context, req reconcile.Request) (_ reconcile.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	machineTemplate := &infrav1.HetznerBareMetalMachineTemplate{}
+	if err := r.Get(ctx, req.NamespacedName, machineTemplate); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	log = log.WithValues("HetznerBareMetalMachineTemplate", klog.KObj(machineTemplate))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	patchHelper, err := patch.NewHelper(machineTemplate, r.Client)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to get patch helper: %w", err)
+	}
+
+	defer func() {
+		if err := patchHelper.Patch(ctx, machineTemplate); err != nil {
+			log.Error(err, "failed to patch HetznerBareMetalMachineTemplate")
+		}
+	}()
+
+	machineTemplateScope := scope.NewBareMetalMachineTemplateScope(scope.BareMetalMachineTemplateScopeParams{
+		Client:                   r.Client,
+		Logger:                   &log,
+		BareMetalMachineTemplate: machineTemplate,
+	})
+
+	if err := machinetemplate.NewService(machineTemplateScope).Reconcile(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile machine template for HetznerBareMetalMachineTemplate %s/%s: %w",
+			machineTemplate.Namespace, machineTemplate.Name, err)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HetznerBareMetalMachineTemplateReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		// updates of the status must not trigger a reconcile, the capacity changes only with the spec or the hosts
+		For(&infrav1.HetznerBareMetalMachineTemplate{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Watches(
+			&infrav1.HetznerBareMetalHost{},
+			handler.EnqueueRequestsFromMapFunc(r.BareMetalHostToBareMetalMachineTemplates(ctx)),
+		).
+		Complete(r)
+}
+
+// BareMetalHostToBareMetalMachineTemplates is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// of all HetznerBareMetalMachineTemplates in the namespace of a HetznerBareMetalHost, as any change of a host can
+// change their capacity.
+func (r *HetznerBareMetalMachineTemplateReconciler) BareMetalHostToBareMetalMachineTemplates(ctx context.Context) handler.MapFunc {
+	log := ctrl.LoggerFrom(ctx)
+	return func(ctx context.Context, o client.Object) []reconcile.Request {
+		templates := &infrav1.HetznerBareMetalMachineTemplateList{}
+		if err := r.List(ctx, templates, client.InNamespace(o.GetNamespace())); err != nil {
+			log.Error(err, "failed to list HetznerBareMetalMachineTemplates, skipping mapping")
+			return nil
+		}
+
+		result := make([]reconcile.Request, 0, len(templates.Items))
+		for _, template := range templates.Items {
+			result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&template)})
+		}
+		return result
+	}
+}
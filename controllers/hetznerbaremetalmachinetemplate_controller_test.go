@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/test/helpers"
+)
+
+var _ = Describe("HetznerBareMetalMachineTemplateReconciler", func() {
+	var (
+		machineTemplate *infrav1.HetznerBareMetalMachineTemplate
+		host            *infrav1.HetznerBareMetalHost
+		testNs          *corev1.Namespace
+		key             client.ObjectKey
+	)
+
+	BeforeEach(func() {
+		var err error
+		testNs, err = testEnv.CreateNamespace(ctx, "hetznerbaremetalmachinetemplate-reconciler")
+		Expect(err).NotTo(HaveOccurred())
+
+		spec := getDefaultHetznerBareMetalMachineSpec()
+		spec.HostSelector = infrav1.HostSelector{MatchLabels: map[string]string{"pool": "workers"}}
+		machineTemplate = &infrav1.HetznerBareMetalMachineTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bm-machine-template",
+				Namespace: testNs.Name,
+			},
+			Spec: infrav1.HetznerBareMetalMachineTemplateSpec{
+				Template: infrav1.HetznerBareMetalMachineTemplateResource{Spec: spec},
+			},
+		}
+		Expect(testEnv.Create(ctx, machineTemplate)).To(Succeed())
+		key = client.ObjectKeyFromObject(machineTemplate)
+
+		host = helpers.BareMetalHost("bm-host", testNs.Name)
+		host.Labels = map[string]string{"pool": "workers"}
+		Expect(testEnv.Create(ctx, host)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(testEnv.Cleanup(ctx, testNs, machineTemplate, host)).To(Succeed())
+	})
+
+	It("counts the matching and available hosts", func() {
+		Eventually(func() bool {
+			if err := testEnv.Get(ctx, key, machineTemplate); err != nil {
+				return false
+			}
+			return machineTemplate.Status.MatchingHosts == 1 && machineTemplate.Status.AvailableHosts == 1
+		}, timeout).Should(BeTrue())
+	})
+
+	It("updates the status when a host changes", func() {
+		Eventually(func() bool {
+			if err := testEnv.Get(ctx, key, machineTemplate); err != nil {
+				return false
+			}
+			return machineTemplate.Status.AvailableHosts == 1
+		}, timeout).Should(BeTrue())
+
+		Eventually(func() error {
+			if err := testEnv.Get(ctx, client.ObjectKeyFromObject(host), host); err != nil {
+				return err
+			}
+			host.Spec.MaintenanceMode = ptr.To(true)
+			return testEnv.Update(ctx, host)
+		}, timeout).Should(Succeed())
+
+		Eventually(func() bool {
+			if err := testEnv.Get(ctx, key, machineTemplate); err != nil {
+				return false
+			}
+			return machineTemplate.Status.MatchingHosts == 1 && machineTemplate.Status.AvailableHosts == 0
+		}, timeout).Should(BeTrue())
+	})
+})
@@ -26,6 +26,7 @@ import (
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -33,13 +34,17 @@ import (
 
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	secretutil "github.com/syself/cluster-api-provider-hetzner/pkg/secrets"
+	robotclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/robot"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/remediation"
 )
 
 // HetznerBareMetalRemediationReconciler reconciles a HetznerBareMetalRemediation object.
 type HetznerBareMetalRemediationReconciler struct {
 	client.Client
-	WatchFilterValue string
+	APIReader          client.Reader
+	RobotClientFactory robotclient.Factory
+	WatchFilterValue   string
 }
 
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=hetznerbaremetalremediations,verbs=get;list;watch;create;update;patch;delete
@@ -119,6 +124,18 @@ func (r *HetznerBareMetalRemediationReconciler) Reconcile(ctx context.Context, r
 	log = log.WithValues("HetznerCluster", klog.KObj(hetznerCluster))
 	ctx = ctrl.LoggerInto(ctx, log)
 
+	// The robot client is only needed to probe the server before the remediation.
+	var robotClient robotclient.Client
+	if strategy := bareMetalRemediation.Spec.Strategy; strategy != nil && strategy.HealthProbe != nil {
+		secretManager := secretutil.NewSecretManager(log, r.Client, r.APIReader)
+		robotCreds, err := getAndValidateRobotCredentials(ctx, req.Namespace, hetznerCluster, secretManager)
+		if err != nil {
+			record.Warnf(bareMetalRemediation, "FailedGetRobotCredentials", "Cannot probe server: %s", err)
+			return reconcile.Result{}, fmt.Errorf("failed to get robot credentials for health probe: %w", err)
+		}
+		robotClient = r.RobotClientFactory.NewClient(robotCreds)
+	}
+
 	// Create the scope.
 	remediationScope, err := scope.NewBareMetalRemediationScope(scope.BareMetalRemediationScopeParams{
 		Client:               r.Client,
@@ -127,6 +144,7 @@ func (r *HetznerBareMetalRemediationReconciler) Reconcile(ctx context.Context, r
 		BareMetalMachine:     bareMetalMachine,
 		HetznerCluster:       hetznerCluster,
 		BareMetalRemediation: bareMetalRemediation,
+		RobotClient:          robotClient,
 	})
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("failed to create scope: %w", err)
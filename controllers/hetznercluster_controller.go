@@ -59,8 +59,12 @@ import (
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
 	secretutil "github.com/syself/cluster-api-provider-hetzner/pkg/secrets"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/dns"
 	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/controlplaneip"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/firewall"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/loadbalancer"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/natgateway"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/network"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/placementgroup"
 )
@@ -73,6 +77,7 @@ type HetznerClusterReconciler struct {
 	RateLimitWaitTime              time.Duration
 	APIReader                      client.Reader
 	HCloudClientFactory            hcloudclient.Factory
+	DNSProviderFactory             dns.ProviderFactory
 	targetClusterManagersStopCh    map[types.NamespacedName]chan struct{}
 	targetClusterManagersLock      sync.Mutex
 	TargetClusterManagersWaitGroup *sync.WaitGroup
@@ -193,6 +198,12 @@ func (r *HetznerClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 		return reconcile.Result{}, fmt.Errorf("failed to reconcile network for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
 	}
 
+	// reconcile the NAT gateway of the network
+	natGatewayResult, err := natgateway.NewService(clusterScope).Reconcile(ctx)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile NAT gateway for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
 	emptyResult := reconcile.Result{}
 
 	// reconcile the load balancers
@@ -204,13 +215,40 @@ func (r *HetznerClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 		return reconcile.Result{}, fmt.Errorf("failed to reconcile load balancers for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
 	}
 
+	// migrate the control plane load balancer if its region or network has changed
+	loadBalancerMigrationResult, err := loadbalancer.NewService(clusterScope).ReconcileMigration(ctx)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to migrate load balancer for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
+	// reconcile the additional load balancers
+	if err := loadbalancer.NewService(clusterScope).ReconcileAdditionalLoadBalancers(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile additional load balancers for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
 	// reconcile the placement groups
 	if err := placementgroup.NewService(clusterScope).Reconcile(ctx); err != nil {
 		return reconcile.Result{}, fmt.Errorf("failed to reconcile placement groups for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
 	}
 
+	// reconcile the firewalls
+	if err := firewall.NewService(clusterScope).Reconcile(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile firewalls for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
+	// reconcile the floating IP of the control plane endpoint
+	controlPlaneIPResult, err := controlplaneip.NewService(clusterScope).Reconcile(ctx)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile control plane IP for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
 	processControlPlaneEndpoint(hetznerCluster)
 
+	// reconcile the DNS records of the control plane endpoint
+	if err := dns.NewService(clusterScope, r.DNSProviderFactory).Reconcile(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile DNS records for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
 	// delete deprecated conditions of old clusters
 	conditions.Delete(clusterScope.HetznerCluster, infrav1.DeprecatedHetznerClusterTargetClusterReadyCondition)
 
@@ -245,28 +283,30 @@ func (r *HetznerClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 	// target cluster secret is ready
 	conditions.MarkTrue(hetznerCluster, infrav1.TargetClusterSecretReadyCondition)
 
-	return reconcile.Result{}, nil
+	return util.LowestNonZeroResult(util.LowestNonZeroResult(controlPlaneIPResult, loadBalancerMigrationResult), natGatewayResult), nil
 }
 
 func processControlPlaneEndpoint(hetznerCluster *infrav1.HetznerCluster) {
-	if hetznerCluster.Spec.ControlPlaneLoadBalancer.Enabled {
-		if hetznerCluster.Status.ControlPlaneLoadBalancer.IPv4 != "<nil>" {
-			defaultHost := hetznerCluster.Status.ControlPlaneLoadBalancer.IPv4
-			defaultPort := int32(hetznerCluster.Spec.ControlPlaneLoadBalancer.Port) //nolint:gosec // Validation for the port range (1 to 65535) is already done via kubebuilder.
+	defaultPort := int32(hetznerCluster.Spec.ControlPlaneLoadBalancer.Port) //nolint:gosec // Validation for the port range (1 to 65535) is already done via kubebuilder.
 
-			if hetznerCluster.Spec.ControlPlaneEndpoint == nil {
-				hetznerCluster.Spec.ControlPlaneEndpoint = &clusterv1.APIEndpoint{
-					Host: defaultHost,
-					Port: defaultPort,
-				}
-			} else {
-				if hetznerCluster.Spec.ControlPlaneEndpoint.Host == "" {
-					hetznerCluster.Spec.ControlPlaneEndpoint.Host = defaultHost
-				}
-				if hetznerCluster.Spec.ControlPlaneEndpoint.Port == 0 {
-					hetznerCluster.Spec.ControlPlaneEndpoint.Port = defaultPort
-				}
-			}
+	switch {
+	case hetznerCluster.UsesControlPlaneIP():
+		if hetznerCluster.Status.ControlPlaneIP != nil && hetznerCluster.Status.ControlPlaneIP.IPv4 != "" {
+			setDefaultControlPlaneEndpoint(hetznerCluster, hetznerCluster.Status.ControlPlaneIP.IPv4, defaultPort)
+			conditions.MarkTrue(hetznerCluster, infrav1.ControlPlaneEndpointSetCondition)
+			hetznerCluster.Status.Ready = true
+		} else {
+			const msg = "control plane IP not ready yet"
+			conditions.MarkFalse(hetznerCluster,
+				infrav1.ControlPlaneEndpointSetCondition,
+				infrav1.ControlPlaneEndpointNotSetReason,
+				clusterv1.ConditionSeverityWarning,
+				msg)
+			hetznerCluster.Status.Ready = false
+		}
+	case hetznerCluster.Spec.ControlPlaneLoadBalancer.Enabled:
+		if hetznerCluster.Status.ControlPlaneLoadBalancer.IPv4 != "<nil>" {
+			setDefaultControlPlaneEndpoint(hetznerCluster, hetznerCluster.Status.ControlPlaneLoadBalancer.IPv4, defaultPort)
 			conditions.MarkTrue(hetznerCluster, infrav1.ControlPlaneEndpointSetCondition)
 			hetznerCluster.Status.Ready = true
 		} else {
@@ -278,7 +318,7 @@ func processControlPlaneEndpoint(hetznerCluster *infrav1.HetznerCluster) {
 				msg)
 			hetznerCluster.Status.Ready = false
 		}
-	} else {
+	default:
 		if hetznerCluster.Spec.ControlPlaneEndpoint != nil && hetznerCluster.Spec.ControlPlaneEndpoint.Host != "" && hetznerCluster.Spec.ControlPlaneEndpoint.Port != 0 {
 			conditions.MarkTrue(hetznerCluster, infrav1.ControlPlaneEndpointSetCondition)
 			hetznerCluster.Status.Ready = true
@@ -294,6 +334,24 @@ func processControlPlaneEndpoint(hetznerCluster *infrav1.HetznerCluster) {
 	}
 }
 
+// setDefaultControlPlaneEndpoint sets host and port of the control plane endpoint if they are not provided by the user.
+func setDefaultControlPlaneEndpoint(hetznerCluster *infrav1.HetznerCluster, defaultHost string, defaultPort int32) {
+	if hetznerCluster.Spec.ControlPlaneEndpoint == nil {
+		hetznerCluster.Spec.ControlPlaneEndpoint = &clusterv1.APIEndpoint{
+			Host: defaultHost,
+			Port: defaultPort,
+		}
+		return
+	}
+
+	if hetznerCluster.Spec.ControlPlaneEndpoint.Host == "" {
+		hetznerCluster.Spec.ControlPlaneEndpoint.Host = defaultHost
+	}
+	if hetznerCluster.Spec.ControlPlaneEndpoint.Port == 0 {
+		hetznerCluster.Spec.ControlPlaneEndpoint.Port = defaultPort
+	}
+}
+
 func (r *HetznerClusterReconciler) reconcileDelete(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	hetznerCluster := clusterScope.HetznerCluster
 
@@ -340,11 +398,26 @@ func (r *HetznerClusterReconciler) reconcileDelete(ctx context.Context, clusterS
 		}
 	}
 
+	// delete the DNS records of the control plane endpoint
+	if err := dns.NewService(clusterScope, r.DNSProviderFactory).Delete(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to delete DNS records for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
 	// delete load balancers
 	if err := loadbalancer.NewService(clusterScope).Delete(ctx); err != nil {
 		return reconcile.Result{}, fmt.Errorf("failed to delete load balancers for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
 	}
 
+	// delete additional load balancers
+	if err := loadbalancer.NewService(clusterScope).DeleteAdditionalLoadBalancers(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to delete additional load balancers for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
+	// delete the NAT gateway
+	if err := natgateway.NewService(clusterScope).Delete(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to delete NAT gateway for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
 	// delete the network
 	if err := network.NewService(clusterScope).Delete(ctx); err != nil {
 		return reconcile.Result{}, fmt.Errorf("failed to delete network for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
@@ -355,6 +428,16 @@ func (r *HetznerClusterReconciler) reconcileDelete(ctx context.Context, clusterS
 		return reconcile.Result{}, fmt.Errorf("failed to delete placement groups for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
 	}
 
+	// delete the firewalls
+	if err := firewall.NewService(clusterScope).Delete(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to delete firewalls for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
+	// delete the floating IP of the control plane endpoint
+	if err := controlplaneip.NewService(clusterScope).Delete(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to delete control plane IP for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
 	// Stop CSR manager
 	r.targetClusterManagersLock.Lock()
 	defer r.targetClusterManagersLock.Unlock()
@@ -755,6 +838,10 @@ func (r *HetznerClusterReconciler) SetupWithManager(ctx context.Context, mgr ctr
 			handler.EnqueueRequestsFromMapFunc(r.clusterToHetznerCluster),
 			builder.WithPredicates(IgnoreInsignificantClusterStatusUpdates(log)),
 		).
+		Watches(
+			&clusterv1.Machine{},
+			handler.EnqueueRequestsFromMapFunc(r.controlPlaneMachineToHetznerCluster),
+		).
 		Complete(r)
 	if err != nil {
 		return fmt.Errorf("error creating controller: %w", err)
@@ -801,6 +888,41 @@ func (r *HetznerClusterReconciler) clusterToHetznerCluster(ctx context.Context,
 	}
 }
 
+// controlPlaneMachineToHetznerCluster enqueues the HetznerCluster of a control plane machine
+// if the control plane endpoint is provided by a Floating IP.
+func (r *HetznerClusterReconciler) controlPlaneMachineToHetznerCluster(ctx context.Context, o client.Object) []reconcile.Request {
+	m, ok := o.(*clusterv1.Machine)
+	if !ok {
+		panic(fmt.Sprintf("Expected a Machine but got a %T", o))
+	}
+
+	if !util.IsControlPlaneMachine(m) {
+		return nil
+	}
+
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, m.ObjectMeta)
+	if err != nil || cluster.Spec.InfrastructureRef == nil {
+		return nil
+	}
+
+	if cluster.Spec.InfrastructureRef.GroupVersionKind().Kind != "HetznerCluster" {
+		return nil
+	}
+
+	hetznerCluster := &infrav1.HetznerCluster{}
+	key := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Spec.InfrastructureRef.Name}
+
+	if err := r.Get(ctx, key, hetznerCluster); err != nil {
+		return nil
+	}
+
+	if !hetznerCluster.UsesControlPlaneIP() {
+		return nil
+	}
+
+	return []ctrl.Request{{NamespacedName: key}}
+}
+
 // IgnoreInsignificantClusterStatusUpdates is a predicate used for ignoring insignificant HetznerCluster.Status updates.
 func IgnoreInsignificantClusterStatusUpdates(logger logr.Logger) predicate.Funcs {
 	return predicate.Funcs{
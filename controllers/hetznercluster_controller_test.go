@@ -1445,4 +1445,64 @@ func TestSetControlPlaneEndpoint(t *testing.T) {
 			t.Fatalf("return value should be true")
 		}
 	})
+
+	t.Run("return false if control plane IP is used and not ready yet. ControlPlaneEndpoint should not change", func(t *testing.T) {
+		hetznerCluster := &infrav1.HetznerCluster{
+			Spec: infrav1.HetznerClusterSpec{
+				ControlPlaneEndpointType: infrav1.ControlPlaneEndpointTypeFloatingIP,
+				ControlPlaneLoadBalancer: infrav1.LoadBalancerSpec{
+					Enabled: false,
+					Port:    6443,
+				},
+				ControlPlaneEndpoint: nil,
+			},
+		}
+
+		processControlPlaneEndpoint(hetznerCluster)
+
+		if hetznerCluster.Spec.ControlPlaneEndpoint != nil {
+			t.Fatalf("ControlPlaneEndpoint should not change. It should remain nil")
+		}
+
+		if hetznerCluster.Status.Ready != false {
+			t.Fatalf("return value should be false")
+		}
+	})
+
+	t.Run("return true if control plane IP is used and ready. ControlPlaneEndpoint should be set to the IP", func(t *testing.T) {
+		hetznerCluster := &infrav1.HetznerCluster{
+			Spec: infrav1.HetznerClusterSpec{
+				ControlPlaneEndpointType: infrav1.ControlPlaneEndpointTypeFloatingIP,
+				ControlPlaneLoadBalancer: infrav1.LoadBalancerSpec{
+					Enabled: false,
+					Port:    6443,
+				},
+				ControlPlaneEndpoint: nil,
+			},
+			Status: infrav1.HetznerClusterStatus{
+				ControlPlaneIP: &infrav1.ControlPlaneIPStatus{
+					Type: infrav1.ControlPlaneEndpointTypeFloatingIP,
+					IPv4: "198.51.100.1",
+				},
+			},
+		}
+
+		processControlPlaneEndpoint(hetznerCluster)
+
+		if hetznerCluster.Spec.ControlPlaneEndpoint == nil {
+			t.Fatalf("ControlPlaneEndpoint must not be nil")
+		}
+
+		if hetznerCluster.Spec.ControlPlaneEndpoint.Host != "198.51.100.1" {
+			t.Fatalf("Wrong value for Host set. Got: %s, Want: '198.51.100.1'", hetznerCluster.Spec.ControlPlaneEndpoint.Host)
+		}
+
+		if hetznerCluster.Spec.ControlPlaneEndpoint.Port != 6443 {
+			t.Fatalf("Wrong value for Port set. Got: %d, Want: 6443", hetznerCluster.Spec.ControlPlaneEndpoint.Port)
+		}
+
+		if hetznerCluster.Status.Ready != true {
+			t.Fatalf("return value should be true")
+		}
+	})
 }
@@ -47,6 +47,7 @@ import (
 	secretutil "github.com/syself/cluster-api-provider-hetzner/pkg/secrets"
 	robotclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/robot"
 	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/dns"
 	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
 	caphversion "github.com/syself/cluster-api-provider-hetzner/pkg/version"
@@ -172,6 +173,7 @@ func main() {
 		APIReader:                      mgr.GetAPIReader(),
 		RateLimitWaitTime:              rateLimitWaitTime,
 		HCloudClientFactory:            hcloudClientFactory,
+		DNSProviderFactory:             dns.NewFactory(),
 		WatchFilterValue:               watchFilterValue,
 		DisableCSRApproval:             disableCSRApproval,
 		TargetClusterManagersWaitGroup: &wg,
@@ -185,6 +187,7 @@ func main() {
 		APIReader:           mgr.GetAPIReader(),
 		RateLimitWaitTime:   rateLimitWaitTime,
 		HCloudClientFactory: hcloudClientFactory,
+		SSHClientFactory:    sshclient.NewFactory(),
 		WatchFilterValue:    watchFilterValue,
 	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: hcloudMachineConcurrency}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "HCloudMachine")
@@ -202,6 +205,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&controllers.HetznerBareMetalMachineTemplateReconciler{
+		Client:           mgr.GetClient(),
+		WatchFilterValue: watchFilterValue,
+	}).SetupWithManager(ctx, mgr, controller.Options{}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HetznerBareMetalMachineTemplate")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.HetznerBareMetalHostReconciler{
 		Client:              mgr.GetClient(),
 		RobotClientFactory:  robotclient.NewFactory(),
@@ -227,8 +238,10 @@ func main() {
 	}
 
 	if err = (&controllers.HetznerBareMetalRemediationReconciler{
-		Client:           mgr.GetClient(),
-		WatchFilterValue: watchFilterValue,
+		Client:             mgr.GetClient(),
+		APIReader:          mgr.GetAPIReader(),
+		RobotClientFactory: robotclient.NewFactory(),
+		WatchFilterValue:   watchFilterValue,
 	}).SetupWithManager(ctx, mgr, controller.Options{}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "HetznerBareMetalRemediation")
 		os.Exit(1)
@@ -279,7 +292,7 @@ func setUpWebhookWithManager(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create webhook", "webhook", "HetznerClusterTemplate")
 		os.Exit(1)
 	}
-	if err := (&infrastructurev1beta1.HCloudMachine{}).SetupWebhookWithManager(mgr); err != nil {
+	if err := (&infrastructurev1beta1.HCloudMachineWebhook{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "HCloudMachine")
 		os.Exit(1)
 	}
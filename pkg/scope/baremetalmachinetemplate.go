@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2/textlogger"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+// BareMetalMachineTemplateScopeParams defines the input parameters used to create a new scope.
+type BareMetalMachineTemplateScopeParams struct {
+	Client                   client.Client
+	Logger                   *logr.Logger
+	BareMetalMachineTemplate *infrav1.HetznerBareMetalMachineTemplate
+}
+
+// NewBareMetalMachineTemplateScope creates a new Scope from the supplied parameters.
+// This is meant to be called for each reconcile iteration.
+func NewBareMetalMachineTemplateScope(params BareMetalMachineTemplateScopeParams) *BareMetalMachineTemplateScope {
+	if params.Logger == nil {
+		logger := textlogger.NewLogger(textlogger.NewConfig())
+		params.Logger = &logger
+	}
+
+	return &BareMetalMachineTemplateScope{
+		Logger:                   params.Logger,
+		Client:                   params.Client,
+		BareMetalMachineTemplate: params.BareMetalMachineTemplate,
+	}
+}
+
+// BareMetalMachineTemplateScope defines the basic context for an actuator to operate upon.
+type BareMetalMachineTemplateScope struct {
+	*logr.Logger
+	Client client.Client
+
+	BareMetalMachineTemplate *infrav1.HetznerBareMetalMachineTemplate
+}
+
+// Name returns the HetznerBareMetalMachineTemplate name.
+func (s *BareMetalMachineTemplateScope) Name() string {
+	return s.BareMetalMachineTemplate.Name
+}
+
+// Namespace returns the namespace name.
+func (s *BareMetalMachineTemplateScope) Namespace() string {
+	return s.BareMetalMachineTemplate.Namespace
+}
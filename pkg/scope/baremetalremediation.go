@@ -27,6 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	robotclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/robot"
 )
 
 // BareMetalRemediationScopeParams defines the input parameters used to create a new Scope.
@@ -37,6 +38,8 @@ type BareMetalRemediationScopeParams struct {
 	BareMetalMachine     *infrav1.HetznerBareMetalMachine
 	HetznerCluster       *infrav1.HetznerCluster
 	BareMetalRemediation *infrav1.HetznerBareMetalRemediation
+	// RobotClient is only needed for the health probe of the remediation strategy.
+	RobotClient robotclient.Client
 }
 
 // NewBareMetalRemediationScope creates a new Scope from the supplied parameters.
@@ -67,6 +70,7 @@ func NewBareMetalRemediationScope(params BareMetalRemediationScopeParams) (*Bare
 		Machine:              params.Machine,
 		BareMetalMachine:     params.BareMetalMachine,
 		BareMetalRemediation: params.BareMetalRemediation,
+		RobotClient:          params.RobotClient,
 	}, nil
 }
 
@@ -78,6 +82,7 @@ type BareMetalRemediationScope struct {
 	Machine              *clusterv1.Machine
 	BareMetalMachine     *infrav1.HetznerBareMetalMachine
 	BareMetalRemediation *infrav1.HetznerBareMetalRemediation
+	RobotClient          robotclient.Client
 }
 
 // Close closes the current scope persisting the cluster configuration and status.
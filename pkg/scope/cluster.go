@@ -184,6 +184,26 @@ func (s *ClusterScope) ClientConfigWithAPIEndpoint(ctx context.Context, endpoint
 	return clientcmd.NewDefaultClientConfig(raw, &clientcmd.ConfigOverrides{}), nil
 }
 
+// ListHCloudMachinesInCreation returns the HCloudMachines of the cluster that reference the given placement
+// group and have no server yet.
+func (s *ClusterScope) ListHCloudMachinesInCreation(ctx context.Context, placementGroupName string) ([]infrav1.HCloudMachine, error) {
+	var hcloudMachineList infrav1.HCloudMachineList
+	if err := s.Client.List(ctx, &hcloudMachineList, client.InNamespace(s.Namespace()),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: s.Cluster.Name}); err != nil {
+		return nil, err
+	}
+
+	hcloudMachines := make([]infrav1.HCloudMachine, 0, len(hcloudMachineList.Items))
+	for _, hm := range hcloudMachineList.Items {
+		if hm.Spec.PlacementGroupName == nil || *hm.Spec.PlacementGroupName != placementGroupName ||
+			hm.Spec.ProviderID != nil || !hm.DeletionTimestamp.IsZero() {
+			continue
+		}
+		hcloudMachines = append(hcloudMachines, hm)
+	}
+	return hcloudMachines, nil
+}
+
 // ListMachines returns HCloudMachines.
 func (s *ClusterScope) ListMachines(ctx context.Context) ([]*clusterv1.Machine, []*infrav1.HCloudMachine, error) {
 	// get and index Machines by HCloudMachine name
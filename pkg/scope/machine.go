@@ -35,13 +35,15 @@ import (
 
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 	secretutil "github.com/syself/cluster-api-provider-hetzner/pkg/secrets"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
 )
 
 // MachineScopeParams defines the input parameters used to create a new Scope.
 type MachineScopeParams struct {
 	ClusterScopeParams
-	Machine       *clusterv1.Machine
-	HCloudMachine *infrav1.HCloudMachine
+	Machine          *clusterv1.Machine
+	HCloudMachine    *infrav1.HCloudMachine
+	SSHClientFactory sshclient.Factory
 }
 
 const maxShutDownTime = 2 * time.Minute
@@ -80,9 +82,10 @@ func NewMachineScope(params MachineScopeParams) (*MachineScope, error) {
 	}
 
 	return &MachineScope{
-		ClusterScope:  *cs,
-		Machine:       params.Machine,
-		HCloudMachine: params.HCloudMachine,
+		ClusterScope:     *cs,
+		Machine:          params.Machine,
+		HCloudMachine:    params.HCloudMachine,
+		SSHClientFactory: params.SSHClientFactory,
 	}, nil
 }
 
@@ -91,6 +94,9 @@ type MachineScope struct {
 	ClusterScope
 	Machine       *clusterv1.Machine
 	HCloudMachine *infrav1.HCloudMachine
+
+	// SSHClientFactory creates the SSH clients that are used to collect diagnostics in the rescue system.
+	SSHClientFactory sshclient.Factory
 }
 
 // Close closes the current scope persisting the cluster configuration and status.
@@ -36,7 +36,6 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
@@ -644,24 +643,7 @@ func (s *Service) removeAttachedServerOfLoadBalancer(ctx context.Context, host *
 }
 
 func (s *Service) getLabelSelector() labels.Selector {
-	labelSelector := labels.NewSelector()
-	var reqs labels.Requirements
-
-	for labelKey, labelVal := range s.scope.BareMetalMachine.Spec.HostSelector.MatchLabels {
-		r, err := labels.NewRequirement(labelKey, selection.Equals, []string{labelVal})
-		if err == nil { // ignore invalid host selector
-			reqs = append(reqs, *r)
-		}
-	}
-	for _, req := range s.scope.BareMetalMachine.Spec.HostSelector.MatchExpressions {
-		lowercaseOperator := selection.Operator(strings.ToLower(string(req.Operator)))
-		r, err := labels.NewRequirement(req.Key, lowercaseOperator, req.Values)
-		if err == nil { // ignore invalid host selector
-			reqs = append(reqs, *r)
-		}
-	}
-
-	return labelSelector.Add(reqs...)
+	return s.scope.BareMetalMachine.Spec.HostSelector.LabelSelector()
 }
 
 func (s *Service) setProviderID(ctx context.Context) error {
@@ -599,6 +599,51 @@ func (_c *Client_GetCloudInitOutput_Call) RunAndReturn(run func() sshclient.Outp
 	return _c
 }
 
+// GetCloudInitOutputOfInstalledOS provides a mock function with given fields:
+func (_m *Client) GetCloudInitOutputOfInstalledOS() sshclient.Output {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCloudInitOutputOfInstalledOS")
+	}
+
+	var r0 sshclient.Output
+	if rf, ok := ret.Get(0).(func() sshclient.Output); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(sshclient.Output)
+	}
+
+	return r0
+}
+
+// Client_GetCloudInitOutputOfInstalledOS_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCloudInitOutputOfInstalledOS'
+type Client_GetCloudInitOutputOfInstalledOS_Call struct {
+	*mock.Call
+}
+
+// GetCloudInitOutputOfInstalledOS is a helper method to define mock.On call
+func (_e *Client_Expecter) GetCloudInitOutputOfInstalledOS() *Client_GetCloudInitOutputOfInstalledOS_Call {
+	return &Client_GetCloudInitOutputOfInstalledOS_Call{Call: _e.mock.On("GetCloudInitOutputOfInstalledOS")}
+}
+
+func (_c *Client_GetCloudInitOutputOfInstalledOS_Call) Run(run func()) *Client_GetCloudInitOutputOfInstalledOS_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Client_GetCloudInitOutputOfInstalledOS_Call) Return(_a0 sshclient.Output) *Client_GetCloudInitOutputOfInstalledOS_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_GetCloudInitOutputOfInstalledOS_Call) RunAndReturn(run func() sshclient.Output) *Client_GetCloudInitOutputOfInstalledOS_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetHardwareDetailsCPUArch provides a mock function with given fields:
 func (_m *Client) GetHardwareDetailsCPUArch() sshclient.Output {
 	ret := _m.Called()
@@ -1149,6 +1194,51 @@ func (_c *Client_GetInstallImageState_Call) RunAndReturn(run func() (sshclient.I
 	return _c
 }
 
+// GetJournalOfInstalledOS provides a mock function with given fields:
+func (_m *Client) GetJournalOfInstalledOS() sshclient.Output {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJournalOfInstalledOS")
+	}
+
+	var r0 sshclient.Output
+	if rf, ok := ret.Get(0).(func() sshclient.Output); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(sshclient.Output)
+	}
+
+	return r0
+}
+
+// Client_GetJournalOfInstalledOS_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJournalOfInstalledOS'
+type Client_GetJournalOfInstalledOS_Call struct {
+	*mock.Call
+}
+
+// GetJournalOfInstalledOS is a helper method to define mock.On call
+func (_e *Client_Expecter) GetJournalOfInstalledOS() *Client_GetJournalOfInstalledOS_Call {
+	return &Client_GetJournalOfInstalledOS_Call{Call: _e.mock.On("GetJournalOfInstalledOS")}
+}
+
+func (_c *Client_GetJournalOfInstalledOS_Call) Run(run func()) *Client_GetJournalOfInstalledOS_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Client_GetJournalOfInstalledOS_Call) Return(_a0 sshclient.Output) *Client_GetJournalOfInstalledOS_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_GetJournalOfInstalledOS_Call) RunAndReturn(run func() sshclient.Output) *Client_GetJournalOfInstalledOS_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetResultOfInstallImage provides a mock function with given fields:
 func (_m *Client) GetResultOfInstallImage() (string, error) {
 	ret := _m.Called()
@@ -174,6 +174,8 @@ var (
 	ErrTimeout = errors.New("i/o timeout")
 	// ErrCheckDiskBrokenDisk means that a disk seams broken.
 	ErrCheckDiskBrokenDisk = errors.New("CheckDisk failed")
+	// ErrNoPersistentJournal means that the installed operating system keeps its journal only in memory.
+	ErrNoPersistentJournal = errors.New("no persistent journal found in /var/log/journal of the installed operating system")
 	errSSHDialFailed       = errors.New("failed to dial ssh")
 )
 
@@ -256,6 +258,8 @@ type Client interface {
 	GetInstallImageState() (InstallImageState, error)
 	GetResultOfInstallImage() (string, error)
 	GetCloudInitOutput() Output
+	GetCloudInitOutputOfInstalledOS() Output
+	GetJournalOfInstalledOS() Output
 	CreateAutoSetup(data string) Output
 	DownloadImage(path, url string) Output
 	CreatePostInstallScript(data string) Output
@@ -367,6 +371,39 @@ func (c *sshClient) GetCloudInitOutput() Output {
 	return out
 }
 
+// mountInstalledOS mounts the root filesystem of the installed operating system read-only to /mnt. The rescue
+// system does not mount the disks of the server.
+const mountInstalledOS = `mountpoint -q /mnt || for part in $(lsblk -lnpo NAME,TYPE | awk '$2 == "part" {print $1}'); do
+	mount -o ro "$part" /mnt 2>/dev/null || continue
+	[ -e /mnt/etc/os-release ] && break
+	umount /mnt
+done
+mountpoint -q /mnt || { echo "root filesystem of the installed operating system not found" >&2; exit 1; }
+`
+
+// GetCloudInitOutputOfInstalledOS implements the GetCloudInitOutputOfInstalledOS method of the SSHClient interface.
+func (c *sshClient) GetCloudInitOutputOfInstalledOS() Output {
+	out := c.runSSH(mountInstalledOS + `cat /mnt/var/log/cloud-init-output.log`)
+	if out.Err == nil {
+		out.StdOut = removeUselessLinesFromCloudInitOutput(out.StdOut)
+	}
+	return out
+}
+
+// noPersistentJournalExitStatus is the exit status of GetJournalOfInstalledOS if /var/log/journal does not exist.
+const noPersistentJournalExitStatus = 3
+
+// GetJournalOfInstalledOS implements the GetJournalOfInstalledOS method of the SSHClient interface.
+// ErrNoPersistentJournal is returned if the journal of the installed operating system is volatile.
+func (c *sshClient) GetJournalOfInstalledOS() Output {
+	out := c.runSSH(mountInstalledOS + fmt.Sprintf(`[ -d /mnt/var/log/journal ] || exit %d
+journalctl --directory=/mnt/var/log/journal --no-pager --lines=1000`, noPersistentJournalExitStatus))
+	if exitStatus, err := out.ExitStatus(); err == nil && exitStatus == noPersistentJournalExitStatus {
+		out.Err = ErrNoPersistentJournal
+	}
+	return out
+}
+
 // GetHardwareDetailsCPUThreads implements the GetHardwareDetailsCPUThreads method of the SSHClient interface.
 func (c *sshClient) GetHardwareDetailsCPUThreads() Output {
 	return c.runSSH(`lscpu | grep "CPU(s):" | head -1 |  awk '{ print $2}'`)
@@ -1812,12 +1812,20 @@ func (s *Service) actionProvisioned(_ context.Context) actionResult {
 			return actionContinue{delay: 10 * time.Second}
 		}
 		// Reboot now
-		out := sshClient.Reboot()
-		if err := handleSSHError(out); err != nil {
-			return actionError{err: err}
+		rebootType := rebootTypeFromAnnotation(s.scope.HetznerBareMetalHost)
+		if rebootType == infrav1.RebootTypeSSH {
+			out := sshClient.Reboot()
+			if err := handleSSHError(out); err != nil {
+				return actionError{err: err}
+			}
+		} else {
+			if _, err := s.scope.RobotClient.RebootBMServer(s.scope.HetznerBareMetalHost.Spec.ServerID, rebootType); err != nil {
+				s.handleRobotRateLimitExceeded(err, rebootServerStr)
+				return actionError{err: fmt.Errorf(errMsgFailedReboot, err)}
+			}
 		}
 
-		createSSHRebootEvent(s.scope.HetznerBareMetalHost, "Rebooting because annotation was set")
+		createRebootEvent(s.scope.HetznerBareMetalHost, rebootType, "Rebooting because annotation was set")
 		s.scope.HetznerBareMetalHost.Spec.Status.Rebooted = true
 		return actionContinue{delay: 10 * time.Second}
 	}
@@ -1825,6 +1833,23 @@ func (s *Service) actionProvisioned(_ context.Context) actionResult {
 	return actionComplete{} // Stays in Provisioned (final state)
 }
 
+// rebootTypeFromAnnotation returns the reboot type requested with the reboot annotation of the host.
+// Software, hardware and power resets are done via the Robot API if it has been requested explicitly.
+// All other values reboot via SSH.
+func rebootTypeFromAnnotation(host *infrav1.HetznerBareMetalHost) infrav1.RebootType {
+	var args infrav1.RebootAnnotationArguments
+	if err := json.Unmarshal([]byte(host.Annotations[infrav1.RebootAnnotation]), &args); err != nil || !args.Robot {
+		return infrav1.RebootTypeSSH
+	}
+
+	switch args.Type {
+	case infrav1.RebootTypeSoftware, infrav1.RebootTypeHardware, infrav1.RebootTypePower:
+		return args.Type
+	default:
+		return infrav1.RebootTypeSSH
+	}
+}
+
 // next: None
 func (s *Service) actionDeprovisioning(_ context.Context) actionResult {
 	// Update name in robot API
@@ -1823,3 +1823,49 @@ var _ = Describe("actionProvisioned", func() {
 		}),
 	)
 })
+
+var _ = Describe("actionProvisioned with reboot type", func() {
+	DescribeTable("rebootTypeFromAnnotation",
+		func(annotation string, expectedRebootType infrav1.RebootType) {
+			host := helpers.BareMetalHost("test-host", "default")
+			host.SetAnnotations(map[string]string{infrav1.RebootAnnotation: annotation})
+			Expect(rebootTypeFromAnnotation(host)).To(Equal(expectedRebootType))
+		},
+		Entry("no arguments", "reboot", infrav1.RebootTypeSSH),
+		Entry("ssh", `{"type":"ssh"}`, infrav1.RebootTypeSSH),
+		Entry("hardware without robot", `{"type":"hw"}`, infrav1.RebootTypeSSH),
+		Entry("software", `{"type":"sw","robot":true}`, infrav1.RebootTypeSoftware),
+		Entry("hardware", `{"type":"hw","robot":true}`, infrav1.RebootTypeHardware),
+		Entry("power", `{"type":"power","robot":true}`, infrav1.RebootTypePower),
+		Entry("manual", `{"type":"man","robot":true}`, infrav1.RebootTypeSSH),
+	)
+
+	DescribeTable("reboots via robot API",
+		func(rebootType infrav1.RebootType) {
+			ctx := context.Background()
+			host := helpers.BareMetalHost(
+				"test-host",
+				"default",
+				helpers.WithSSHSpecInclPorts(23, 24),
+				helpers.WithIPv4(),
+				helpers.WithConsumerRef(),
+			)
+			host.SetAnnotations(map[string]string{infrav1.RebootAnnotation: fmt.Sprintf(`{"type":%q,"robot":true}`, rebootType)})
+
+			sshMock := &sshmock.Client{}
+			robotMock := robotmock.Client{}
+			robotMock.On("RebootBMServer", mock.Anything, mock.Anything).Return(nil, nil)
+
+			service := newTestService(host, &robotMock, bmmock.NewSSHFactory(sshMock, sshMock, sshMock), helpers.GetDefaultSSHSecret(osSSHKeyName, "default"), helpers.GetDefaultSSHSecret(rescueSSHKeyName, "default"))
+
+			actResult := service.actionProvisioned(ctx)
+			Expect(actResult).Should(BeAssignableToTypeOf(actionContinue{}))
+			Expect(host.Spec.Status.Rebooted).To(BeTrue())
+			Expect(host.HasRebootAnnotation()).To(BeTrue())
+			Expect(robotMock.AssertCalled(GinkgoT(), "RebootBMServer", host.Spec.ServerID, rebootType)).To(BeTrue())
+			Expect(sshMock.AssertNotCalled(GinkgoT(), "Reboot")).To(BeTrue())
+		},
+		Entry("software reset", infrav1.RebootTypeSoftware),
+		Entry("hardware reset", infrav1.RebootTypeHardware),
+	)
+})
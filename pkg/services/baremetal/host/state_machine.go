@@ -337,6 +337,17 @@ func (hsm *hostStateMachine) handleProvisioned(ctx context.Context) actionResult
 		hsm.nextState = infrav1.StateDeprovisioning
 		return actionComplete{}
 	}
+
+	if _, reprovision := hsm.host.Annotations[infrav1.ReprovisionAnnotation]; reprovision {
+		// Provision the host again with the same InstallImage. Pending reboots are obsolete.
+		delete(hsm.host.Annotations, infrav1.ReprovisionAnnotation)
+		hsm.host.ClearRebootAnnotations()
+		hsm.host.Spec.Status.Rebooted = false
+		record.Eventf(hsm.host, "ReprovisioningHost", "Provisioning host again because annotation %s was set", infrav1.ReprovisionAnnotation)
+		hsm.nextState = infrav1.StatePreparing
+		return actionComplete{}
+	}
+
 	return hsm.reconciler.actionProvisioned(ctx)
 }
 
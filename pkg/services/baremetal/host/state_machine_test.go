@@ -17,6 +17,7 @@ limitations under the License.
 package host
 
 import (
+	"context"
 	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -230,3 +231,33 @@ var _ = Describe("updateSSHKey", func() {
 		}),
 	)
 })
+
+var _ = Describe("handleProvisioned", func() {
+	It("starts provisioning again with the same image if the reprovision annotation is set", func() {
+		host := helpers.BareMetalHost(
+			"test-host",
+			"default",
+			helpers.WithSSHSpecInclPorts(23, 24),
+			helpers.WithIPv4(),
+			helpers.WithConsumerRef(),
+		)
+		installImage := &infrav1.InstallImage{Image: infrav1.Image{Name: "ubuntu", URL: "https://example.com/ubuntu.tar.gz"}}
+		host.Spec.Status.InstallImage = installImage
+		host.Spec.Status.ProvisioningState = infrav1.StateProvisioned
+		host.Spec.Status.Rebooted = true
+		host.SetAnnotations(map[string]string{
+			infrav1.ReprovisionAnnotation: "",
+			infrav1.RebootAnnotation:      `{"type":"hw"}`,
+		})
+
+		service := newTestService(host, nil, nil, helpers.GetDefaultSSHSecret(osSSHKeyName, "default"), helpers.GetDefaultSSHSecret(rescueSSHKeyName, "default"))
+		hsm := newTestHostStateMachine(host, service)
+
+		Expect(hsm.handleProvisioned(context.Background())).Should(BeAssignableToTypeOf(actionComplete{}))
+		Expect(hsm.nextState).To(Equal(infrav1.StatePreparing))
+		Expect(host.Annotations).ToNot(HaveKey(infrav1.ReprovisionAnnotation))
+		Expect(host.HasRebootAnnotation()).To(BeFalse())
+		Expect(host.Spec.Status.Rebooted).To(BeFalse())
+		Expect(host.Spec.Status.InstallImage).To(Equal(installImage))
+	})
+})
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package machinetemplate implements functions to report the capacity of HetznerBareMetalMachineTemplates.
+package machinetemplate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+)
+
+// Service defines struct with BareMetalMachineTemplate scope to reconcile HetznerBareMetalMachineTemplates.
+type Service struct {
+	scope *scope.BareMetalMachineTemplateScope
+}
+
+// NewService outs a new service with BareMetalMachineTemplate scope.
+func NewService(scope *scope.BareMetalMachineTemplateScope) *Service {
+	return &Service{
+		scope: scope,
+	}
+}
+
+// Reconcile evaluates the host selector of the template against the HetznerBareMetalHosts of its namespace and
+// reports the minimum capacity of the matching hosts and the number of hosts that are still available.
+func (s *Service) Reconcile(ctx context.Context) error {
+	template := s.scope.BareMetalMachineTemplate
+
+	hosts := infrav1.HetznerBareMetalHostList{}
+	if err := s.scope.Client.List(ctx, &hosts, client.InNamespace(template.Namespace)); err != nil {
+		return fmt.Errorf("failed to list hosts: %w", err)
+	}
+
+	selector := template.Spec.Template.Spec.HostSelector.LabelSelector()
+
+	var (
+		matching  int
+		available int
+		capacity  corev1.ResourceList
+		nodeInfo  *infrav1.NodeInfo
+	)
+	for i := range hosts.Items {
+		host := &hosts.Items[i]
+		if !selector.Matches(labels.Set(host.Labels)) || host.DeletionTimestamp != nil {
+			continue
+		}
+
+		matching++
+		if isAvailable(host) {
+			available++
+		}
+
+		details := host.Spec.Status.HardwareDetails
+		if details == nil {
+			// hosts that have never been provisioned have no hardware details
+			continue
+		}
+		capacity = minCapacity(capacity, hostCapacity(host))
+		if nodeInfo == nil {
+			nodeInfo = &infrav1.NodeInfo{
+				Architecture:    nodeArchitecture(details.CPU.Arch),
+				OperatingSystem: "linux",
+			}
+		}
+	}
+
+	status := &template.Status
+	if status.LastCapacityUpdate != nil &&
+		status.MatchingHosts == matching &&
+		status.AvailableHosts == available &&
+		apiequality.Semantic.DeepEqual(status.Capacity, capacity) &&
+		apiequality.Semantic.DeepEqual(status.NodeInfo, nodeInfo) {
+		// writing the timestamp would change the status on every reconcile, which triggers the next reconcile
+		return nil
+	}
+
+	status.MatchingHosts = matching
+	status.AvailableHosts = available
+	status.Capacity = capacity
+	status.NodeInfo = nodeInfo
+	now := metav1.Now()
+	status.LastCapacityUpdate = &now
+	return nil
+}
+
+// isAvailable returns whether a machine could claim the host. Mirrors the checks of the HetznerBareMetalMachine
+// controller that do not depend on the machine.
+func isAvailable(host *infrav1.HetznerBareMetalHost) bool {
+	return host.Spec.ConsumerRef == nil &&
+		(host.Spec.MaintenanceMode == nil || !*host.Spec.MaintenanceMode) &&
+		host.Spec.Status.ErrorMessage == "" &&
+		host.Spec.Status.ProvisioningState == infrav1.StateNone
+}
+
+// hostCapacity returns the CPU threads, the RAM and the size of the root disk of the host.
+func hostCapacity(host *infrav1.HetznerBareMetalHost) corev1.ResourceList {
+	details := host.Spec.Status.HardwareDetails
+
+	capacity := corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewQuantity(int64(details.CPU.Threads), resource.DecimalSI),
+		corev1.ResourceMemory: resource.MustParse(fmt.Sprintf("%dG", details.RAMGB)),
+	}
+	if disk := rootDiskBytes(host); disk > 0 {
+		capacity[corev1.ResourceEphemeralStorage] = *resource.NewQuantity(disk, resource.DecimalSI)
+	}
+	return capacity
+}
+
+// rootDiskBytes returns the size of the root disk. If the root device hints are set, the smallest of the referenced
+// disks is used, as they form a RAID. Otherwise, the smallest disk of the host is used, as any disk could become
+// the root disk.
+func rootDiskBytes(host *infrav1.HetznerBareMetalHost) int64 {
+	var wwns []string
+	if hints := host.Spec.RootDeviceHints; hints != nil {
+		if hints.WWN != "" {
+			wwns = []string{hints.WWN}
+		} else {
+			wwns = hints.Raid.WWN
+		}
+	}
+
+	var size int64
+	for _, storage := range host.Spec.Status.HardwareDetails.Storage {
+		if len(wwns) > 0 && !slices.Contains(wwns, storage.WWN) {
+			continue
+		}
+		if bytes := int64(storage.SizeBytes); size == 0 || bytes < size {
+			size = bytes
+		}
+	}
+	return size
+}
+
+// minCapacity returns the minimum of each resource of both lists. Resources that are missing in one of the lists
+// are not guaranteed and therefore dropped.
+func minCapacity(a, b corev1.ResourceList) corev1.ResourceList {
+	if a == nil {
+		return b
+	}
+
+	result := make(corev1.ResourceList, len(a))
+	for name, quantity := range a {
+		other, found := b[name]
+		if !found {
+			continue
+		}
+		if other.Cmp(quantity) < 0 {
+			quantity = other
+		}
+		result[name] = quantity
+	}
+	return result
+}
+
+// nodeArchitecture returns the architecture of Kubernetes nodes for the CPU architecture reported by the host.
+func nodeArchitecture(arch string) string {
+	switch arch {
+	case "x86_64", "amd64":
+		return "amd64"
+	case "aarch64", "arm64":
+		return "arm64"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinetemplate
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+)
+
+const (
+	wwn1 = "eui.002538b411b2cee8"
+	wwn2 = "eui.0025388801b4dff2"
+)
+
+func newHost(name, poolLabel string, threads, ramGB int, diskSizes ...int64) *infrav1.HetznerBareMetalHost {
+	host := &infrav1.HetznerBareMetalHost{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"pool": poolLabel},
+		},
+		Spec: infrav1.HetznerBareMetalHostSpec{
+			Status: infrav1.ControllerGeneratedStatus{
+				ProvisioningState: infrav1.StateNone,
+			},
+		},
+	}
+	if threads == 0 {
+		return host
+	}
+
+	details := &infrav1.HardwareDetails{
+		RAMGB: ramGB,
+		CPU:   infrav1.CPU{Arch: "x86_64", Threads: threads},
+	}
+	for i, size := range diskSizes {
+		details.Storage = append(details.Storage, infrav1.Storage{
+			Name:      "/dev/sd" + string(rune('a'+i)),
+			SizeBytes: infrav1.Capacity(size),
+			WWN:       []string{wwn1, wwn2}[i%2],
+		})
+	}
+	host.Spec.Status.HardwareDetails = details
+	return host
+}
+
+var _ = Describe("Reconcile", func() {
+	var template *infrav1.HetznerBareMetalMachineTemplate
+
+	BeforeEach(func() {
+		template = &infrav1.HetznerBareMetalMachineTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: "default"},
+			Spec: infrav1.HetznerBareMetalMachineTemplateSpec{
+				Template: infrav1.HetznerBareMetalMachineTemplateResource{
+					Spec: infrav1.HetznerBareMetalMachineSpec{
+						HostSelector: infrav1.HostSelector{MatchLabels: map[string]string{"pool": "workers"}},
+					},
+				},
+			},
+		}
+	})
+
+	reconcile := func(hosts ...client.Object) {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(infrav1.AddToScheme(scheme))
+		c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(hosts...).Build()
+
+		service := NewService(scope.NewBareMetalMachineTemplateScope(scope.BareMetalMachineTemplateScopeParams{
+			Client:                   c,
+			BareMetalMachineTemplate: template,
+		}))
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+	}
+
+	It("reports the minimum capacity of the matching hosts", func() {
+		reconcile(
+			newHost("small", "workers", 8, 64, 500e9, 1000e9),
+			newHost("large", "workers", 16, 128, 2000e9, 2000e9),
+			newHost("other-pool", "control-planes", 4, 32, 100e9),
+		)
+
+		status := template.Status
+		Expect(status.MatchingHosts).To(Equal(2))
+		Expect(status.AvailableHosts).To(Equal(2))
+		Expect(status.Capacity.Cpu().String()).To(Equal("8"))
+		Expect(status.Capacity.Memory().String()).To(Equal("64G"))
+		Expect(status.Capacity.StorageEphemeral().String()).To(Equal("500G"))
+		Expect(status.NodeInfo).To(Equal(&infrav1.NodeInfo{Architecture: "amd64", OperatingSystem: "linux"}))
+		Expect(status.LastCapacityUpdate).ToNot(BeNil())
+	})
+
+	It("keeps the time of the last update if nothing changed", func() {
+		host := newHost("host", "workers", 8, 64, 500e9)
+		reconcile(host)
+
+		lastUpdate := metav1.NewTime(time.Now().Add(-time.Hour))
+		template.Status.LastCapacityUpdate = &lastUpdate
+		reconcile(host)
+		Expect(template.Status.LastCapacityUpdate).To(Equal(&lastUpdate))
+
+		host.Spec.ConsumerRef = &corev1.ObjectReference{Name: "machine"}
+		reconcile(host)
+		Expect(template.Status.AvailableHosts).To(Equal(0))
+		Expect(template.Status.LastCapacityUpdate.After(lastUpdate.Time)).To(BeTrue())
+	})
+
+	It("uses the disks of the root device hints as root disk", func() {
+		host := newHost("host", "workers", 8, 64, 500e9, 1000e9)
+		host.Spec.RootDeviceHints = &infrav1.RootDeviceHints{WWN: wwn2}
+		reconcile(host)
+		Expect(template.Status.Capacity.StorageEphemeral().String()).To(Equal("1T"))
+
+		host.Spec.RootDeviceHints = &infrav1.RootDeviceHints{Raid: infrav1.Raid{WWN: []string{wwn1, wwn2}}}
+		reconcile(host)
+		Expect(template.Status.Capacity.StorageEphemeral().String()).To(Equal("500G"))
+	})
+
+	It("counts only hosts that can be claimed as available", func() {
+		inUse := newHost("in-use", "workers", 8, 64, 500e9)
+		inUse.Spec.ConsumerRef = &corev1.ObjectReference{Name: "machine"}
+		inUse.Spec.Status.ProvisioningState = infrav1.StateProvisioned
+
+		inMaintenance := newHost("in-maintenance", "workers", 8, 64, 500e9)
+		inMaintenance.Spec.MaintenanceMode = ptr.To(true)
+
+		withError := newHost("with-error", "workers", 8, 64, 500e9)
+		withError.Spec.Status.ErrorMessage = "failed"
+
+		reconcile(inUse, inMaintenance, withError, newHost("free", "workers", 8, 64, 500e9))
+		Expect(template.Status.MatchingHosts).To(Equal(4))
+		Expect(template.Status.AvailableHosts).To(Equal(1))
+	})
+
+	It("ignores hosts without hardware details for the capacity", func() {
+		reconcile(newHost("unregistered", "workers", 0, 0))
+		Expect(template.Status.MatchingHosts).To(Equal(1))
+		Expect(template.Status.AvailableHosts).To(Equal(1))
+		Expect(template.Status.Capacity).To(BeNil())
+		Expect(template.Status.NodeInfo).To(BeNil())
+	})
+
+	It("drops resources that are not reported by all hosts", func() {
+		reconcile(
+			newHost("with-disk", "workers", 8, 64, 500e9),
+			newHost("without-disk", "workers", 8, 64),
+		)
+		Expect(template.Status.Capacity).ToNot(HaveKey(corev1.ResourceEphemeralStorage))
+		Expect(template.Status.Capacity).To(HaveKey(corev1.ResourceCPU))
+	})
+})
@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+// reprovisionRequeueAfter is the interval in which a host is checked until it is provisioned again.
+const reprovisionRequeueAfter = 30 * time.Second
+
+// rebootTypes maps the reboot steps of an escalating remediation to the reboot type of the host.
+var rebootTypes = map[infrav1.RemediationStepType]infrav1.RebootType{
+	infrav1.RemediationStepTypeReboot:        infrav1.RebootTypeSSH,
+	infrav1.RemediationStepTypeSoftwareReset: infrav1.RebootTypeSoftware,
+	infrav1.RemediationStepTypeHardwareReset: infrav1.RebootTypeHardware,
+}
+
+// handleEscalation executes the steps of an escalating remediation one after another. Each step is retried until
+// its retry limit is reached before the next step is executed. If the machine is still unhealthy after the last
+// step, it is handed back to CAPI for replacement. Once the machine is healthy again, CAPI deletes the remediation.
+func (s *Service) handleEscalation(ctx context.Context, host infrav1.HetznerBareMetalHost) (reconcile.Result, error) {
+	remediation := s.scope.BareMetalRemediation
+	strategy := remediation.Spec.Strategy
+	steps := strategy.EscalationSteps(infrav1.DefaultBareMetalRemediationSteps)
+
+	if remediation.Status.CurrentStep >= len(steps) {
+		return reconcile.Result{}, s.replaceMachine(ctx, "all remediation steps have been executed")
+	}
+
+	step := steps[remediation.Status.CurrentStep]
+	now := time.Now()
+
+	if remediation.Status.LastRemediated != nil {
+		if step.Type == infrav1.RemediationStepTypeRebuild && !s.completeReprovisioning(host) {
+			return reconcile.Result{RequeueAfter: reprovisionRequeueAfter}, nil
+		}
+
+		// give the machine the time of the step to become healthy
+		if wait := remediation.Status.LastRemediated.Add(strategy.StepTimeout(step)).Sub(now); wait > 0 {
+			return reconcile.Result{RequeueAfter: wait + time.Second}, nil
+		}
+	}
+
+	// escalate to the next step if the current one has been executed often enough
+	if remediation.Status.RetryCount >= step.Attempts() {
+		remediation.Status.CurrentStep++
+		remediation.Status.RetryCount = 0
+		if remediation.Status.CurrentStep >= len(steps) {
+			return reconcile.Result{}, s.replaceMachine(ctx, "machine is still unhealthy after all remediation steps")
+		}
+		step = steps[remediation.Status.CurrentStep]
+	}
+
+	if err := s.executeStep(ctx, host, step.Type); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	remediation.Status.RetryCount++
+	remediation.Status.LastRemediated = &metav1.Time{Time: now}
+
+	stepStatus := infrav1.RemediationStepStatus{
+		Type:    step.Type,
+		Attempt: remediation.Status.RetryCount,
+		Started: metav1.Time{Time: now},
+	}
+	if step.Type != infrav1.RemediationStepTypeRebuild {
+		stepStatus.Finished = &stepStatus.Started
+	}
+	remediation.Status.Steps = append(remediation.Status.Steps, stepStatus)
+
+	if step.Type == infrav1.RemediationStepTypeRebuild {
+		return reconcile.Result{RequeueAfter: reprovisionRequeueAfter}, nil
+	}
+	return reconcile.Result{RequeueAfter: strategy.StepTimeout(step) + time.Second}, nil
+}
+
+// executeStep executes the action of a remediation step by annotating the host. The host controller reboots the
+// host with the requested reboot type or provisions it again with the same InstallImage.
+func (s *Service) executeStep(ctx context.Context, host infrav1.HetznerBareMetalHost, stepType infrav1.RemediationStepType) error {
+	patchHelper, err := patch.NewHelper(&host, s.scope.Client)
+	if err != nil {
+		return fmt.Errorf("failed to init patch helper: %s %s/%s %w", host.Kind, host.Namespace, host.Name, err)
+	}
+
+	if stepType == infrav1.RemediationStepTypeRebuild {
+		if host.Annotations == nil {
+			host.Annotations = make(map[string]string)
+		}
+		host.Annotations[infrav1.ReprovisionAnnotation] = ""
+	} else {
+		rebootType, ok := rebootTypes[stepType]
+		if !ok {
+			return fmt.Errorf("unsupported remediation step %q", stepType)
+		}
+
+		args := infrav1.RebootAnnotationArguments{Type: rebootType, Robot: rebootType != infrav1.RebootTypeSSH}
+		host.Annotations, err = addRebootAnnotation(host.Annotations, args)
+		if err != nil {
+			record.Warn(s.scope.BareMetalRemediation, "FailedAddingRebootAnnotation", err.Error())
+			return fmt.Errorf("failed to add reboot annotation: %w", err)
+		}
+		// a reboot of a previous step might still be ongoing, so the host has to reboot again
+		host.Spec.Status.Rebooted = false
+	}
+
+	if err := patchHelper.Patch(ctx, &host); err != nil {
+		return fmt.Errorf("failed to patch: %s %s/%s %w", host.Kind, host.Namespace, host.Name, err)
+	}
+
+	if stepType == infrav1.RemediationStepTypeRebuild {
+		record.Event(s.scope.BareMetalRemediation, "AnnotationAdded", "Reprovision annotation is added to the BareMetalHost")
+	} else {
+		record.Eventf(s.scope.BareMetalRemediation, "AnnotationAdded", "Reboot annotation with type %s is added to the BareMetalHost", rebootTypes[stepType])
+	}
+	return nil
+}
+
+// completeReprovisioning returns whether the host has been provisioned again. Once it is provisioned, the timeout
+// of the step starts.
+func (s *Service) completeReprovisioning(host infrav1.HetznerBareMetalHost) bool {
+	remediation := s.scope.BareMetalRemediation
+	if len(remediation.Status.Steps) == 0 {
+		return true
+	}
+	stepStatus := &remediation.Status.Steps[len(remediation.Status.Steps)-1]
+	if stepStatus.Finished != nil {
+		return true
+	}
+
+	if _, pending := host.Annotations[infrav1.ReprovisionAnnotation]; pending ||
+		host.Spec.Status.ProvisioningState != infrav1.StateProvisioned {
+		return false
+	}
+
+	record.Event(s.scope.BareMetalRemediation, "HostReprovisioned", "Host has been provisioned again")
+	now := metav1.Now()
+	stepStatus.Finished = &now
+	remediation.Status.LastRemediated = &now
+	return true
+}
+
+// isReprovisioning returns whether the host is being provisioned again by a step of an escalating remediation.
+func (s *Service) isReprovisioning() bool {
+	remediation := s.scope.BareMetalRemediation
+	if remediation.Spec.Strategy.Type != infrav1.RemediationTypeEscalate || len(remediation.Status.Steps) == 0 {
+		return false
+	}
+	stepStatus := remediation.Status.Steps[len(remediation.Status.Steps)-1]
+	return stepStatus.Type == infrav1.RemediationStepTypeRebuild && stepStatus.Finished == nil
+}
+
+// replaceMachine hands the machine back to CAPI, which replaces it.
+func (s *Service) replaceMachine(ctx context.Context, reason string) error {
+	s.scope.BareMetalRemediation.Status.Phase = infrav1.PhaseDeleting
+
+	if err := s.setOwnerRemediatedConditionNew(ctx); err != nil {
+		err = fmt.Errorf("failed to set remediated condition on capi machine: %w", err)
+		record.Warn(s.scope.BareMetalRemediation, "FailedSettingConditionOnMachine", err.Error())
+		return err
+	}
+	record.Eventf(s.scope.BareMetalRemediation, "SetOwnerRemediatedCondition", "exit remediation because %s", reason)
+	return nil
+}
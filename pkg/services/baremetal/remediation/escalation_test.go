@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+)
+
+var _ = Describe("Escalating remediation", func() {
+	var (
+		ctx         context.Context
+		c           client.Client
+		remediation *infrav1.HetznerBareMetalRemediation
+		machine     *clusterv1.Machine
+		host        *infrav1.HetznerBareMetalHost
+		service     *Service
+	)
+
+	// expireStep lets the timeout of the last executed step pass.
+	expireStep := func() {
+		remediation.Status.LastRemediated = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	}
+
+	getHost := func() *infrav1.HetznerBareMetalHost {
+		var h infrav1.HetznerBareMetalHost
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(host), &h)).To(Succeed())
+		return &h
+	}
+
+	// updateHost simulates the host controller.
+	updateHost := func(update func(h *infrav1.HetznerBareMetalHost)) {
+		h := getHost()
+		update(h)
+		Expect(c.Update(ctx, h)).To(Succeed())
+	}
+
+	rebootArgs := func() infrav1.RebootAnnotationArguments {
+		var args infrav1.RebootAnnotationArguments
+		Expect(json.Unmarshal([]byte(getHost().Annotations[infrav1.RebootAnnotation]), &args)).To(Succeed())
+		return args
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		host = &infrav1.HetznerBareMetalHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "host", Namespace: "default"},
+			Spec: infrav1.HetznerBareMetalHostSpec{
+				ServerID: 1,
+				Status:   infrav1.ControllerGeneratedStatus{ProvisioningState: infrav1.StateProvisioned},
+			},
+		}
+		machine = &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"}}
+		bmMachine := &infrav1.HetznerBareMetalMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "machine",
+				Namespace:   "default",
+				Annotations: map[string]string{infrav1.HostAnnotation: "default/host"},
+			},
+		}
+		remediation = &infrav1.HetznerBareMetalRemediation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "machine",
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Machine",
+					Name:       machine.Name,
+				}},
+			},
+			Spec: infrav1.HetznerBareMetalRemediationSpec{
+				Strategy: &infrav1.RemediationStrategy{
+					Type:    infrav1.RemediationTypeEscalate,
+					Timeout: &metav1.Duration{Duration: 5 * time.Minute},
+				},
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		utilruntime.Must(infrav1.AddToScheme(scheme))
+		utilruntime.Must(clusterv1.AddToScheme(scheme))
+		c = fakeclient.NewClientBuilder().WithScheme(scheme).
+			WithObjects(host, machine, remediation).
+			WithStatusSubresource(machine, remediation).
+			Build()
+
+		remediationScope, err := scope.NewBareMetalRemediationScope(scope.BareMetalRemediationScopeParams{
+			Logger:               &GinkgoLogr,
+			Client:               c,
+			Machine:              machine,
+			BareMetalMachine:     bmMachine,
+			BareMetalRemediation: remediation,
+		})
+		Expect(err).To(Succeed())
+		service = NewService(remediationScope)
+	})
+
+	It("escalates from ssh reboot to software and hardware reset and hands the machine back to CAPI", func() {
+		res, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(BeNumerically(">", 5*time.Minute))
+		Expect(remediation.Status.Phase).To(Equal(infrav1.PhaseRunning))
+		Expect(remediation.Status.Steps).To(HaveLen(1))
+		Expect(remediation.Status.Steps[0].Type).To(Equal(infrav1.RemediationStepTypeReboot))
+		Expect(rebootArgs()).To(Equal(infrav1.RebootAnnotationArguments{Type: infrav1.RebootTypeSSH}))
+
+		// nothing happens before the step timed out
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.Steps).To(HaveLen(1))
+
+		// the host has not finished the ssh reboot
+		updateHost(func(h *infrav1.HetznerBareMetalHost) { h.Spec.Status.Rebooted = true })
+
+		expireStep()
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.CurrentStep).To(Equal(1))
+		Expect(remediation.Status.Steps).To(HaveLen(2))
+		Expect(remediation.Status.Steps[1].Type).To(Equal(infrav1.RemediationStepTypeSoftwareReset))
+		Expect(rebootArgs()).To(Equal(infrav1.RebootAnnotationArguments{Type: infrav1.RebootTypeSoftware, Robot: true}))
+		Expect(getHost().Spec.Status.Rebooted).To(BeFalse())
+
+		expireStep()
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.CurrentStep).To(Equal(2))
+		Expect(remediation.Status.Steps[2].Type).To(Equal(infrav1.RemediationStepTypeHardwareReset))
+		Expect(remediation.Status.Steps[2].Finished).ToNot(BeNil())
+		Expect(rebootArgs()).To(Equal(infrav1.RebootAnnotationArguments{Type: infrav1.RebootTypeHardware, Robot: true}))
+
+		expireStep()
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.Phase).To(Equal(infrav1.PhaseDeleting))
+		Expect(remediation.Status.Steps).To(HaveLen(3))
+
+		var m clusterv1.Machine
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(machine), &m)).To(Succeed())
+		Expect(conditions.IsFalse(&m, clusterv1.MachineOwnerRemediatedCondition)).To(BeTrue())
+	})
+
+	It("provisions the host again and starts the timeout once it is provisioned", func() {
+		remediation.Spec.Strategy.Steps = []infrav1.RemediationStep{{Type: infrav1.RemediationStepTypeRebuild}}
+
+		res, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(Equal(reprovisionRequeueAfter))
+		Expect(getHost().Annotations).To(HaveKey(infrav1.ReprovisionAnnotation))
+		Expect(remediation.Status.Steps).To(HaveLen(1))
+		Expect(remediation.Status.Steps[0].Finished).To(BeNil())
+
+		// the host is provisioned again, which does not end the remediation
+		updateHost(func(h *infrav1.HetznerBareMetalHost) {
+			delete(h.Annotations, infrav1.ReprovisionAnnotation)
+			h.Spec.Status.ProvisioningState = infrav1.StateImageInstalling
+		})
+		expireStep()
+		res, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(Equal(reprovisionRequeueAfter))
+		Expect(remediation.Status.Phase).To(Equal(infrav1.PhaseRunning))
+		Expect(remediation.Status.Steps[0].Finished).To(BeNil())
+
+		updateHost(func(h *infrav1.HetznerBareMetalHost) { h.Spec.Status.ProvisioningState = infrav1.StateProvisioned })
+		res, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(BeNumerically(">", 4*time.Minute))
+		Expect(remediation.Status.Steps[0].Finished).ToNot(BeNil())
+		Expect(remediation.Status.LastRemediated).To(Equal(remediation.Status.Steps[0].Finished))
+	})
+
+	It("hands the machine back to CAPI right away with the Replace strategy", func() {
+		remediation.Spec.Strategy.Type = infrav1.RemediationTypeReplace
+
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.Phase).To(Equal(infrav1.PhaseDeleting))
+		Expect(remediation.Status.Steps).To(BeEmpty())
+		Expect(getHost().HasRebootAnnotation()).To(BeFalse())
+	})
+})
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+// robotServerStatusReady is the status of a bare metal server in the Robot API that is not being processed by Hetzner.
+const robotServerStatusReady = "ready"
+
+// skipRemediation runs the health probe of the remediation strategy before any action of the remediation. If the
+// server is ready and all probed ports are reachable, the machine is only unreachable from the management cluster,
+// so the remediation is annotated and the action is skipped.
+func (s *Service) skipRemediation(ctx context.Context, host infrav1.HetznerBareMetalHost) (bool, error) {
+	remediation := s.scope.BareMetalRemediation
+	probe := remediation.Spec.Strategy.HealthProbe
+	if probe == nil || remediation.Status.Phase == infrav1.PhaseDeleting || s.isReprovisioning() {
+		return false, nil
+	}
+
+	reachable, msg, err := s.probeHost(ctx, host, probe)
+	if err != nil {
+		return false, err
+	}
+	if !reachable {
+		if _, found := remediation.Annotations[infrav1.RemediationSkippedAnnotation]; found {
+			delete(remediation.Annotations, infrav1.RemediationSkippedAnnotation)
+			record.Eventf(remediation, "HealthProbeFailed", "Continue remediation because %s", msg)
+		}
+		return false, nil
+	}
+
+	if remediation.Annotations == nil {
+		remediation.Annotations = make(map[string]string)
+	}
+	remediation.Annotations[infrav1.RemediationSkippedAnnotation] = fmt.Sprintf("%s: %s", time.Now().UTC().Format(time.RFC3339), msg)
+	record.Eventf(remediation, "RemediationSkipped", "Skipped remediation because %s", msg)
+	return true, nil
+}
+
+// probeHost returns whether the server of the host is reachable from the provider side and a description of the
+// result. The Robot API does not report the power state, so the status of the server is checked instead.
+func (s *Service) probeHost(ctx context.Context, host infrav1.HetznerBareMetalHost, probe *infrav1.RemediationHealthProbe) (bool, string, error) {
+	if s.scope.RobotClient == nil {
+		return false, "", fmt.Errorf("cannot probe server %d without robot client", host.Spec.ServerID)
+	}
+
+	server, err := s.scope.RobotClient.GetBMServer(host.Spec.ServerID)
+	if err != nil {
+		record.Warnf(s.scope.BareMetalRemediation, "FailedGetBMServer", "Failed to probe server %d: %s", host.Spec.ServerID, err)
+		return false, "", fmt.Errorf("failed to get bare metal server %d: %w", host.Spec.ServerID, err)
+	}
+	if server.Status != robotServerStatusReady {
+		return false, fmt.Sprintf("server has status %q", server.Status), nil
+	}
+	if len(probe.TCPPorts) == 0 {
+		// a ready server alone does not show that the node works
+		return false, "health probe has no ports", nil
+	}
+
+	address := host.Spec.Status.GetIPAddress()
+	if address == "" {
+		return false, "server has no address to probe", nil
+	}
+
+	if unreachable := utils.UnreachableTCPPorts(ctx, address, probe.TCPPorts, probe.ConnectTimeout()); len(unreachable) > 0 {
+		return false, fmt.Sprintf("ports %v of %s are unreachable", unreachable, address), nil
+	}
+	return true, fmt.Sprintf("server is ready and ports %v of %s are reachable", probe.TCPPorts, address), nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remediation
+
+import (
+	"context"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/syself/hrobot-go/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	robotmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks/robot"
+)
+
+var _ = Describe("Health probe", func() {
+	var (
+		ctx         context.Context
+		c           client.Client
+		remediation *infrav1.HetznerBareMetalRemediation
+		host        *infrav1.HetznerBareMetalHost
+		robotClient *robotmock.Client
+		listener    net.Listener
+		service     *Service
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		var err error
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(Succeed())
+		port := listener.Addr().(*net.TCPAddr).Port
+
+		host = &infrav1.HetznerBareMetalHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "host", Namespace: "default"},
+			Spec: infrav1.HetznerBareMetalHostSpec{
+				ServerID: 1,
+				Status: infrav1.ControllerGeneratedStatus{
+					ProvisioningState: infrav1.StateProvisioned,
+					IPv4:              "127.0.0.1",
+				},
+			},
+		}
+		machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"}}
+		bmMachine := &infrav1.HetznerBareMetalMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "machine",
+				Namespace:   "default",
+				Annotations: map[string]string{infrav1.HostAnnotation: "default/host"},
+			},
+		}
+		remediation = &infrav1.HetznerBareMetalRemediation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "machine",
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Machine",
+					Name:       machine.Name,
+				}},
+			},
+			Spec: infrav1.HetznerBareMetalRemediationSpec{
+				Strategy: &infrav1.RemediationStrategy{
+					Type:        infrav1.RemediationTypeReboot,
+					Timeout:     &metav1.Duration{Duration: 5 * time.Minute},
+					HealthProbe: &infrav1.RemediationHealthProbe{TCPPorts: []int{port}},
+				},
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		utilruntime.Must(infrav1.AddToScheme(scheme))
+		utilruntime.Must(clusterv1.AddToScheme(scheme))
+		c = fakeclient.NewClientBuilder().WithScheme(scheme).
+			WithObjects(host, machine, remediation).
+			WithStatusSubresource(machine, remediation).
+			Build()
+
+		robotClient = &robotmock.Client{}
+
+		remediationScope, err := scope.NewBareMetalRemediationScope(scope.BareMetalRemediationScopeParams{
+			Logger:               &GinkgoLogr,
+			Client:               c,
+			Machine:              machine,
+			BareMetalMachine:     bmMachine,
+			BareMetalRemediation: remediation,
+			RobotClient:          robotClient,
+		})
+		Expect(err).To(Succeed())
+		service = NewService(remediationScope)
+	})
+
+	AfterEach(func() {
+		_ = listener.Close()
+	})
+
+	hasRebootAnnotation := func() bool {
+		var h infrav1.HetznerBareMetalHost
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(host), &h)).To(Succeed())
+		return h.HasRebootAnnotation()
+	}
+
+	It("skips the remediation of a ready server with reachable ports", func() {
+		robotClient.On("GetBMServer", 1).Return(&models.Server{Status: "ready"}, nil)
+
+		res, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(Equal(5 * time.Minute))
+		Expect(remediation.Status.LastRemediated).To(BeNil())
+		Expect(remediation.Annotations[infrav1.RemediationSkippedAnnotation]).To(ContainSubstring("server is ready"))
+		Expect(hasRebootAnnotation()).To(BeFalse())
+	})
+
+	It("remediates once a port is unreachable", func() {
+		robotClient.On("GetBMServer", 1).Return(&models.Server{Status: "ready"}, nil)
+
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Annotations).To(HaveKey(infrav1.RemediationSkippedAnnotation))
+
+		Expect(listener.Close()).To(Succeed())
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.LastRemediated).ToNot(BeNil())
+		Expect(remediation.Annotations).ToNot(HaveKey(infrav1.RemediationSkippedAnnotation))
+		Expect(hasRebootAnnotation()).To(BeTrue())
+	})
+
+	It("remediates a server that is not ready", func() {
+		robotClient.On("GetBMServer", 1).Return(&models.Server{Status: "in process"}, nil)
+
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.LastRemediated).ToNot(BeNil())
+		Expect(hasRebootAnnotation()).To(BeTrue())
+	})
+})
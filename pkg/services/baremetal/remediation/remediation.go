@@ -52,6 +52,14 @@ func NewService(scope *scope.BareMetalRemediationScope) *Service {
 
 // Reconcile implements reconcilement of HetznerBareMetalRemediations.
 func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err error) {
+	remediationType := s.scope.BareMetalRemediation.Spec.Strategy.Type
+	switch remediationType {
+	case infrav1.RemediationTypeReboot, infrav1.RemediationTypeEscalate, infrav1.RemediationTypeReplace:
+	default:
+		record.Warnf(s.scope.BareMetalRemediation, "UnsupportedRemediationStrategy", "remediation strategy %q is unsupported", remediationType)
+		return res, nil
+	}
+
 	// try to get information about host from bare metal machine annotations
 	key, err := objectKeyFromAnnotations(s.scope.BareMetalMachine.ObjectMeta.GetAnnotations())
 	if err != nil {
@@ -81,8 +89,9 @@ func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err erro
 		return res, err
 	}
 
-	// if host is not provisioned or in maintenance mode, then we do not try to reboot server
-	if host.Spec.Status.ProvisioningState != infrav1.StateProvisioned ||
+	// if host is not provisioned or in maintenance mode, then we do not try to reboot server. A host that is
+	// provisioned again by the remediation is not provisioned until it is finished.
+	if host.Spec.Status.ProvisioningState != infrav1.StateProvisioned && !s.isReprovisioning() ||
 		host.Spec.MaintenanceMode != nil && *host.Spec.MaintenanceMode {
 		if err := s.setOwnerRemediatedConditionNew(ctx); err != nil {
 			err := fmt.Errorf("failed to set remediated condition on capi machine: %w", err)
@@ -98,18 +107,28 @@ func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err erro
 		return res, nil
 	}
 
-	if s.scope.BareMetalRemediation.Spec.Strategy.Type != infrav1.RemediationTypeReboot {
-		record.Warn(s.scope.BareMetalRemediation, "UnsupportedRemediationStrategy", "unsupported remediation strategy")
-		return res, nil
-	}
-
 	// If no phase set, default to running
 	if s.scope.BareMetalRemediation.Status.Phase == "" {
 		s.scope.BareMetalRemediation.Status.Phase = infrav1.PhaseRunning
 	}
 
+	skip, err := s.skipRemediation(ctx, host)
+	if err != nil {
+		return res, err
+	}
+	if skip {
+		return reconcile.Result{RequeueAfter: s.scope.BareMetalRemediation.Spec.Strategy.Timeout.Duration}, nil
+	}
+
+	if remediationType == infrav1.RemediationTypeReplace {
+		return res, s.replaceMachine(ctx, "remediation strategy is Replace")
+	}
+
 	switch s.scope.BareMetalRemediation.Status.Phase {
 	case infrav1.PhaseRunning:
+		if remediationType == infrav1.RemediationTypeEscalate {
+			return s.handleEscalation(ctx, host)
+		}
 		return s.handlePhaseRunning(ctx, host)
 	case infrav1.PhaseWaiting:
 		return s.handlePhaseWaiting(ctx)
@@ -156,7 +175,7 @@ func (s *Service) remediate(ctx context.Context, host infrav1.HetznerBareMetalHo
 	}
 
 	// add annotation to host so that it reboots
-	host.Annotations, err = addRebootAnnotation(host.Annotations)
+	host.Annotations, err = addRebootAnnotation(host.Annotations, infrav1.RebootAnnotationArguments{Type: infrav1.RebootTypeHardware})
 	if err != nil {
 		record.Warn(s.scope.BareMetalRemediation, "FailedAddingRebootAnnotation", err.Error())
 		return fmt.Errorf("failed to add reboot annotation: %w", err)
@@ -261,10 +280,8 @@ func objectKeyFromAnnotations(annotations map[string]string) (client.ObjectKey,
 	return client.ObjectKey{Name: hostName, Namespace: hostNamespace}, nil
 }
 
-// addRebootAnnotation sets reboot annotation on unhealthy host.
-func addRebootAnnotation(annotations map[string]string) (map[string]string, error) {
-	rebootAnnotationArguments := infrav1.RebootAnnotationArguments{Type: infrav1.RebootTypeHardware}
-
+// addRebootAnnotation sets reboot annotation with the given arguments on unhealthy host.
+func addRebootAnnotation(annotations map[string]string, rebootAnnotationArguments infrav1.RebootAnnotationArguments) (map[string]string, error) {
 	b, err := json.Marshal(rebootAnnotationArguments)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal reboot annotation arguments %+v: %w", rebootAnnotationArguments, err)
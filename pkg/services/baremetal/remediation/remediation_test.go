@@ -182,7 +182,7 @@ var _ = Describe("Test AddRebootAnnotation", func() {
 
 	DescribeTable("Test AddRebootAnnotation",
 		func(tc testCaseAddRebootAnnotation) {
-			annotations, err := addRebootAnnotation(tc.annotations)
+			annotations, err := addRebootAnnotation(tc.annotations, rebootAnnotationArguments)
 
 			Expect(annotations).To(Equal(tc.expectAnnotations))
 			Expect(err).To(BeNil())
@@ -0,0 +1,435 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dns implements the lifecycle of the DNS records of the control plane endpoint.
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+)
+
+// ownerValuePrefix is the prefix of the value of the TXT records that mark the owner of the records of a name. It is
+// followed by the UID of the cluster.
+const ownerValuePrefix = "heritage=caph,caph/owner="
+
+var errNoProviderFactory = errors.New("no DNS provider factory configured")
+
+// Service struct contains cluster scope to reconcile the DNS records of the control plane endpoint.
+type Service struct {
+	scope   *scope.ClusterScope
+	factory ProviderFactory
+}
+
+// NewService creates new service object.
+func NewService(scope *scope.ClusterScope, factory ProviderFactory) *Service {
+	return &Service{
+		scope:   scope,
+		factory: factory,
+	}
+}
+
+// Reconcile implements the life cycle of the DNS records of the control plane endpoint.
+func (s *Service) Reconcile(ctx context.Context) (err error) {
+	hetznerCluster := s.scope.HetznerCluster
+	spec := hetznerCluster.Spec.ControlPlaneDNS
+
+	if spec == nil {
+		// delete the records if the DNS spec has been removed
+		if err := s.Delete(ctx); err != nil {
+			return err
+		}
+		if hetznerCluster.Status.ControlPlaneDNS == nil {
+			conditions.Delete(hetznerCluster, infrav1.ControlPlaneDNSReadyCondition)
+		}
+		return nil
+	}
+
+	defer func() {
+		if err != nil {
+			conditions.MarkFalse(
+				hetznerCluster,
+				infrav1.ControlPlaneDNSReadyCondition,
+				infrav1.ControlPlaneDNSSyncFailedReason,
+				clusterv1.ConditionSeverityWarning,
+				"%s",
+				err.Error(),
+			)
+		}
+	}()
+
+	desired := desiredRecords(hetznerCluster)
+	if len(desired) == 0 {
+		conditions.MarkFalse(
+			hetznerCluster,
+			infrav1.ControlPlaneDNSReadyCondition,
+			infrav1.ControlPlaneDNSIPNotAvailableReason,
+			clusterv1.ConditionSeverityInfo,
+			"control plane endpoint has no IP yet",
+		)
+		return nil
+	}
+
+	provider, err := s.provider(spec.Provider)
+	if err != nil {
+		return err
+	}
+
+	zoneID, err := provider.GetZoneID(ctx, spec.Zone)
+	if err != nil {
+		return err
+	}
+
+	existing, err := provider.ListRecords(ctx, zoneID)
+	if err != nil {
+		return err
+	}
+
+	owner := ownerRecord(spec.Name, string(s.scope.Cluster.UID), spec.TTL)
+
+	// the records of a name are managed if the owner record of the cluster exists next to them. Records in the status
+	// have been created for the cluster as well, which adopts records that have been created without owner record.
+	owned := make(map[string]bool)
+	if status := hetznerCluster.Status.ControlPlaneDNS; status != nil && status.ZoneID == zoneID {
+		for _, rec := range status.Records {
+			owned[rec.ID] = true
+		}
+	}
+
+	var records []infrav1.DNSRecordStatus
+	if conflicts := foreignRecords(existing, owner, owned); len(conflicts) > 0 {
+		// a record of the cluster would share the traffic with the foreign records, so nothing of the name is touched
+		// until the conflict has been resolved
+		records = ownedStatusRecords(hetznerCluster.Status.ControlPlaneDNS, zoneID, spec.Name)
+		if err := s.deleteOldRecords(ctx, provider, records); err != nil {
+			return err
+		}
+		s.setStatus(zoneID, records)
+
+		msg := fmt.Sprintf("records that have not been created for the cluster exist already: %s", strings.Join(conflicts, ", "))
+		record.Warnf(hetznerCluster, "DNSRecordConflict", "Failed to sync DNS records: %s", msg)
+		conditions.MarkFalse(
+			hetznerCluster,
+			infrav1.ControlPlaneDNSReadyCondition,
+			infrav1.ControlPlaneDNSRecordConflictReason,
+			clusterv1.ConditionSeverityError,
+			"%s",
+			msg,
+		)
+		return nil
+	}
+
+	// the owner record is created first, so that the records of the cluster are never left without it
+	ownerRec, err := s.ensureOwnerRecord(ctx, provider, zoneID, owner, existing)
+	if err != nil {
+		return err
+	}
+
+	for _, want := range desired {
+		rec, err := s.syncRecord(ctx, provider, zoneID, want, existing)
+		if err != nil {
+			return err
+		}
+		records = append(records, recordStatus(rec))
+	}
+	// the owner record is deleted last
+	records = append(records, recordStatus(ownerRec))
+
+	if err := s.deleteOldRecords(ctx, provider, records); err != nil {
+		return err
+	}
+	s.setStatus(zoneID, records)
+
+	conditions.MarkTrue(hetznerCluster, infrav1.ControlPlaneDNSReadyCondition)
+	return nil
+}
+
+// deleteOldRecords deletes the records of the status that are not desired anymore, e.g. because the name or zone
+// changed.
+func (s *Service) deleteOldRecords(ctx context.Context, provider Provider, records []infrav1.DNSRecordStatus) error {
+	status := s.scope.HetznerCluster.Status.ControlPlaneDNS
+	if status == nil {
+		return nil
+	}
+	for _, old := range status.Records {
+		if containsRecord(records, old.ID) {
+			continue
+		}
+		if err := s.deleteRecord(ctx, provider, status.ZoneID, old); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) setStatus(zoneID string, records []infrav1.DNSRecordStatus) {
+	s.scope.HetznerCluster.Status.ControlPlaneDNS = &infrav1.ControlPlaneDNSStatus{
+		ZoneID:  zoneID,
+		Records: records,
+	}
+}
+
+// ensureOwnerRecord creates the owner record of the cluster unless it exists already.
+func (s *Service) ensureOwnerRecord(ctx context.Context, provider Provider, zoneID string, owner Record, existing []Record) (Record, error) {
+	for _, rec := range existing {
+		if rec.Name == owner.Name && rec.Type == RecordTypeTXT && unquote(rec.Value) == unquote(owner.Value) {
+			return rec, nil
+		}
+	}
+
+	rec, err := provider.CreateRecord(ctx, zoneID, owner)
+	if err != nil {
+		record.Warnf(s.scope.HetznerCluster, "DNSRecordCreateFailed", "Failed to create owner record %s: %s", owner.Name, err)
+		return Record{}, err
+	}
+	return rec, nil
+}
+
+// syncRecord makes sure that exactly one record of the cluster with the name and type of the desired record exists
+// and that it points to the desired value.
+func (s *Service) syncRecord(ctx context.Context, provider Provider, zoneID string, want Record, existing []Record) (Record, error) {
+	var current *Record
+	for i, rec := range existing {
+		if rec.Name != want.Name || rec.Type != want.Type {
+			continue
+		}
+		if current == nil {
+			current = &existing[i]
+			continue
+		}
+		// a second record of the same name and type would send traffic elsewhere
+		if err := provider.DeleteRecord(ctx, zoneID, rec.ID); err != nil && !errors.Is(err, ErrNotFound) {
+			return Record{}, err
+		}
+	}
+
+	if current == nil {
+		rec, err := provider.CreateRecord(ctx, zoneID, want)
+		if err != nil {
+			record.Warnf(s.scope.HetznerCluster, "DNSRecordCreateFailed", "Failed to create %s record %s: %s", want.Type, want.Name, err)
+			return Record{}, err
+		}
+		record.Eventf(s.scope.HetznerCluster, "DNSRecordCreated", "Created %s record %s pointing to %s", want.Type, want.Name, want.Value)
+		return rec, nil
+	}
+
+	if current.Value == want.Value && current.TTL == want.TTL {
+		return *current, nil
+	}
+
+	want.ID = current.ID
+	rec, err := provider.UpdateRecord(ctx, zoneID, want)
+	if err != nil {
+		record.Warnf(s.scope.HetznerCluster, "DNSRecordUpdateFailed", "Failed to update %s record %s: %s", want.Type, want.Name, err)
+		return Record{}, err
+	}
+	record.Eventf(s.scope.HetznerCluster, "DNSRecordUpdated", "Updated %s record %s from %s to %s", want.Type, want.Name, current.Value, want.Value)
+	return rec, nil
+}
+
+// Delete implements the deletion of the DNS records of the control plane endpoint. If the DNS provider is not
+// available, the records are reported in the conditions and left behind instead of blocking the deletion.
+func (s *Service) Delete(ctx context.Context) error {
+	status := s.scope.HetznerCluster.Status.ControlPlaneDNS
+	if status == nil || len(status.Records) == 0 {
+		s.scope.HetznerCluster.Status.ControlPlaneDNS = nil
+		return nil
+	}
+
+	providerType := infrav1.DNSProviderHetzner
+	if spec := s.scope.HetznerCluster.Spec.ControlPlaneDNS; spec != nil {
+		providerType = spec.Provider
+	}
+
+	provider, err := s.provider(providerType)
+	if err != nil {
+		names := make([]string, 0, len(status.Records))
+		for _, rec := range status.Records {
+			names = append(names, fmt.Sprintf("%s %s", rec.Type, rec.Name))
+		}
+		msg := fmt.Sprintf("failed to delete DNS records %s: %s", strings.Join(names, ", "), err)
+		record.Warnf(s.scope.HetznerCluster, "DNSRecordDeleteFailed", "%s", msg)
+		conditions.MarkFalse(
+			s.scope.HetznerCluster,
+			infrav1.ControlPlaneDNSReadyCondition,
+			infrav1.ControlPlaneDNSDeleteFailedReason,
+			clusterv1.ConditionSeverityError,
+			"%s",
+			msg,
+		)
+		return nil
+	}
+
+	for _, rec := range status.Records {
+		if err := s.deleteRecord(ctx, provider, status.ZoneID, rec); err != nil {
+			return err
+		}
+	}
+
+	s.scope.HetznerCluster.Status.ControlPlaneDNS = nil
+	return nil
+}
+
+func (s *Service) deleteRecord(ctx context.Context, provider Provider, zoneID string, rec infrav1.DNSRecordStatus) error {
+	if err := provider.DeleteRecord(ctx, zoneID, rec.ID); err != nil {
+		// if the record has been deleted already then do nothing
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		record.Warnf(s.scope.HetznerCluster, "DNSRecordDeleteFailed", "Failed to delete %s record %s: %s", rec.Type, rec.Name, err)
+		return err
+	}
+	record.Eventf(s.scope.HetznerCluster, "DNSRecordDeleted", "Deleted %s record %s", rec.Type, rec.Name)
+	return nil
+}
+
+func (s *Service) provider(providerType infrav1.DNSProvider) (Provider, error) {
+	if s.factory == nil {
+		return nil, errNoProviderFactory
+	}
+
+	secret := s.scope.HetznerSecret()
+	key := s.scope.HetznerCluster.Spec.HetznerSecret.Key.HetznerDNSToken
+	if secret == nil || len(secret.Data[key]) == 0 {
+		return nil, fmt.Errorf("DNS token not found in key %q of the Hetzner secret", key)
+	}
+
+	return s.factory.NewProvider(providerType, string(secret.Data[key]))
+}
+
+// desiredRecords returns the records that point to the IPs of the control plane endpoint.
+func desiredRecords(hetznerCluster *infrav1.HetznerCluster) []Record {
+	var ipv4, ipv6 string
+	switch {
+	case hetznerCluster.UsesControlPlaneIP():
+		if hetznerCluster.Status.ControlPlaneIP != nil {
+			ipv4 = hetznerCluster.Status.ControlPlaneIP.IPv4
+		}
+	case hetznerCluster.Spec.ControlPlaneLoadBalancer.Enabled:
+		if hetznerCluster.Status.ControlPlaneLoadBalancer != nil {
+			ipv4 = hetznerCluster.Status.ControlPlaneLoadBalancer.IPv4
+			ipv6 = hetznerCluster.Status.ControlPlaneLoadBalancer.IPv6
+		}
+	}
+
+	spec := hetznerCluster.Spec.ControlPlaneDNS
+	var records []Record
+	if ip := net.ParseIP(ipv4); ip != nil && ip.To4() != nil {
+		records = append(records, Record{Type: RecordTypeA, Name: spec.Name, Value: ip.String(), TTL: spec.TTL})
+	}
+	if ip := net.ParseIP(ipv6); ip != nil && ip.To4() == nil {
+		records = append(records, Record{Type: RecordTypeAAAA, Name: spec.Name, Value: ip.String(), TTL: spec.TTL})
+	}
+	return records
+}
+
+// ownerRecord returns the TXT record that marks the records of the name as records of the cluster with the given
+// UID, similar to the registry of external-dns.
+func ownerRecord(name, clusterUID string, ttl int) Record {
+	return Record{
+		Type:  RecordTypeTXT,
+		Name:  name,
+		Value: fmt.Sprintf("%q", ownerValuePrefix+clusterUID),
+		TTL:   ttl,
+	}
+}
+
+// foreignRecords returns the records of the name of the owner record that have not been created for the cluster:
+// A and AAAA records without the owner record of the cluster that are not known from the status, and the owner
+// records of other clusters.
+func foreignRecords(existing []Record, owner Record, owned map[string]bool) []string {
+	hasOwner := false
+	for _, rec := range existing {
+		if rec.Name == owner.Name && rec.Type == RecordTypeTXT && unquote(rec.Value) == unquote(owner.Value) {
+			hasOwner = true
+		}
+	}
+
+	var foreign []string
+	for _, rec := range existing {
+		if rec.Name != owner.Name {
+			continue
+		}
+		switch rec.Type {
+		case RecordTypeA, RecordTypeAAAA:
+			if hasOwner || owned[rec.ID] {
+				continue
+			}
+		case RecordTypeTXT:
+			// other TXT records, e.g. for the verification of the domain, share the name without any conflict
+			value := unquote(rec.Value)
+			if !strings.HasPrefix(value, ownerValuePrefix) || value == unquote(owner.Value) {
+				continue
+			}
+		default:
+			continue
+		}
+
+		conflict := fmt.Sprintf("%s record %s", rec.Type, rec.Name)
+		if !slices.Contains(foreign, conflict) {
+			foreign = append(foreign, conflict)
+		}
+	}
+	return foreign
+}
+
+// ownedStatusRecords returns the records of the status with the given name.
+func ownedStatusRecords(status *infrav1.ControlPlaneDNSStatus, zoneID, name string) []infrav1.DNSRecordStatus {
+	if status == nil || status.ZoneID != zoneID {
+		return nil
+	}
+
+	var records []infrav1.DNSRecordStatus
+	for _, rec := range status.Records {
+		if rec.Name == name {
+			records = append(records, rec)
+		}
+	}
+	return records
+}
+
+func recordStatus(rec Record) infrav1.DNSRecordStatus {
+	return infrav1.DNSRecordStatus{
+		ID:    rec.ID,
+		Type:  rec.Type,
+		Name:  rec.Name,
+		Value: rec.Value,
+	}
+}
+
+func unquote(value string) string {
+	return strings.Trim(value, `"`)
+}
+
+func containsRecord(records []infrav1.DNSRecordStatus, id string) bool {
+	for _, rec := range records {
+		if rec.ID == id {
+			return true
+		}
+	}
+	return false
+}
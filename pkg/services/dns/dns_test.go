@@ -0,0 +1,288 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2/textlogger"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/dns/fake"
+	fakehcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+)
+
+var _ = Describe("desiredRecords", func() {
+	var hetznerCluster *infrav1.HetznerCluster
+
+	BeforeEach(func() {
+		hetznerCluster = &infrav1.HetznerCluster{
+			Spec: infrav1.HetznerClusterSpec{
+				ControlPlaneDNS:          &infrav1.ControlPlaneDNSSpec{Zone: "example.com", Name: "api", TTL: 300},
+				ControlPlaneLoadBalancer: infrav1.LoadBalancerSpec{Enabled: true},
+			},
+		}
+	})
+
+	It("returns A and AAAA records for the IPs of the load balancer", func() {
+		hetznerCluster.Status.ControlPlaneLoadBalancer = &infrav1.LoadBalancerStatus{IPv4: "1.2.3.4", IPv6: "2001:db8::1"}
+		Expect(desiredRecords(hetznerCluster)).To(Equal([]Record{
+			{Type: RecordTypeA, Name: "api", Value: "1.2.3.4", TTL: 300},
+			{Type: RecordTypeAAAA, Name: "api", Value: "2001:db8::1", TTL: 300},
+		}))
+	})
+
+	It("ignores IPs that are not set yet", func() {
+		hetznerCluster.Status.ControlPlaneLoadBalancer = &infrav1.LoadBalancerStatus{IPv4: "<nil>", IPv6: "<nil>"}
+		Expect(desiredRecords(hetznerCluster)).To(BeEmpty())
+	})
+
+	It("returns an A record for the control plane IP", func() {
+		hetznerCluster.Spec.ControlPlaneLoadBalancer.Enabled = false
+		hetznerCluster.Spec.ControlPlaneEndpointType = infrav1.ControlPlaneEndpointTypeFloatingIP
+		hetznerCluster.Status.ControlPlaneIP = &infrav1.ControlPlaneIPStatus{IPv4: "1.2.3.4"}
+		Expect(desiredRecords(hetznerCluster)).To(Equal([]Record{
+			{Type: RecordTypeA, Name: "api", Value: "1.2.3.4", TTL: 300},
+		}))
+	})
+})
+
+var _ = Describe("Reconcile and Delete", func() {
+	var (
+		ctx            context.Context
+		server         *fake.Server
+		zoneID         string
+		hetznerCluster *infrav1.HetznerCluster
+		secret         *corev1.Secret
+	)
+
+	newService := func() *Service {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(infrav1.AddToScheme(scheme))
+		utilruntime.Must(clusterv1.AddToScheme(scheme))
+		c := fakeclient.NewClientBuilder().WithScheme(scheme).Build()
+
+		clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+			Client:         c,
+			APIReader:      c,
+			Logger:         textlogger.NewLogger(textlogger.NewConfig()),
+			HetznerSecret:  secret,
+			HCloudClient:   fakehcloudclient.NewHCloudClientFactory().NewClient(""),
+			Cluster:        &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default", UID: "cluster-uid"}},
+			HetznerCluster: hetznerCluster,
+		})
+		Expect(err).To(Succeed())
+
+		return NewService(clusterScope, &factory{hetznerEndpoint: server.URL})
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server = fake.NewServer("dns-token")
+		DeferCleanup(server.Close)
+		zoneID = server.AddZone("example.com")
+
+		secret = &corev1.Secret{Data: map[string][]byte{"hetzner-dns-token": []byte("dns-token")}}
+		hetznerCluster = &infrav1.HetznerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "hetzner-cluster", Namespace: "default"},
+			Spec: infrav1.HetznerClusterSpec{
+				ControlPlaneDNS:          &infrav1.ControlPlaneDNSSpec{Zone: "example.com", Name: "api", TTL: 300},
+				ControlPlaneLoadBalancer: infrav1.LoadBalancerSpec{Enabled: true},
+				HetznerSecret: infrav1.HetznerSecretRef{
+					Key: infrav1.HetznerSecretKeyRef{HetznerDNSToken: "hetzner-dns-token"},
+				},
+			},
+			Status: infrav1.HetznerClusterStatus{
+				ControlPlaneLoadBalancer: &infrav1.LoadBalancerStatus{IPv4: "1.2.3.4", IPv6: "2001:db8::1"},
+			},
+		}
+	})
+
+	It("waits for the IPs of the control plane endpoint", func() {
+		hetznerCluster.Status.ControlPlaneLoadBalancer = &infrav1.LoadBalancerStatus{IPv4: "<nil>", IPv6: "<nil>"}
+
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+		Expect(server.Records(zoneID)).To(BeEmpty())
+		Expect(conditions.GetReason(hetznerCluster, infrav1.ControlPlaneDNSReadyCondition)).To(Equal(infrav1.ControlPlaneDNSIPNotAvailableReason))
+	})
+
+	It("creates the records and updates them when the IPs change", func() {
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+		Expect(server.Records(zoneID)).To(ConsistOf(
+			And(HaveField("Type", RecordTypeA), HaveField("Name", "api"), HaveField("Value", "1.2.3.4")),
+			And(HaveField("Type", RecordTypeAAAA), HaveField("Name", "api"), HaveField("Value", "2001:db8::1")),
+			And(HaveField("Type", RecordTypeTXT), HaveField("Name", "api"), HaveField("Value", `"heritage=caph,caph/owner=cluster-uid"`)),
+		))
+		Expect(hetznerCluster.Status.ControlPlaneDNS.ZoneID).To(Equal(zoneID))
+		Expect(hetznerCluster.Status.ControlPlaneDNS.Records).To(HaveLen(3))
+		Expect(conditions.IsTrue(hetznerCluster, infrav1.ControlPlaneDNSReadyCondition)).To(BeTrue())
+
+		hetznerCluster.Status.ControlPlaneLoadBalancer.IPv4 = "5.6.7.8"
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+		Expect(server.Records(zoneID)).To(ConsistOf(
+			HaveField("Value", "5.6.7.8"),
+			HaveField("Value", "2001:db8::1"),
+			HaveField("Type", RecordTypeTXT),
+		))
+	})
+
+	It("adopts the records of its owner record", func() {
+		server.AddRecord(fake.Record{ZoneID: zoneID, Type: RecordTypeTXT, Name: "api", Value: `"heritage=caph,caph/owner=cluster-uid"`})
+		server.AddRecord(fake.Record{ZoneID: zoneID, Type: RecordTypeA, Name: "api", Value: "9.9.9.9"})
+		server.AddRecord(fake.Record{ZoneID: zoneID, Type: RecordTypeTXT, Name: "api", Value: "site-verification"})
+
+		// the status of the cluster has been lost
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+		Expect(server.Records(zoneID)).To(ConsistOf(
+			HaveField("Value", "1.2.3.4"),
+			HaveField("Value", "2001:db8::1"),
+			HaveField("Value", `"heritage=caph,caph/owner=cluster-uid"`),
+			HaveField("Value", "site-verification"),
+		))
+		Expect(hetznerCluster.Status.ControlPlaneDNS.Records).To(HaveLen(3))
+		Expect(conditions.IsTrue(hetznerCluster, infrav1.ControlPlaneDNSReadyCondition)).To(BeTrue())
+
+		Expect(newService().Delete(ctx)).To(Succeed())
+		Expect(server.Records(zoneID)).To(ConsistOf(HaveField("Value", "site-verification")))
+	})
+
+	It("reports a conflict on records of another cluster", func() {
+		server.AddRecord(fake.Record{ZoneID: zoneID, Type: RecordTypeTXT, Name: "api", Value: `"heritage=caph,caph/owner=other-uid"`})
+		server.AddRecord(fake.Record{ZoneID: zoneID, Type: RecordTypeA, Name: "api", Value: "9.9.9.9"})
+
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+		Expect(server.Records(zoneID)).To(HaveLen(2))
+		Expect(hetznerCluster.Status.ControlPlaneDNS.Records).To(BeEmpty())
+		Expect(conditions.GetReason(hetznerCluster, infrav1.ControlPlaneDNSReadyCondition)).To(Equal(infrav1.ControlPlaneDNSRecordConflictReason))
+	})
+
+	It("reports a conflict on records that have not been created for the cluster", func() {
+		foreignID := server.AddRecord(fake.Record{ZoneID: zoneID, Type: RecordTypeA, Name: "api", Value: "9.9.9.9"})
+		server.AddRecord(fake.Record{ZoneID: zoneID, Type: RecordTypeA, Name: "www", Value: "9.9.9.7"})
+
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+		Expect(server.Records(zoneID)).To(ConsistOf(
+			HaveField("Value", "9.9.9.9"),
+			HaveField("Value", "9.9.9.7"),
+		))
+		Expect(conditions.GetReason(hetznerCluster, infrav1.ControlPlaneDNSReadyCondition)).To(Equal(infrav1.ControlPlaneDNSRecordConflictReason))
+
+		// the foreign record is not deleted together with the cluster
+		Expect(newService().Delete(ctx)).To(Succeed())
+		Expect(server.Records(zoneID)).To(ConsistOf(
+			HaveField("ID", foreignID),
+			HaveField("Value", "9.9.9.7"),
+		))
+	})
+
+	It("removes duplicates of its own records", func() {
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+
+		duplicateID := server.AddRecord(fake.Record{ZoneID: zoneID, Type: RecordTypeA, Name: "api", Value: "9.9.9.9"})
+		hetznerCluster.Status.ControlPlaneDNS.Records = append(hetznerCluster.Status.ControlPlaneDNS.Records,
+			infrav1.DNSRecordStatus{ID: duplicateID, Type: RecordTypeA, Name: "api", Value: "9.9.9.9"},
+		)
+
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+		Expect(server.Records(zoneID)).To(ConsistOf(
+			HaveField("Value", "1.2.3.4"),
+			HaveField("Value", "2001:db8::1"),
+			HaveField("Type", RecordTypeTXT),
+		))
+		Expect(hetznerCluster.Status.ControlPlaneDNS.Records).To(HaveLen(3))
+		Expect(conditions.IsTrue(hetznerCluster, infrav1.ControlPlaneDNSReadyCondition)).To(BeTrue())
+	})
+
+	It("deletes the records of the old name if the name changes", func() {
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+
+		hetznerCluster.Spec.ControlPlaneDNS.Name = "kube"
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+		Expect(server.Records(zoneID)).To(HaveEach(HaveField("Name", "kube")))
+		Expect(server.Records(zoneID)).To(HaveLen(3))
+	})
+
+	It("deletes the records if the spec is removed", func() {
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+
+		hetznerCluster.Spec.ControlPlaneDNS = nil
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+		Expect(server.Records(zoneID)).To(BeEmpty())
+		Expect(hetznerCluster.Status.ControlPlaneDNS).To(BeNil())
+		Expect(conditions.Has(hetznerCluster, infrav1.ControlPlaneDNSReadyCondition)).To(BeFalse())
+	})
+
+	It("deletes the records and ignores records that are gone already", func() {
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+		records := server.Records(zoneID)
+		p := newHetznerProvider(server.URL, "dns-token")
+		Expect(p.DeleteRecord(ctx, zoneID, records[0].ID)).To(Succeed())
+
+		Expect(newService().Delete(ctx)).To(Succeed())
+		Expect(server.Records(zoneID)).To(BeEmpty())
+		Expect(hetznerCluster.Status.ControlPlaneDNS).To(BeNil())
+	})
+
+	It("skips the deletion if no records have been created", func() {
+		secret.Data = nil
+		hetznerCluster.Status.ControlPlaneDNS = &infrav1.ControlPlaneDNSStatus{ZoneID: zoneID}
+
+		Expect(newService().Delete(ctx)).To(Succeed())
+		Expect(hetznerCluster.Status.ControlPlaneDNS).To(BeNil())
+		Expect(conditions.Has(hetznerCluster, infrav1.ControlPlaneDNSReadyCondition)).To(BeFalse())
+	})
+
+	It("reports the records instead of blocking the deletion if the DNS token is missing", func() {
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+		secret.Data = nil
+
+		Expect(newService().Delete(ctx)).To(Succeed())
+		Expect(server.Records(zoneID)).To(HaveLen(3))
+		Expect(conditions.GetReason(hetznerCluster, infrav1.ControlPlaneDNSReadyCondition)).To(Equal(infrav1.ControlPlaneDNSDeleteFailedReason))
+
+		// the records are deleted once the token is back
+		hetznerCluster.Spec.ControlPlaneDNS = nil
+		secret.Data = map[string][]byte{"hetzner-dns-token": []byte("dns-token")}
+		Expect(newService().Reconcile(ctx)).To(Succeed())
+		Expect(server.Records(zoneID)).To(BeEmpty())
+		Expect(conditions.Has(hetznerCluster, infrav1.ControlPlaneDNSReadyCondition)).To(BeFalse())
+	})
+
+	It("fails if the DNS token is missing", func() {
+		secret.Data = nil
+
+		Expect(newService().Reconcile(ctx)).ToNot(Succeed())
+		Expect(conditions.GetReason(hetznerCluster, infrav1.ControlPlaneDNSReadyCondition)).To(Equal(infrav1.ControlPlaneDNSSyncFailedReason))
+	})
+
+	It("fails if the zone does not exist", func() {
+		hetznerCluster.Spec.ControlPlaneDNS.Zone = "example.org"
+
+		Expect(newService().Reconcile(ctx)).To(MatchError(ErrNotFound))
+		Expect(conditions.GetReason(hetznerCluster, infrav1.ControlPlaneDNSReadyCondition)).To(Equal(infrav1.ControlPlaneDNSSyncFailedReason))
+	})
+})
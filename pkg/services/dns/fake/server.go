@@ -0,0 +1,229 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake implements an in-process fake of the Hetzner DNS API for tests.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PerPage is the number of records the fake server returns per page.
+const PerPage = 2
+
+// Record is a record stored in the fake server.
+type Record struct {
+	ID     string `json:"id"`
+	ZoneID string `json:"zone_id"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	TTL    *int   `json:"ttl,omitempty"`
+}
+
+type zone struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Server is an in-process fake of the zones and records endpoints of the Hetzner DNS API.
+type Server struct {
+	*httptest.Server
+
+	token string
+
+	mu      sync.Mutex
+	counter int
+	zones   map[string]zone
+	records map[string]Record
+}
+
+// NewServer starts a fake server that accepts requests authenticated with the given token.
+// The caller has to close the server.
+func NewServer(token string) *Server {
+	s := &Server{
+		token:   token,
+		zones:   make(map[string]zone),
+		records: make(map[string]Record),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// AddZone adds a zone and returns its ID.
+func (s *Server) AddZone(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID("zone")
+	s.zones[id] = zone{ID: id, Name: name}
+	return id
+}
+
+// AddRecord adds a record and returns its ID.
+func (s *Server) AddRecord(record Record) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record.ID = s.nextID("record")
+	s.records[record.ID] = record
+	return record.ID
+}
+
+// Records returns all records of a zone sorted by ID.
+func (s *Server) Records(zoneID string) []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.recordsOfZone(zoneID)
+}
+
+func (s *Server) nextID(prefix string) string {
+	s.counter++
+	return fmt.Sprintf("%s-%03d", prefix, s.counter)
+}
+
+func (s *Server) recordsOfZone(zoneID string) []Record {
+	records := make([]Record, 0, len(s.records))
+	for _, r := range s.records {
+		if r.ZoneID == zoneID {
+			records = append(records, r)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Auth-API-Token") != s.token {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "Invalid authentication credentials"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/zones" && r.Method == http.MethodGet:
+		s.listZones(w, r)
+	case r.URL.Path == "/records" && r.Method == http.MethodGet:
+		s.listRecords(w, r)
+	case r.URL.Path == "/records" && r.Method == http.MethodPost:
+		s.createRecord(w, r)
+	case strings.HasPrefix(r.URL.Path, "/records/") && r.Method == http.MethodPut:
+		s.updateRecord(w, r, strings.TrimPrefix(r.URL.Path, "/records/"))
+	case strings.HasPrefix(r.URL.Path, "/records/") && r.Method == http.MethodDelete:
+		s.deleteRecord(w, strings.TrimPrefix(r.URL.Path, "/records/"))
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "not found"})
+	}
+}
+
+func (s *Server) listZones(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	zones := []zone{}
+	for _, z := range s.zones {
+		if name == "" || z.Name == name {
+			zones = append(zones, z)
+		}
+	}
+	if name != "" && len(zones) == 0 {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "zone not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"zones": zones})
+}
+
+func (s *Server) listRecords(w http.ResponseWriter, r *http.Request) {
+	records := s.recordsOfZone(r.URL.Query().Get("zone_id"))
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	lastPage := (len(records) + PerPage - 1) / PerPage
+	if lastPage == 0 {
+		lastPage = 1
+	}
+
+	start := min((page-1)*PerPage, len(records))
+	end := min(start+PerPage, len(records))
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"records": records[start:end],
+		"meta": map[string]any{
+			"pagination": map[string]int{"page": page, "per_page": PerPage, "last_page": lastPage, "total_entries": len(records)},
+		},
+	})
+}
+
+func (s *Server) createRecord(w http.ResponseWriter, r *http.Request) {
+	var record Record
+	if !s.decodeRecord(w, r, &record) {
+		return
+	}
+	record.ID = s.nextID("record")
+	s.records[record.ID] = record
+	writeJSON(w, http.StatusOK, map[string]any{"record": record})
+}
+
+func (s *Server) updateRecord(w http.ResponseWriter, r *http.Request, id string) {
+	if _, ok := s.records[id]; !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "record not found"})
+		return
+	}
+	var record Record
+	if !s.decodeRecord(w, r, &record) {
+		return
+	}
+	record.ID = id
+	s.records[id] = record
+	writeJSON(w, http.StatusOK, map[string]any{"record": record})
+}
+
+func (s *Server) deleteRecord(w http.ResponseWriter, id string) {
+	if _, ok := s.records[id]; !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "record not found"})
+		return
+	}
+	delete(s.records, id)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) decodeRecord(w http.ResponseWriter, r *http.Request, record *Record) bool {
+	if err := json.NewDecoder(r.Body).Decode(record); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		return false
+	}
+	if _, ok := s.zones[record.ZoneID]; !ok || record.Type == "" || record.Name == "" || record.Value == "" {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "invalid record"})
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultHetznerEndpoint = "https://dns.hetzner.com/api/v1"
+	hetznerTokenHeader     = "Auth-API-Token" // #nosec
+	hetznerRequestTimeout  = 30 * time.Second
+)
+
+// hetznerProvider implements Provider with the Hetzner DNS API.
+type hetznerProvider struct {
+	endpoint   string
+	token      string
+	httpClient *http.Client
+}
+
+func newHetznerProvider(endpoint, token string) *hetznerProvider {
+	return &hetznerProvider{
+		endpoint:   endpoint,
+		token:      token,
+		httpClient: &http.Client{Timeout: hetznerRequestTimeout},
+	}
+}
+
+type hetznerZone struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type hetznerRecord struct {
+	ID     string `json:"id,omitempty"`
+	ZoneID string `json:"zone_id"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	TTL    *int   `json:"ttl,omitempty"`
+}
+
+type hetznerMeta struct {
+	Pagination struct {
+		Page     int `json:"page"`
+		LastPage int `json:"last_page"`
+	} `json:"pagination"`
+}
+
+func (r hetznerRecord) toRecord() Record {
+	record := Record{
+		ID:    r.ID,
+		Type:  r.Type,
+		Name:  r.Name,
+		Value: r.Value,
+	}
+	if r.TTL != nil {
+		record.TTL = *r.TTL
+	}
+	return record
+}
+
+func fromRecord(zoneID string, record Record) hetznerRecord {
+	r := hetznerRecord{
+		ZoneID: zoneID,
+		Type:   record.Type,
+		Name:   record.Name,
+		Value:  record.Value,
+	}
+	if record.TTL > 0 {
+		ttl := record.TTL
+		r.TTL = &ttl
+	}
+	return r
+}
+
+// GetZoneID implements the GetZoneID method of the Provider interface.
+func (p *hetznerProvider) GetZoneID(ctx context.Context, zone string) (string, error) {
+	var resp struct {
+		Zones []hetznerZone `json:"zones"`
+	}
+	if err := p.do(ctx, http.MethodGet, "/zones?"+url.Values{"name": {zone}}.Encode(), nil, &resp); err != nil {
+		return "", fmt.Errorf("failed to get zone %s: %w", zone, err)
+	}
+	for _, z := range resp.Zones {
+		if z.Name == zone {
+			return z.ID, nil
+		}
+	}
+	return "", fmt.Errorf("failed to get zone %s: %w", zone, ErrNotFound)
+}
+
+// ListRecords implements the ListRecords method of the Provider interface.
+func (p *hetznerProvider) ListRecords(ctx context.Context, zoneID string) ([]Record, error) {
+	var records []Record
+	for page := 1; ; page++ {
+		var resp struct {
+			Records []hetznerRecord `json:"records"`
+			Meta    hetznerMeta     `json:"meta"`
+		}
+		query := url.Values{"zone_id": {zoneID}, "page": {strconv.Itoa(page)}}
+		if err := p.do(ctx, http.MethodGet, "/records?"+query.Encode(), nil, &resp); err != nil {
+			return nil, fmt.Errorf("failed to list records of zone %s: %w", zoneID, err)
+		}
+		for _, r := range resp.Records {
+			records = append(records, r.toRecord())
+		}
+		if page >= resp.Meta.Pagination.LastPage {
+			return records, nil
+		}
+	}
+}
+
+// CreateRecord implements the CreateRecord method of the Provider interface.
+func (p *hetznerProvider) CreateRecord(ctx context.Context, zoneID string, record Record) (Record, error) {
+	var resp struct {
+		Record hetznerRecord `json:"record"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/records", fromRecord(zoneID, record), &resp); err != nil {
+		return Record{}, fmt.Errorf("failed to create %s record %s: %w", record.Type, record.Name, err)
+	}
+	return resp.Record.toRecord(), nil
+}
+
+// UpdateRecord implements the UpdateRecord method of the Provider interface.
+func (p *hetznerProvider) UpdateRecord(ctx context.Context, zoneID string, record Record) (Record, error) {
+	var resp struct {
+		Record hetznerRecord `json:"record"`
+	}
+	if err := p.do(ctx, http.MethodPut, "/records/"+url.PathEscape(record.ID), fromRecord(zoneID, record), &resp); err != nil {
+		return Record{}, fmt.Errorf("failed to update %s record %s: %w", record.Type, record.Name, err)
+	}
+	return resp.Record.toRecord(), nil
+}
+
+// DeleteRecord implements the DeleteRecord method of the Provider interface.
+func (p *hetznerProvider) DeleteRecord(ctx context.Context, _, recordID string) error {
+	if err := p.do(ctx, http.MethodDelete, "/records/"+url.PathEscape(recordID), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete record %s: %w", recordID, err)
+	}
+	return nil
+}
+
+func (p *hetznerProvider) do(ctx context.Context, method, path string, body, result any) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.endpoint+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set(hetznerTokenHeader, p.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode >= http.StatusBadRequest:
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+
+	if result == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
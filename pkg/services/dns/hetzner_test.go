@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/dns/fake"
+)
+
+var _ = Describe("hetznerProvider", func() {
+	var (
+		ctx      context.Context
+		server   *fake.Server
+		provider *hetznerProvider
+		zoneID   string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server = fake.NewServer("token")
+		DeferCleanup(server.Close)
+		provider = newHetznerProvider(server.URL, "token")
+		zoneID = server.AddZone("example.com")
+	})
+
+	It("gets the ID of a zone", func() {
+		Expect(provider.GetZoneID(ctx, "example.com")).To(Equal(zoneID))
+	})
+
+	It("returns ErrNotFound for an unknown zone", func() {
+		_, err := provider.GetZoneID(ctx, "example.org")
+		Expect(err).To(MatchError(ErrNotFound))
+	})
+
+	It("returns ErrUnauthorized for an invalid token", func() {
+		_, err := newHetznerProvider(server.URL, "invalid").GetZoneID(ctx, "example.com")
+		Expect(err).To(MatchError(ErrUnauthorized))
+	})
+
+	It("lists the records of all pages", func() {
+		for _, value := range []string{"1.2.3.4", "1.2.3.5", "1.2.3.6"} {
+			server.AddRecord(fake.Record{ZoneID: zoneID, Type: RecordTypeA, Name: "www", Value: value})
+		}
+		server.AddRecord(fake.Record{ZoneID: server.AddZone("example.org"), Type: RecordTypeA, Name: "www", Value: "1.2.3.7"})
+
+		records, err := provider.ListRecords(ctx, zoneID)
+		Expect(err).To(Succeed())
+		Expect(records).To(HaveLen(fake.PerPage + 1))
+	})
+
+	It("creates, updates and deletes a record", func() {
+		rec, err := provider.CreateRecord(ctx, zoneID, Record{Type: RecordTypeA, Name: "api", Value: "1.2.3.4", TTL: 300})
+		Expect(err).To(Succeed())
+		Expect(rec.ID).ToNot(BeEmpty())
+		Expect(rec.TTL).To(Equal(300))
+
+		rec.Value = "1.2.3.5"
+		_, err = provider.UpdateRecord(ctx, zoneID, rec)
+		Expect(err).To(Succeed())
+		Expect(server.Records(zoneID)).To(ConsistOf(HaveField("Value", "1.2.3.5")))
+
+		Expect(provider.DeleteRecord(ctx, zoneID, rec.ID)).To(Succeed())
+		Expect(server.Records(zoneID)).To(BeEmpty())
+
+		Expect(provider.DeleteRecord(ctx, zoneID, rec.ID)).To(MatchError(ErrNotFound))
+	})
+})
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+const (
+	// RecordTypeA is the type of records that point to an IPv4 address.
+	RecordTypeA = "A"
+	// RecordTypeAAAA is the type of records that point to an IPv6 address.
+	RecordTypeAAAA = "AAAA"
+	// RecordTypeTXT is the type of records that hold text, e.g. the owner of the records of a name.
+	RecordTypeTXT = "TXT"
+)
+
+var (
+	// ErrNotFound is returned if a zone or record does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized is returned if the API token of the DNS provider is invalid.
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// Record is a DNS record.
+type Record struct {
+	ID    string
+	Type  string
+	Name  string
+	Value string
+	TTL   int
+}
+
+// Provider is the interface of DNS providers that manage the records of a zone.
+type Provider interface {
+	// GetZoneID returns the ID of the zone with the given name.
+	GetZoneID(ctx context.Context, zone string) (string, error)
+	// ListRecords returns all records of a zone.
+	ListRecords(ctx context.Context, zoneID string) ([]Record, error)
+	// CreateRecord creates a record in a zone and returns it with its ID.
+	CreateRecord(ctx context.Context, zoneID string, record Record) (Record, error)
+	// UpdateRecord updates the record with the ID of the given record.
+	UpdateRecord(ctx context.Context, zoneID string, record Record) (Record, error)
+	// DeleteRecord deletes a record. It returns ErrNotFound if the record does not exist.
+	DeleteRecord(ctx context.Context, zoneID, recordID string) error
+}
+
+// ProviderFactory is the interface for creating new Provider objects.
+type ProviderFactory interface {
+	// NewProvider returns a Provider of the given type that authenticates with the given token.
+	NewProvider(provider infrav1.DNSProvider, token string) (Provider, error)
+}
+
+type factory struct {
+	hetznerEndpoint string
+}
+
+// NewFactory creates a new factory for DNS providers.
+func NewFactory() ProviderFactory {
+	return &factory{hetznerEndpoint: defaultHetznerEndpoint}
+}
+
+// NewProvider implements the NewProvider method of the ProviderFactory interface.
+func (f *factory) NewProvider(provider infrav1.DNSProvider, token string) (Provider, error) {
+	switch provider {
+	case infrav1.DNSProviderHetzner, "":
+		return newHetznerProvider(f.hetznerEndpoint, token), nil
+	default:
+		return nil, fmt.Errorf("unsupported DNS provider %q", provider)
+	}
+}
@@ -47,6 +47,7 @@ type Client interface {
 	DeleteLoadBalancer(context.Context, int64) error
 	ListLoadBalancers(context.Context, hcloud.LoadBalancerListOpts) ([]*hcloud.LoadBalancer, error)
 	AttachLoadBalancerToNetwork(context.Context, *hcloud.LoadBalancer, hcloud.LoadBalancerAttachToNetworkOpts) error
+	DetachLoadBalancerFromNetwork(context.Context, *hcloud.LoadBalancer, hcloud.LoadBalancerDetachFromNetworkOpts) error
 	ChangeLoadBalancerType(context.Context, *hcloud.LoadBalancer, hcloud.LoadBalancerChangeTypeOpts) error
 	ChangeLoadBalancerAlgorithm(context.Context, *hcloud.LoadBalancer, hcloud.LoadBalancerChangeAlgorithmOpts) error
 	UpdateLoadBalancer(context.Context, *hcloud.LoadBalancer, hcloud.LoadBalancerUpdateOpts) (*hcloud.LoadBalancer, error)
@@ -54,27 +55,61 @@ type Client interface {
 	DeleteTargetServerOfLoadBalancer(context.Context, *hcloud.LoadBalancer, *hcloud.Server) error
 	AddIPTargetToLoadBalancer(context.Context, hcloud.LoadBalancerAddIPTargetOpts, *hcloud.LoadBalancer) error
 	DeleteIPTargetOfLoadBalancer(context.Context, *hcloud.LoadBalancer, net.IP) error
+	AddLabelSelectorTargetToLoadBalancer(context.Context, hcloud.LoadBalancerAddLabelSelectorTargetOpts, *hcloud.LoadBalancer) error
+	DeleteLabelSelectorTargetOfLoadBalancer(context.Context, *hcloud.LoadBalancer, string) error
 	AddServiceToLoadBalancer(context.Context, *hcloud.LoadBalancer, hcloud.LoadBalancerAddServiceOpts) error
 	DeleteServiceFromLoadBalancer(context.Context, *hcloud.LoadBalancer, int) error
+	UpdateServiceOfLoadBalancer(context.Context, *hcloud.LoadBalancer, int, hcloud.LoadBalancerUpdateServiceOpts) error
 	ListImages(context.Context, hcloud.ImageListOpts) ([]*hcloud.Image, error)
+	GetImage(context.Context, int64) (*hcloud.Image, error)
+	CreateImage(context.Context, *hcloud.Server, hcloud.ServerCreateImageOpts) (*hcloud.Image, error)
+	DeleteImage(context.Context, int64) error
 	CreateServer(context.Context, hcloud.ServerCreateOpts) (*hcloud.Server, error)
 	AttachServerToNetwork(context.Context, *hcloud.Server, hcloud.ServerAttachToNetworkOpts) error
 	ListServers(context.Context, hcloud.ServerListOpts) ([]*hcloud.Server, error)
 	GetServer(context.Context, int64) (*hcloud.Server, error)
+	UpdateServer(context.Context, *hcloud.Server, hcloud.ServerUpdateOpts) (*hcloud.Server, error)
 	DeleteServer(context.Context, *hcloud.Server) error
 	ListServerTypes(context.Context) ([]*hcloud.ServerType, error)
 	GetServerType(context.Context, string) (*hcloud.ServerType, error)
 	PowerOnServer(context.Context, *hcloud.Server) error
 	ShutdownServer(context.Context, *hcloud.Server) error
+	PowerOffServer(context.Context, *hcloud.Server) error
 	RebootServer(context.Context, *hcloud.Server) error
+	ResetServer(context.Context, *hcloud.Server) error
+	EnableRescueSystem(context.Context, *hcloud.Server, hcloud.ServerEnableRescueOpts) error
+	RebuildServer(context.Context, *hcloud.Server, hcloud.ServerRebuildOpts) error
+	EnableServerBackup(context.Context, *hcloud.Server) error
+	DisableServerBackup(context.Context, *hcloud.Server) error
 	CreateNetwork(context.Context, hcloud.NetworkCreateOpts) (*hcloud.Network, error)
 	ListNetworks(context.Context, hcloud.NetworkListOpts) ([]*hcloud.Network, error)
+	GetNetwork(context.Context, int64) (*hcloud.Network, error)
 	DeleteNetwork(context.Context, *hcloud.Network) error
+	AddSubnetToNetwork(context.Context, *hcloud.Network, hcloud.NetworkAddSubnetOpts) error
+	AddRouteToNetwork(context.Context, *hcloud.Network, hcloud.NetworkAddRouteOpts) error
+	DeleteRouteFromNetwork(context.Context, *hcloud.Network, hcloud.NetworkDeleteRouteOpts) error
 	ListSSHKeys(context.Context, hcloud.SSHKeyListOpts) ([]*hcloud.SSHKey, error)
 	CreatePlacementGroup(context.Context, hcloud.PlacementGroupCreateOpts) (*hcloud.PlacementGroup, error)
 	DeletePlacementGroup(context.Context, int64) error
 	ListPlacementGroups(context.Context, hcloud.PlacementGroupListOpts) ([]*hcloud.PlacementGroup, error)
 	AddServerToPlacementGroup(context.Context, *hcloud.Server, *hcloud.PlacementGroup) error
+	CreateFirewall(context.Context, hcloud.FirewallCreateOpts) (*hcloud.Firewall, error)
+	DeleteFirewall(context.Context, int64) error
+	ListFirewalls(context.Context, hcloud.FirewallListOpts) ([]*hcloud.Firewall, error)
+	SetFirewallRules(context.Context, *hcloud.Firewall, hcloud.FirewallSetRulesOpts) error
+	ApplyFirewallResources(context.Context, *hcloud.Firewall, []hcloud.FirewallResource) error
+	RemoveFirewallResources(context.Context, *hcloud.Firewall, []hcloud.FirewallResource) error
+	CreateFloatingIP(context.Context, hcloud.FloatingIPCreateOpts) (*hcloud.FloatingIP, error)
+	DeleteFloatingIP(context.Context, int64) error
+	ListFloatingIPs(context.Context, hcloud.FloatingIPListOpts) ([]*hcloud.FloatingIP, error)
+	AssignFloatingIP(context.Context, *hcloud.FloatingIP, *hcloud.Server) error
+	UnassignFloatingIP(context.Context, *hcloud.FloatingIP) error
+	CreateVolume(context.Context, hcloud.VolumeCreateOpts) (*hcloud.Volume, error)
+	DeleteVolume(context.Context, int64) error
+	ListVolumes(context.Context, hcloud.VolumeListOpts) ([]*hcloud.Volume, error)
+	AttachVolume(context.Context, *hcloud.Volume, hcloud.VolumeAttachOpts) error
+	DetachVolume(context.Context, *hcloud.Volume) error
+	UpdateVolume(context.Context, *hcloud.Volume, hcloud.VolumeUpdateOpts) (*hcloud.Volume, error)
 }
 
 // Factory is the interface for creating new Client objects.
@@ -170,6 +205,11 @@ func (c *realClient) AttachLoadBalancerToNetwork(ctx context.Context, lb *hcloud
 	return err
 }
 
+func (c *realClient) DetachLoadBalancerFromNetwork(ctx context.Context, lb *hcloud.LoadBalancer, opts hcloud.LoadBalancerDetachFromNetworkOpts) error {
+	_, _, err := c.client.LoadBalancer.DetachFromNetwork(ctx, lb, opts)
+	return err
+}
+
 func (c *realClient) ChangeLoadBalancerType(ctx context.Context, lb *hcloud.LoadBalancer, opts hcloud.LoadBalancerChangeTypeOpts) error {
 	_, _, err := c.client.LoadBalancer.ChangeType(ctx, lb, opts)
 	return err
@@ -214,6 +254,22 @@ func (c *realClient) DeleteIPTargetOfLoadBalancer(ctx context.Context, lb *hclou
 	return err
 }
 
+func (c *realClient) AddLabelSelectorTargetToLoadBalancer(ctx context.Context, opts hcloud.LoadBalancerAddLabelSelectorTargetOpts, lb *hcloud.LoadBalancer) error {
+	_, _, err := c.client.LoadBalancer.AddLabelSelectorTarget(ctx, lb, opts)
+	if err != nil && strings.Contains(err.Error(), errStringUnauthorized) {
+		return fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return err
+}
+
+func (c *realClient) DeleteLabelSelectorTargetOfLoadBalancer(ctx context.Context, lb *hcloud.LoadBalancer, selector string) error {
+	_, _, err := c.client.LoadBalancer.RemoveLabelSelectorTarget(ctx, lb, selector)
+	if err != nil && strings.Contains(err.Error(), errStringUnauthorized) {
+		return fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return err
+}
+
 func (c *realClient) AddServiceToLoadBalancer(ctx context.Context, lb *hcloud.LoadBalancer, opts hcloud.LoadBalancerAddServiceOpts) error {
 	_, _, err := c.client.LoadBalancer.AddService(ctx, lb, opts)
 	return err
@@ -224,10 +280,30 @@ func (c *realClient) DeleteServiceFromLoadBalancer(ctx context.Context, lb *hclo
 	return err
 }
 
+func (c *realClient) UpdateServiceOfLoadBalancer(ctx context.Context, lb *hcloud.LoadBalancer, listenPort int, opts hcloud.LoadBalancerUpdateServiceOpts) error {
+	_, _, err := c.client.LoadBalancer.UpdateService(ctx, lb, listenPort, opts)
+	return err
+}
+
 func (c *realClient) ListImages(ctx context.Context, opts hcloud.ImageListOpts) ([]*hcloud.Image, error) {
 	return c.client.Image.AllWithOpts(ctx, opts)
 }
 
+func (c *realClient) GetImage(ctx context.Context, id int64) (*hcloud.Image, error) {
+	res, _, err := c.client.Image.GetByID(ctx, id)
+	return res, err
+}
+
+func (c *realClient) CreateImage(ctx context.Context, server *hcloud.Server, opts hcloud.ServerCreateImageOpts) (*hcloud.Image, error) {
+	res, _, err := c.client.Server.CreateImage(ctx, server, &opts)
+	return res.Image, err
+}
+
+func (c *realClient) DeleteImage(ctx context.Context, id int64) error {
+	_, err := c.client.Image.Delete(ctx, &hcloud.Image{ID: id})
+	return err
+}
+
 func (c *realClient) CreateServer(ctx context.Context, opts hcloud.ServerCreateOpts) (*hcloud.Server, error) {
 	res, _, err := c.client.Server.Create(ctx, opts)
 	return res.Server, err
@@ -254,6 +330,11 @@ func (c *realClient) GetServer(ctx context.Context, id int64) (*hcloud.Server, e
 	return res, err
 }
 
+func (c *realClient) UpdateServer(ctx context.Context, server *hcloud.Server, opts hcloud.ServerUpdateOpts) (*hcloud.Server, error) {
+	res, _, err := c.client.Server.Update(ctx, server, opts)
+	return res, err
+}
+
 func (c *realClient) ListServerTypes(ctx context.Context) ([]*hcloud.ServerType, error) {
 	resp, err := c.client.ServerType.All(ctx)
 	if err != nil && strings.Contains(err.Error(), errStringUnauthorized) {
@@ -272,11 +353,41 @@ func (c *realClient) ShutdownServer(ctx context.Context, server *hcloud.Server)
 	return err
 }
 
+func (c *realClient) PowerOffServer(ctx context.Context, server *hcloud.Server) error {
+	_, _, err := c.client.Server.Poweroff(ctx, server)
+	return err
+}
+
 func (c *realClient) RebootServer(ctx context.Context, server *hcloud.Server) error {
 	_, _, err := c.client.Server.Reboot(ctx, server)
 	return err
 }
 
+func (c *realClient) ResetServer(ctx context.Context, server *hcloud.Server) error {
+	_, _, err := c.client.Server.Reset(ctx, server)
+	return err
+}
+
+func (c *realClient) EnableRescueSystem(ctx context.Context, server *hcloud.Server, opts hcloud.ServerEnableRescueOpts) error {
+	_, _, err := c.client.Server.EnableRescue(ctx, server, opts)
+	return err
+}
+
+func (c *realClient) RebuildServer(ctx context.Context, server *hcloud.Server, opts hcloud.ServerRebuildOpts) error {
+	_, _, err := c.client.Server.RebuildWithResult(ctx, server, opts)
+	return err
+}
+
+func (c *realClient) EnableServerBackup(ctx context.Context, server *hcloud.Server) error {
+	_, _, err := c.client.Server.EnableBackup(ctx, server, "")
+	return err
+}
+
+func (c *realClient) DisableServerBackup(ctx context.Context, server *hcloud.Server) error {
+	_, _, err := c.client.Server.DisableBackup(ctx, server)
+	return err
+}
+
 func (c *realClient) PowerOnServer(ctx context.Context, server *hcloud.Server) error {
 	_, _, err := c.client.Server.Poweron(ctx, server)
 	return err
@@ -300,11 +411,34 @@ func (c *realClient) ListNetworks(ctx context.Context, opts hcloud.NetworkListOp
 	return resp, err
 }
 
+func (c *realClient) GetNetwork(ctx context.Context, id int64) (*hcloud.Network, error) {
+	res, _, err := c.client.Network.GetByID(ctx, id)
+	if err != nil && strings.Contains(err.Error(), errStringUnauthorized) {
+		return res, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return res, err
+}
+
 func (c *realClient) DeleteNetwork(ctx context.Context, network *hcloud.Network) error {
 	_, err := c.client.Network.Delete(ctx, network)
 	return err
 }
 
+func (c *realClient) AddSubnetToNetwork(ctx context.Context, network *hcloud.Network, opts hcloud.NetworkAddSubnetOpts) error {
+	_, _, err := c.client.Network.AddSubnet(ctx, network, opts)
+	return err
+}
+
+func (c *realClient) AddRouteToNetwork(ctx context.Context, network *hcloud.Network, opts hcloud.NetworkAddRouteOpts) error {
+	_, _, err := c.client.Network.AddRoute(ctx, network, opts)
+	return err
+}
+
+func (c *realClient) DeleteRouteFromNetwork(ctx context.Context, network *hcloud.Network, opts hcloud.NetworkDeleteRouteOpts) error {
+	_, _, err := c.client.Network.DeleteRoute(ctx, network, opts)
+	return err
+}
+
 func (c *realClient) ListSSHKeys(ctx context.Context, opts hcloud.SSHKeyListOpts) ([]*hcloud.SSHKey, error) {
 	res, _, err := c.client.SSHKey.List(ctx, opts)
 	return res, err
@@ -328,3 +462,100 @@ func (c *realClient) AddServerToPlacementGroup(ctx context.Context, server *hclo
 	_, _, err := c.client.Server.AddToPlacementGroup(ctx, server, pg)
 	return err
 }
+
+func (c *realClient) CreateFirewall(ctx context.Context, opts hcloud.FirewallCreateOpts) (*hcloud.Firewall, error) {
+	res, _, err := c.client.Firewall.Create(ctx, opts)
+	return res.Firewall, err
+}
+
+func (c *realClient) DeleteFirewall(ctx context.Context, id int64) error {
+	_, err := c.client.Firewall.Delete(ctx, &hcloud.Firewall{ID: id})
+	return err
+}
+
+func (c *realClient) ListFirewalls(ctx context.Context, opts hcloud.FirewallListOpts) ([]*hcloud.Firewall, error) {
+	resp, err := c.client.Firewall.AllWithOpts(ctx, opts)
+	if err != nil && strings.Contains(err.Error(), errStringUnauthorized) {
+		return resp, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return resp, err
+}
+
+func (c *realClient) SetFirewallRules(ctx context.Context, firewall *hcloud.Firewall, opts hcloud.FirewallSetRulesOpts) error {
+	_, _, err := c.client.Firewall.SetRules(ctx, firewall, opts)
+	return err
+}
+
+func (c *realClient) ApplyFirewallResources(ctx context.Context, firewall *hcloud.Firewall, resources []hcloud.FirewallResource) error {
+	_, _, err := c.client.Firewall.ApplyResources(ctx, firewall, resources)
+	return err
+}
+
+func (c *realClient) RemoveFirewallResources(ctx context.Context, firewall *hcloud.Firewall, resources []hcloud.FirewallResource) error {
+	_, _, err := c.client.Firewall.RemoveResources(ctx, firewall, resources)
+	return err
+}
+
+func (c *realClient) CreateFloatingIP(ctx context.Context, opts hcloud.FloatingIPCreateOpts) (*hcloud.FloatingIP, error) {
+	res, _, err := c.client.FloatingIP.Create(ctx, opts)
+	return res.FloatingIP, err
+}
+
+func (c *realClient) DeleteFloatingIP(ctx context.Context, id int64) error {
+	_, err := c.client.FloatingIP.Delete(ctx, &hcloud.FloatingIP{ID: id})
+	return err
+}
+
+func (c *realClient) ListFloatingIPs(ctx context.Context, opts hcloud.FloatingIPListOpts) ([]*hcloud.FloatingIP, error) {
+	resp, err := c.client.FloatingIP.AllWithOpts(ctx, opts)
+	if err != nil && strings.Contains(err.Error(), errStringUnauthorized) {
+		return resp, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return resp, err
+}
+
+func (c *realClient) AssignFloatingIP(ctx context.Context, floatingIP *hcloud.FloatingIP, server *hcloud.Server) error {
+	_, _, err := c.client.FloatingIP.Assign(ctx, floatingIP, server)
+	return err
+}
+
+func (c *realClient) UnassignFloatingIP(ctx context.Context, floatingIP *hcloud.FloatingIP) error {
+	_, _, err := c.client.FloatingIP.Unassign(ctx, floatingIP)
+	return err
+}
+
+func (c *realClient) CreateVolume(ctx context.Context, opts hcloud.VolumeCreateOpts) (*hcloud.Volume, error) {
+	res, _, err := c.client.Volume.Create(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return res.Volume, nil
+}
+
+func (c *realClient) DeleteVolume(ctx context.Context, id int64) error {
+	_, err := c.client.Volume.Delete(ctx, &hcloud.Volume{ID: id})
+	return err
+}
+
+func (c *realClient) ListVolumes(ctx context.Context, opts hcloud.VolumeListOpts) ([]*hcloud.Volume, error) {
+	resp, err := c.client.Volume.AllWithOpts(ctx, opts)
+	if err != nil && strings.Contains(err.Error(), errStringUnauthorized) {
+		return resp, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return resp, err
+}
+
+func (c *realClient) AttachVolume(ctx context.Context, volume *hcloud.Volume, opts hcloud.VolumeAttachOpts) error {
+	_, _, err := c.client.Volume.AttachWithOpts(ctx, volume, opts)
+	return err
+}
+
+func (c *realClient) DetachVolume(ctx context.Context, volume *hcloud.Volume) error {
+	_, _, err := c.client.Volume.Detach(ctx, volume)
+	return err
+}
+
+func (c *realClient) UpdateVolume(ctx context.Context, volume *hcloud.Volume, opts hcloud.VolumeUpdateOpts) (*hcloud.Volume, error) {
+	res, _, err := c.client.Volume.Update(ctx, volume, opts)
+	return res, err
+}
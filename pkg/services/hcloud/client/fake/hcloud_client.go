@@ -21,7 +21,10 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"slices"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 
@@ -35,6 +38,9 @@ const DefaultCPUCores = 1
 // DefaultMemoryInGB defines the default memory in GB for HCloud machines' capacities.
 const DefaultMemoryInGB = float32(4)
 
+// DefaultDiskInGB defines the default disk size in GB for HCloud machines' capacities.
+const DefaultDiskInGB = 40
+
 // DefaultArchitecture defines the default CPU architecture for HCloud server types.
 const DefaultArchitecture = hcloud.ArchitectureX86
 
@@ -43,11 +49,19 @@ type cacheHCloudClient struct {
 	placementGroupCache     placementGroupCache
 	loadBalancerCache       loadBalancerCache
 	networkCache            networkCache
+	firewallCache           firewallCache
+	floatingIPCache         floatingIPCache
+	volumeCache             volumeCache
+	imageCache              imageCache
 	mutex                   sync.RWMutex
 	serverIDCounter         int64
 	placementGroupIDCounter int64
 	loadBalancerIDCounter   int64
 	networkIDCounter        int64
+	firewallIDCounter       int64
+	floatingIPIDCounter     int64
+	volumeIDCounter         int64
+	imageIDCounter          int64
 }
 
 // NewClient gives reference to the fake client using cache for HCloud API.
@@ -60,32 +74,53 @@ func (c *cacheHCloudClient) Reset() {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	cacheHCloudClientInstance.serverCache = serverCache{}
-	cacheHCloudClientInstance.networkCache = networkCache{}
-	cacheHCloudClientInstance.loadBalancerCache = loadBalancerCache{}
-	cacheHCloudClientInstance.placementGroupCache = placementGroupCache{}
+	c.serverCache = serverCache{}
+	c.networkCache = networkCache{}
+	c.loadBalancerCache = loadBalancerCache{}
+	c.placementGroupCache = placementGroupCache{}
+	c.firewallCache = firewallCache{}
+	c.floatingIPCache = floatingIPCache{}
+	c.volumeCache = volumeCache{}
 
-	cacheHCloudClientInstance.serverCache = serverCache{
+	c.serverCache = serverCache{
 		idMap:   make(map[int64]*hcloud.Server),
 		nameMap: make(map[string]struct{}),
 	}
-	cacheHCloudClientInstance.placementGroupCache = placementGroupCache{
+	c.placementGroupCache = placementGroupCache{
 		idMap:   make(map[int64]*hcloud.PlacementGroup),
 		nameMap: make(map[string]struct{}),
 	}
-	cacheHCloudClientInstance.loadBalancerCache = loadBalancerCache{
+	c.loadBalancerCache = loadBalancerCache{
 		idMap:   make(map[int64]*hcloud.LoadBalancer),
 		nameMap: make(map[string]struct{}),
 	}
-	cacheHCloudClientInstance.networkCache = networkCache{
+	c.networkCache = networkCache{
 		idMap:   make(map[int64]*hcloud.Network),
 		nameMap: make(map[string]struct{}),
 	}
+	c.firewallCache = firewallCache{
+		idMap:   make(map[int64]*hcloud.Firewall),
+		nameMap: make(map[string]struct{}),
+	}
+	c.floatingIPCache = floatingIPCache{
+		idMap:   make(map[int64]*hcloud.FloatingIP),
+		nameMap: make(map[string]struct{}),
+	}
+	c.volumeCache = volumeCache{
+		idMap:   make(map[int64]*hcloud.Volume),
+		nameMap: make(map[string]struct{}),
+	}
+	c.imageCache = imageCache{
+		idMap: make(map[int64]*hcloud.Image),
+	}
 
-	cacheHCloudClientInstance.serverIDCounter = 0
-	cacheHCloudClientInstance.placementGroupIDCounter = 0
-	cacheHCloudClientInstance.loadBalancerIDCounter = 0
-	cacheHCloudClientInstance.networkIDCounter = 0
+	c.serverIDCounter = 0
+	c.placementGroupIDCounter = 0
+	c.loadBalancerIDCounter = 0
+	c.networkIDCounter = 0
+	c.firewallIDCounter = 0
+	c.floatingIPIDCounter = 0
+	c.volumeIDCounter = 0
 }
 
 type cacheHCloudClientFactory struct{}
@@ -107,6 +142,29 @@ var cacheHCloudClientInstance = &cacheHCloudClient{
 		idMap:   make(map[int64]*hcloud.Network),
 		nameMap: make(map[string]struct{}),
 	},
+	firewallCache: firewallCache{
+		idMap:   make(map[int64]*hcloud.Firewall),
+		nameMap: make(map[string]struct{}),
+	},
+	floatingIPCache: floatingIPCache{
+		idMap:   make(map[int64]*hcloud.FloatingIP),
+		nameMap: make(map[string]struct{}),
+	},
+	volumeCache: volumeCache{
+		idMap:   make(map[int64]*hcloud.Volume),
+		nameMap: make(map[string]struct{}),
+	},
+	imageCache: imageCache{
+		idMap: make(map[int64]*hcloud.Image),
+	},
+}
+
+// NewHCloudClient creates a fake HCloud client with its own cache, which is not shared with the clients of the
+// factory.
+func NewHCloudClient() hcloudclient.Client {
+	c := &cacheHCloudClient{}
+	c.Reset()
+	return c
 }
 
 // NewHCloudClientFactory creates new fake HCloud client factories using cache.
@@ -136,6 +194,25 @@ type placementGroupCache struct {
 	nameMap map[string]struct{}
 }
 
+type firewallCache struct {
+	idMap   map[int64]*hcloud.Firewall
+	nameMap map[string]struct{}
+}
+
+type floatingIPCache struct {
+	idMap   map[int64]*hcloud.FloatingIP
+	nameMap map[string]struct{}
+}
+
+type volumeCache struct {
+	idMap   map[int64]*hcloud.Volume
+	nameMap map[string]struct{}
+}
+
+type imageCache struct {
+	idMap map[int64]*hcloud.Image
+}
+
 var defaultSSHKey = hcloud.SSHKey{
 	ID:          1,
 	Name:        "testsshkey",
@@ -178,7 +255,8 @@ func (c *cacheHCloudClient) CreateLoadBalancer(_ context.Context, opts hcloud.Lo
 	}
 	if opts.Network != nil {
 		lb.PrivateNet = append(lb.PrivateNet, hcloud.LoadBalancerPrivateNet{
-			IP: net.IP("10.0.0.2"),
+			Network: opts.Network,
+			IP:      net.IP("10.0.0.2"),
 		})
 	}
 
@@ -256,7 +334,25 @@ func (c *cacheHCloudClient) AttachLoadBalancerToNetwork(_ context.Context, lb *h
 	// Add it
 	c.loadBalancerCache.idMap[lb.ID].PrivateNet = append(
 		c.loadBalancerCache.idMap[lb.ID].PrivateNet,
-		hcloud.LoadBalancerPrivateNet{IP: network.IPRange.IP},
+		hcloud.LoadBalancerPrivateNet{Network: network, IP: network.IPRange.IP},
+	)
+	return nil
+}
+
+func (c *cacheHCloudClient) DetachLoadBalancerFromNetwork(_ context.Context, lb *hcloud.LoadBalancer, opts hcloud.LoadBalancerDetachFromNetworkOpts) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Check if loadBalancer exists
+	if _, found := c.loadBalancerCache.idMap[lb.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	// Remove it
+	c.loadBalancerCache.idMap[lb.ID].PrivateNet = slices.DeleteFunc(
+		c.loadBalancerCache.idMap[lb.ID].PrivateNet,
+		func(privateNet hcloud.LoadBalancerPrivateNet) bool {
+			return privateNet.Network != nil && privateNet.Network.ID == opts.Network.ID
+		},
 	)
 	return nil
 }
@@ -354,6 +450,51 @@ func (c *cacheHCloudClient) DeleteTargetServerOfLoadBalancer(_ context.Context,
 	return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
 }
 
+func (c *cacheHCloudClient) AddLabelSelectorTargetToLoadBalancer(_ context.Context, opts hcloud.LoadBalancerAddLabelSelectorTargetOpts, lb *hcloud.LoadBalancer) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Check if loadBalancer exists
+	if _, found := c.loadBalancerCache.idMap[lb.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	// check if already exists
+	for _, t := range c.loadBalancerCache.idMap[lb.ID].Targets {
+		if t.Type == hcloud.LoadBalancerTargetTypeLabelSelector && t.LabelSelector.Selector == opts.Selector {
+			return hcloud.Error{Code: hcloud.ErrorCodeTargetAlreadyDefined, Message: "already added"}
+		}
+	}
+
+	// Add it
+	c.loadBalancerCache.idMap[lb.ID].Targets = append(
+		c.loadBalancerCache.idMap[lb.ID].Targets,
+		hcloud.LoadBalancerTarget{
+			Type:          hcloud.LoadBalancerTargetTypeLabelSelector,
+			LabelSelector: &hcloud.LoadBalancerTargetLabelSelector{Selector: opts.Selector},
+			UsePrivateIP:  opts.UsePrivateIP != nil && *opts.UsePrivateIP,
+		},
+	)
+	return nil
+}
+
+func (c *cacheHCloudClient) DeleteLabelSelectorTargetOfLoadBalancer(_ context.Context, lb *hcloud.LoadBalancer, selector string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Check if loadBalancer exists
+	if _, found := c.loadBalancerCache.idMap[lb.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	// delete it if it exists
+	for i, t := range c.loadBalancerCache.idMap[lb.ID].Targets {
+		if t.Type == hcloud.LoadBalancerTargetTypeLabelSelector && t.LabelSelector.Selector == selector {
+			c.loadBalancerCache.idMap[lb.ID].Targets = append(c.loadBalancerCache.idMap[lb.ID].Targets[:i], c.loadBalancerCache.idMap[lb.ID].Targets[i+1:]...)
+			return nil
+		}
+	}
+	return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+}
+
 func (c *cacheHCloudClient) AddIPTargetToLoadBalancer(_ context.Context, opts hcloud.LoadBalancerAddIPTargetOpts, lb *hcloud.LoadBalancer) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -417,12 +558,149 @@ func (c *cacheHCloudClient) AddServiceToLoadBalancer(_ context.Context, lb *hclo
 		}
 	}
 
+	service := hcloud.LoadBalancerService{
+		Protocol:        opts.Protocol,
+		ListenPort:      *opts.ListenPort,
+		DestinationPort: *opts.DestinationPort,
+	}
+	if opts.Proxyprotocol != nil {
+		service.Proxyprotocol = *opts.Proxyprotocol
+	}
+	if opts.HTTP != nil {
+		service.HTTP = hcloud.LoadBalancerServiceHTTP{
+			Certificates: opts.HTTP.Certificates,
+		}
+		if opts.HTTP.CookieName != nil {
+			service.HTTP.CookieName = *opts.HTTP.CookieName
+		}
+		if opts.HTTP.CookieLifetime != nil {
+			service.HTTP.CookieLifetime = *opts.HTTP.CookieLifetime
+		}
+		if opts.HTTP.RedirectHTTP != nil {
+			service.HTTP.RedirectHTTP = *opts.HTTP.RedirectHTTP
+		}
+		if opts.HTTP.StickySessions != nil {
+			service.HTTP.StickySessions = *opts.HTTP.StickySessions
+		}
+	}
+	// HCloud checks the destination port via tcp by default
+	service.HealthCheck = healthCheckFromOpts(hcloud.LoadBalancerUpdateServiceOptsHealthCheck{
+		Protocol: hcloud.LoadBalancerServiceProtocolTCP,
+		Port:     opts.DestinationPort,
+	})
+	if opts.HealthCheck != nil {
+		healthCheckOpts := hcloud.LoadBalancerUpdateServiceOptsHealthCheck{
+			Protocol: opts.HealthCheck.Protocol,
+			Port:     opts.HealthCheck.Port,
+			Interval: opts.HealthCheck.Interval,
+			Timeout:  opts.HealthCheck.Timeout,
+			Retries:  opts.HealthCheck.Retries,
+		}
+		if opts.HealthCheck.HTTP != nil {
+			httpOpts := hcloud.LoadBalancerUpdateServiceOptsHealthCheckHTTP(*opts.HealthCheck.HTTP)
+			healthCheckOpts.HTTP = &httpOpts
+		}
+		service.HealthCheck = healthCheckFromOpts(healthCheckOpts)
+	}
+
 	// Add it
-	c.loadBalancerCache.idMap[lb.ID].Services = append(
-		c.loadBalancerCache.idMap[lb.ID].Services, hcloud.LoadBalancerService{ListenPort: *opts.ListenPort, DestinationPort: *opts.DestinationPort})
+	c.loadBalancerCache.idMap[lb.ID].Services = append(c.loadBalancerCache.idMap[lb.ID].Services, service)
 	return nil
 }
 
+func (c *cacheHCloudClient) UpdateServiceOfLoadBalancer(_ context.Context, lb *hcloud.LoadBalancer, listenPort int, opts hcloud.LoadBalancerUpdateServiceOpts) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Check if loadBalancer exists
+	if _, found := c.loadBalancerCache.idMap[lb.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	for i := range c.loadBalancerCache.idMap[lb.ID].Services {
+		service := &c.loadBalancerCache.idMap[lb.ID].Services[i]
+		if service.ListenPort != listenPort {
+			continue
+		}
+
+		if opts.Protocol != "" {
+			service.Protocol = opts.Protocol
+		}
+		if opts.DestinationPort != nil {
+			service.DestinationPort = *opts.DestinationPort
+		}
+		if opts.Proxyprotocol != nil {
+			service.Proxyprotocol = *opts.Proxyprotocol
+		}
+		if opts.HTTP != nil {
+			if opts.HTTP.Certificates != nil {
+				service.HTTP.Certificates = opts.HTTP.Certificates
+			}
+			if opts.HTTP.CookieName != nil {
+				service.HTTP.CookieName = *opts.HTTP.CookieName
+			}
+			if opts.HTTP.CookieLifetime != nil {
+				service.HTTP.CookieLifetime = *opts.HTTP.CookieLifetime
+			}
+			if opts.HTTP.RedirectHTTP != nil {
+				service.HTTP.RedirectHTTP = *opts.HTTP.RedirectHTTP
+			}
+			if opts.HTTP.StickySessions != nil {
+				service.HTTP.StickySessions = *opts.HTTP.StickySessions
+			}
+		}
+		if opts.HealthCheck != nil {
+			service.HealthCheck = healthCheckFromOpts(*opts.HealthCheck)
+		}
+		return nil
+	}
+
+	return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+}
+
+// healthCheckFromOpts applies the defaults of HCloud to the health check options.
+func healthCheckFromOpts(opts hcloud.LoadBalancerUpdateServiceOptsHealthCheck) hcloud.LoadBalancerServiceHealthCheck {
+	healthCheck := hcloud.LoadBalancerServiceHealthCheck{
+		Protocol: opts.Protocol,
+		Interval: 15 * time.Second,
+		Timeout:  10 * time.Second,
+		Retries:  3,
+	}
+	if opts.Port != nil {
+		healthCheck.Port = *opts.Port
+	}
+	if opts.Interval != nil {
+		healthCheck.Interval = *opts.Interval
+	}
+	if opts.Timeout != nil {
+		healthCheck.Timeout = *opts.Timeout
+	}
+	if opts.Retries != nil {
+		healthCheck.Retries = *opts.Retries
+	}
+	if opts.HTTP != nil {
+		healthCheck.HTTP = &hcloud.LoadBalancerServiceHealthCheckHTTP{
+			Path:        "/",
+			StatusCodes: []string{"2??", "3??"},
+		}
+		if opts.HTTP.Domain != nil {
+			healthCheck.HTTP.Domain = *opts.HTTP.Domain
+		}
+		if opts.HTTP.Path != nil {
+			healthCheck.HTTP.Path = *opts.HTTP.Path
+		}
+		if opts.HTTP.Response != nil {
+			healthCheck.HTTP.Response = *opts.HTTP.Response
+		}
+		if opts.HTTP.StatusCodes != nil {
+			healthCheck.HTTP.StatusCodes = opts.HTTP.StatusCodes
+		}
+		if opts.HTTP.TLS != nil {
+			healthCheck.HTTP.TLS = *opts.HTTP.TLS
+		}
+	}
+	return healthCheck
+}
+
 func (c *cacheHCloudClient) DeleteServiceFromLoadBalancer(_ context.Context, lb *hcloud.LoadBalancer, listenPort int) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -455,20 +733,82 @@ func (c *cacheHCloudClient) ListImages(_ context.Context, opts hcloud.ImageListO
 		return nil, fmt.Errorf("failed to convert label selector to labels: %w", err)
 	}
 
-	allLabelsFound := true
-	for key, label := range labels {
-		if val, found := defaultImage.Labels[key]; !found || val != label {
-			allLabelsFound = false
-			break
+	var images []*hcloud.Image
+	for _, image := range append([]*hcloud.Image{&defaultImage}, c.sortedImages()...) {
+		allLabelsFound := true
+		for key, label := range labels {
+			if val, found := image.Labels[key]; !found || val != label {
+				allLabelsFound = false
+				break
+			}
+		}
+		if allLabelsFound {
+			images = append(images, image)
 		}
 	}
-	if allLabelsFound {
-		return []*hcloud.Image{&defaultImage}, nil
+
+	return images, nil
+}
+
+func (c *cacheHCloudClient) sortedImages() []*hcloud.Image {
+	images := make([]*hcloud.Image, 0, len(c.imageCache.idMap))
+	for _, image := range c.imageCache.idMap {
+		images = append(images, image)
 	}
+	sort.Slice(images, func(i, j int) bool { return images[i].ID < images[j].ID })
+	return images
+}
 
+func (c *cacheHCloudClient) GetImage(_ context.Context, id int64) (*hcloud.Image, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if id == defaultImage.ID {
+		return &defaultImage, nil
+	}
+	if image, found := c.imageCache.idMap[id]; found {
+		return image, nil
+	}
 	return nil, nil
 }
 
+func (c *cacheHCloudClient) CreateImage(_ context.Context, server *hcloud.Server, opts hcloud.ServerCreateImageOpts) (*hcloud.Image, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, found := c.serverCache.idMap[server.ID]
+	if !found {
+		return nil, hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	c.imageIDCounter++
+	image := &hcloud.Image{
+		ID:          defaultImage.ID + c.imageIDCounter,
+		Type:        opts.Type,
+		Status:      hcloud.ImageStatusCreating,
+		Labels:      opts.Labels,
+		CreatedFrom: &hcloud.Server{ID: s.ID, Name: s.Name},
+		Created:     time.Now(),
+	}
+	if opts.Description != nil {
+		image.Description = *opts.Description
+	}
+	if s.ServerType != nil {
+		image.Architecture = s.ServerType.Architecture
+	}
+
+	c.imageCache.idMap[image.ID] = image
+	return image, nil
+}
+
+func (c *cacheHCloudClient) DeleteImage(_ context.Context, id int64) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, found := c.imageCache.idMap[id]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+	delete(c.imageCache.idMap, id)
+	return nil
+}
+
 func (c *cacheHCloudClient) CreateServer(_ context.Context, opts hcloud.ServerCreateOpts) (*hcloud.Server, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -488,6 +828,10 @@ func (c *cacheHCloudClient) CreateServer(_ context.Context, opts hcloud.ServerCr
 		Status:         hcloud.ServerStatusRunning,
 	}
 
+	if opts.Location != nil {
+		server.Datacenter = &hcloud.Datacenter{Location: opts.Location}
+	}
+
 	for _, network := range opts.Networks {
 		network, found := c.networkCache.idMap[network.ID]
 		if !found {
@@ -497,6 +841,12 @@ func (c *cacheHCloudClient) CreateServer(_ context.Context, opts hcloud.ServerCr
 		server.PrivateNet = append(server.PrivateNet, hcloud.ServerPrivateNet{IP: ip})
 	}
 
+	if opts.PlacementGroup != nil {
+		if pg, found := c.placementGroupCache.idMap[opts.PlacementGroup.ID]; found {
+			pg.Servers = append(pg.Servers, server.ID)
+		}
+	}
+
 	// Add server to cache
 	c.serverCache.idMap[server.ID] = server
 	c.serverCache.nameMap[server.Name] = struct{}{}
@@ -559,6 +909,24 @@ func (c *cacheHCloudClient) GetServer(_ context.Context, id int64) (*hcloud.Serv
 	return c.serverCache.idMap[id], nil
 }
 
+func (c *cacheHCloudClient) UpdateServer(_ context.Context, server *hcloud.Server, opts hcloud.ServerUpdateOpts) (*hcloud.Server, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, found := c.serverCache.idMap[server.ID]
+	if !found {
+		return nil, hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+	if opts.Name != "" {
+		delete(c.serverCache.nameMap, s.Name)
+		s.Name = opts.Name
+		c.serverCache.nameMap[s.Name] = struct{}{}
+	}
+	if opts.Labels != nil {
+		s.Labels = opts.Labels
+	}
+	return s, nil
+}
+
 func (c *cacheHCloudClient) ShutdownServer(_ context.Context, server *hcloud.Server) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -569,10 +937,76 @@ func (c *cacheHCloudClient) ShutdownServer(_ context.Context, server *hcloud.Ser
 	return nil
 }
 
+func (c *cacheHCloudClient) PowerOffServer(_ context.Context, server *hcloud.Server) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, found := c.serverCache.idMap[server.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+	c.serverCache.idMap[server.ID].Status = hcloud.ServerStatusOff
+	return nil
+}
+
 func (c *cacheHCloudClient) RebootServer(_ context.Context, _ *hcloud.Server) error {
 	return nil
 }
 
+func (c *cacheHCloudClient) ResetServer(_ context.Context, server *hcloud.Server) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, found := c.serverCache.idMap[server.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+	s.Status = hcloud.ServerStatusRunning
+	return nil
+}
+
+func (c *cacheHCloudClient) EnableRescueSystem(_ context.Context, server *hcloud.Server, _ hcloud.ServerEnableRescueOpts) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, found := c.serverCache.idMap[server.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+	s.RescueEnabled = true
+	return nil
+}
+
+func (c *cacheHCloudClient) RebuildServer(_ context.Context, server *hcloud.Server, opts hcloud.ServerRebuildOpts) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, found := c.serverCache.idMap[server.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+	s.Image = opts.Image
+	s.Status = hcloud.ServerStatusRebuilding
+	return nil
+}
+
+func (c *cacheHCloudClient) EnableServerBackup(_ context.Context, server *hcloud.Server) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, found := c.serverCache.idMap[server.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+	s.BackupWindow = "22-02"
+	return nil
+}
+
+func (c *cacheHCloudClient) DisableServerBackup(_ context.Context, server *hcloud.Server) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, found := c.serverCache.idMap[server.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+	s.BackupWindow = ""
+	return nil
+}
+
 func (c *cacheHCloudClient) PowerOnServer(_ context.Context, server *hcloud.Server) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -592,6 +1026,20 @@ func (c *cacheHCloudClient) DeleteServer(_ context.Context, server *hcloud.Serve
 		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
 	}
 
+	// IPs are unassigned when the server is deleted
+	for _, floatingIP := range c.floatingIPCache.idMap {
+		if floatingIP.Server != nil && floatingIP.Server.ID == server.ID {
+			floatingIP.Server = nil
+		}
+	}
+
+	// volumes are detached when the server is deleted
+	for _, volume := range c.volumeCache.idMap {
+		if volume.Server != nil && volume.Server.ID == server.ID {
+			volume.Server = nil
+		}
+	}
+
 	delete(c.serverCache.nameMap, n.Name)
 	delete(c.serverCache.idMap, server.ID)
 	return nil
@@ -606,6 +1054,8 @@ func (c *cacheHCloudClient) ListServerTypes(_ context.Context) ([]*hcloud.Server
 			Name:         "cpx11",
 			Cores:        DefaultCPUCores,
 			Memory:       DefaultMemoryInGB,
+			Disk:         DefaultDiskInGB,
+			CPUType:      hcloud.CPUTypeShared,
 			Architecture: DefaultArchitecture,
 		},
 		{
@@ -613,6 +1063,8 @@ func (c *cacheHCloudClient) ListServerTypes(_ context.Context) ([]*hcloud.Server
 			Name:         "cpx21",
 			Cores:        DefaultCPUCores,
 			Memory:       DefaultMemoryInGB,
+			Disk:         DefaultDiskInGB,
+			CPUType:      hcloud.CPUTypeShared,
 			Architecture: DefaultArchitecture,
 		},
 		{
@@ -620,6 +1072,8 @@ func (c *cacheHCloudClient) ListServerTypes(_ context.Context) ([]*hcloud.Server
 			Name:         "cpx31",
 			Cores:        DefaultCPUCores,
 			Memory:       DefaultMemoryInGB,
+			Disk:         DefaultDiskInGB,
+			CPUType:      hcloud.CPUTypeShared,
 			Architecture: DefaultArchitecture,
 		},
 	}, nil
@@ -665,6 +1119,7 @@ func (c *cacheHCloudClient) CreateNetwork(_ context.Context, opts hcloud.Network
 		Labels:  opts.Labels,
 		IPRange: opts.IPRange,
 		Subnets: opts.Subnets,
+		Routes:  opts.Routes,
 	}
 
 	// Add network to cache
@@ -699,6 +1154,12 @@ func (c *cacheHCloudClient) ListNetworks(_ context.Context, opts hcloud.NetworkL
 	return networks, nil
 }
 
+func (c *cacheHCloudClient) GetNetwork(_ context.Context, id int64) (*hcloud.Network, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.networkCache.idMap[id], nil
+}
+
 func (c *cacheHCloudClient) DeleteNetwork(_ context.Context, network *hcloud.Network) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -711,6 +1172,64 @@ func (c *cacheHCloudClient) DeleteNetwork(_ context.Context, network *hcloud.Net
 	return nil
 }
 
+func (c *cacheHCloudClient) AddSubnetToNetwork(_ context.Context, network *hcloud.Network, opts hcloud.NetworkAddSubnetOpts) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Check if network exists
+	n, found := c.networkCache.idMap[network.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	// check if already exists
+	for _, subnet := range n.Subnets {
+		if subnet.IPRange.String() == opts.Subnet.IPRange.String() {
+			return hcloud.Error{Code: hcloud.ErrorCodeInvalidInput, Message: "subnet overlaps with existing subnet"}
+		}
+	}
+
+	n.Subnets = append(n.Subnets, opts.Subnet)
+	return nil
+}
+
+func (c *cacheHCloudClient) AddRouteToNetwork(_ context.Context, network *hcloud.Network, opts hcloud.NetworkAddRouteOpts) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Check if network exists
+	n, found := c.networkCache.idMap[network.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	// check if already exists
+	for _, route := range n.Routes {
+		if route.Destination.String() == opts.Route.Destination.String() && route.Gateway.Equal(opts.Route.Gateway) {
+			return hcloud.Error{Code: hcloud.ErrorCodeInvalidInput, Message: "route already exists"}
+		}
+	}
+
+	n.Routes = append(n.Routes, opts.Route)
+	return nil
+}
+
+func (c *cacheHCloudClient) DeleteRouteFromNetwork(_ context.Context, network *hcloud.Network, opts hcloud.NetworkDeleteRouteOpts) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Check if network exists
+	n, found := c.networkCache.idMap[network.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	for i, route := range n.Routes {
+		if route.Destination.String() == opts.Route.Destination.String() && route.Gateway.Equal(opts.Route.Gateway) {
+			n.Routes = append(n.Routes[:i], n.Routes[i+1:]...)
+			return nil
+		}
+	}
+	return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "route not found"}
+}
+
 func (c *cacheHCloudClient) ListSSHKeys(_ context.Context, _ hcloud.SSHKeyListOpts) ([]*hcloud.SSHKey, error) {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
@@ -802,6 +1321,146 @@ func (c *cacheHCloudClient) AddServerToPlacementGroup(_ context.Context, server
 	return nil
 }
 
+func (c *cacheHCloudClient) CreateFirewall(_ context.Context, opts hcloud.FirewallCreateOpts) (*hcloud.Firewall, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, found := c.firewallCache.nameMap[opts.Name]; found {
+		return nil, fmt.Errorf("already exists")
+	}
+
+	c.firewallIDCounter++
+	firewall := &hcloud.Firewall{
+		ID:        c.firewallIDCounter,
+		Name:      opts.Name,
+		Labels:    opts.Labels,
+		Rules:     opts.Rules,
+		AppliedTo: opts.ApplyTo,
+	}
+
+	// Add firewall to cache
+	c.firewallCache.idMap[firewall.ID] = firewall
+	c.firewallCache.nameMap[firewall.Name] = struct{}{}
+	return firewall, nil
+}
+
+func (c *cacheHCloudClient) DeleteFirewall(_ context.Context, id int64) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	n, found := c.firewallCache.idMap[id]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	if len(n.AppliedTo) > 0 {
+		return hcloud.Error{Code: hcloud.ErrorCodeResourceInUse, Message: "firewall is still in use"}
+	}
+
+	delete(c.firewallCache.nameMap, n.Name)
+	delete(c.firewallCache.idMap, id)
+	return nil
+}
+
+func (c *cacheHCloudClient) ListFirewalls(_ context.Context, opts hcloud.FirewallListOpts) ([]*hcloud.Firewall, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	firewalls := make([]*hcloud.Firewall, 0, len(c.firewallCache.idMap))
+
+	labels, err := utils.LabelSelectorToLabels(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert label selector to labels: %w", err)
+	}
+
+	for _, firewall := range c.firewallCache.idMap {
+		allLabelsFound := true
+		for key, label := range labels {
+			if val, found := firewall.Labels[key]; !found || val != label {
+				allLabelsFound = false
+				break
+			}
+		}
+		if allLabelsFound {
+			firewalls = append(firewalls, firewall)
+		}
+	}
+
+	return firewalls, nil
+}
+
+func (c *cacheHCloudClient) SetFirewallRules(_ context.Context, firewall *hcloud.Firewall, opts hcloud.FirewallSetRulesOpts) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Check if firewall exists
+	if _, found := c.firewallCache.idMap[firewall.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	// Update it
+	c.firewallCache.idMap[firewall.ID].Rules = opts.Rules
+	return nil
+}
+
+func (c *cacheHCloudClient) ApplyFirewallResources(_ context.Context, firewall *hcloud.Firewall, resources []hcloud.FirewallResource) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Check if firewall exists
+	if _, found := c.firewallCache.idMap[firewall.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	for _, resource := range resources {
+		// check if already applied
+		for _, applied := range c.firewallCache.idMap[firewall.ID].AppliedTo {
+			if firewallResourcesEqual(applied, resource) {
+				return hcloud.Error{Code: hcloud.ErrorCodeFirewallAlreadyApplied, Message: "already applied"}
+			}
+		}
+
+		// Add it
+		c.firewallCache.idMap[firewall.ID].AppliedTo = append(c.firewallCache.idMap[firewall.ID].AppliedTo, resource)
+	}
+	return nil
+}
+
+func (c *cacheHCloudClient) RemoveFirewallResources(_ context.Context, firewall *hcloud.Firewall, resources []hcloud.FirewallResource) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Check if firewall exists
+	if _, found := c.firewallCache.idMap[firewall.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	for _, resource := range resources {
+		appliedTo := c.firewallCache.idMap[firewall.ID].AppliedTo
+		removed := false
+		for i, applied := range appliedTo {
+			if firewallResourcesEqual(applied, resource) {
+				c.firewallCache.idMap[firewall.ID].AppliedTo = append(appliedTo[:i], appliedTo[i+1:]...)
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			return hcloud.Error{Code: hcloud.ErrorCodeFirewallAlreadyRemoved, Message: "already removed"}
+		}
+	}
+	return nil
+}
+
+func firewallResourcesEqual(a, b hcloud.FirewallResource) bool {
+	if a.Type != b.Type {
+		return false
+	}
+	switch a.Type {
+	case hcloud.FirewallResourceTypeServer:
+		return a.Server != nil && b.Server != nil && a.Server.ID == b.Server.ID
+	case hcloud.FirewallResourceTypeLabelSelector:
+		return a.LabelSelector != nil && b.LabelSelector != nil && a.LabelSelector.Selector == b.LabelSelector.Selector
+	}
+	return false
+}
+
 func isIntInList(list []int64, str int64) bool {
 	for _, s := range list {
 		if s == str {
@@ -810,3 +1469,227 @@ func isIntInList(list []int64, str int64) bool {
 	}
 	return false
 }
+
+func (c *cacheHCloudClient) CreateFloatingIP(_ context.Context, opts hcloud.FloatingIPCreateOpts) (*hcloud.FloatingIP, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var name string
+	if opts.Name != nil {
+		name = *opts.Name
+	}
+
+	if _, found := c.floatingIPCache.nameMap[name]; found && name != "" {
+		return nil, hcloud.Error{Code: hcloud.ErrorCodeUniquenessError, Message: "already exists"}
+	}
+
+	c.floatingIPIDCounter++
+	floatingIP := &hcloud.FloatingIP{
+		ID:           c.floatingIPIDCounter,
+		Name:         name,
+		Labels:       opts.Labels,
+		Type:         opts.Type,
+		HomeLocation: opts.HomeLocation,
+		Server:       opts.Server,
+		IP:           net.IPv4(203, 0, 113, byte(c.floatingIPIDCounter)),
+	}
+
+	// Add floating IP to cache
+	c.floatingIPCache.idMap[floatingIP.ID] = floatingIP
+	c.floatingIPCache.nameMap[floatingIP.Name] = struct{}{}
+	return floatingIP, nil
+}
+
+func (c *cacheHCloudClient) DeleteFloatingIP(_ context.Context, id int64) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	n, found := c.floatingIPCache.idMap[id]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	delete(c.floatingIPCache.nameMap, n.Name)
+	delete(c.floatingIPCache.idMap, id)
+	return nil
+}
+
+func (c *cacheHCloudClient) ListFloatingIPs(_ context.Context, opts hcloud.FloatingIPListOpts) ([]*hcloud.FloatingIP, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	floatingIPs := make([]*hcloud.FloatingIP, 0, len(c.floatingIPCache.idMap))
+
+	labels, err := utils.LabelSelectorToLabels(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert label selector to labels: %w", err)
+	}
+
+	for _, floatingIP := range c.floatingIPCache.idMap {
+		allLabelsFound := true
+		for key, label := range labels {
+			if val, found := floatingIP.Labels[key]; !found || val != label {
+				allLabelsFound = false
+				break
+			}
+		}
+		if allLabelsFound {
+			floatingIPs = append(floatingIPs, floatingIP)
+		}
+	}
+
+	return floatingIPs, nil
+}
+
+func (c *cacheHCloudClient) AssignFloatingIP(_ context.Context, floatingIP *hcloud.FloatingIP, server *hcloud.Server) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Check if floating IP exists
+	if _, found := c.floatingIPCache.idMap[floatingIP.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	// Check if server exists
+	if _, found := c.serverCache.idMap[server.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	c.floatingIPCache.idMap[floatingIP.ID].Server = &hcloud.Server{ID: server.ID}
+	return nil
+}
+
+func (c *cacheHCloudClient) UnassignFloatingIP(_ context.Context, floatingIP *hcloud.FloatingIP) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Check if floating IP exists
+	if _, found := c.floatingIPCache.idMap[floatingIP.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	c.floatingIPCache.idMap[floatingIP.ID].Server = nil
+	return nil
+}
+
+func (c *cacheHCloudClient) CreateVolume(_ context.Context, opts hcloud.VolumeCreateOpts) (*hcloud.Volume, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, found := c.volumeCache.nameMap[opts.Name]; found {
+		return nil, hcloud.Error{Code: hcloud.ErrorCodeUniquenessError, Message: "already exists"}
+	}
+
+	c.volumeIDCounter++
+	volume := &hcloud.Volume{
+		ID:          c.volumeIDCounter,
+		Name:        opts.Name,
+		Status:      hcloud.VolumeStatusAvailable,
+		Location:    opts.Location,
+		Size:        opts.Size,
+		Format:      opts.Format,
+		Labels:      opts.Labels,
+		LinuxDevice: fmt.Sprintf("/dev/disk/by-id/scsi-0HC_Volume_%d", c.volumeIDCounter),
+	}
+	if opts.Server != nil {
+		volume.Server = &hcloud.Server{ID: opts.Server.ID}
+	}
+
+	// Add volume to cache
+	c.volumeCache.idMap[volume.ID] = volume
+	c.volumeCache.nameMap[volume.Name] = struct{}{}
+	return volume, nil
+}
+
+func (c *cacheHCloudClient) DeleteVolume(_ context.Context, id int64) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	n, found := c.volumeCache.idMap[id]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	if n.Server != nil {
+		return hcloud.Error{Code: hcloud.ErrorCodeLocked, Message: "volume is still attached"}
+	}
+
+	delete(c.volumeCache.nameMap, n.Name)
+	delete(c.volumeCache.idMap, id)
+	return nil
+}
+
+func (c *cacheHCloudClient) ListVolumes(_ context.Context, opts hcloud.VolumeListOpts) ([]*hcloud.Volume, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	volumes := make([]*hcloud.Volume, 0, len(c.volumeCache.idMap))
+
+	labels, err := utils.LabelSelectorToLabels(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert label selector to labels: %w", err)
+	}
+
+	for _, volume := range c.volumeCache.idMap {
+		allLabelsFound := true
+		for key, label := range labels {
+			if val, found := volume.Labels[key]; !found || val != label {
+				allLabelsFound = false
+				break
+			}
+		}
+		if allLabelsFound {
+			volumes = append(volumes, volume)
+		}
+	}
+
+	return volumes, nil
+}
+
+func (c *cacheHCloudClient) AttachVolume(_ context.Context, volume *hcloud.Volume, opts hcloud.VolumeAttachOpts) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Check if volume exists
+	v, found := c.volumeCache.idMap[volume.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	// Check if server exists
+	if _, found := c.serverCache.idMap[opts.Server.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	if v.Server != nil {
+		return hcloud.Error{Code: hcloud.ErrorCodeConflict, Message: "volume is already attached"}
+	}
+
+	v.Server = &hcloud.Server{ID: opts.Server.ID}
+	return nil
+}
+
+func (c *cacheHCloudClient) DetachVolume(_ context.Context, volume *hcloud.Volume) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// Check if volume exists
+	if _, found := c.volumeCache.idMap[volume.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	c.volumeCache.idMap[volume.ID].Server = nil
+	return nil
+}
+
+func (c *cacheHCloudClient) UpdateVolume(_ context.Context, volume *hcloud.Volume, opts hcloud.VolumeUpdateOpts) (*hcloud.Volume, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	v, found := c.volumeCache.idMap[volume.ID]
+	if !found {
+		return nil, hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+	if opts.Name != "" {
+		delete(c.volumeCache.nameMap, v.Name)
+		v.Name = opts.Name
+		c.volumeCache.nameMap[v.Name] = struct{}{}
+	}
+	if opts.Labels != nil {
+		v.Labels = opts.Labels
+	}
+	return v, nil
+}
@@ -583,6 +583,8 @@ var _ = Describe("Server", func() {
 				Name:         "cpx11",
 				Cores:        fake.DefaultCPUCores,
 				Memory:       fake.DefaultMemoryInGB,
+				Disk:         fake.DefaultDiskInGB,
+				CPUType:      hcloud.CPUTypeShared,
 				Architecture: fake.DefaultArchitecture,
 			},
 			{
@@ -590,6 +592,8 @@ var _ = Describe("Server", func() {
 				Name:         "cpx21",
 				Cores:        fake.DefaultCPUCores,
 				Memory:       fake.DefaultMemoryInGB,
+				Disk:         fake.DefaultDiskInGB,
+				CPUType:      hcloud.CPUTypeShared,
 				Architecture: fake.DefaultArchitecture,
 			},
 			{
@@ -597,6 +601,8 @@ var _ = Describe("Server", func() {
 				Name:         "cpx31",
 				Cores:        fake.DefaultCPUCores,
 				Memory:       fake.DefaultMemoryInGB,
+				Disk:         fake.DefaultDiskInGB,
+				CPUType:      hcloud.CPUTypeShared,
 				Architecture: fake.DefaultArchitecture,
 			},
 		}))
@@ -760,3 +766,101 @@ var _ = Describe("Placement groups", func() {
 		Expect(hcloud.IsError(err, hcloud.ErrorCodeNotFound)).To(BeTrue())
 	})
 })
+
+var _ = Describe("Firewalls", func() {
+	var listOpts hcloud.FirewallListOpts
+	listOpts.LabelSelector = labelSelector
+
+	resource := hcloud.FirewallResource{
+		Type:          hcloud.FirewallResourceTypeLabelSelector,
+		LabelSelector: &hcloud.FirewallResourceLabelSelector{Selector: "key1==val1"},
+	}
+
+	opts := hcloud.FirewallCreateOpts{
+		Name: "firewall-name",
+		Labels: map[string]string{
+			"key1": "val1",
+			"key2": "val2",
+		},
+		Rules: []hcloud.FirewallRule{
+			{
+				Direction: hcloud.FirewallRuleDirectionIn,
+				Protocol:  hcloud.FirewallRuleProtocolICMP,
+				SourceIPs: []net.IPNet{{IP: net.IPv4zero, Mask: net.CIDRMask(0, 32)}},
+			},
+		},
+	}
+
+	client := factory.NewClient("")
+	var firewall *hcloud.Firewall
+
+	BeforeEach(func() {
+		client.Reset()
+		var err error
+		firewall, err = client.CreateFirewall(ctx, opts)
+		Expect(err).To(Succeed())
+	})
+
+	It("creates a firewall with an ID", func() {
+		Expect(firewall.ID).ToNot(Equal(0))
+		Expect(len(firewall.Rules)).To(Equal(1))
+	})
+
+	It("gives an error when a firewall is created twice", func() {
+		_, err := client.CreateFirewall(ctx, opts)
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("lists firewalls", func() {
+		resp, err := client.ListFirewalls(ctx, listOpts)
+		Expect(err).To(Succeed())
+		Expect(len(resp)).To(Equal(1))
+		Expect(resp[0].ID).To(Equal(firewall.ID))
+	})
+
+	It("sets the rules of a firewall", func() {
+		Expect(client.SetFirewallRules(ctx, firewall, hcloud.FirewallSetRulesOpts{})).To(Succeed())
+		resp, err := client.ListFirewalls(ctx, listOpts)
+		Expect(err).To(Succeed())
+		Expect(len(resp[0].Rules)).To(Equal(0))
+	})
+
+	It("applies and removes resources", func() {
+		Expect(client.ApplyFirewallResources(ctx, firewall, []hcloud.FirewallResource{resource})).To(Succeed())
+		resp, err := client.ListFirewalls(ctx, listOpts)
+		Expect(err).To(Succeed())
+		Expect(len(resp[0].AppliedTo)).To(Equal(1))
+
+		Expect(client.RemoveFirewallResources(ctx, firewall, []hcloud.FirewallResource{resource})).To(Succeed())
+		resp, err = client.ListFirewalls(ctx, listOpts)
+		Expect(err).To(Succeed())
+		Expect(len(resp[0].AppliedTo)).To(Equal(0))
+	})
+
+	It("gives an error when a resource is applied twice", func() {
+		Expect(client.ApplyFirewallResources(ctx, firewall, []hcloud.FirewallResource{resource})).To(Succeed())
+		err := client.ApplyFirewallResources(ctx, firewall, []hcloud.FirewallResource{resource})
+		Expect(err).ToNot(Succeed())
+		Expect(hcloud.IsError(err, hcloud.ErrorCodeFirewallAlreadyApplied)).To(BeTrue())
+	})
+
+	It("gives an error when a firewall that is still in use is deleted", func() {
+		Expect(client.ApplyFirewallResources(ctx, firewall, []hcloud.FirewallResource{resource})).To(Succeed())
+		err := client.DeleteFirewall(ctx, firewall.ID)
+		Expect(err).ToNot(Succeed())
+		Expect(hcloud.IsError(err, hcloud.ErrorCodeResourceInUse)).To(BeTrue())
+	})
+
+	It("deletes a firewall", func() {
+		Expect(client.DeleteFirewall(ctx, firewall.ID)).To(Succeed())
+		resp, err := client.ListFirewalls(ctx, listOpts)
+		Expect(err).To(Succeed())
+		Expect(len(resp)).To(Equal(0))
+	})
+
+	It("gives an error when a non-existing firewall is deleted", func() {
+		err := client.DeleteFirewall(ctx, 999)
+		Expect(err).ToNot(Succeed())
+		Expect(hcloud.IsError(err, hcloud.ErrorCodeNotFound)).To(BeTrue())
+	})
+})
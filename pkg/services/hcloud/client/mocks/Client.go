@@ -35,6 +35,42 @@ func (_m *Client) AddIPTargetToLoadBalancer(_a0 context.Context, _a1 hcloud.Load
 	return r0
 }
 
+// AddLabelSelectorTargetToLoadBalancer provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) AddLabelSelectorTargetToLoadBalancer(_a0 context.Context, _a1 hcloud.LoadBalancerAddLabelSelectorTargetOpts, _a2 *hcloud.LoadBalancer) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddLabelSelectorTargetToLoadBalancer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.LoadBalancerAddLabelSelectorTargetOpts, *hcloud.LoadBalancer) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddRouteToNetwork provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) AddRouteToNetwork(_a0 context.Context, _a1 *hcloud.Network, _a2 hcloud.NetworkAddRouteOpts) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddRouteToNetwork")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Network, hcloud.NetworkAddRouteOpts) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddServerToPlacementGroup provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) AddServerToPlacementGroup(_a0 context.Context, _a1 *hcloud.Server, _a2 *hcloud.PlacementGroup) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -71,6 +107,24 @@ func (_m *Client) AddServiceToLoadBalancer(_a0 context.Context, _a1 *hcloud.Load
 	return r0
 }
 
+// AddSubnetToNetwork provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) AddSubnetToNetwork(_a0 context.Context, _a1 *hcloud.Network, _a2 hcloud.NetworkAddSubnetOpts) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddSubnetToNetwork")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Network, hcloud.NetworkAddSubnetOpts) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddTargetServerToLoadBalancer provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) AddTargetServerToLoadBalancer(_a0 context.Context, _a1 hcloud.LoadBalancerAddServerTargetOpts, _a2 *hcloud.LoadBalancer) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -89,6 +143,42 @@ func (_m *Client) AddTargetServerToLoadBalancer(_a0 context.Context, _a1 hcloud.
 	return r0
 }
 
+// ApplyFirewallResources provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) ApplyFirewallResources(_a0 context.Context, _a1 *hcloud.Firewall, _a2 []hcloud.FirewallResource) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyFirewallResources")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Firewall, []hcloud.FirewallResource) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AssignFloatingIP provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) AssignFloatingIP(_a0 context.Context, _a1 *hcloud.FloatingIP, _a2 *hcloud.Server) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignFloatingIP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.FloatingIP, *hcloud.Server) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AttachLoadBalancerToNetwork provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) AttachLoadBalancerToNetwork(_a0 context.Context, _a1 *hcloud.LoadBalancer, _a2 hcloud.LoadBalancerAttachToNetworkOpts) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -125,6 +215,24 @@ func (_m *Client) AttachServerToNetwork(_a0 context.Context, _a1 *hcloud.Server,
 	return r0
 }
 
+// AttachVolume provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) AttachVolume(_a0 context.Context, _a1 *hcloud.Volume, _a2 hcloud.VolumeAttachOpts) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachVolume")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Volume, hcloud.VolumeAttachOpts) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ChangeLoadBalancerAlgorithm provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) ChangeLoadBalancerAlgorithm(_a0 context.Context, _a1 *hcloud.LoadBalancer, _a2 hcloud.LoadBalancerChangeAlgorithmOpts) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -161,6 +269,96 @@ func (_m *Client) ChangeLoadBalancerType(_a0 context.Context, _a1 *hcloud.LoadBa
 	return r0
 }
 
+// CreateFirewall provides a mock function with given fields: _a0, _a1
+func (_m *Client) CreateFirewall(_a0 context.Context, _a1 hcloud.FirewallCreateOpts) (*hcloud.Firewall, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateFirewall")
+	}
+
+	var r0 *hcloud.Firewall
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FirewallCreateOpts) (*hcloud.Firewall, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FirewallCreateOpts) *hcloud.Firewall); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hcloud.Firewall)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, hcloud.FirewallCreateOpts) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateFloatingIP provides a mock function with given fields: _a0, _a1
+func (_m *Client) CreateFloatingIP(_a0 context.Context, _a1 hcloud.FloatingIPCreateOpts) (*hcloud.FloatingIP, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateFloatingIP")
+	}
+
+	var r0 *hcloud.FloatingIP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FloatingIPCreateOpts) (*hcloud.FloatingIP, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FloatingIPCreateOpts) *hcloud.FloatingIP); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hcloud.FloatingIP)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, hcloud.FloatingIPCreateOpts) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateImage provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) CreateImage(_a0 context.Context, _a1 *hcloud.Server, _a2 hcloud.ServerCreateImageOpts) (*hcloud.Image, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateImage")
+	}
+
+	var r0 *hcloud.Image
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Server, hcloud.ServerCreateImageOpts) (*hcloud.Image, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Server, hcloud.ServerCreateImageOpts) *hcloud.Image); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hcloud.Image)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *hcloud.Server, hcloud.ServerCreateImageOpts) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateLoadBalancer provides a mock function with given fields: _a0, _a1
 func (_m *Client) CreateLoadBalancer(_a0 context.Context, _a1 hcloud.LoadBalancerCreateOpts) (*hcloud.LoadBalancer, error) {
 	ret := _m.Called(_a0, _a1)
@@ -281,6 +479,72 @@ func (_m *Client) CreateServer(_a0 context.Context, _a1 hcloud.ServerCreateOpts)
 	return r0, r1
 }
 
+// CreateVolume provides a mock function with given fields: _a0, _a1
+func (_m *Client) CreateVolume(_a0 context.Context, _a1 hcloud.VolumeCreateOpts) (*hcloud.Volume, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateVolume")
+	}
+
+	var r0 *hcloud.Volume
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.VolumeCreateOpts) (*hcloud.Volume, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.VolumeCreateOpts) *hcloud.Volume); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hcloud.Volume)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, hcloud.VolumeCreateOpts) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteFirewall provides a mock function with given fields: _a0, _a1
+func (_m *Client) DeleteFirewall(_a0 context.Context, _a1 int64) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteFirewall")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteFloatingIP provides a mock function with given fields: _a0, _a1
+func (_m *Client) DeleteFloatingIP(_a0 context.Context, _a1 int64) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteFloatingIP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteIPTargetOfLoadBalancer provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) DeleteIPTargetOfLoadBalancer(_a0 context.Context, _a1 *hcloud.LoadBalancer, _a2 net.IP) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -299,6 +563,42 @@ func (_m *Client) DeleteIPTargetOfLoadBalancer(_a0 context.Context, _a1 *hcloud.
 	return r0
 }
 
+// DeleteImage provides a mock function with given fields: _a0, _a1
+func (_m *Client) DeleteImage(_a0 context.Context, _a1 int64) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteImage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteLabelSelectorTargetOfLoadBalancer provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) DeleteLabelSelectorTargetOfLoadBalancer(_a0 context.Context, _a1 *hcloud.LoadBalancer, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLabelSelectorTargetOfLoadBalancer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.LoadBalancer, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteLoadBalancer provides a mock function with given fields: _a0, _a1
 func (_m *Client) DeleteLoadBalancer(_a0 context.Context, _a1 int64) error {
 	ret := _m.Called(_a0, _a1)
@@ -353,6 +653,24 @@ func (_m *Client) DeletePlacementGroup(_a0 context.Context, _a1 int64) error {
 	return r0
 }
 
+// DeleteRouteFromNetwork provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) DeleteRouteFromNetwork(_a0 context.Context, _a1 *hcloud.Network, _a2 hcloud.NetworkDeleteRouteOpts) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRouteFromNetwork")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Network, hcloud.NetworkDeleteRouteOpts) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteServer provides a mock function with given fields: _a0, _a1
 func (_m *Client) DeleteServer(_a0 context.Context, _a1 *hcloud.Server) error {
 	ret := _m.Called(_a0, _a1)
@@ -407,20 +725,188 @@ func (_m *Client) DeleteTargetServerOfLoadBalancer(_a0 context.Context, _a1 *hcl
 	return r0
 }
 
-// GetServer provides a mock function with given fields: _a0, _a1
-func (_m *Client) GetServer(_a0 context.Context, _a1 int64) (*hcloud.Server, error) {
+// DeleteVolume provides a mock function with given fields: _a0, _a1
+func (_m *Client) DeleteVolume(_a0 context.Context, _a1 int64) error {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetServer")
+		panic("no return value specified for DeleteVolume")
 	}
 
-	var r0 *hcloud.Server
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) (*hcloud.Server, error)); ok {
-		return rf(_a0, _a1)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64) *hcloud.Server); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DetachLoadBalancerFromNetwork provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) DetachLoadBalancerFromNetwork(_a0 context.Context, _a1 *hcloud.LoadBalancer, _a2 hcloud.LoadBalancerDetachFromNetworkOpts) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DetachLoadBalancerFromNetwork")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.LoadBalancer, hcloud.LoadBalancerDetachFromNetworkOpts) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DetachVolume provides a mock function with given fields: _a0, _a1
+func (_m *Client) DetachVolume(_a0 context.Context, _a1 *hcloud.Volume) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DetachVolume")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Volume) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableServerBackup provides a mock function with given fields: _a0, _a1
+func (_m *Client) DisableServerBackup(_a0 context.Context, _a1 *hcloud.Server) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableServerBackup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Server) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableRescueSystem provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) EnableRescueSystem(_a0 context.Context, _a1 *hcloud.Server, _a2 hcloud.ServerEnableRescueOpts) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableRescueSystem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Server, hcloud.ServerEnableRescueOpts) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableServerBackup provides a mock function with given fields: _a0, _a1
+func (_m *Client) EnableServerBackup(_a0 context.Context, _a1 *hcloud.Server) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableServerBackup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Server) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetImage provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetImage(_a0 context.Context, _a1 int64) (*hcloud.Image, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImage")
+	}
+
+	var r0 *hcloud.Image
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*hcloud.Image, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *hcloud.Image); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hcloud.Image)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNetwork provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetNetwork(_a0 context.Context, _a1 int64) (*hcloud.Network, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNetwork")
+	}
+
+	var r0 *hcloud.Network
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*hcloud.Network, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *hcloud.Network); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hcloud.Network)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServer provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetServer(_a0 context.Context, _a1 int64) (*hcloud.Server, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetServer")
+	}
+
+	var r0 *hcloud.Server
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*hcloud.Server, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *hcloud.Server); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
@@ -467,6 +953,66 @@ func (_m *Client) GetServerType(_a0 context.Context, _a1 string) (*hcloud.Server
 	return r0, r1
 }
 
+// ListFirewalls provides a mock function with given fields: _a0, _a1
+func (_m *Client) ListFirewalls(_a0 context.Context, _a1 hcloud.FirewallListOpts) ([]*hcloud.Firewall, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFirewalls")
+	}
+
+	var r0 []*hcloud.Firewall
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FirewallListOpts) ([]*hcloud.Firewall, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FirewallListOpts) []*hcloud.Firewall); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*hcloud.Firewall)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, hcloud.FirewallListOpts) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFloatingIPs provides a mock function with given fields: _a0, _a1
+func (_m *Client) ListFloatingIPs(_a0 context.Context, _a1 hcloud.FloatingIPListOpts) ([]*hcloud.FloatingIP, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFloatingIPs")
+	}
+
+	var r0 []*hcloud.FloatingIP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FloatingIPListOpts) ([]*hcloud.FloatingIP, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FloatingIPListOpts) []*hcloud.FloatingIP); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*hcloud.FloatingIP)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, hcloud.FloatingIPListOpts) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListImages provides a mock function with given fields: _a0, _a1
 func (_m *Client) ListImages(_a0 context.Context, _a1 hcloud.ImageListOpts) ([]*hcloud.Image, error) {
 	ret := _m.Called(_a0, _a1)
@@ -677,6 +1223,54 @@ func (_m *Client) ListServers(_a0 context.Context, _a1 hcloud.ServerListOpts) ([
 	return r0, r1
 }
 
+// ListVolumes provides a mock function with given fields: _a0, _a1
+func (_m *Client) ListVolumes(_a0 context.Context, _a1 hcloud.VolumeListOpts) ([]*hcloud.Volume, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListVolumes")
+	}
+
+	var r0 []*hcloud.Volume
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.VolumeListOpts) ([]*hcloud.Volume, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.VolumeListOpts) []*hcloud.Volume); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*hcloud.Volume)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, hcloud.VolumeListOpts) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PowerOffServer provides a mock function with given fields: _a0, _a1
+func (_m *Client) PowerOffServer(_a0 context.Context, _a1 *hcloud.Server) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PowerOffServer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Server) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // PowerOnServer provides a mock function with given fields: _a0, _a1
 func (_m *Client) PowerOnServer(_a0 context.Context, _a1 *hcloud.Server) error {
 	ret := _m.Called(_a0, _a1)
@@ -713,11 +1307,83 @@ func (_m *Client) RebootServer(_a0 context.Context, _a1 *hcloud.Server) error {
 	return r0
 }
 
+// RebuildServer provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) RebuildServer(_a0 context.Context, _a1 *hcloud.Server, _a2 hcloud.ServerRebuildOpts) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RebuildServer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Server, hcloud.ServerRebuildOpts) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveFirewallResources provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) RemoveFirewallResources(_a0 context.Context, _a1 *hcloud.Firewall, _a2 []hcloud.FirewallResource) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveFirewallResources")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Firewall, []hcloud.FirewallResource) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Reset provides a mock function with given fields:
 func (_m *Client) Reset() {
 	_m.Called()
 }
 
+// ResetServer provides a mock function with given fields: _a0, _a1
+func (_m *Client) ResetServer(_a0 context.Context, _a1 *hcloud.Server) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetServer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Server) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetFirewallRules provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) SetFirewallRules(_a0 context.Context, _a1 *hcloud.Firewall, _a2 hcloud.FirewallSetRulesOpts) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetFirewallRules")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Firewall, hcloud.FirewallSetRulesOpts) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ShutdownServer provides a mock function with given fields: _a0, _a1
 func (_m *Client) ShutdownServer(_a0 context.Context, _a1 *hcloud.Server) error {
 	ret := _m.Called(_a0, _a1)
@@ -736,6 +1402,24 @@ func (_m *Client) ShutdownServer(_a0 context.Context, _a1 *hcloud.Server) error
 	return r0
 }
 
+// UnassignFloatingIP provides a mock function with given fields: _a0, _a1
+func (_m *Client) UnassignFloatingIP(_a0 context.Context, _a1 *hcloud.FloatingIP) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnassignFloatingIP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.FloatingIP) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateLoadBalancer provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) UpdateLoadBalancer(_a0 context.Context, _a1 *hcloud.LoadBalancer, _a2 hcloud.LoadBalancerUpdateOpts) (*hcloud.LoadBalancer, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -766,6 +1450,84 @@ func (_m *Client) UpdateLoadBalancer(_a0 context.Context, _a1 *hcloud.LoadBalanc
 	return r0, r1
 }
 
+// UpdateServer provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) UpdateServer(_a0 context.Context, _a1 *hcloud.Server, _a2 hcloud.ServerUpdateOpts) (*hcloud.Server, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateServer")
+	}
+
+	var r0 *hcloud.Server
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Server, hcloud.ServerUpdateOpts) (*hcloud.Server, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Server, hcloud.ServerUpdateOpts) *hcloud.Server); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hcloud.Server)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *hcloud.Server, hcloud.ServerUpdateOpts) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateServiceOfLoadBalancer provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Client) UpdateServiceOfLoadBalancer(_a0 context.Context, _a1 *hcloud.LoadBalancer, _a2 int, _a3 hcloud.LoadBalancerUpdateServiceOpts) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateServiceOfLoadBalancer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.LoadBalancer, int, hcloud.LoadBalancerUpdateServiceOpts) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateVolume provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) UpdateVolume(_a0 context.Context, _a1 *hcloud.Volume, _a2 hcloud.VolumeUpdateOpts) (*hcloud.Volume, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateVolume")
+	}
+
+	var r0 *hcloud.Volume
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Volume, hcloud.VolumeUpdateOpts) (*hcloud.Volume, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Volume, hcloud.VolumeUpdateOpts) *hcloud.Volume); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hcloud.Volume)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *hcloud.Volume, hcloud.VolumeUpdateOpts) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewClient creates a new instance of Client. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewClient(t interface {
@@ -0,0 +1,329 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controlplaneip implements the lifecycle of the HCloud Floating IP that is used as control plane endpoint.
+package controlplaneip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudutil "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/util"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+const (
+	// roleLabelKey distinguishes the control plane IP from other IPs of the cluster.
+	roleLabelKey   = "caph-ip-role"
+	roleLabelValue = "control-plane-endpoint"
+
+	// requeueAfterNotAssigned is the interval after which an unassigned IP is checked again.
+	requeueAfterNotAssigned = 30 * time.Second
+)
+
+var errNoControlPlaneRegion = errors.New("no control plane region specified")
+
+// Service struct contains cluster scope to reconcile the control plane IP.
+type Service struct {
+	scope *scope.ClusterScope
+}
+
+// NewService creates new service object.
+func NewService(scope *scope.ClusterScope) *Service {
+	return &Service{
+		scope: scope,
+	}
+}
+
+// Reconcile implements the life cycle of the control plane IP.
+func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err error) {
+	switch endpointType := s.scope.HetznerCluster.Spec.ControlPlaneEndpointType; endpointType {
+	case "", infrav1.ControlPlaneEndpointTypeLoadBalancer:
+		return reconcile.Result{}, nil
+	case infrav1.ControlPlaneEndpointTypeFloatingIP:
+		return s.reconcileFloatingIP(ctx)
+	default:
+		// clusters that have been created with a type that has been removed from the API, e.g. PrimaryIP. Its status
+		// is dropped, as it would not pass the validation of the API server anymore.
+		s.scope.HetznerCluster.Status.ControlPlaneIP = nil
+		conditions.MarkFalse(
+			s.scope.HetznerCluster,
+			infrav1.ControlPlaneIPReadyCondition,
+			infrav1.ControlPlaneIPTypeUnsupportedReason,
+			clusterv1.ConditionSeverityError,
+			"control plane endpoint type %s is not supported",
+			endpointType,
+		)
+		return reconcile.Result{}, nil
+	}
+}
+
+// Delete implements the deletion of the control plane IP.
+func (s *Service) Delete(ctx context.Context) error {
+	if !s.scope.HetznerCluster.UsesControlPlaneIP() {
+		return nil
+	}
+
+	floatingIP, err := s.findFloatingIP(ctx)
+	if err != nil {
+		return err
+	}
+	if floatingIP != nil {
+		if err := s.scope.HCloudClient.DeleteFloatingIP(ctx, floatingIP.ID); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteFloatingIP")
+			// if resource has been deleted already then do nothing
+			if !hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+				record.Warnf(s.scope.HetznerCluster, "FloatingIPDeleteFailed", "Failed to delete floating IP with ID %v", floatingIP.ID)
+				return fmt.Errorf("failed to delete floating IP %v: %w", floatingIP.ID, err)
+			}
+		}
+		record.Eventf(s.scope.HetznerCluster, "FloatingIPDeleted", "Deleted floating IP with ID %v", floatingIP.ID)
+	}
+
+	s.scope.HetznerCluster.Status.ControlPlaneIP = nil
+	return nil
+}
+
+func (s *Service) reconcileFloatingIP(ctx context.Context) (reconcile.Result, error) {
+	floatingIP, err := s.findFloatingIP(ctx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if floatingIP == nil {
+		floatingIP, err = s.createFloatingIP(ctx)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	s.scope.HetznerCluster.Status.ControlPlaneIP = statusFromHCloudFloatingIP(floatingIP)
+
+	serverIDs, err := s.healthyControlPlaneServerIDs(ctx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	currentServerID := s.scope.HetznerCluster.Status.ControlPlaneIP.ServerID
+	serverID := selectServer(currentServerID, serverIDs)
+
+	if serverID == 0 {
+		conditions.MarkFalse(
+			s.scope.HetznerCluster,
+			infrav1.ControlPlaneIPReadyCondition,
+			infrav1.ControlPlaneIPNotAssignedReason,
+			clusterv1.ConditionSeverityInfo,
+			"no healthy control plane server available for floating IP",
+		)
+		return reconcile.Result{RequeueAfter: requeueAfterNotAssigned}, nil
+	}
+
+	if serverID != currentServerID {
+		if err := s.scope.HCloudClient.AssignFloatingIP(ctx, floatingIP, &hcloud.Server{ID: serverID}); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "AssignFloatingIP")
+			err = fmt.Errorf("failed to assign floating IP %v to server %v: %w", floatingIP.ID, serverID, err)
+			conditions.MarkFalse(
+				s.scope.HetznerCluster,
+				infrav1.ControlPlaneIPReadyCondition,
+				infrav1.ControlPlaneIPAssignFailedReason,
+				clusterv1.ConditionSeverityWarning,
+				"%s",
+				err.Error(),
+			)
+			return reconcile.Result{}, err
+		}
+
+		record.Eventf(
+			s.scope.HetznerCluster,
+			"FloatingIPAssigned",
+			"Assigned floating IP %s to server with ID %v",
+			floatingIP.IP.String(),
+			serverID,
+		)
+		s.scope.HetznerCluster.Status.ControlPlaneIP.ServerID = serverID
+	}
+
+	conditions.MarkTrue(s.scope.HetznerCluster, infrav1.ControlPlaneIPReadyCondition)
+	return reconcile.Result{}, nil
+}
+
+func (s *Service) createFloatingIP(ctx context.Context) (*hcloud.FloatingIP, error) {
+	region, err := s.homeRegion()
+	if err != nil {
+		return nil, s.markCreateFailed(err)
+	}
+
+	name := s.name()
+	description := fmt.Sprintf("Control plane endpoint of cluster %s", s.scope.HetznerCluster.Name)
+	floatingIP, err := s.scope.HCloudClient.CreateFloatingIP(ctx, hcloud.FloatingIPCreateOpts{
+		Type:         hcloud.FloatingIPTypeIPv4,
+		HomeLocation: &hcloud.Location{Name: string(region)},
+		Name:         &name,
+		Description:  &description,
+		Labels:       s.labels(),
+	})
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "CreateFloatingIP")
+		record.Warnf(s.scope.HetznerCluster, "FailedCreateFloatingIP", "Failed to create floating IP: %s", err)
+		return nil, s.markCreateFailed(fmt.Errorf("failed to create floating IP: %w", err))
+	}
+
+	record.Eventf(s.scope.HetznerCluster, "CreateFloatingIP", "Created floating IP %s", floatingIP.IP.String())
+	return floatingIP, nil
+}
+
+func (s *Service) findFloatingIP(ctx context.Context) (*hcloud.FloatingIP, error) {
+	opts := hcloud.FloatingIPListOpts{}
+	opts.LabelSelector = utils.LabelsToLabelSelector(s.labels())
+
+	floatingIPs, err := s.scope.HCloudClient.ListFloatingIPs(ctx, opts)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ListFloatingIPs")
+		return nil, fmt.Errorf("failed to list floating IPs: %w", err)
+	}
+
+	if len(floatingIPs) > 1 {
+		return nil, fmt.Errorf("found %d floating IPs for the control plane endpoint, expected at most one", len(floatingIPs))
+	}
+	if len(floatingIPs) == 0 {
+		return nil, nil
+	}
+	return floatingIPs[0], nil
+}
+
+// healthyControlPlaneServerIDs returns the IDs of the servers of all healthy control plane machines,
+// ordered by the creation time of the machines.
+func (s *Service) healthyControlPlaneServerIDs(ctx context.Context) ([]int64, error) {
+	machines, hcloudMachines, err := s.scope.ListMachines(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list machines: %w", err)
+	}
+
+	type candidate struct {
+		serverID int64
+		created  time.Time
+	}
+
+	candidates := make([]candidate, 0, len(machines))
+	for i, machine := range machines {
+		if !isHealthyControlPlaneMachine(machine, hcloudMachines[i]) {
+			continue
+		}
+
+		serverID, err := hcloudutil.ServerIDFromProviderID(hcloudMachines[i].Spec.ProviderID)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, candidate{serverID: serverID, created: machine.CreationTimestamp.Time})
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return a.created.Compare(b.created)
+	})
+
+	serverIDs := make([]int64, len(candidates))
+	for i := range candidates {
+		serverIDs[i] = candidates[i].serverID
+	}
+	return serverIDs, nil
+}
+
+func (s *Service) homeRegion() (infrav1.Region, error) {
+	if len(s.scope.HetznerCluster.Spec.ControlPlaneRegions) == 0 {
+		return "", errNoControlPlaneRegion
+	}
+	return s.scope.HetznerCluster.Spec.ControlPlaneRegions[0], nil
+}
+
+func (s *Service) markCreateFailed(err error) error {
+	conditions.MarkFalse(
+		s.scope.HetznerCluster,
+		infrav1.ControlPlaneIPReadyCondition,
+		infrav1.ControlPlaneIPCreateFailedReason,
+		clusterv1.ConditionSeverityError,
+		"%s",
+		err.Error(),
+	)
+	return err
+}
+
+func (s *Service) name() string {
+	return fmt.Sprintf("%s-control-plane", s.scope.HetznerCluster.Name)
+}
+
+func (s *Service) labels() map[string]string {
+	return map[string]string{
+		s.scope.HetznerCluster.ClusterTagKey(): string(infrav1.ResourceLifecycleOwned),
+		roleLabelKey:                           roleLabelValue,
+	}
+}
+
+// isHealthyControlPlaneMachine returns true if the machine is a control plane machine that can serve the control plane IP.
+func isHealthyControlPlaneMachine(machine *clusterv1.Machine, hcloudMachine *infrav1.HCloudMachine) bool {
+	if !util.IsControlPlaneMachine(machine) {
+		return false
+	}
+
+	if !machine.DeletionTimestamp.IsZero() || !hcloudMachine.DeletionTimestamp.IsZero() {
+		return false
+	}
+
+	// machines that are unhealthy or remediated should not serve the control plane endpoint
+	if conditions.IsFalse(machine, clusterv1.MachineHealthCheckSucceededCondition) ||
+		conditions.Has(machine, clusterv1.MachineOwnerRemediatedCondition) {
+		return false
+	}
+
+	return hcloudMachine.Status.InstanceState != nil && *hcloudMachine.Status.InstanceState == hcloud.ServerStatusRunning
+}
+
+// selectServer returns the server the IP should be assigned to. The current server is kept as long as it is healthy.
+func selectServer(currentServerID int64, healthyServerIDs []int64) int64 {
+	if currentServerID != 0 && slices.Contains(healthyServerIDs, currentServerID) {
+		return currentServerID
+	}
+	if len(healthyServerIDs) == 0 {
+		return 0
+	}
+	return healthyServerIDs[0]
+}
+
+func statusFromHCloudFloatingIP(floatingIP *hcloud.FloatingIP) *infrav1.ControlPlaneIPStatus {
+	status := &infrav1.ControlPlaneIPStatus{
+		Type: infrav1.ControlPlaneEndpointTypeFloatingIP,
+		ID:   floatingIP.ID,
+		IPv4: floatingIP.IP.String(),
+	}
+	if floatingIP.Server != nil {
+		status.ServerID = floatingIP.Server.ID
+	}
+	if floatingIP.HomeLocation != nil {
+		status.Location = infrav1.Region(floatingIP.HomeLocation.Name)
+	}
+	return status
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplaneip
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+	fakehcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+)
+
+func TestControlPlaneIP(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ControlPlaneIP Suite")
+}
+
+func newMachines(name string, serverID int64, created time.Time) (*clusterv1.Machine, *infrav1.HCloudMachine) {
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(created),
+			Labels:            map[string]string{clusterv1.MachineControlPlaneLabel: ""},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "cluster",
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: infrav1.GroupVersion.String(),
+				Kind:       "HCloudMachine",
+				Name:       name,
+			},
+		},
+	}
+
+	hcloudMachine := &infrav1.HCloudMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: infrav1.HCloudMachineSpec{
+			ProviderID: ptr.To(fmt.Sprintf("hcloud://%d", serverID)),
+		},
+		Status: infrav1.HCloudMachineStatus{
+			InstanceState: ptr.To(hcloud.ServerStatusRunning),
+		},
+	}
+	return machine, hcloudMachine
+}
+
+var _ = Describe("selectServer", func() {
+	It("keeps the current server if it is healthy", func() {
+		Expect(selectServer(2, []int64{1, 2})).To(Equal(int64(2)))
+	})
+	It("selects the first healthy server if the current server is not healthy", func() {
+		Expect(selectServer(3, []int64{1, 2})).To(Equal(int64(1)))
+	})
+	It("selects the first healthy server if the IP is not assigned", func() {
+		Expect(selectServer(0, []int64{1, 2})).To(Equal(int64(1)))
+	})
+	It("returns zero if there is no healthy server", func() {
+		Expect(selectServer(3, nil)).To(Equal(int64(0)))
+	})
+})
+
+var _ = Describe("isHealthyControlPlaneMachine", func() {
+	var (
+		machine       *clusterv1.Machine
+		hcloudMachine *infrav1.HCloudMachine
+	)
+
+	BeforeEach(func() {
+		machine, hcloudMachine = newMachines("cp-1", 1, time.Now())
+	})
+
+	It("returns true for a running control plane machine", func() {
+		Expect(isHealthyControlPlaneMachine(machine, hcloudMachine)).To(BeTrue())
+	})
+	It("returns false for a worker machine", func() {
+		machine.Labels = nil
+		Expect(isHealthyControlPlaneMachine(machine, hcloudMachine)).To(BeFalse())
+	})
+	It("returns false for a deleted machine", func() {
+		machine.DeletionTimestamp = ptr.To(metav1.Now())
+		Expect(isHealthyControlPlaneMachine(machine, hcloudMachine)).To(BeFalse())
+	})
+	It("returns false for an unhealthy machine", func() {
+		conditions.MarkFalse(machine, clusterv1.MachineHealthCheckSucceededCondition, clusterv1.UnhealthyNodeConditionReason, clusterv1.ConditionSeverityWarning, "")
+		Expect(isHealthyControlPlaneMachine(machine, hcloudMachine)).To(BeFalse())
+	})
+	It("returns false for a machine that is remediated", func() {
+		conditions.MarkFalse(machine, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")
+		Expect(isHealthyControlPlaneMachine(machine, hcloudMachine)).To(BeFalse())
+	})
+	It("returns false for a server that is not running", func() {
+		hcloudMachine.Status.InstanceState = ptr.To(hcloud.ServerStatusOff)
+		Expect(isHealthyControlPlaneMachine(machine, hcloudMachine)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Reconcile and Delete", func() {
+	var (
+		ctx            context.Context
+		hcloudClient   hcloudclient.Client
+		hetznerCluster *infrav1.HetznerCluster
+		objects        []client.Object
+	)
+
+	newService := func() *Service {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(infrav1.AddToScheme(scheme))
+		utilruntime.Must(clusterv1.AddToScheme(scheme))
+		c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+		return NewService(&scope.ClusterScope{
+			Client:         c,
+			HCloudClient:   hcloudClient,
+			HetznerCluster: hetznerCluster,
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+			},
+		})
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		hcloudClient = fakehcloudclient.NewHCloudClientFactory().NewClient("")
+		hcloudClient.Reset()
+		objects = nil
+
+		hetznerCluster = &infrav1.HetznerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "hetzner-cluster", Namespace: "default"},
+			Spec: infrav1.HetznerClusterSpec{
+				ControlPlaneRegions: []infrav1.Region{"fsn1"},
+			},
+		}
+	})
+
+	It("does nothing if no control plane IP is used", func() {
+		_, err := newService().Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(hetznerCluster.Status.ControlPlaneIP).To(BeNil())
+	})
+
+	Context("FloatingIP", func() {
+		BeforeEach(func() {
+			hetznerCluster.Spec.ControlPlaneEndpointType = infrav1.ControlPlaneEndpointTypeFloatingIP
+		})
+
+		It("creates a floating IP and waits for a healthy control plane server", func() {
+			res, err := newService().Reconcile(ctx)
+			Expect(err).To(Succeed())
+			Expect(res.RequeueAfter).To(Equal(requeueAfterNotAssigned))
+
+			Expect(hetznerCluster.Status.ControlPlaneIP).ToNot(BeNil())
+			Expect(hetznerCluster.Status.ControlPlaneIP.IPv4).ToNot(BeEmpty())
+			Expect(hetznerCluster.Status.ControlPlaneIP.Location).To(Equal(infrav1.Region("fsn1")))
+			Expect(hetznerCluster.Status.ControlPlaneIP.ServerID).To(BeZero())
+			Expect(conditions.IsFalse(hetznerCluster, infrav1.ControlPlaneIPReadyCondition)).To(BeTrue())
+		})
+
+		It("assigns the floating IP to the oldest healthy control plane server and reassigns it", func() {
+			server1, err := hcloudClient.CreateServer(ctx, hcloud.ServerCreateOpts{Name: "cp-1"})
+			Expect(err).To(Succeed())
+			server2, err := hcloudClient.CreateServer(ctx, hcloud.ServerCreateOpts{Name: "cp-2"})
+			Expect(err).To(Succeed())
+
+			now := time.Now()
+			machine1, hcloudMachine1 := newMachines("cp-1", server1.ID, now.Add(-time.Hour))
+			machine2, hcloudMachine2 := newMachines("cp-2", server2.ID, now)
+			objects = []client.Object{machine1, hcloudMachine1, machine2, hcloudMachine2}
+
+			_, err = newService().Reconcile(ctx)
+			Expect(err).To(Succeed())
+			Expect(hetznerCluster.Status.ControlPlaneIP.ServerID).To(Equal(server1.ID))
+			Expect(conditions.IsTrue(hetznerCluster, infrav1.ControlPlaneIPReadyCondition)).To(BeTrue())
+
+			// the first machine gets remediated
+			conditions.MarkFalse(machine1, clusterv1.MachineHealthCheckSucceededCondition, clusterv1.UnhealthyNodeConditionReason, clusterv1.ConditionSeverityWarning, "")
+
+			_, err = newService().Reconcile(ctx)
+			Expect(err).To(Succeed())
+			Expect(hetznerCluster.Status.ControlPlaneIP.ServerID).To(Equal(server2.ID))
+		})
+
+		It("deletes the floating IP", func() {
+			_, err := newService().Reconcile(ctx)
+			Expect(err).To(Succeed())
+
+			Expect(newService().Delete(ctx)).To(Succeed())
+			Expect(hetznerCluster.Status.ControlPlaneIP).To(BeNil())
+
+			floatingIPs, err := hcloudClient.ListFloatingIPs(ctx, hcloud.FloatingIPListOpts{})
+			Expect(err).To(Succeed())
+			Expect(floatingIPs).To(BeEmpty())
+		})
+	})
+
+	It("reports a control plane endpoint type that is no longer supported", func() {
+		hetznerCluster.Spec.ControlPlaneEndpointType = infrav1.ControlPlaneEndpointType("PrimaryIP")
+
+		res, err := newService().Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(res.IsZero()).To(BeTrue())
+		Expect(hetznerCluster.Status.ControlPlaneIP).To(BeNil())
+		Expect(conditions.GetReason(hetznerCluster, infrav1.ControlPlaneIPReadyCondition)).To(Equal(infrav1.ControlPlaneIPTypeUnsupportedReason))
+	})
+})
@@ -0,0 +1,430 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package firewall implements the lifecycle of HCloud firewalls.
+package firewall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudutil "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/util"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+// Service struct contains cluster scope to reconcile firewalls.
+type Service struct {
+	scope *scope.ClusterScope
+}
+
+// NewService creates new service object.
+func NewService(scope *scope.ClusterScope) *Service {
+	return &Service{
+		scope: scope,
+	}
+}
+
+// Reconcile implements life cycle of firewalls.
+func (s *Service) Reconcile(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			conditions.MarkFalse(
+				s.scope.HetznerCluster,
+				infrav1.FirewallsSyncedCondition,
+				infrav1.FirewallsSyncFailedReason,
+				clusterv1.ConditionSeverityWarning,
+				"%s",
+				err.Error(),
+			)
+		}
+	}()
+
+	firewalls, err := s.findFirewalls(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find firewalls: %w", err)
+	}
+
+	firewallsSpec := s.scope.HetznerCluster.Spec.HCloudFirewalls
+
+	// Create arrays and maps to make diff
+	firewallNamesExisting := make([]string, len(firewalls))
+	firewallNamesDesired := make([]string, len(firewallsSpec))
+	firewallExistingMap := make(map[string]*hcloud.Firewall)
+	firewallDesiredMap := make(map[string]infrav1.HCloudFirewallSpec)
+
+	for i, fwSpec := range firewallsSpec {
+		firewallNamesDesired[i] = fwSpec.Name
+		firewallDesiredMap[fwSpec.Name] = firewallsSpec[i]
+	}
+
+	for i, fw := range firewalls {
+		name := s.specName(fw)
+		firewallNamesExisting[i] = name
+		firewallExistingMap[name] = firewalls[i]
+	}
+
+	// make diff of existing and desired firewalls
+	toCreate, toDelete := utils.DifferenceOfStringSlices(firewallNamesDesired, firewallNamesExisting)
+
+	var multierr error
+
+	// create new firewalls
+	for _, fwName := range toCreate {
+		opts, err := s.createOpts(firewallDesiredMap[fwName])
+		if err != nil {
+			multierr = errors.Join(multierr, fmt.Errorf("invalid spec of firewall %q: %w", fwName, err))
+			continue
+		}
+
+		if _, err := s.scope.HCloudClient.CreateFirewall(ctx, opts); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "CreateFirewall")
+			multierr = errors.Join(multierr, fmt.Errorf("failed to create firewall %q: %w", fwName, err))
+			continue
+		}
+
+		record.Eventf(s.scope.HetznerCluster, "FirewallCreated", "Created firewall %s", opts.Name)
+	}
+
+	// delete old firewalls
+	for _, fwName := range toDelete {
+		if err := s.deleteFirewall(ctx, firewallExistingMap[fwName]); err != nil {
+			multierr = errors.Join(multierr, err)
+		}
+	}
+
+	// update rules and resources of existing firewalls
+	var updated bool
+	for name, fw := range firewallExistingMap {
+		fwSpec, ok := firewallDesiredMap[name]
+		if !ok {
+			continue
+		}
+
+		changed, err := s.reconcileFirewall(ctx, fw, fwSpec)
+		if err != nil {
+			multierr = errors.Join(multierr, fmt.Errorf("failed to update firewall %q: %w", name, err))
+		}
+		updated = updated || changed
+	}
+
+	if multierr != nil {
+		return fmt.Errorf("aggregate error - syncing firewalls: %w", multierr)
+	}
+
+	// Update status
+	if len(toCreate) > 0 || len(toDelete) > 0 || updated {
+		// No need to update status if nothing changed
+		firewalls, err = s.findFirewalls(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to find firewalls: %w", err)
+		}
+	}
+
+	s.scope.HetznerCluster.Status.HCloudFirewalls = statusFromHCloudFirewalls(firewalls, s.scope.HetznerCluster.Name)
+	conditions.MarkTrue(s.scope.HetznerCluster, infrav1.FirewallsSyncedCondition)
+
+	return nil
+}
+
+// Delete implements deletion of firewalls.
+func (s *Service) Delete(ctx context.Context) error {
+	firewalls, err := s.findFirewalls(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find firewalls: %w", err)
+	}
+
+	var multierr error
+	for _, fw := range firewalls {
+		if err := s.deleteFirewall(ctx, fw); err != nil {
+			multierr = errors.Join(multierr, err)
+		}
+	}
+
+	if multierr != nil {
+		return fmt.Errorf("aggregate error - deleting firewalls: %w", multierr)
+	}
+
+	s.scope.HetznerCluster.Status.HCloudFirewalls = nil
+
+	return nil
+}
+
+// reconcileFirewall updates rules and resources of an existing firewall. It returns true if the firewall was changed.
+func (s *Service) reconcileFirewall(ctx context.Context, fw *hcloud.Firewall, fwSpec infrav1.HCloudFirewallSpec) (bool, error) {
+	var changed bool
+
+	rules, err := rulesFromSpec(fwSpec.Rules)
+	if err != nil {
+		return false, fmt.Errorf("invalid rules: %w", err)
+	}
+
+	if !rulesEqual(rules, fw.Rules) {
+		if err := s.scope.HCloudClient.SetFirewallRules(ctx, fw, hcloud.FirewallSetRulesOpts{Rules: rules}); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "SetFirewallRules")
+			return false, fmt.Errorf("failed to set rules: %w", err)
+		}
+		record.Eventf(s.scope.HetznerCluster, "FirewallRulesUpdated", "Updated rules of firewall %s", fw.Name)
+		changed = true
+	}
+
+	// only label selectors that select servers of the cluster are managed - servers and label selectors that have
+	// been added manually are left untouched
+	desiredSelector := s.labelSelector(fwSpec)
+	var toRemove []hcloud.FirewallResource
+	var found bool
+	for _, resource := range fw.AppliedTo {
+		if resource.Type != hcloud.FirewallResourceTypeLabelSelector || resource.LabelSelector == nil {
+			continue
+		}
+		if resource.LabelSelector.Selector == desiredSelector {
+			found = true
+			continue
+		}
+		if !s.isOwnedSelector(resource.LabelSelector.Selector) {
+			continue
+		}
+		toRemove = append(toRemove, resource)
+	}
+
+	if !found {
+		if err := s.scope.HCloudClient.ApplyFirewallResources(ctx, fw, []hcloud.FirewallResource{labelSelectorResource(desiredSelector)}); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ApplyFirewallResources")
+			if !hcloud.IsError(err, hcloud.ErrorCodeFirewallAlreadyApplied) {
+				return changed, fmt.Errorf("failed to apply firewall to label selector %q: %w", desiredSelector, err)
+			}
+		}
+		changed = true
+	}
+
+	if len(toRemove) > 0 {
+		if err := s.scope.HCloudClient.RemoveFirewallResources(ctx, fw, toRemove); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "RemoveFirewallResources")
+			if !hcloud.IsError(err, hcloud.ErrorCodeFirewallAlreadyRemoved) {
+				return changed, fmt.Errorf("failed to remove outdated resources from firewall: %w", err)
+			}
+		}
+		changed = true
+	}
+
+	return changed, nil
+}
+
+func (s *Service) deleteFirewall(ctx context.Context, fw *hcloud.Firewall) error {
+	// a firewall can only be deleted if it is not applied to any resources
+	if len(fw.AppliedTo) > 0 {
+		if err := s.scope.HCloudClient.RemoveFirewallResources(ctx, fw, fw.AppliedTo); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "RemoveFirewallResources")
+			if !hcloud.IsError(err, hcloud.ErrorCodeFirewallAlreadyRemoved) && !hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+				return fmt.Errorf("failed to remove resources from firewall %v: %w", fw.ID, err)
+			}
+		}
+	}
+
+	if err := s.scope.HCloudClient.DeleteFirewall(ctx, fw.ID); err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteFirewall")
+		// if resource has been deleted already then do nothing
+		if hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+			return nil
+		}
+		record.Warnf(s.scope.HetznerCluster, "FirewallDeleteFailed", "Failed to delete firewall with ID %v", fw.ID)
+		return fmt.Errorf("failed to delete firewall %v: %w", fw.ID, err)
+	}
+
+	record.Eventf(s.scope.HetznerCluster, "FirewallDeleted", "Deleted firewall with ID %v", fw.ID)
+	return nil
+}
+
+func (s *Service) createOpts(fwSpec infrav1.HCloudFirewallSpec) (hcloud.FirewallCreateOpts, error) {
+	rules, err := rulesFromSpec(fwSpec.Rules)
+	if err != nil {
+		return hcloud.FirewallCreateOpts{}, err
+	}
+
+	return hcloud.FirewallCreateOpts{
+		Name:    fmt.Sprintf("%s-%s", s.scope.HetznerCluster.Name, fwSpec.Name),
+		Labels:  s.labels(),
+		Rules:   rules,
+		ApplyTo: []hcloud.FirewallResource{labelSelectorResource(s.labelSelector(fwSpec))},
+	}, nil
+}
+
+func (s *Service) findFirewalls(ctx context.Context) ([]*hcloud.Firewall, error) {
+	opts := hcloud.FirewallListOpts{}
+	opts.LabelSelector = utils.LabelsToLabelSelector(s.labels())
+
+	firewalls, err := s.scope.HCloudClient.ListFirewalls(ctx, opts)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ListFirewalls")
+		return nil, fmt.Errorf("failed to list firewalls: %w", err)
+	}
+	return firewalls, nil
+}
+
+// labelSelector returns the HCloud label selector that selects the servers of the cluster the firewall is applied to.
+func (s *Service) labelSelector(fwSpec infrav1.HCloudFirewallSpec) string {
+	labels := make(map[string]string, len(fwSpec.LabelSelector)+1)
+	for key, val := range fwSpec.LabelSelector {
+		labels[key] = val
+	}
+	labels[s.scope.HetznerCluster.ClusterTagKey()] = string(infrav1.ResourceLifecycleOwned)
+	return utils.LabelsToLabelSelector(labels)
+}
+
+// isOwnedSelector returns whether the label selector has been applied by the controller, which always selects the
+// servers owned by the cluster.
+func (s *Service) isOwnedSelector(selector string) bool {
+	labels, err := utils.LabelSelectorToLabels(selector)
+	if err != nil {
+		return false
+	}
+	return labels[s.scope.HetznerCluster.ClusterTagKey()] == string(infrav1.ResourceLifecycleOwned)
+}
+
+func (s *Service) specName(fw *hcloud.Firewall) string {
+	return strings.TrimPrefix(fw.Name, s.scope.HetznerCluster.Name+"-")
+}
+
+func (s *Service) labels() map[string]string {
+	return map[string]string{
+		s.scope.HetznerCluster.ClusterTagKey(): string(infrav1.ResourceLifecycleOwned),
+	}
+}
+
+func labelSelectorResource(selector string) hcloud.FirewallResource {
+	return hcloud.FirewallResource{
+		Type:          hcloud.FirewallResourceTypeLabelSelector,
+		LabelSelector: &hcloud.FirewallResourceLabelSelector{Selector: selector},
+	}
+}
+
+// rulesFromSpec converts the rules of the spec to HCloud firewall rules.
+func rulesFromSpec(rulesSpec []infrav1.HCloudFirewallRuleSpec) ([]hcloud.FirewallRule, error) {
+	rules := make([]hcloud.FirewallRule, 0, len(rulesSpec))
+	for i, ruleSpec := range rulesSpec {
+		sourceIPs, err := parseCIDRs(ruleSpec.SourceIPs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source IPs in rule %d: %w", i, err)
+		}
+		destinationIPs, err := parseCIDRs(ruleSpec.DestinationIPs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid destination IPs in rule %d: %w", i, err)
+		}
+
+		rules = append(rules, hcloud.FirewallRule{
+			Direction:      hcloud.FirewallRuleDirection(ruleSpec.Direction),
+			Protocol:       hcloud.FirewallRuleProtocol(ruleSpec.Protocol),
+			Port:           ruleSpec.Port,
+			SourceIPs:      sourceIPs,
+			DestinationIPs: destinationIPs,
+			Description:    ruleSpec.Description,
+		})
+	}
+	return rules, nil
+}
+
+func parseCIDRs(cidrs []string) ([]net.IPNet, error) {
+	ipNets := make([]net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+		ipNets = append(ipNets, *ipNet)
+	}
+	return ipNets, nil
+}
+
+// rulesEqual compares two lists of firewall rules independent of the order of rules and IPs.
+func rulesEqual(a, b []hcloud.FirewallRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	keysA := make([]string, len(a))
+	keysB := make([]string, len(b))
+	for i := range a {
+		keysA[i] = ruleKey(a[i])
+		keysB[i] = ruleKey(b[i])
+	}
+	slices.Sort(keysA)
+	slices.Sort(keysB)
+
+	return slices.Equal(keysA, keysB)
+}
+
+func ruleKey(rule hcloud.FirewallRule) string {
+	ipNetsToString := func(ipNets []net.IPNet) string {
+		strs := make([]string, len(ipNets))
+		for i := range ipNets {
+			strs[i] = ipNets[i].String()
+		}
+		slices.Sort(strs)
+		return strings.Join(strs, ",")
+	}
+
+	var port, description string
+	if rule.Port != nil {
+		port = *rule.Port
+	}
+	if rule.Description != nil {
+		description = *rule.Description
+	}
+
+	return strings.Join([]string{
+		string(rule.Direction),
+		string(rule.Protocol),
+		port,
+		ipNetsToString(rule.SourceIPs),
+		ipNetsToString(rule.DestinationIPs),
+		description,
+	}, "|")
+}
+
+// statusFromHCloudFirewalls gets the information of the Hetzner firewalls and returns it in our status object.
+func statusFromHCloudFirewalls(firewalls []*hcloud.Firewall, clusterName string) []infrav1.HCloudFirewallStatus {
+	status := make([]infrav1.HCloudFirewallStatus, len(firewalls))
+	for i, fw := range firewalls {
+		var appliedTo []string
+		for _, resource := range fw.AppliedTo {
+			if resource.Type == hcloud.FirewallResourceTypeLabelSelector && resource.LabelSelector != nil {
+				appliedTo = append(appliedTo, resource.LabelSelector.Selector)
+			}
+		}
+		status[i] = infrav1.HCloudFirewallStatus{
+			ID:        fw.ID,
+			Name:      strings.TrimPrefix(fw.Name, clusterName+"-"),
+			AppliedTo: appliedTo,
+		}
+	}
+
+	// HCloud does not return the firewalls in a fixed order, but we want to have a
+	// deterministic order to avoid unnecessary updates to the HetznerCluster resource.
+	slices.SortFunc(status, func(a, b infrav1.HCloudFirewallStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return status
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firewall
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+)
+
+func TestFirewall(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Firewall Suite")
+}
+
+var _ = Describe("Test labelSelector", func() {
+	It("selects all servers of the cluster if no label selector is given", func() {
+		service := Service{&scope.ClusterScope{HetznerCluster: &infrav1.HetznerCluster{}}}
+		service.scope.HetznerCluster.Name = "hetzner-cluster"
+
+		Expect(service.labelSelector(infrav1.HCloudFirewallSpec{})).To(Equal("caph-cluster-hetzner-cluster==owned"))
+	})
+
+	It("combines the label selector with the cluster label", func() {
+		service := Service{&scope.ClusterScope{HetznerCluster: &infrav1.HetznerCluster{}}}
+		service.scope.HetznerCluster.Name = "hetzner-cluster"
+
+		selector := service.labelSelector(infrav1.HCloudFirewallSpec{
+			LabelSelector: map[string]string{"role": "control-plane"},
+		})
+		Expect(selector).To(Equal("caph-cluster-hetzner-cluster==owned,role==control-plane"))
+	})
+})
+
+var _ = Describe("Test rules", func() {
+	rulesSpec := []infrav1.HCloudFirewallRuleSpec{
+		{
+			Direction: "in",
+			Protocol:  "tcp",
+			Port:      ptr.To("6443"),
+			SourceIPs: []string{"10.0.0.0/16", "0.0.0.0/0"},
+		},
+		{
+			Direction:   "in",
+			Protocol:    "icmp",
+			SourceIPs:   []string{"::/0"},
+			Description: ptr.To("ping"),
+		},
+	}
+
+	It("converts the rules of the spec", func() {
+		rules, err := rulesFromSpec(rulesSpec)
+		Expect(err).To(BeNil())
+		Expect(rules).To(HaveLen(2))
+		Expect(rules[0].Direction).To(Equal(hcloud.FirewallRuleDirectionIn))
+		Expect(rules[0].Protocol).To(Equal(hcloud.FirewallRuleProtocolTCP))
+		Expect(rules[0].Port).To(Equal(ptr.To("6443")))
+		Expect(rules[0].SourceIPs).To(HaveLen(2))
+		Expect(rules[0].SourceIPs[0].String()).To(Equal("10.0.0.0/16"))
+		Expect(rules[1].Description).To(Equal(ptr.To("ping")))
+	})
+
+	It("fails on invalid CIDRs", func() {
+		_, err := rulesFromSpec([]infrav1.HCloudFirewallRuleSpec{
+			{Direction: "in", Protocol: "tcp", Port: ptr.To("22"), SourceIPs: []string{"10.0.0.0"}},
+		})
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("ignores the order of rules and IPs when comparing", func() {
+		rules, err := rulesFromSpec(rulesSpec)
+		Expect(err).To(BeNil())
+
+		reversedSpec := []infrav1.HCloudFirewallRuleSpec{rulesSpec[1], rulesSpec[0]}
+		reversedSpec[1].SourceIPs = []string{"0.0.0.0/0", "10.0.0.0/16"}
+		reversed, err := rulesFromSpec(reversedSpec)
+		Expect(err).To(BeNil())
+
+		Expect(rulesEqual(rules, reversed)).To(BeTrue())
+	})
+
+	It("detects changed rules", func() {
+		rules, err := rulesFromSpec(rulesSpec)
+		Expect(err).To(BeNil())
+
+		changedSpec := []infrav1.HCloudFirewallRuleSpec{rulesSpec[0], rulesSpec[1]}
+		changedSpec[0].Port = ptr.To("443")
+		changed, err := rulesFromSpec(changedSpec)
+		Expect(err).To(BeNil())
+
+		Expect(rulesEqual(rules, changed)).To(BeFalse())
+		Expect(rulesEqual(rules, rules[:1])).To(BeFalse())
+	})
+})
+
+var _ = Describe("Test Reconcile and Delete", func() {
+	var (
+		ctx            context.Context
+		hetznerCluster *infrav1.HetznerCluster
+		service        *Service
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		hcloudClient := fakeclient.NewHCloudClientFactory().NewClient("")
+		hcloudClient.Reset()
+
+		hetznerCluster = &infrav1.HetznerCluster{}
+		hetznerCluster.Name = "hetzner-cluster"
+		hetznerCluster.Spec.HCloudFirewalls = []infrav1.HCloudFirewallSpec{
+			{
+				Name: "control-plane",
+				Rules: []infrav1.HCloudFirewallRuleSpec{
+					{Direction: "in", Protocol: "tcp", Port: ptr.To("6443"), SourceIPs: []string{"0.0.0.0/0"}},
+				},
+				LabelSelector: map[string]string{"role": "control-plane"},
+			},
+			{
+				Name: "all",
+			},
+		}
+
+		service = NewService(&scope.ClusterScope{HCloudClient: hcloudClient, HetznerCluster: hetznerCluster})
+	})
+
+	It("creates the firewalls and sets the status", func() {
+		Expect(service.Reconcile(ctx)).To(Succeed())
+		Expect(conditions.IsTrue(hetznerCluster, infrav1.FirewallsSyncedCondition)).To(BeTrue())
+
+		Expect(hetznerCluster.Status.HCloudFirewalls).To(HaveLen(2))
+		Expect(hetznerCluster.Status.HCloudFirewalls[0].Name).To(Equal("all"))
+		Expect(hetznerCluster.Status.HCloudFirewalls[0].AppliedTo).To(Equal([]string{"caph-cluster-hetzner-cluster==owned"}))
+		Expect(hetznerCluster.Status.HCloudFirewalls[1].Name).To(Equal("control-plane"))
+		Expect(hetznerCluster.Status.HCloudFirewalls[1].AppliedTo).To(Equal([]string{"caph-cluster-hetzner-cluster==owned,role==control-plane"}))
+	})
+
+	It("updates rules and label selectors of existing firewalls", func() {
+		Expect(service.Reconcile(ctx)).To(Succeed())
+
+		hetznerCluster.Spec.HCloudFirewalls[1].LabelSelector = map[string]string{"role": "worker"}
+		hetznerCluster.Spec.HCloudFirewalls[0].Rules[0].Port = ptr.To("443")
+		Expect(service.Reconcile(ctx)).To(Succeed())
+
+		Expect(hetznerCluster.Status.HCloudFirewalls[0].AppliedTo).To(Equal([]string{"caph-cluster-hetzner-cluster==owned,role==worker"}))
+
+		firewalls, err := service.findFirewalls(ctx)
+		Expect(err).To(BeNil())
+		for _, fw := range firewalls {
+			if fw.Name == "hetzner-cluster-control-plane" {
+				Expect(fw.Rules).To(HaveLen(1))
+				Expect(fw.Rules[0].Port).To(Equal(ptr.To("443")))
+			}
+		}
+	})
+
+	It("keeps label selectors that have been added manually", func() {
+		Expect(service.Reconcile(ctx)).To(Succeed())
+
+		firewalls, err := service.findFirewalls(ctx)
+		Expect(err).To(BeNil())
+		var allFirewall *hcloud.Firewall
+		for _, fw := range firewalls {
+			if fw.Name == "hetzner-cluster-all" {
+				allFirewall = fw
+			}
+		}
+		Expect(allFirewall).ToNot(BeNil())
+		manual := labelSelectorResource("team==platform")
+		Expect(service.scope.HCloudClient.ApplyFirewallResources(ctx, allFirewall, []hcloud.FirewallResource{manual})).To(Succeed())
+
+		hetznerCluster.Spec.HCloudFirewalls[1].LabelSelector = map[string]string{"role": "worker"}
+		Expect(service.Reconcile(ctx)).To(Succeed())
+
+		firewalls, err = service.findFirewalls(ctx)
+		Expect(err).To(BeNil())
+		for _, fw := range firewalls {
+			if fw.ID != allFirewall.ID {
+				continue
+			}
+			var selectors []string
+			for _, resource := range fw.AppliedTo {
+				selectors = append(selectors, resource.LabelSelector.Selector)
+			}
+			Expect(selectors).To(ConsistOf("team==platform", "caph-cluster-hetzner-cluster==owned,role==worker"))
+		}
+	})
+
+	It("deletes firewalls that have been removed from the spec", func() {
+		Expect(service.Reconcile(ctx)).To(Succeed())
+
+		hetznerCluster.Spec.HCloudFirewalls = hetznerCluster.Spec.HCloudFirewalls[:1]
+		Expect(service.Reconcile(ctx)).To(Succeed())
+
+		Expect(hetznerCluster.Status.HCloudFirewalls).To(HaveLen(1))
+		Expect(hetznerCluster.Status.HCloudFirewalls[0].Name).To(Equal("control-plane"))
+	})
+
+	It("deletes all firewalls", func() {
+		Expect(service.Reconcile(ctx)).To(Succeed())
+		Expect(service.Delete(ctx)).To(Succeed())
+
+		firewalls, err := service.findFirewalls(ctx)
+		Expect(err).To(BeNil())
+		Expect(firewalls).To(BeEmpty())
+		Expect(hetznerCluster.Status.HCloudFirewalls).To(BeEmpty())
+	})
+})
@@ -0,0 +1,407 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	hcloudutil "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/util"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+// ReconcileAdditionalLoadBalancers implements the life cycle of the additional load balancers of the cluster.
+func (s *Service) ReconcileAdditionalLoadBalancers(ctx context.Context) (err error) {
+	specs := s.scope.HetznerCluster.Spec.AdditionalLoadBalancers
+
+	defer func() {
+		if err != nil {
+			conditions.MarkFalse(
+				s.scope.HetznerCluster,
+				infrav1.AdditionalLoadBalancersReadyCondition,
+				infrav1.AdditionalLoadBalancersNotReadyReason,
+				clusterv1.ConditionSeverityWarning,
+				"%s",
+				err.Error(),
+			)
+		}
+	}()
+
+	loadBalancers, err := s.findAdditionalLoadBalancers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find additional load balancers: %w", err)
+	}
+
+	var multierr error
+	statuses := make([]infrav1.AdditionalLoadBalancerStatus, 0, len(specs))
+	for _, spec := range specs {
+		status, err := s.reconcileAdditionalLoadBalancer(ctx, spec, loadBalancers[spec.Name])
+		if err != nil {
+			multierr = errors.Join(multierr, fmt.Errorf("load balancer %q: %w", spec.Name, err))
+		}
+		statuses = append(statuses, status)
+	}
+
+	// delete load balancers that have been removed from the spec
+	for name, lb := range loadBalancers {
+		if slices.ContainsFunc(specs, func(spec infrav1.AdditionalLoadBalancerSpec) bool { return spec.Name == name }) {
+			continue
+		}
+		if err := s.deleteAdditionalLoadBalancer(ctx, lb); err != nil {
+			multierr = errors.Join(multierr, fmt.Errorf("load balancer %q: %w", name, err))
+		}
+	}
+
+	slices.SortFunc(statuses, func(a, b infrav1.AdditionalLoadBalancerStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	s.scope.HetznerCluster.Status.AdditionalLoadBalancers = statuses
+
+	if multierr != nil {
+		return multierr
+	}
+
+	if len(specs) == 0 {
+		conditions.Delete(s.scope.HetznerCluster, infrav1.AdditionalLoadBalancersReadyCondition)
+		return nil
+	}
+
+	conditions.MarkTrue(s.scope.HetznerCluster, infrav1.AdditionalLoadBalancersReadyCondition)
+	return nil
+}
+
+func (s *Service) reconcileAdditionalLoadBalancer(
+	ctx context.Context,
+	spec infrav1.AdditionalLoadBalancerSpec,
+	lb *hcloud.LoadBalancer,
+) (infrav1.AdditionalLoadBalancerStatus, error) {
+	status := infrav1.AdditionalLoadBalancerStatus{Name: spec.Name}
+	for _, oldStatus := range s.scope.HetznerCluster.Status.AdditionalLoadBalancers {
+		if oldStatus.Name == spec.Name {
+			status = oldStatus
+		}
+	}
+
+	if lb == nil {
+		var err error
+		lb, err = s.createAdditionalLoadBalancer(ctx, spec)
+		if err != nil {
+			setLoadBalancerReadyFalse(&status, infrav1.LoadBalancerCreateFailedReason, err)
+			return status, err
+		}
+	}
+
+	status.LoadBalancerStatus = *statusFromHCloudLB(lb, s.scope.HetznerCluster.Status.Network != nil, s.scope.Logger)
+
+	if err := s.reconcileAdditionalLBProperties(ctx, spec, lb); err != nil {
+		setLoadBalancerReadyFalse(&status, infrav1.LoadBalancerUpdateFailedReason, err)
+		return status, err
+	}
+
+	usePrivateIP, err := s.reconcileAdditionalLBNetworkAttachment(ctx, spec, lb)
+	if err != nil {
+		setLoadBalancerReadyFalse(&status, infrav1.NetworkAttachFailedReason, err)
+		return status, err
+	}
+
+	if err := s.syncServices(ctx, lb, spec.Services); err != nil {
+		setLoadBalancerReadyFalse(&status, infrav1.LoadBalancerServiceSyncFailedReason, err)
+		return status, err
+	}
+
+	if err := s.reconcileAdditionalLBTarget(ctx, spec, lb, usePrivateIP); err != nil {
+		setLoadBalancerReadyFalse(&status, infrav1.LoadBalancerTargetSyncFailedReason, err)
+		return status, err
+	}
+
+	// detach only after the target has been switched to the public IPs of the servers
+	if !usePrivateIP {
+		if err := s.detachAdditionalLBFromNetworks(ctx, lb); err != nil {
+			setLoadBalancerReadyFalse(&status, infrav1.NetworkAttachFailedReason, err)
+			return status, err
+		}
+	}
+
+	setLoadBalancerCondition(&status, *conditions.TrueCondition(infrav1.LoadBalancerReadyCondition))
+	return status, nil
+}
+
+func (s *Service) createAdditionalLoadBalancer(ctx context.Context, spec infrav1.AdditionalLoadBalancerSpec) (*hcloud.LoadBalancer, error) {
+	hc := s.scope.HetznerCluster
+
+	var network *hcloud.Network
+	if spec.AttachToNetwork && hc.Status.Network != nil {
+		network = &hcloud.Network{ID: hc.Status.Network.ID}
+	}
+
+	publicInterface := true
+	opts := hcloud.LoadBalancerCreateOpts{
+		LoadBalancerType: &hcloud.LoadBalancerType{Name: spec.Type},
+		Name:             fmt.Sprintf("%s-%s", hc.Name, spec.Name),
+		Algorithm:        &hcloud.LoadBalancerAlgorithm{Type: spec.Algorithm.HCloudAlgorithmType()},
+		Location:         &hcloud.Location{Name: string(spec.Region)},
+		Network:          network,
+		Labels:           additionalLoadBalancerLabels(hc, spec.Name),
+		PublicInterface:  &publicInterface,
+	}
+
+	lb, err := s.scope.HCloudClient.CreateLoadBalancer(ctx, opts)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(hc, err, "CreateLoadBalancer")
+		err = fmt.Errorf("failed to create load balancer: %w", err)
+		record.Warnf(hc, "FailedCreateLoadBalancer", err.Error())
+		return nil, err
+	}
+
+	record.Eventf(hc, "CreateLoadBalancer", "Created additional load balancer %s", opts.Name)
+	return lb, nil
+}
+
+func (s *Service) reconcileAdditionalLBProperties(ctx context.Context, spec infrav1.AdditionalLoadBalancerSpec, lb *hcloud.LoadBalancer) error {
+	var multierr error
+
+	if lb.LoadBalancerType == nil || spec.Type != lb.LoadBalancerType.Name {
+		opts := hcloud.LoadBalancerChangeTypeOpts{LoadBalancerType: &hcloud.LoadBalancerType{Name: spec.Type}}
+		if err := s.scope.HCloudClient.ChangeLoadBalancerType(ctx, lb, opts); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ChangeLoadBalancerType")
+			multierr = errors.Join(multierr, fmt.Errorf("failed to change load balancer type: %w", err))
+		} else {
+			record.Eventf(s.scope.HetznerCluster, "ChangeLoadBalancerType", "Changed type of load balancer %s", lb.Name)
+		}
+	}
+
+	if string(spec.Algorithm) != string(lb.Algorithm.Type) {
+		opts := hcloud.LoadBalancerChangeAlgorithmOpts{Type: spec.Algorithm.HCloudAlgorithmType()}
+		if err := s.scope.HCloudClient.ChangeLoadBalancerAlgorithm(ctx, lb, opts); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ChangeLoadBalancerAlgorithm")
+			multierr = errors.Join(multierr, fmt.Errorf("failed to change load balancer algorithm: %w", err))
+		} else {
+			record.Eventf(s.scope.HetznerCluster, "ChangeLoadBalancerAlgorithm", "Changed algorithm of load balancer %s", lb.Name)
+		}
+	}
+
+	return multierr
+}
+
+// reconcileAdditionalLBNetworkAttachment attaches the load balancer to the network of the cluster if wanted.
+// It returns whether the private IPs of the servers should be targeted.
+func (s *Service) reconcileAdditionalLBNetworkAttachment(ctx context.Context, spec infrav1.AdditionalLoadBalancerSpec, lb *hcloud.LoadBalancer) (bool, error) {
+	if !spec.AttachToNetwork {
+		return false, nil
+	}
+
+	if len(lb.PrivateNet) > 0 {
+		return true, nil
+	}
+
+	if s.scope.HetznerCluster.Status.Network == nil {
+		return false, nil
+	}
+
+	opts := hcloud.LoadBalancerAttachToNetworkOpts{
+		Network: &hcloud.Network{ID: s.scope.HetznerCluster.Status.Network.ID},
+	}
+	if err := s.scope.HCloudClient.AttachLoadBalancerToNetwork(ctx, lb, opts); err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "AttachLoadBalancerToNetwork")
+		if !hcloud.IsError(err, hcloud.ErrorCodeLoadBalancerAlreadyAttached) {
+			return false, fmt.Errorf("failed to attach load balancer to network: %w", err)
+		}
+	}
+
+	return true, nil
+}
+
+// detachAdditionalLBFromNetworks detaches the load balancer from its networks if it should not be attached anymore.
+func (s *Service) detachAdditionalLBFromNetworks(ctx context.Context, lb *hcloud.LoadBalancer) error {
+	for _, privateNet := range lb.PrivateNet {
+		if privateNet.Network == nil {
+			continue
+		}
+
+		opts := hcloud.LoadBalancerDetachFromNetworkOpts{
+			Network: &hcloud.Network{ID: privateNet.Network.ID},
+		}
+		if err := s.scope.HCloudClient.DetachLoadBalancerFromNetwork(ctx, lb, opts); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DetachLoadBalancerFromNetwork")
+			if !hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+				return fmt.Errorf("failed to detach load balancer from network %d: %w", privateNet.Network.ID, err)
+			}
+		}
+
+		record.Eventf(s.scope.HetznerCluster, "DetachLoadBalancerFromNetwork", "Detached load balancer %s from network %d", lb.Name, privateNet.Network.ID)
+	}
+
+	lb.PrivateNet = nil
+	return nil
+}
+
+// reconcileAdditionalLBTarget makes sure that the load balancer targets the selected servers of the cluster
+// with a single label selector target.
+func (s *Service) reconcileAdditionalLBTarget(ctx context.Context, spec infrav1.AdditionalLoadBalancerSpec, lb *hcloud.LoadBalancer, usePrivateIP bool) error {
+	labels := maps.Clone(spec.MachineSelector)
+	if labels == nil {
+		labels = make(map[string]string, 1)
+	}
+	labels[s.scope.HetznerCluster.ClusterTagKey()] = string(infrav1.ResourceLifecycleOwned)
+	selector := utils.LabelsToLabelSelector(labels)
+
+	// servers that are being deleted lose their machine type label and are not targeted anymore
+	if _, found := labels[infrav1.MachineTypeTagKey]; !found {
+		selector += "," + infrav1.MachineTypeTagKey
+	}
+
+	var hasTarget bool
+	for _, target := range lb.Targets {
+		if target.Type != hcloud.LoadBalancerTargetTypeLabelSelector || target.LabelSelector == nil {
+			continue
+		}
+
+		if target.LabelSelector.Selector == selector && target.UsePrivateIP == usePrivateIP {
+			hasTarget = true
+			continue
+		}
+
+		// the selector has changed
+		if err := s.scope.HCloudClient.DeleteLabelSelectorTargetOfLoadBalancer(ctx, lb, target.LabelSelector.Selector); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteLabelSelectorTargetOfLoadBalancer")
+			return fmt.Errorf("failed to delete label selector target %q: %w", target.LabelSelector.Selector, err)
+		}
+	}
+
+	if hasTarget {
+		return nil
+	}
+
+	opts := hcloud.LoadBalancerAddLabelSelectorTargetOpts{
+		Selector:     selector,
+		UsePrivateIP: &usePrivateIP,
+	}
+	if err := s.scope.HCloudClient.AddLabelSelectorTargetToLoadBalancer(ctx, opts, lb); err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "AddLabelSelectorTargetToLoadBalancer")
+		return fmt.Errorf("failed to add label selector target %q: %w", selector, err)
+	}
+
+	record.Eventf(s.scope.HetznerCluster, "AddedLabelSelectorTarget", "Added label selector target %q to load balancer %s", selector, lb.Name)
+	return nil
+}
+
+// DeleteAdditionalLoadBalancers deletes all additional load balancers of the cluster.
+func (s *Service) DeleteAdditionalLoadBalancers(ctx context.Context) error {
+	loadBalancers, err := s.findAdditionalLoadBalancers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find additional load balancers: %w", err)
+	}
+
+	var multierr error
+	for name, lb := range loadBalancers {
+		if err := s.deleteAdditionalLoadBalancer(ctx, lb); err != nil {
+			multierr = errors.Join(multierr, fmt.Errorf("load balancer %q: %w", name, err))
+		}
+	}
+	if multierr != nil {
+		return multierr
+	}
+
+	s.scope.HetznerCluster.Status.AdditionalLoadBalancers = nil
+	return nil
+}
+
+func (s *Service) deleteAdditionalLoadBalancer(ctx context.Context, lb *hcloud.LoadBalancer) error {
+	if err := s.scope.HCloudClient.DeleteLoadBalancer(ctx, lb.ID); err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteLoadBalancer")
+		if hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+			return nil
+		}
+		err = fmt.Errorf("failed to delete load balancer: %w", err)
+		record.Warnf(s.scope.HetznerCluster, "FailedLoadBalancerDelete", err.Error())
+		return err
+	}
+
+	record.Eventf(s.scope.HetznerCluster, "DeleteLoadBalancer", "Deleted additional load balancer %s", lb.Name)
+	return nil
+}
+
+// findAdditionalLoadBalancers returns the additional load balancers of the cluster by their name in the spec.
+func (s *Service) findAdditionalLoadBalancers(ctx context.Context) (map[string]*hcloud.LoadBalancer, error) {
+	opts := hcloud.LoadBalancerListOpts{
+		ListOpts: hcloud.ListOpts{
+			LabelSelector: utils.LabelsToLabelSelector(map[string]string{
+				s.scope.HetznerCluster.ClusterTagKey(): string(infrav1.ResourceLifecycleOwned),
+			}),
+		},
+	}
+	loadBalancers, err := s.scope.HCloudClient.ListLoadBalancers(ctx, opts)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ListLoadBalancers")
+		return nil, fmt.Errorf("failed to list load balancers: %w", err)
+	}
+
+	additionalLoadBalancers := make(map[string]*hcloud.LoadBalancer)
+	for _, lb := range loadBalancers {
+		if name, ok := lb.Labels[infrav1.LoadBalancerNameTagKey]; ok {
+			additionalLoadBalancers[name] = lb
+		}
+	}
+	return additionalLoadBalancers, nil
+}
+
+func additionalLoadBalancerLabels(hc *infrav1.HetznerCluster, name string) map[string]string {
+	return map[string]string{
+		hc.ClusterTagKey():             string(infrav1.ResourceLifecycleOwned),
+		infrav1.LoadBalancerNameTagKey: name,
+	}
+}
+
+func setLoadBalancerReadyFalse(status *infrav1.AdditionalLoadBalancerStatus, reason string, err error) {
+	setLoadBalancerCondition(status, *conditions.FalseCondition(
+		infrav1.LoadBalancerReadyCondition,
+		reason,
+		clusterv1.ConditionSeverityWarning,
+		"%s",
+		err.Error(),
+	))
+}
+
+// setLoadBalancerCondition sets the condition in the status of the load balancer. The transition time is only
+// updated if the status of the condition changes.
+func setLoadBalancerCondition(status *infrav1.AdditionalLoadBalancerStatus, condition clusterv1.Condition) {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type != condition.Type {
+			continue
+		}
+		condition.LastTransitionTime = status.Conditions[i].LastTransitionTime
+		if status.Conditions[i].Status != condition.Status {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		status.Conditions[i] = condition
+		return
+	}
+
+	condition.LastTransitionTime = metav1.Now()
+	status.Conditions = append(status.Conditions, condition)
+}
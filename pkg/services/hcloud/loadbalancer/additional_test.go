@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+)
+
+var _ = Describe("ReconcileAdditionalLoadBalancers", func() {
+	var (
+		ctx            context.Context
+		hcloudClient   hcloudclient.Client
+		hetznerCluster *infrav1.HetznerCluster
+		service        *Service
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		hcloudClient = fakeclient.NewHCloudClientFactory().NewClient("")
+		hcloudClient.Reset()
+
+		network, err := hcloudClient.CreateNetwork(ctx, hcloud.NetworkCreateOpts{Name: "hetzner-cluster"})
+		Expect(err).To(BeNil())
+
+		hetznerCluster = &infrav1.HetznerCluster{
+			Spec: infrav1.HetznerClusterSpec{
+				AdditionalLoadBalancers: []infrav1.AdditionalLoadBalancerSpec{
+					{
+						Name:            "ingress",
+						Algorithm:       infrav1.LoadBalancerAlgorithmTypeRoundRobin,
+						Type:            "lb11",
+						Region:          "fsn1",
+						AttachToNetwork: true,
+						MachineSelector: map[string]string{"machine_type": "worker"},
+						Services: []infrav1.LoadBalancerServiceSpec{
+							{Protocol: "tcp", ListenPort: 80, DestinationPort: 30080},
+							{Protocol: "tcp", ListenPort: 443, DestinationPort: 30443},
+						},
+					},
+				},
+			},
+			Status: infrav1.HetznerClusterStatus{
+				Network: &infrav1.NetworkStatus{ID: network.ID},
+			},
+		}
+		hetznerCluster.Name = "hetzner-cluster"
+
+		service = NewService(&scope.ClusterScope{HCloudClient: hcloudClient, HetznerCluster: hetznerCluster, Logger: logr.Discard()})
+	})
+
+	listAdditionalLoadBalancers := func() map[string]*hcloud.LoadBalancer {
+		lbs, err := service.findAdditionalLoadBalancers(ctx)
+		Expect(err).To(BeNil())
+		return lbs
+	}
+
+	It("creates the load balancer with services and a label selector target", func() {
+		Expect(service.ReconcileAdditionalLoadBalancers(ctx)).To(Succeed())
+
+		lbs := listAdditionalLoadBalancers()
+		Expect(lbs).To(HaveLen(1))
+		lb := lbs["ingress"]
+		Expect(lb).ToNot(BeNil())
+		Expect(lb.Name).To(Equal("hetzner-cluster-ingress"))
+		Expect(lb.Location.Name).To(Equal("fsn1"))
+		Expect(lb.PrivateNet).To(HaveLen(1))
+		Expect(lb.Services).To(HaveLen(2))
+		Expect(lb.Targets).To(HaveLen(1))
+		Expect(lb.Targets[0].LabelSelector.Selector).To(Equal("caph-cluster-hetzner-cluster==owned,machine_type==worker"))
+		Expect(lb.Targets[0].UsePrivateIP).To(BeTrue())
+
+		Expect(hetznerCluster.Status.AdditionalLoadBalancers).To(HaveLen(1))
+		status := hetznerCluster.Status.AdditionalLoadBalancers[0]
+		Expect(status.Name).To(Equal("ingress"))
+		Expect(status.ID).To(Equal(lb.ID))
+		Expect(status.Conditions).To(HaveLen(1))
+		Expect(status.Conditions[0].Status).To(Equal(corev1.ConditionTrue))
+		Expect(conditions.IsTrue(hetznerCluster, infrav1.AdditionalLoadBalancersReadyCondition)).To(BeTrue())
+	})
+
+	It("updates the load balancer according to the spec", func() {
+		Expect(service.ReconcileAdditionalLoadBalancers(ctx)).To(Succeed())
+		transitionTime := hetznerCluster.Status.AdditionalLoadBalancers[0].Conditions[0].LastTransitionTime
+
+		spec := &hetznerCluster.Spec.AdditionalLoadBalancers[0]
+		spec.Type = "lb21"
+		spec.Algorithm = infrav1.LoadBalancerAlgorithmTypeLeastConnections
+		spec.MachineSelector = map[string]string{"pool": "ingress"}
+		spec.Services = spec.Services[:1]
+		Expect(service.ReconcileAdditionalLoadBalancers(ctx)).To(Succeed())
+
+		lb := listAdditionalLoadBalancers()["ingress"]
+		Expect(lb.LoadBalancerType.Name).To(Equal("lb21"))
+		Expect(lb.Algorithm.Type).To(Equal(hcloud.LoadBalancerAlgorithmTypeLeastConnections))
+		Expect(lb.Services).To(HaveLen(1))
+		Expect(lb.Targets).To(HaveLen(1))
+		// servers that are being deleted have no machine type label
+		Expect(lb.Targets[0].LabelSelector.Selector).To(Equal("caph-cluster-hetzner-cluster==owned,pool==ingress,machine_type"))
+
+		Expect(hetznerCluster.Status.AdditionalLoadBalancers[0].Conditions[0].LastTransitionTime).To(Equal(transitionTime))
+	})
+
+	It("detaches the load balancer from the network if it should not be attached anymore", func() {
+		Expect(service.ReconcileAdditionalLoadBalancers(ctx)).To(Succeed())
+
+		hetznerCluster.Spec.AdditionalLoadBalancers[0].AttachToNetwork = false
+		Expect(service.ReconcileAdditionalLoadBalancers(ctx)).To(Succeed())
+
+		lb := listAdditionalLoadBalancers()["ingress"]
+		Expect(lb.PrivateNet).To(BeEmpty())
+		Expect(lb.Targets).To(HaveLen(1))
+		Expect(lb.Targets[0].UsePrivateIP).To(BeFalse())
+		Expect(conditions.IsTrue(hetznerCluster, infrav1.AdditionalLoadBalancersReadyCondition)).To(BeTrue())
+	})
+
+	It("deletes load balancers that are removed from the spec", func() {
+		Expect(service.ReconcileAdditionalLoadBalancers(ctx)).To(Succeed())
+
+		hetznerCluster.Spec.AdditionalLoadBalancers = nil
+		Expect(service.ReconcileAdditionalLoadBalancers(ctx)).To(Succeed())
+
+		Expect(listAdditionalLoadBalancers()).To(BeEmpty())
+		Expect(hetznerCluster.Status.AdditionalLoadBalancers).To(BeEmpty())
+		Expect(conditions.Has(hetznerCluster, infrav1.AdditionalLoadBalancersReadyCondition)).To(BeFalse())
+	})
+
+	It("deletes all load balancers when the cluster is deleted", func() {
+		Expect(service.ReconcileAdditionalLoadBalancers(ctx)).To(Succeed())
+		Expect(service.DeleteAdditionalLoadBalancers(ctx)).To(Succeed())
+
+		Expect(listAdditionalLoadBalancers()).To(BeEmpty())
+		Expect(hetznerCluster.Status.AdditionalLoadBalancers).To(BeNil())
+	})
+
+	It("does not take additional load balancers for the control plane load balancer", func() {
+		Expect(service.ReconcileAdditionalLoadBalancers(ctx)).To(Succeed())
+
+		lb, err := service.findLoadBalancer(ctx)
+		Expect(err).To(BeNil())
+		Expect(lb).To(BeNil())
+	})
+
+	It("reports a failure in the conditions", func() {
+		// a load balancer of another owner blocks the name
+		_, err := hcloudClient.CreateLoadBalancer(ctx, hcloud.LoadBalancerCreateOpts{
+			Name:      "hetzner-cluster-ingress",
+			Algorithm: &hcloud.LoadBalancerAlgorithm{Type: hcloud.LoadBalancerAlgorithmTypeRoundRobin},
+		})
+		Expect(err).To(BeNil())
+
+		Expect(service.ReconcileAdditionalLoadBalancers(ctx)).ToNot(Succeed())
+
+		Expect(hetznerCluster.Status.AdditionalLoadBalancers).To(HaveLen(1))
+		Expect(hetznerCluster.Status.AdditionalLoadBalancers[0].Conditions[0].Reason).To(Equal(infrav1.LoadBalancerCreateFailedReason))
+		Expect(conditions.GetReason(hetznerCluster, infrav1.AdditionalLoadBalancersReadyCondition)).To(Equal(infrav1.AdditionalLoadBalancersNotReadyReason))
+		Expect(*conditions.GetSeverity(hetznerCluster, infrav1.AdditionalLoadBalancersReadyCondition)).To(Equal(clusterv1.ConditionSeverityWarning))
+	})
+})
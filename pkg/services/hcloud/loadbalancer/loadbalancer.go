@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -116,6 +117,18 @@ func (s *Service) Reconcile(ctx context.Context) (reconcile.Result, error) {
 		return reconcile.Result{}, fmt.Errorf("failed to reconcile services: %w", err)
 	}
 
+	if err := s.reconcileLabelSelectorTarget(ctx, lb); err != nil {
+		conditions.MarkFalse(
+			s.scope.HetznerCluster,
+			infrav1.LoadBalancerReadyCondition,
+			infrav1.LoadBalancerTargetSyncFailedReason,
+			clusterv1.ConditionSeverityWarning,
+			"%s",
+			err.Error(),
+		)
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile label selector target: %w", err)
+	}
+
 	conditions.MarkTrue(s.scope.HetznerCluster, infrav1.LoadBalancerReadyCondition)
 	return reconcile.Result{}, nil
 }
@@ -218,34 +231,44 @@ func (s *Service) reconcileLBProperties(ctx context.Context, lb *hcloud.LoadBala
 }
 
 func (s *Service) reconcileServices(ctx context.Context, lb *hcloud.LoadBalancer) error {
-	extraServicesSpec := s.scope.HetznerCluster.Spec.ControlPlaneLoadBalancer.ExtraServices
+	return s.syncServices(ctx, lb, s.controlPlaneServices())
+}
+
+// controlPlaneServices returns the services of the control plane load balancer as given by the spec.
+func (s *Service) controlPlaneServices() []infrav1.LoadBalancerServiceSpec {
+	wantServices := slices.Clone(s.scope.HetznerCluster.Spec.ControlPlaneLoadBalancer.ExtraServices)
+
+	// add kubeAPI service if exists
+	if s.scope.HetznerCluster.Spec.ControlPlaneEndpoint != nil && s.scope.HetznerCluster.Spec.ControlPlaneEndpoint.Port != 0 {
+		wantServices = append(wantServices, infrav1.LoadBalancerServiceSpec{
+			Protocol:        "tcp",
+			ListenPort:      int(s.scope.HetznerCluster.Spec.ControlPlaneEndpoint.Port),
+			DestinationPort: s.scope.HetznerCluster.Spec.ControlPlaneLoadBalancer.Port,
+			HealthCheck:     s.scope.HetznerCluster.Spec.ControlPlaneLoadBalancer.HealthCheck,
+		})
+	}
+
+	return wantServices
+}
 
+// syncServices creates, updates and deletes the services of the load balancer to match the wanted services.
+func (s *Service) syncServices(ctx context.Context, lb *hcloud.LoadBalancer, wantServices []infrav1.LoadBalancerServiceSpec) error {
 	// build slices and maps to make diffs
 	haveServiceListenPorts := make([]int, 0, len(lb.Services))
-	wantServiceListenPorts := make([]int, 0, len(extraServicesSpec)+1)
-	wantServiceListenPortsMap := make(map[int]infrav1.LoadBalancerServiceSpec, len(extraServicesSpec)+1)
+	haveServiceListenPortsMap := make(map[int]hcloud.LoadBalancerService, len(lb.Services))
+	wantServiceListenPorts := make([]int, 0, len(wantServices))
+	wantServiceListenPortsMap := make(map[int]infrav1.LoadBalancerServiceSpec, len(wantServices))
 
-	// filter kubeAPI service out
 	for _, service := range lb.Services {
 		haveServiceListenPorts = append(haveServiceListenPorts, service.ListenPort)
+		haveServiceListenPortsMap[service.ListenPort] = service
 	}
 
-	for _, serviceInSpec := range extraServicesSpec {
+	for _, serviceInSpec := range wantServices {
 		wantServiceListenPorts = append(wantServiceListenPorts, serviceInSpec.ListenPort)
 		wantServiceListenPortsMap[serviceInSpec.ListenPort] = serviceInSpec
 	}
 
-	// add kubeAPI service if exists
-	if s.scope.HetznerCluster.Spec.ControlPlaneEndpoint != nil && s.scope.HetznerCluster.Spec.ControlPlaneEndpoint.Port != 0 {
-		kubeAPIServicePort := int(s.scope.HetznerCluster.Spec.ControlPlaneEndpoint.Port)
-		wantServiceListenPorts = append(wantServiceListenPorts, kubeAPIServicePort)
-		wantServiceListenPortsMap[kubeAPIServicePort] = infrav1.LoadBalancerServiceSpec{
-			Protocol:        "tcp",
-			ListenPort:      kubeAPIServicePort,
-			DestinationPort: s.scope.HetznerCluster.Spec.ControlPlaneLoadBalancer.Port,
-		}
-	}
-
 	toCreate, toDelete := utils.DifferenceOfIntSlices(wantServiceListenPorts, haveServiceListenPorts)
 
 	// delete services which are registered for lb but are not in specs
@@ -265,15 +288,8 @@ func (s *Service) reconcileServices(ctx context.Context, lb *hcloud.LoadBalancer
 	}
 
 	// create services which are in specs and not yet in API
-	for i, listenPort := range toCreate {
-		proxyProtocol := false
-		destinationPort := wantServiceListenPortsMap[listenPort].DestinationPort
-		serviceOpts := hcloud.LoadBalancerAddServiceOpts{
-			Protocol:        hcloud.LoadBalancerServiceProtocol(wantServiceListenPortsMap[listenPort].Protocol),
-			ListenPort:      &toCreate[i],
-			DestinationPort: &destinationPort,
-			Proxyprotocol:   &proxyProtocol,
-		}
+	for _, listenPort := range toCreate {
+		serviceOpts := addServiceOpts(wantServiceListenPortsMap[listenPort])
 		if err := s.scope.HCloudClient.AddServiceToLoadBalancer(ctx, lb, serviceOpts); err != nil {
 			// return immediately on rate limit
 			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "AddServiceToLoadBalancer")
@@ -283,9 +299,156 @@ func (s *Service) reconcileServices(ctx context.Context, lb *hcloud.LoadBalancer
 			}
 		}
 	}
+
+	// update services which are in specs and in API, but have different settings
+	for _, listenPort := range wantServiceListenPorts {
+		haveService, ok := haveServiceListenPortsMap[listenPort]
+		if !ok || serviceMatchesSpec(haveService, wantServiceListenPortsMap[listenPort]) {
+			continue
+		}
+
+		serviceOpts := updateServiceOpts(wantServiceListenPortsMap[listenPort])
+		if err := s.scope.HCloudClient.UpdateServiceOfLoadBalancer(ctx, lb, listenPort, serviceOpts); err != nil {
+			// return immediately on rate limit
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "UpdateServiceOfLoadBalancer")
+			multierr = errors.Join(multierr, fmt.Errorf("failed to update service with listen port %d of load balancer: %w", listenPort, err))
+			if hcloud.IsError(err, hcloud.ErrorCodeRateLimitExceeded) {
+				return multierr
+			}
+			continue
+		}
+		record.Eventf(s.scope.HetznerCluster, "UpdateLoadBalancerService", "Updated service with listen port %d of load balancer", listenPort)
+	}
 	return multierr
 }
 
+// reconcileLabelSelectorTarget adds a label selector target for the control plane servers if it is enabled.
+// The server targets that it replaces are removed once HCloud has resolved their servers as targets of the label
+// selector, which can take until the next reconcile. If it is disabled, the servers of the label selector target are
+// added as targets one by one before the label selector target is removed. The load balancer keeps its targets
+// during both transitions.
+func (s *Service) reconcileLabelSelectorTarget(ctx context.Context, lb *hcloud.LoadBalancer) error {
+	selector := controlPlaneLabelSelector(s.scope.HetznerCluster)
+
+	var labelSelectorTarget *hcloud.LoadBalancerTarget
+	var serverTargets []*hcloud.Server
+	for _, target := range lb.Targets {
+		switch target.Type {
+		case hcloud.LoadBalancerTargetTypeLabelSelector:
+			if target.LabelSelector != nil && target.LabelSelector.Selector == selector {
+				labelSelectorTarget = &target
+			}
+		case hcloud.LoadBalancerTargetTypeServer:
+			if target.Server != nil && target.Server.Server != nil {
+				serverTargets = append(serverTargets, target.Server.Server)
+			}
+		}
+	}
+
+	if !s.scope.HetznerCluster.Spec.ControlPlaneLoadBalancer.UseLabelSelectorTarget {
+		if labelSelectorTarget == nil {
+			return nil
+		}
+		if err := s.addServerTargetsOfLabelSelector(ctx, lb, labelSelectorTarget, serverTargets); err != nil {
+			return err
+		}
+		if err := s.scope.HCloudClient.DeleteLabelSelectorTargetOfLoadBalancer(ctx, lb, selector); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteLabelSelectorTargetOfLoadBalancer")
+			return fmt.Errorf("failed to delete label selector target %q: %w", selector, err)
+		}
+		record.Eventf(s.scope.HetznerCluster, "DeletedLabelSelectorTarget", "Deleted label selector target %q of load balancer", selector)
+		return s.updateTargetStatus(ctx, lb)
+	}
+
+	if labelSelectorTarget == nil {
+		usePrivateIP := s.scope.HetznerCluster.Status.Network != nil && len(lb.PrivateNet) > 0
+		opts := hcloud.LoadBalancerAddLabelSelectorTargetOpts{
+			Selector:     selector,
+			UsePrivateIP: &usePrivateIP,
+		}
+		if err := s.scope.HCloudClient.AddLabelSelectorTargetToLoadBalancer(ctx, opts, lb); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "AddLabelSelectorTargetToLoadBalancer")
+			if !hcloud.IsError(err, hcloud.ErrorCodeTargetAlreadyDefined) {
+				return fmt.Errorf("failed to add label selector target %q: %w", selector, err)
+			}
+		}
+		record.Eventf(s.scope.HetznerCluster, "AddedLabelSelectorTarget", "Added label selector target %q to load balancer", selector)
+		return s.updateTargetStatus(ctx, lb)
+	}
+
+	// the label selector target replaces the targets of single servers that it selects
+	var changed bool
+	for _, server := range serverTargets {
+		if !labelSelectorTargetSelects(labelSelectorTarget, server) {
+			continue
+		}
+		if err := s.scope.HCloudClient.DeleteTargetServerOfLoadBalancer(ctx, lb, server); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteTargetServerOfLoadBalancer")
+			return fmt.Errorf("failed to delete server target with ID %d: %w", server.ID, err)
+		}
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return s.updateTargetStatus(ctx, lb)
+}
+
+// labelSelectorTargetSelects returns whether HCloud has resolved the server as target of the label selector target.
+func labelSelectorTargetSelects(labelSelectorTarget *hcloud.LoadBalancerTarget, server *hcloud.Server) bool {
+	return slices.ContainsFunc(labelSelectorTarget.Targets, func(t hcloud.LoadBalancerTarget) bool {
+		return t.Server != nil && t.Server.Server != nil && t.Server.Server.ID == server.ID
+	})
+}
+
+// addServerTargetsOfLabelSelector adds the servers that the label selector target selects as single targets, so
+// that the label selector target can be removed without leaving the load balancer without targets.
+func (s *Service) addServerTargetsOfLabelSelector(ctx context.Context, lb *hcloud.LoadBalancer, labelSelectorTarget *hcloud.LoadBalancerTarget, serverTargets []*hcloud.Server) error {
+	for _, target := range labelSelectorTarget.Targets {
+		if target.Server == nil || target.Server.Server == nil {
+			continue
+		}
+		server := target.Server.Server
+		if slices.ContainsFunc(serverTargets, func(t *hcloud.Server) bool { return t.ID == server.ID }) {
+			continue
+		}
+
+		opts := hcloud.LoadBalancerAddServerTargetOpts{
+			Server:       server,
+			UsePrivateIP: &labelSelectorTarget.UsePrivateIP,
+		}
+		if err := s.scope.HCloudClient.AddTargetServerToLoadBalancer(ctx, opts, lb); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "AddTargetServerToLoadBalancer")
+			if !hcloud.IsError(err, hcloud.ErrorCodeTargetAlreadyDefined) && !hcloud.IsError(err, hcloud.ErrorCodeServerAlreadyAdded) {
+				return fmt.Errorf("failed to add server target with ID %d: %w", server.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// updateTargetStatus updates the targets of the load balancer in the status.
+func (s *Service) updateTargetStatus(ctx context.Context, lb *hcloud.LoadBalancer) error {
+	loadBalancers, err := s.scope.HCloudClient.ListLoadBalancers(ctx, hcloud.LoadBalancerListOpts{Name: lb.Name})
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ListLoadBalancers")
+		return fmt.Errorf("failed to list load balancers: %w", err)
+	}
+	if len(loadBalancers) == 1 {
+		s.scope.HetznerCluster.Status.ControlPlaneLoadBalancer = statusFromHCloudLB(loadBalancers[0], s.scope.HetznerCluster.Status.Network != nil, s.scope.Logger)
+	}
+	return nil
+}
+
+// controlPlaneLabelSelector selects the control plane servers of the cluster.
+func controlPlaneLabelSelector(hc *infrav1.HetznerCluster) string {
+	return utils.LabelsToLabelSelector(map[string]string{
+		hc.ClusterTagKey():        string(infrav1.ResourceLifecycleOwned),
+		infrav1.MachineTypeTagKey: infrav1.MachineTypeControlPlane,
+	})
+}
+
 func (s *Service) createLoadBalancer(ctx context.Context) (*hcloud.LoadBalancer, error) {
 	opts := createOptsFromSpec(s.scope.HetznerCluster)
 	lb, err := s.scope.HCloudClient.CreateLoadBalancer(ctx, opts)
@@ -351,6 +514,11 @@ func (s *Service) Delete(ctx context.Context) (err error) {
 		return nil
 	}
 
+	// delete the load balancers of an unfinished migration
+	if err := s.deleteMigrationLoadBalancers(ctx); err != nil {
+		return err
+	}
+
 	// do not delete a protected load balancer or one that has not been created by this controller
 	if s.scope.HetznerCluster.Status.ControlPlaneLoadBalancer.Protected || s.scope.HetznerCluster.Spec.ControlPlaneLoadBalancer.Name != nil {
 		lb, err := s.findLoadBalancer(ctx)
@@ -428,6 +596,20 @@ func (s *Service) findLoadBalancer(ctx context.Context) (*hcloud.LoadBalancer, e
 		return nil, fmt.Errorf("failed to list load balancers: %w", err)
 	}
 
+	// additional load balancers are owned by the cluster as well, and so is the old load balancer
+	// that is drained after a migration
+	loadBalancers = slices.DeleteFunc(loadBalancers, func(lb *hcloud.LoadBalancer) bool {
+		_, ok := lb.Labels[infrav1.LoadBalancerNameTagKey]
+		return ok || lb.Labels[infrav1.LoadBalancerMigrationTagKey] == infrav1.LoadBalancerMigrationDraining
+	})
+
+	// the new load balancer of a migration only takes over once the old one is drained
+	if len(loadBalancers) > 1 {
+		loadBalancers = slices.DeleteFunc(loadBalancers, func(lb *hcloud.LoadBalancer) bool {
+			return lb.Labels[infrav1.LoadBalancerMigrationTagKey] == infrav1.LoadBalancerMigrationTarget
+		})
+	}
+
 	if len(loadBalancers) > 1 {
 		return nil, fmt.Errorf("found %v loadbalancers in HCloud", len(loadBalancers))
 	} else if len(loadBalancers) == 0 {
@@ -525,6 +707,12 @@ func statusFromHCloudLB(lb *hcloud.LoadBalancer, hasNetwork bool, log logr.Logge
 				IP:   target.IP.IP,
 			},
 			)
+		case hcloud.LoadBalancerTargetTypeLabelSelector:
+			targetObjects = append(targetObjects, infrav1.LoadBalancerTarget{
+				Type:          infrav1.LoadBalancerTargetTypeLabelSelector,
+				LabelSelector: target.LabelSelector.Selector,
+			},
+			)
 		default:
 			log.Info("Unknown load balancer target type - will be ignored", "target type", target.Type)
 		}
@@ -17,6 +17,8 @@ limitations under the License.
 package loadbalancer
 
 import (
+	"context"
+
 	"github.com/go-logr/logr"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 	. "github.com/onsi/ginkgo/v2"
@@ -24,6 +26,9 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
 )
 
 var _ = Describe("Loadbalancer", func() {
@@ -153,3 +158,98 @@ var _ = Describe("createOptsFromSpec", func() {
 		Expect(createOpts).To(Equal(wantCreateOpts))
 	})
 })
+
+var _ = Describe("reconcileLabelSelectorTarget", func() {
+	var (
+		ctx            context.Context
+		hcloudClient   hcloudclient.Client
+		hetznerCluster *infrav1.HetznerCluster
+		service        *Service
+		loadBalancer   *hcloud.LoadBalancer
+	)
+
+	const selector = "caph-cluster-hetzner-cluster==owned,machine_type==control_plane"
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		hcloudClient = fakeclient.NewHCloudClientFactory().NewClient("")
+		hcloudClient.Reset()
+
+		hetznerCluster = &infrav1.HetznerCluster{}
+		hetznerCluster.Name = "hetzner-cluster"
+		hetznerCluster.Spec.ControlPlaneLoadBalancer.UseLabelSelectorTarget = true
+
+		var err error
+		loadBalancer, err = hcloudClient.CreateLoadBalancer(ctx, hcloud.LoadBalancerCreateOpts{
+			Name:      "lb",
+			Algorithm: &hcloud.LoadBalancerAlgorithm{Type: hcloud.LoadBalancerAlgorithmTypeRoundRobin},
+		})
+		Expect(err).To(BeNil())
+
+		Expect(hcloudClient.AddTargetServerToLoadBalancer(ctx, hcloud.LoadBalancerAddServerTargetOpts{
+			Server: &hcloud.Server{ID: 1},
+		}, loadBalancer)).To(Succeed())
+
+		service = NewService(&scope.ClusterScope{HCloudClient: hcloudClient, HetznerCluster: hetznerCluster, Logger: logr.Discard()})
+	})
+
+	It("selects the control plane servers of the cluster", func() {
+		Expect(controlPlaneLabelSelector(hetznerCluster)).To(Equal(selector))
+	})
+
+	// resolveLabelSelectorTarget lets the label selector target select the servers, as HCloud does
+	resolveLabelSelectorTarget := func(serverIDs ...int64) {
+		for i, target := range loadBalancer.Targets {
+			if target.Type != hcloud.LoadBalancerTargetTypeLabelSelector {
+				continue
+			}
+			loadBalancer.Targets[i].Targets = nil
+			for _, id := range serverIDs {
+				loadBalancer.Targets[i].Targets = append(loadBalancer.Targets[i].Targets, hcloud.LoadBalancerTarget{
+					Type:   hcloud.LoadBalancerTargetTypeServer,
+					Server: &hcloud.LoadBalancerTargetServer{Server: &hcloud.Server{ID: id}},
+				})
+			}
+		}
+	}
+
+	It("replaces server targets with the label selector target", func() {
+		Expect(service.reconcileLabelSelectorTarget(ctx, loadBalancer)).To(Succeed())
+
+		// the server target is kept until the label selector target selects the server
+		Expect(loadBalancer.Targets).To(HaveLen(2))
+		Expect(service.reconcileLabelSelectorTarget(ctx, loadBalancer)).To(Succeed())
+		Expect(loadBalancer.Targets).To(HaveLen(2))
+
+		resolveLabelSelectorTarget(1)
+		Expect(service.reconcileLabelSelectorTarget(ctx, loadBalancer)).To(Succeed())
+
+		Expect(loadBalancer.Targets).To(HaveLen(1))
+		Expect(loadBalancer.Targets[0].Type).To(Equal(hcloud.LoadBalancerTargetTypeLabelSelector))
+		Expect(loadBalancer.Targets[0].LabelSelector.Selector).To(Equal(selector))
+
+		Expect(hetznerCluster.Status.ControlPlaneLoadBalancer.Target).To(Equal([]infrav1.LoadBalancerTarget{
+			{Type: infrav1.LoadBalancerTargetTypeLabelSelector, LabelSelector: selector},
+		}))
+
+		// nothing changes when reconciling again
+		Expect(service.reconcileLabelSelectorTarget(ctx, loadBalancer)).To(Succeed())
+		Expect(loadBalancer.Targets).To(HaveLen(1))
+	})
+
+	It("adds the servers of the label selector target before removing it if it is disabled", func() {
+		Expect(service.reconcileLabelSelectorTarget(ctx, loadBalancer)).To(Succeed())
+		resolveLabelSelectorTarget(1, 2)
+		Expect(service.reconcileLabelSelectorTarget(ctx, loadBalancer)).To(Succeed())
+
+		hetznerCluster.Spec.ControlPlaneLoadBalancer.UseLabelSelectorTarget = false
+		Expect(service.reconcileLabelSelectorTarget(ctx, loadBalancer)).To(Succeed())
+
+		var serverIDs []int64
+		for _, target := range loadBalancer.Targets {
+			Expect(target.Type).To(Equal(hcloud.LoadBalancerTargetTypeServer))
+			serverIDs = append(serverIDs, target.Server.Server.ID)
+		}
+		Expect(serverIDs).To(ConsistOf(int64(1), int64(2)))
+	})
+})
@@ -0,0 +1,545 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	hcloudutil "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/util"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+const (
+	// migrationHealthCheckRequeueAfter is the interval in which the health of the targets of a new load balancer is checked.
+	migrationHealthCheckRequeueAfter = 30 * time.Second
+
+	// migrationDrainTimeout is the time that the old load balancer keeps serving clients after the new one took over.
+	migrationDrainTimeout = 5 * time.Minute
+)
+
+// lookupHost resolves the control plane endpoint before the old load balancer is deleted.
+var lookupHost = net.DefaultResolver.LookupHost
+
+// ReconcileMigration migrates the control plane load balancer if it is in another region than specified or
+// attached to another network than the one of the cluster. The migration has to be approved with an annotation,
+// and it is refused if the control plane endpoint is the IP of the load balancer. A new load balancer is created
+// next to the old one and gets the same services and targets. Once all of its targets are healthy, it takes over,
+// and the old load balancer is deleted after it has been drained and the control plane endpoint does not resolve
+// to it anymore.
+//
+// The control plane endpoint is never switched to the new load balancer. HCloud cannot move the IP of a load
+// balancer, and the Cluster, the certificates of the API server and the kubeconfigs keep the endpoint that has been
+// set when the cluster was created.
+//
+// The state of the migration is kept in the labels of the load balancers, so that it survives restarts of
+// the controller.
+func (s *Service) ReconcileMigration(ctx context.Context) (res reconcile.Result, err error) {
+	hc := s.scope.HetznerCluster
+
+	// load balancers that have not been created by the controller are not migrated
+	if !hc.Spec.ControlPlaneLoadBalancer.Enabled || hc.Spec.ControlPlaneLoadBalancer.Name != nil {
+		return reconcile.Result{}, nil
+	}
+
+	defer func() {
+		if err != nil {
+			conditions.MarkFalse(
+				hc,
+				infrav1.LoadBalancerMigratedCondition,
+				infrav1.LoadBalancerMigrationFailedReason,
+				clusterv1.ConditionSeverityWarning,
+				"%s",
+				err.Error(),
+			)
+		}
+	}()
+
+	lb, err := s.findLoadBalancer(ctx)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to find load balancer: %w", err)
+	}
+
+	// the load balancer is created in the regular reconcile loop
+	if lb == nil {
+		return reconcile.Result{}, nil
+	}
+
+	target, draining, err := s.findMigrationLoadBalancers(ctx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// the new load balancer has taken over already
+	if draining != nil {
+		return s.drainLoadBalancer(ctx, lb, draining)
+	}
+	if lb.Labels[infrav1.LoadBalancerMigrationTagKey] == infrav1.LoadBalancerMigrationTarget {
+		return reconcile.Result{}, s.finishMigration(ctx, lb)
+	}
+
+	if !s.needsMigration(lb) {
+		// the spec has been reverted during the migration
+		if target != nil {
+			if err := s.deleteLoadBalancer(ctx, target); err != nil {
+				return reconcile.Result{}, err
+			}
+			conditions.Delete(hc, infrav1.LoadBalancerMigratedCondition)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if lb.Protection.Delete {
+		return reconcile.Result{}, fmt.Errorf("cannot migrate load balancer %s: it is protected against deletion", lb.Name)
+	}
+
+	if !s.migrationAllowed(lb) {
+		return reconcile.Result{}, nil
+	}
+
+	// the spec has changed again during the migration
+	if target != nil && s.needsMigration(target) {
+		if err := s.deleteLoadBalancer(ctx, target); err != nil {
+			return reconcile.Result{}, err
+		}
+		target = nil
+	}
+
+	if target == nil {
+		target, err = s.createMigrationLoadBalancer(ctx)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if err := s.syncServices(ctx, target, s.controlPlaneServices()); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to sync services of load balancer %s: %w", target.Name, err)
+	}
+
+	changed, err := s.syncMigrationTargets(ctx, lb, target)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to sync targets of load balancer %s: %w", target.Name, err)
+	}
+
+	// the health of changed targets is only known in the next loop
+	if changed || !targetsHealthy(target) {
+		conditions.MarkFalse(
+			hc,
+			infrav1.LoadBalancerMigratedCondition,
+			infrav1.LoadBalancerMigrationWaitingForHealthChecksReason,
+			clusterv1.ConditionSeverityInfo,
+			"waiting for healthy targets of new load balancer %s",
+			target.Name,
+		)
+		return reconcile.Result{RequeueAfter: migrationHealthCheckRequeueAfter}, nil
+	}
+
+	// the new load balancer takes over
+	if err := s.setMigrationLabel(ctx, lb, infrav1.LoadBalancerMigrationDraining); err != nil {
+		return reconcile.Result{}, err
+	}
+	record.Eventf(hc, "LoadBalancerMigrationSwitched", "Load balancer %s took over from %s", target.Name, lb.Name)
+
+	return s.drainLoadBalancer(ctx, target, lb)
+}
+
+// migrationAllowed checks whether the load balancer may be migrated and reports the reason in the condition if not.
+// Kubeconfigs, kubelets and the certificates of the API server use the control plane endpoint that has been set
+// when the cluster was created. If it is the IP of the load balancer, they would lose the API server.
+func (s *Service) migrationAllowed(lb *hcloud.LoadBalancer) bool {
+	hc := s.scope.HetznerCluster
+
+	if endpoint := hc.Spec.ControlPlaneEndpoint; endpoint != nil && isLoadBalancerIP(lb, endpoint.Host) {
+		conditions.MarkFalse(
+			hc,
+			infrav1.LoadBalancerMigratedCondition,
+			infrav1.LoadBalancerMigrationBlockedReason,
+			clusterv1.ConditionSeverityError,
+			"control plane endpoint %s is the IP of load balancer %s. It can only be migrated if the control plane endpoint is a DNS name",
+			endpoint.Host,
+			lb.Name,
+		)
+		return false
+	}
+
+	if _, ok := hc.Annotations[infrav1.LoadBalancerMigrationApprovedAnnotation]; !ok {
+		conditions.MarkFalse(
+			hc,
+			infrav1.LoadBalancerMigratedCondition,
+			infrav1.LoadBalancerMigrationNotApprovedReason,
+			clusterv1.ConditionSeverityWarning,
+			"load balancer %s has to be migrated %s. Set the annotation %s to approve the migration",
+			lb.Name,
+			s.migrationReason(lb),
+			infrav1.LoadBalancerMigrationApprovedAnnotation,
+		)
+		return false
+	}
+
+	return true
+}
+
+// needsMigration checks whether the load balancer is in another region or attached to another network than specified.
+func (s *Service) needsMigration(lb *hcloud.LoadBalancer) bool {
+	return s.migrationReason(lb) != ""
+}
+
+// migrationReason describes where the load balancer has to be migrated to. It is empty if the load balancer matches
+// the spec. A load balancer that is not attached to any network is attached in place by the regular reconcile loop.
+func (s *Service) migrationReason(lb *hcloud.LoadBalancer) string {
+	hc := s.scope.HetznerCluster
+
+	if region := string(hc.Spec.ControlPlaneLoadBalancer.Region); region != "" && lb.Location != nil && lb.Location.Name != region {
+		return fmt.Sprintf("to region %s", region)
+	}
+
+	if network := hc.Status.Network; network != nil && len(lb.PrivateNet) > 0 &&
+		!slices.ContainsFunc(lb.PrivateNet, func(privateNet hcloud.LoadBalancerPrivateNet) bool {
+			return privateNet.Network != nil && privateNet.Network.ID == network.ID
+		}) {
+		return fmt.Sprintf("to network %d", network.ID)
+	}
+
+	return ""
+}
+
+func (s *Service) createMigrationLoadBalancer(ctx context.Context) (*hcloud.LoadBalancer, error) {
+	opts := createOptsFromSpec(s.scope.HetznerCluster)
+	opts.Labels[infrav1.LoadBalancerMigrationTagKey] = infrav1.LoadBalancerMigrationTarget
+
+	lb, err := s.scope.HCloudClient.CreateLoadBalancer(ctx, opts)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "CreateLoadBalancer")
+		err = fmt.Errorf("failed to create load balancer: %w", err)
+		record.Warnf(s.scope.HetznerCluster, "FailedCreateLoadBalancer", err.Error())
+		return nil, err
+	}
+
+	record.Eventf(s.scope.HetznerCluster, "CreateLoadBalancer", "Created load balancer %s to migrate the control plane load balancer", lb.Name)
+	return lb, nil
+}
+
+// syncMigrationTargets gives the new load balancer the same targets as the current one.
+// It returns whether targets have been added or deleted.
+func (s *Service) syncMigrationTargets(ctx context.Context, lb, target *hcloud.LoadBalancer) (changed bool, err error) {
+	usePrivateIP := len(target.PrivateNet) > 0
+	have := make(map[string]hcloud.LoadBalancerTarget, len(target.Targets))
+	for _, t := range target.Targets {
+		have[targetKey(t)] = t
+	}
+	want := make(map[string]hcloud.LoadBalancerTarget, len(lb.Targets))
+	for _, t := range lb.Targets {
+		want[targetKey(t)] = t
+	}
+
+	for key, t := range want {
+		if _, ok := have[key]; ok {
+			continue
+		}
+		if addErr := s.addTarget(ctx, target, t, usePrivateIP); addErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to add target %s: %w", key, addErr))
+			continue
+		}
+		changed = true
+	}
+
+	for key, t := range have {
+		if _, ok := want[key]; ok {
+			continue
+		}
+		if deleteErr := s.deleteTarget(ctx, target, t); deleteErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to delete target %s: %w", key, deleteErr))
+			continue
+		}
+		changed = true
+	}
+
+	return changed, err
+}
+
+func (s *Service) addTarget(ctx context.Context, lb *hcloud.LoadBalancer, t hcloud.LoadBalancerTarget, usePrivateIP bool) (err error) {
+	switch t.Type {
+	case hcloud.LoadBalancerTargetTypeServer:
+		opts := hcloud.LoadBalancerAddServerTargetOpts{Server: t.Server.Server, UsePrivateIP: &usePrivateIP}
+		err = s.scope.HCloudClient.AddTargetServerToLoadBalancer(ctx, opts, lb)
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "AddTargetServerToLoadBalancer")
+	case hcloud.LoadBalancerTargetTypeIP:
+		opts := hcloud.LoadBalancerAddIPTargetOpts{IP: net.ParseIP(t.IP.IP)}
+		err = s.scope.HCloudClient.AddIPTargetToLoadBalancer(ctx, opts, lb)
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "AddIPTargetToLoadBalancer")
+	case hcloud.LoadBalancerTargetTypeLabelSelector:
+		opts := hcloud.LoadBalancerAddLabelSelectorTargetOpts{Selector: t.LabelSelector.Selector, UsePrivateIP: &usePrivateIP}
+		err = s.scope.HCloudClient.AddLabelSelectorTargetToLoadBalancer(ctx, opts, lb)
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "AddLabelSelectorTargetToLoadBalancer")
+	}
+	return err
+}
+
+func (s *Service) deleteTarget(ctx context.Context, lb *hcloud.LoadBalancer, t hcloud.LoadBalancerTarget) (err error) {
+	switch t.Type {
+	case hcloud.LoadBalancerTargetTypeServer:
+		err = s.scope.HCloudClient.DeleteTargetServerOfLoadBalancer(ctx, lb, t.Server.Server)
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteTargetServerOfLoadBalancer")
+	case hcloud.LoadBalancerTargetTypeIP:
+		err = s.scope.HCloudClient.DeleteIPTargetOfLoadBalancer(ctx, lb, net.ParseIP(t.IP.IP))
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteIPTargetOfLoadBalancer")
+	case hcloud.LoadBalancerTargetTypeLabelSelector:
+		err = s.scope.HCloudClient.DeleteLabelSelectorTargetOfLoadBalancer(ctx, lb, t.LabelSelector.Selector)
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteLabelSelectorTargetOfLoadBalancer")
+	}
+	return err
+}
+
+// targetKey identifies a target of a load balancer independent of the load balancer.
+func targetKey(t hcloud.LoadBalancerTarget) string {
+	switch t.Type {
+	case hcloud.LoadBalancerTargetTypeServer:
+		if t.Server != nil && t.Server.Server != nil {
+			return fmt.Sprintf("server/%d", t.Server.Server.ID)
+		}
+	case hcloud.LoadBalancerTargetTypeIP:
+		if t.IP != nil {
+			return "ip/" + t.IP.IP
+		}
+	case hcloud.LoadBalancerTargetTypeLabelSelector:
+		if t.LabelSelector != nil {
+			return "label_selector/" + t.LabelSelector.Selector
+		}
+	}
+	return string(t.Type)
+}
+
+// targetsHealthy checks whether all targets of the load balancer pass the health checks of all services.
+// The servers that are selected by label selector targets are checked one by one.
+func targetsHealthy(lb *hcloud.LoadBalancer) bool {
+	for _, target := range lb.Targets {
+		targets := []hcloud.LoadBalancerTarget{target}
+		if target.Type == hcloud.LoadBalancerTargetTypeLabelSelector {
+			targets = target.Targets
+		}
+
+		for _, t := range targets {
+			if len(t.HealthStatus) < len(lb.Services) {
+				return false
+			}
+			for _, healthStatus := range t.HealthStatus {
+				if healthStatus.Status != hcloud.LoadBalancerTargetHealthStatusStatusHealthy {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// drainLoadBalancer deletes the old load balancer once it has been drained and the control plane endpoint
+// does not resolve to it anymore. The control plane endpoint itself is not changed.
+func (s *Service) drainLoadBalancer(ctx context.Context, lb, draining *hcloud.LoadBalancer) (reconcile.Result, error) {
+	hc := s.scope.HetznerCluster
+
+	condition := conditions.Get(hc, infrav1.LoadBalancerMigratedCondition)
+	if condition == nil || (condition.Reason != infrav1.LoadBalancerMigrationDrainingReason &&
+		condition.Reason != infrav1.LoadBalancerMigrationWaitingForDNSReason) {
+		conditions.MarkFalse(
+			hc,
+			infrav1.LoadBalancerMigratedCondition,
+			infrav1.LoadBalancerMigrationDrainingReason,
+			clusterv1.ConditionSeverityInfo,
+			"draining old load balancer %s",
+			draining.Name,
+		)
+		return reconcile.Result{RequeueAfter: migrationDrainTimeout}, nil
+	}
+
+	if condition.Reason == infrav1.LoadBalancerMigrationDrainingReason {
+		if remaining := time.Until(condition.LastTransitionTime.Add(migrationDrainTimeout)); remaining > 0 {
+			return reconcile.Result{RequeueAfter: remaining}, nil
+		}
+	}
+
+	if endpoint := hc.Spec.ControlPlaneEndpoint; endpoint != nil && endpoint.Host != "" {
+		resolves, err := resolvesToLoadBalancer(ctx, endpoint.Host, draining)
+		if err != nil || resolves {
+			message := fmt.Sprintf("control plane endpoint %s still resolves to old load balancer %s", endpoint.Host, draining.Name)
+			if err != nil {
+				message = fmt.Sprintf("failed to resolve control plane endpoint %s: %s", endpoint.Host, err)
+			}
+			conditions.MarkFalse(
+				hc,
+				infrav1.LoadBalancerMigratedCondition,
+				infrav1.LoadBalancerMigrationWaitingForDNSReason,
+				clusterv1.ConditionSeverityWarning,
+				"%s",
+				message,
+			)
+			return reconcile.Result{RequeueAfter: migrationHealthCheckRequeueAfter}, nil
+		}
+	}
+
+	if err := s.deleteLoadBalancer(ctx, draining); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, s.finishMigration(ctx, lb)
+}
+
+// resolvesToLoadBalancer checks whether the host is an IP of the load balancer or resolves to one.
+func resolvesToLoadBalancer(ctx context.Context, host string, lb *hcloud.LoadBalancer) (bool, error) {
+	if net.ParseIP(host) != nil {
+		return isLoadBalancerIP(lb, host), nil
+	}
+
+	addresses, err := lookupHost(ctx, host)
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(addresses, func(address string) bool {
+		return isLoadBalancerIP(lb, address)
+	}), nil
+}
+
+// isLoadBalancerIP checks whether the address is a public or private IP of the load balancer.
+func isLoadBalancerIP(lb *hcloud.LoadBalancer, address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	if lb.PublicNet.IPv4.IP.Equal(ip) || lb.PublicNet.IPv6.IP.Equal(ip) {
+		return true
+	}
+	return slices.ContainsFunc(lb.PrivateNet, func(privateNet hcloud.LoadBalancerPrivateNet) bool {
+		return privateNet.IP.Equal(ip)
+	})
+}
+
+// finishMigration removes the migration label from the new load balancer after the old one has been deleted.
+// The approval is removed as well, so that a later migration has to be approved again.
+func (s *Service) finishMigration(ctx context.Context, lb *hcloud.LoadBalancer) error {
+	if err := s.setMigrationLabel(ctx, lb, ""); err != nil {
+		return err
+	}
+	delete(s.scope.HetznerCluster.Annotations, infrav1.LoadBalancerMigrationApprovedAnnotation)
+
+	conditions.MarkTrue(s.scope.HetznerCluster, infrav1.LoadBalancerMigratedCondition)
+	record.Eventf(s.scope.HetznerCluster, "LoadBalancerMigrated", "Migrated control plane load balancer to %s", lb.Name)
+	return nil
+}
+
+// setMigrationLabel sets the migration label of the load balancer. An empty value removes the label.
+func (s *Service) setMigrationLabel(ctx context.Context, lb *hcloud.LoadBalancer, value string) error {
+	labels := maps.Clone(lb.Labels)
+	if labels == nil {
+		labels = make(map[string]string, 1)
+	}
+	if value == "" {
+		delete(labels, infrav1.LoadBalancerMigrationTagKey)
+	} else {
+		labels[infrav1.LoadBalancerMigrationTagKey] = value
+	}
+
+	if _, err := s.scope.HCloudClient.UpdateLoadBalancer(ctx, lb, hcloud.LoadBalancerUpdateOpts{Labels: labels}); err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "UpdateLoadBalancer")
+		return fmt.Errorf("failed to update labels of load balancer %s: %w", lb.Name, err)
+	}
+	lb.Labels = labels
+	return nil
+}
+
+func (s *Service) deleteLoadBalancer(ctx context.Context, lb *hcloud.LoadBalancer) error {
+	if err := s.scope.HCloudClient.DeleteLoadBalancer(ctx, lb.ID); err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteLoadBalancer")
+		if hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+			return nil
+		}
+		err = fmt.Errorf("failed to delete load balancer %s: %w", lb.Name, err)
+		record.Warnf(s.scope.HetznerCluster, "FailedLoadBalancerDelete", err.Error())
+		return err
+	}
+
+	record.Eventf(s.scope.HetznerCluster, "DeleteLoadBalancer", "Deleted load balancer %s", lb.Name)
+	return nil
+}
+
+// findMigrationLoadBalancers returns the new and the old load balancer of an ongoing migration.
+func (s *Service) findMigrationLoadBalancers(ctx context.Context) (target, draining *hcloud.LoadBalancer, err error) {
+	loadBalancers, err := s.listMigrationLoadBalancers(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, lb := range loadBalancers {
+		switch lb.Labels[infrav1.LoadBalancerMigrationTagKey] {
+		case infrav1.LoadBalancerMigrationTarget:
+			if target != nil {
+				return nil, nil, fmt.Errorf("found multiple new load balancers of a migration: %s and %s", target.Name, lb.Name)
+			}
+			target = lb
+		case infrav1.LoadBalancerMigrationDraining:
+			if draining != nil {
+				return nil, nil, fmt.Errorf("found multiple old load balancers of a migration: %s and %s", draining.Name, lb.Name)
+			}
+			draining = lb
+		}
+	}
+	return target, draining, nil
+}
+
+func (s *Service) listMigrationLoadBalancers(ctx context.Context) ([]*hcloud.LoadBalancer, error) {
+	opts := hcloud.LoadBalancerListOpts{
+		ListOpts: hcloud.ListOpts{
+			LabelSelector: utils.LabelsToLabelSelector(map[string]string{
+				s.scope.HetznerCluster.ClusterTagKey(): string(infrav1.ResourceLifecycleOwned),
+			}),
+		},
+	}
+	loadBalancers, err := s.scope.HCloudClient.ListLoadBalancers(ctx, opts)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ListLoadBalancers")
+		return nil, fmt.Errorf("failed to list load balancers: %w", err)
+	}
+
+	return slices.DeleteFunc(loadBalancers, func(lb *hcloud.LoadBalancer) bool {
+		_, ok := lb.Labels[infrav1.LoadBalancerMigrationTagKey]
+		return !ok
+	}), nil
+}
+
+// deleteMigrationLoadBalancers deletes the load balancers of an ongoing migration.
+func (s *Service) deleteMigrationLoadBalancers(ctx context.Context) error {
+	loadBalancers, err := s.listMigrationLoadBalancers(ctx)
+	if err != nil {
+		return err
+	}
+
+	var multierr error
+	for _, lb := range loadBalancers {
+		multierr = errors.Join(multierr, s.deleteLoadBalancer(ctx, lb))
+	}
+	return multierr
+}
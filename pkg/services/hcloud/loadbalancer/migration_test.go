@@ -0,0 +1,270 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+)
+
+var _ = Describe("ReconcileMigration", func() {
+	var (
+		ctx            context.Context
+		hcloudClient   hcloudclient.Client
+		hetznerCluster *infrav1.HetznerCluster
+		service        *Service
+		oldLB          *hcloud.LoadBalancer
+		resolved       []string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		hcloudClient = fakeclient.NewHCloudClientFactory().NewClient("")
+		hcloudClient.Reset()
+
+		hetznerCluster = &infrav1.HetznerCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{infrav1.LoadBalancerMigrationApprovedAnnotation: ""},
+			},
+			Spec: infrav1.HetznerClusterSpec{
+				ControlPlaneEndpoint: &clusterv1.APIEndpoint{Host: "api.example.com", Port: 6443},
+				ControlPlaneLoadBalancer: infrav1.LoadBalancerSpec{
+					Enabled:   true,
+					Algorithm: infrav1.LoadBalancerAlgorithmTypeRoundRobin,
+					Type:      "lb11",
+					Port:      6443,
+					Region:    "fsn1",
+				},
+			},
+		}
+		hetznerCluster.Name = "hetzner-cluster"
+
+		service = NewService(&scope.ClusterScope{HCloudClient: hcloudClient, HetznerCluster: hetznerCluster, Logger: logr.Discard()})
+
+		var err error
+		oldLB, err = hcloudClient.CreateLoadBalancer(ctx, createOptsFromSpec(hetznerCluster))
+		Expect(err).To(BeNil())
+		oldLB.PublicNet.IPv4.IP = net.ParseIP("1.1.1.1")
+		Expect(hcloudClient.AddTargetServerToLoadBalancer(ctx, hcloud.LoadBalancerAddServerTargetOpts{
+			Server: &hcloud.Server{ID: 1},
+		}, oldLB)).To(Succeed())
+
+		resolved = []string{"1.1.1.1"}
+		lookupHostOrig := lookupHost
+		lookupHost = func(_ context.Context, host string) ([]string, error) {
+			Expect(host).To(Equal("api.example.com"))
+			return resolved, nil
+		}
+		DeferCleanup(func() { lookupHost = lookupHostOrig })
+	})
+
+	findTarget := func() *hcloud.LoadBalancer {
+		target, _, err := service.findMigrationLoadBalancers(ctx)
+		Expect(err).To(BeNil())
+		return target
+	}
+
+	setHealthy := func(lb *hcloud.LoadBalancer) {
+		for i := range lb.Targets {
+			lb.Targets[i].HealthStatus = []hcloud.LoadBalancerTargetHealthStatus{
+				{ListenPort: 6443, Status: hcloud.LoadBalancerTargetHealthStatusStatusHealthy},
+			}
+		}
+	}
+
+	It("does nothing if the load balancer matches the spec", func() {
+		res, err := service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(res.IsZero()).To(BeTrue())
+		Expect(findTarget()).To(BeNil())
+		Expect(conditions.Has(hetznerCluster, infrav1.LoadBalancerMigratedCondition)).To(BeFalse())
+	})
+
+	It("migrates the load balancer to a new region", func() {
+		hetznerCluster.Spec.ControlPlaneLoadBalancer.Region = "nbg1"
+
+		// the new load balancer is created and waits for healthy targets
+		res, err := service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(res.RequeueAfter).To(Equal(migrationHealthCheckRequeueAfter))
+
+		target := findTarget()
+		Expect(target).ToNot(BeNil())
+		Expect(target.Location.Name).To(Equal("nbg1"))
+		Expect(target.Services).To(HaveLen(1))
+		Expect(target.Targets).To(HaveLen(1))
+		Expect(target.Targets[0].Server.Server.ID).To(Equal(int64(1)))
+		Expect(conditions.GetReason(hetznerCluster, infrav1.LoadBalancerMigratedCondition)).To(Equal(infrav1.LoadBalancerMigrationWaitingForHealthChecksReason))
+
+		// the old load balancer stays in charge until the targets are healthy
+		lb, err := service.findLoadBalancer(ctx)
+		Expect(err).To(BeNil())
+		Expect(lb.ID).To(Equal(oldLB.ID))
+
+		res, err = service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(res.RequeueAfter).To(Equal(migrationHealthCheckRequeueAfter))
+
+		// the new load balancer takes over once it is healthy
+		setHealthy(target)
+		target.PublicNet.IPv4.IP = net.ParseIP("2.2.2.2")
+
+		res, err = service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(res.RequeueAfter).To(Equal(migrationDrainTimeout))
+		Expect(oldLB.Labels[infrav1.LoadBalancerMigrationTagKey]).To(Equal(infrav1.LoadBalancerMigrationDraining))
+		Expect(hetznerCluster.Spec.ControlPlaneEndpoint.Host).To(Equal("api.example.com"))
+		Expect(conditions.GetReason(hetznerCluster, infrav1.LoadBalancerMigratedCondition)).To(Equal(infrav1.LoadBalancerMigrationDrainingReason))
+
+		lb, err = service.findLoadBalancer(ctx)
+		Expect(err).To(BeNil())
+		Expect(lb.ID).To(Equal(target.ID))
+
+		// the old load balancer is drained before it is deleted
+		res, err = service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+
+		for i := range hetznerCluster.Status.Conditions {
+			if hetznerCluster.Status.Conditions[i].Type == infrav1.LoadBalancerMigratedCondition {
+				hetznerCluster.Status.Conditions[i].LastTransitionTime = metav1.NewTime(time.Now().Add(-migrationDrainTimeout))
+			}
+		}
+
+		// the old load balancer is kept as long as the control plane endpoint resolves to it
+		res, err = service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(res.RequeueAfter).To(Equal(migrationHealthCheckRequeueAfter))
+		Expect(conditions.GetReason(hetznerCluster, infrav1.LoadBalancerMigratedCondition)).To(Equal(infrav1.LoadBalancerMigrationWaitingForDNSReason))
+		lbs, err := hcloudClient.ListLoadBalancers(ctx, hcloud.LoadBalancerListOpts{})
+		Expect(err).To(BeNil())
+		Expect(lbs).To(HaveLen(2))
+
+		resolved = []string{"2.2.2.2"}
+		res, err = service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(res.IsZero()).To(BeTrue())
+		Expect(conditions.IsTrue(hetznerCluster, infrav1.LoadBalancerMigratedCondition)).To(BeTrue())
+		Expect(hetznerCluster.Annotations).ToNot(HaveKey(infrav1.LoadBalancerMigrationApprovedAnnotation))
+
+		lbs, err = hcloudClient.ListLoadBalancers(ctx, hcloud.LoadBalancerListOpts{})
+		Expect(err).To(BeNil())
+		Expect(lbs).To(HaveLen(1))
+		Expect(lbs[0].ID).To(Equal(target.ID))
+		Expect(lbs[0].Labels).ToNot(HaveKey(infrav1.LoadBalancerMigrationTagKey))
+	})
+
+	It("migrates the load balancer to the network of the cluster", func() {
+		oldLB.PrivateNet = []hcloud.LoadBalancerPrivateNet{{Network: &hcloud.Network{ID: 1}, IP: net.ParseIP("10.0.0.2")}}
+		hetznerCluster.Status.Network = &infrav1.NetworkStatus{ID: 2}
+
+		res, err := service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(res.RequeueAfter).To(Equal(migrationHealthCheckRequeueAfter))
+
+		target := findTarget()
+		Expect(target).ToNot(BeNil())
+		Expect(target.Location.Name).To(Equal("fsn1"))
+		Expect(target.PrivateNet).To(HaveLen(1))
+		Expect(target.PrivateNet[0].Network.ID).To(Equal(int64(2)))
+		Expect(target.Targets).To(HaveLen(1))
+		Expect(conditions.GetReason(hetznerCluster, infrav1.LoadBalancerMigratedCondition)).To(Equal(infrav1.LoadBalancerMigrationWaitingForHealthChecksReason))
+	})
+
+	It("does not migrate a load balancer that is not attached to a network", func() {
+		hetznerCluster.Status.Network = &infrav1.NetworkStatus{ID: 2}
+
+		res, err := service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(res.IsZero()).To(BeTrue())
+		Expect(findTarget()).To(BeNil())
+	})
+
+	It("does not migrate the load balancer without approval", func() {
+		delete(hetznerCluster.Annotations, infrav1.LoadBalancerMigrationApprovedAnnotation)
+		hetznerCluster.Spec.ControlPlaneLoadBalancer.Region = "nbg1"
+
+		res, err := service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(res.IsZero()).To(BeTrue())
+		Expect(findTarget()).To(BeNil())
+		Expect(conditions.GetReason(hetznerCluster, infrav1.LoadBalancerMigratedCondition)).To(Equal(infrav1.LoadBalancerMigrationNotApprovedReason))
+	})
+
+	It("does not migrate the load balancer if the control plane endpoint is its IP", func() {
+		hetznerCluster.Spec.ControlPlaneEndpoint.Host = "1.1.1.1"
+		hetznerCluster.Spec.ControlPlaneLoadBalancer.Region = "nbg1"
+
+		res, err := service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(res.IsZero()).To(BeTrue())
+		Expect(findTarget()).To(BeNil())
+		Expect(conditions.GetReason(hetznerCluster, infrav1.LoadBalancerMigratedCondition)).To(Equal(infrav1.LoadBalancerMigrationBlockedReason))
+		Expect(conditions.GetSeverity(hetznerCluster, infrav1.LoadBalancerMigratedCondition)).To(Equal(ptr.To(clusterv1.ConditionSeverityError)))
+	})
+
+	It("deletes the new load balancer if the region is reverted", func() {
+		hetznerCluster.Spec.ControlPlaneLoadBalancer.Region = "nbg1"
+		_, err := service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(findTarget()).ToNot(BeNil())
+
+		hetznerCluster.Spec.ControlPlaneLoadBalancer.Region = "fsn1"
+		_, err = service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(findTarget()).To(BeNil())
+		Expect(conditions.Has(hetznerCluster, infrav1.LoadBalancerMigratedCondition)).To(BeFalse())
+	})
+
+	It("deletes the load balancers of an unfinished migration with the cluster", func() {
+		hetznerCluster.Spec.ControlPlaneLoadBalancer.Region = "nbg1"
+		_, err := service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(findTarget()).ToNot(BeNil())
+
+		hetznerCluster.Status.ControlPlaneLoadBalancer = &infrav1.LoadBalancerStatus{ID: oldLB.ID}
+		Expect(service.Delete(ctx)).To(Succeed())
+
+		lbs, err := hcloudClient.ListLoadBalancers(ctx, hcloud.LoadBalancerListOpts{})
+		Expect(err).To(BeNil())
+		Expect(lbs).To(BeEmpty())
+	})
+
+	It("does not migrate a load balancer with a fixed name", func() {
+		hetznerCluster.Spec.ControlPlaneLoadBalancer.Name = &oldLB.Name
+		hetznerCluster.Spec.ControlPlaneLoadBalancer.Region = "nbg1"
+
+		_, err := service.ReconcileMigration(ctx)
+		Expect(err).To(BeNil())
+		Expect(findTarget()).To(BeNil())
+	})
+})
@@ -0,0 +1,209 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"slices"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"k8s.io/utils/ptr"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+// Defaults of HCloud for health checks. They are applied to fields that are not specified,
+// so that services are not updated in every reconcile loop.
+const (
+	defaultHealthCheckInterval = 15 * time.Second
+	defaultHealthCheckTimeout  = 10 * time.Second
+	defaultHealthCheckRetries  = 3
+	defaultHealthCheckPath     = "/"
+)
+
+func addServiceOpts(spec infrav1.LoadBalancerServiceSpec) hcloud.LoadBalancerAddServiceOpts {
+	opts := hcloud.LoadBalancerAddServiceOpts{
+		Protocol:        hcloud.LoadBalancerServiceProtocol(spec.Protocol),
+		ListenPort:      ptr.To(spec.ListenPort),
+		DestinationPort: ptr.To(spec.DestinationPort),
+		Proxyprotocol:   ptr.To(spec.ProxyProtocol),
+	}
+
+	if httpOpts := serviceHTTPOpts(spec.HTTP); httpOpts != nil {
+		addHTTPOpts := hcloud.LoadBalancerAddServiceOptsHTTP(*httpOpts)
+		opts.HTTP = &addHTTPOpts
+	}
+
+	if healthCheckOpts := serviceHealthCheckOpts(spec); healthCheckOpts != nil {
+		opts.HealthCheck = &hcloud.LoadBalancerAddServiceOptsHealthCheck{
+			Protocol: healthCheckOpts.Protocol,
+			Port:     healthCheckOpts.Port,
+			Interval: healthCheckOpts.Interval,
+			Timeout:  healthCheckOpts.Timeout,
+			Retries:  healthCheckOpts.Retries,
+		}
+		if healthCheckOpts.HTTP != nil {
+			addHealthCheckHTTPOpts := hcloud.LoadBalancerAddServiceOptsHealthCheckHTTP(*healthCheckOpts.HTTP)
+			opts.HealthCheck.HTTP = &addHealthCheckHTTPOpts
+		}
+	}
+
+	return opts
+}
+
+func updateServiceOpts(spec infrav1.LoadBalancerServiceSpec) hcloud.LoadBalancerUpdateServiceOpts {
+	return hcloud.LoadBalancerUpdateServiceOpts{
+		Protocol:        hcloud.LoadBalancerServiceProtocol(spec.Protocol),
+		DestinationPort: ptr.To(spec.DestinationPort),
+		Proxyprotocol:   ptr.To(spec.ProxyProtocol),
+		HTTP:            serviceHTTPOpts(spec.HTTP),
+		HealthCheck:     serviceHealthCheckOpts(spec),
+	}
+}
+
+func serviceHTTPOpts(spec *infrav1.LoadBalancerServiceHTTPSpec) *hcloud.LoadBalancerUpdateServiceOptsHTTP {
+	if spec == nil {
+		return nil
+	}
+
+	opts := &hcloud.LoadBalancerUpdateServiceOptsHTTP{
+		RedirectHTTP:   ptr.To(spec.RedirectHTTP),
+		StickySessions: ptr.To(spec.StickySessions),
+	}
+	for _, id := range spec.CertificateIDs {
+		opts.Certificates = append(opts.Certificates, &hcloud.Certificate{ID: id})
+	}
+	if spec.CookieName != "" {
+		opts.CookieName = ptr.To(spec.CookieName)
+	}
+	if spec.CookieLifetime != nil {
+		opts.CookieLifetime = ptr.To(spec.CookieLifetime.Duration)
+	}
+	return opts
+}
+
+func serviceHealthCheckOpts(spec infrav1.LoadBalancerServiceSpec) *hcloud.LoadBalancerUpdateServiceOptsHealthCheck {
+	if spec.HealthCheck == nil {
+		return nil
+	}
+	healthCheck := spec.HealthCheck
+
+	opts := &hcloud.LoadBalancerUpdateServiceOptsHealthCheck{
+		Protocol: hcloud.LoadBalancerServiceProtocol(healthCheck.Protocol),
+		Port:     ptr.To(spec.DestinationPort),
+		Interval: ptr.To(defaultHealthCheckInterval),
+		Timeout:  ptr.To(defaultHealthCheckTimeout),
+		Retries:  ptr.To(defaultHealthCheckRetries),
+	}
+	if healthCheck.Port != nil {
+		opts.Port = ptr.To(*healthCheck.Port)
+	}
+	if healthCheck.Interval != nil {
+		opts.Interval = ptr.To(healthCheck.Interval.Duration)
+	}
+	if healthCheck.Timeout != nil {
+		opts.Timeout = ptr.To(healthCheck.Timeout.Duration)
+	}
+	if healthCheck.Retries != nil {
+		opts.Retries = ptr.To(*healthCheck.Retries)
+	}
+
+	if healthCheck.HTTP != nil {
+		opts.HTTP = &hcloud.LoadBalancerUpdateServiceOptsHealthCheckHTTP{
+			Domain:   ptr.To(healthCheck.HTTP.Domain),
+			Path:     ptr.To(defaultHealthCheckPath),
+			Response: ptr.To(healthCheck.HTTP.Response),
+			TLS:      ptr.To(healthCheck.HTTP.TLS),
+		}
+		if healthCheck.HTTP.Path != "" {
+			opts.HTTP.Path = ptr.To(healthCheck.HTTP.Path)
+		}
+		if len(healthCheck.HTTP.StatusCodes) > 0 {
+			opts.HTTP.StatusCodes = healthCheck.HTTP.StatusCodes
+		}
+	}
+	return opts
+}
+
+// serviceMatchesSpec checks whether the service in HCloud has the settings of the spec.
+// Settings that are not specified are not compared, as HCloud sets defaults for them.
+func serviceMatchesSpec(have hcloud.LoadBalancerService, spec infrav1.LoadBalancerServiceSpec) bool {
+	if string(have.Protocol) != spec.Protocol ||
+		have.DestinationPort != spec.DestinationPort ||
+		have.Proxyprotocol != spec.ProxyProtocol {
+		return false
+	}
+
+	if httpOpts := serviceHTTPOpts(spec.HTTP); httpOpts != nil {
+		if have.HTTP.RedirectHTTP != *httpOpts.RedirectHTTP || have.HTTP.StickySessions != *httpOpts.StickySessions {
+			return false
+		}
+		if httpOpts.CookieName != nil && have.HTTP.CookieName != *httpOpts.CookieName {
+			return false
+		}
+		if httpOpts.CookieLifetime != nil && have.HTTP.CookieLifetime != *httpOpts.CookieLifetime {
+			return false
+		}
+		if !slices.Equal(sortedCertificateIDs(have.HTTP.Certificates), sortedCertificateIDs(httpOpts.Certificates)) {
+			return false
+		}
+	}
+
+	if healthCheckOpts := serviceHealthCheckOpts(spec); healthCheckOpts != nil {
+		haveHealthCheck := have.HealthCheck
+		if haveHealthCheck.Protocol != healthCheckOpts.Protocol ||
+			haveHealthCheck.Port != *healthCheckOpts.Port ||
+			haveHealthCheck.Interval != *healthCheckOpts.Interval ||
+			haveHealthCheck.Timeout != *healthCheckOpts.Timeout ||
+			haveHealthCheck.Retries != *healthCheckOpts.Retries {
+			return false
+		}
+
+		if healthCheckOpts.HTTP != nil {
+			if haveHealthCheck.HTTP == nil {
+				return false
+			}
+			if haveHealthCheck.HTTP.Domain != *healthCheckOpts.HTTP.Domain ||
+				haveHealthCheck.HTTP.Path != *healthCheckOpts.HTTP.Path ||
+				haveHealthCheck.HTTP.Response != *healthCheckOpts.HTTP.Response ||
+				haveHealthCheck.HTTP.TLS != *healthCheckOpts.HTTP.TLS {
+				return false
+			}
+			if healthCheckOpts.HTTP.StatusCodes != nil &&
+				!slices.Equal(sortedStrings(haveHealthCheck.HTTP.StatusCodes), sortedStrings(healthCheckOpts.HTTP.StatusCodes)) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func sortedCertificateIDs(certificates []*hcloud.Certificate) []int64 {
+	ids := make([]int64, 0, len(certificates))
+	for _, certificate := range certificates {
+		ids = append(ids, certificate.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func sortedStrings(strs []string) []string {
+	sorted := slices.Clone(strs)
+	slices.Sort(sorted)
+	return sorted
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"context"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+)
+
+var _ = Describe("serviceMatchesSpec", func() {
+	var have hcloud.LoadBalancerService
+	var spec infrav1.LoadBalancerServiceSpec
+
+	BeforeEach(func() {
+		have = hcloud.LoadBalancerService{
+			Protocol:        hcloud.LoadBalancerServiceProtocolHTTPS,
+			ListenPort:      443,
+			DestinationPort: 80,
+			HTTP: hcloud.LoadBalancerServiceHTTP{
+				CookieName:     "HCLBSTICKY",
+				CookieLifetime: 300 * time.Second,
+				Certificates:   []*hcloud.Certificate{{ID: 2}, {ID: 1}},
+				StickySessions: true,
+			},
+			HealthCheck: hcloud.LoadBalancerServiceHealthCheck{
+				Protocol: hcloud.LoadBalancerServiceProtocolHTTP,
+				Port:     80,
+				Interval: 15 * time.Second,
+				Timeout:  10 * time.Second,
+				Retries:  3,
+				HTTP: &hcloud.LoadBalancerServiceHealthCheckHTTP{
+					Path:        "/healthz",
+					StatusCodes: []string{"2??", "3??"},
+				},
+			},
+		}
+		spec = infrav1.LoadBalancerServiceSpec{
+			Protocol:        "https",
+			ListenPort:      443,
+			DestinationPort: 80,
+			HTTP: &infrav1.LoadBalancerServiceHTTPSpec{
+				CertificateIDs: []int64{1, 2},
+				StickySessions: true,
+			},
+			HealthCheck: &infrav1.LoadBalancerHealthCheckSpec{
+				Protocol: "http",
+				HTTP:     &infrav1.LoadBalancerHealthCheckHTTPSpec{Path: "/healthz"},
+			},
+		}
+	})
+
+	It("matches if unspecified settings have the defaults of HCloud", func() {
+		Expect(serviceMatchesSpec(have, spec)).To(BeTrue())
+	})
+
+	It("ignores health check and HTTP settings that are not specified", func() {
+		spec.HealthCheck = nil
+		spec.HTTP = nil
+		have.HealthCheck.Retries = 5
+		Expect(serviceMatchesSpec(have, spec)).To(BeTrue())
+	})
+
+	It("detects a changed destination port", func() {
+		spec.DestinationPort = 8080
+		Expect(serviceMatchesSpec(have, spec)).To(BeFalse())
+	})
+
+	It("detects a changed proxy protocol", func() {
+		spec.ProxyProtocol = true
+		Expect(serviceMatchesSpec(have, spec)).To(BeFalse())
+	})
+
+	It("detects changed certificates", func() {
+		spec.HTTP.CertificateIDs = []int64{1}
+		Expect(serviceMatchesSpec(have, spec)).To(BeFalse())
+	})
+
+	It("detects a changed health check", func() {
+		spec.HealthCheck.Interval = &metav1.Duration{Duration: 5 * time.Second}
+		Expect(serviceMatchesSpec(have, spec)).To(BeFalse())
+	})
+
+	It("detects a changed health check path", func() {
+		spec.HealthCheck.HTTP.Path = "/readyz"
+		Expect(serviceMatchesSpec(have, spec)).To(BeFalse())
+	})
+})
+
+var _ = Describe("addServiceOpts", func() {
+	It("uses the destination port for the health check by default", func() {
+		opts := addServiceOpts(infrav1.LoadBalancerServiceSpec{
+			Protocol:        "tcp",
+			ListenPort:      6443,
+			DestinationPort: 6444,
+			HealthCheck: &infrav1.LoadBalancerHealthCheckSpec{
+				Protocol: "http",
+				HTTP:     &infrav1.LoadBalancerHealthCheckHTTPSpec{Path: "/readyz", TLS: true},
+			},
+		})
+
+		Expect(*opts.ListenPort).To(Equal(6443))
+		Expect(*opts.DestinationPort).To(Equal(6444))
+		Expect(*opts.Proxyprotocol).To(BeFalse())
+		Expect(opts.HTTP).To(BeNil())
+		Expect(opts.HealthCheck).ToNot(BeNil())
+		Expect(*opts.HealthCheck.Port).To(Equal(6444))
+		Expect(*opts.HealthCheck.Interval).To(Equal(15 * time.Second))
+		Expect(*opts.HealthCheck.HTTP.Path).To(Equal("/readyz"))
+		Expect(*opts.HealthCheck.HTTP.TLS).To(BeTrue())
+	})
+})
+
+var _ = Describe("reconcileServices", func() {
+	var (
+		ctx            context.Context
+		hcloudClient   hcloudclient.Client
+		hetznerCluster *infrav1.HetznerCluster
+		service        *Service
+		loadBalancer   *hcloud.LoadBalancer
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		hcloudClient = fakeclient.NewHCloudClientFactory().NewClient("")
+		hcloudClient.Reset()
+
+		hetznerCluster = &infrav1.HetznerCluster{
+			Spec: infrav1.HetznerClusterSpec{
+				ControlPlaneEndpoint: &clusterv1.APIEndpoint{Port: 6443},
+				ControlPlaneLoadBalancer: infrav1.LoadBalancerSpec{
+					Port: 6443,
+					HealthCheck: &infrav1.LoadBalancerHealthCheckSpec{
+						Protocol: "http",
+						HTTP:     &infrav1.LoadBalancerHealthCheckHTTPSpec{Path: "/readyz", TLS: true},
+					},
+					ExtraServices: []infrav1.LoadBalancerServiceSpec{
+						{Protocol: "tcp", ListenPort: 443, DestinationPort: 8443},
+					},
+				},
+			},
+		}
+		hetznerCluster.Name = "hetzner-cluster"
+
+		var err error
+		loadBalancer, err = hcloudClient.CreateLoadBalancer(ctx, hcloud.LoadBalancerCreateOpts{
+			Name:      "lb",
+			Algorithm: &hcloud.LoadBalancerAlgorithm{Type: hcloud.LoadBalancerAlgorithmTypeRoundRobin},
+		})
+		Expect(err).To(BeNil())
+
+		service = NewService(&scope.ClusterScope{HCloudClient: hcloudClient, HetznerCluster: hetznerCluster})
+	})
+
+	getServices := func() map[int]hcloud.LoadBalancerService {
+		lbs, err := hcloudClient.ListLoadBalancers(ctx, hcloud.LoadBalancerListOpts{Name: "lb"})
+		Expect(err).To(BeNil())
+		Expect(lbs).To(HaveLen(1))
+		loadBalancer = lbs[0]
+
+		services := make(map[int]hcloud.LoadBalancerService)
+		for _, s := range loadBalancer.Services {
+			services[s.ListenPort] = s
+		}
+		return services
+	}
+
+	It("creates the services with health checks", func() {
+		Expect(service.reconcileServices(ctx, loadBalancer)).To(Succeed())
+
+		services := getServices()
+		Expect(services).To(HaveLen(2))
+		Expect(services[6443].HealthCheck.Protocol).To(Equal(hcloud.LoadBalancerServiceProtocolHTTP))
+		Expect(services[6443].HealthCheck.HTTP.Path).To(Equal("/readyz"))
+		Expect(services[6443].HealthCheck.HTTP.TLS).To(BeTrue())
+		Expect(services[443].HealthCheck.Protocol).To(Equal(hcloud.LoadBalancerServiceProtocolTCP))
+		Expect(services[443].HealthCheck.Port).To(Equal(8443))
+	})
+
+	It("updates changed services in place", func() {
+		Expect(service.reconcileServices(ctx, loadBalancer)).To(Succeed())
+		getServices()
+
+		hetznerCluster.Spec.ControlPlaneLoadBalancer.ExtraServices[0] = infrav1.LoadBalancerServiceSpec{
+			Protocol:        "https",
+			ListenPort:      443,
+			DestinationPort: 8080,
+			HTTP: &infrav1.LoadBalancerServiceHTTPSpec{
+				CertificateIDs: []int64{1},
+				StickySessions: true,
+			},
+			HealthCheck: &infrav1.LoadBalancerHealthCheckSpec{
+				Protocol: "http",
+				Retries:  ptr.To(5),
+			},
+		}
+		Expect(service.reconcileServices(ctx, loadBalancer)).To(Succeed())
+
+		services := getServices()
+		Expect(services).To(HaveLen(2))
+		Expect(services[443].Protocol).To(Equal(hcloud.LoadBalancerServiceProtocolHTTPS))
+		Expect(services[443].DestinationPort).To(Equal(8080))
+		Expect(services[443].HTTP.StickySessions).To(BeTrue())
+		Expect(services[443].HTTP.Certificates).To(HaveLen(1))
+		Expect(services[443].HealthCheck.Retries).To(Equal(5))
+		Expect(services[443].HealthCheck.Port).To(Equal(8080))
+
+		// services are in sync now
+		for _, s := range loadBalancer.Services {
+			wantSpec := hetznerCluster.Spec.ControlPlaneLoadBalancer.ExtraServices[0]
+			if s.ListenPort == 6443 {
+				wantSpec = infrav1.LoadBalancerServiceSpec{
+					Protocol:        "tcp",
+					ListenPort:      6443,
+					DestinationPort: 6443,
+					HealthCheck:     hetznerCluster.Spec.ControlPlaneLoadBalancer.HealthCheck,
+				}
+			}
+			Expect(serviceMatchesSpec(s, wantSpec)).To(BeTrue())
+		}
+	})
+})
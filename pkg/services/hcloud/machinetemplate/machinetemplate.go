@@ -20,15 +20,29 @@ package machinetemplate
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
 
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
 	hcloudutil "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/util"
 )
 
+// CapacityRefreshInterval is the interval after which the capacity of a template is evaluated again, so that
+// changes of the server type in HCloud are picked up.
+const CapacityRefreshInterval = 6 * time.Hour
+
+// gpuResourceName is the resource of GPUs in the capacity.
+const gpuResourceName corev1.ResourceName = "nvidia.com/gpu"
+
 // Service defines struct with HCloudMachineTemplate scope to reconcile HCloud machine templates.
 type Service struct {
 	scope *scope.HCloudMachineTemplateScope
@@ -46,19 +60,48 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	// delete the deprecated condition from existing machinetemplate objects
 	conditions.Delete(s.scope.HCloudMachineTemplate, infrav1.DeprecatedRateLimitExceededCondition)
 
-	if s.scope.HCloudMachineTemplate.Status.Capacity == nil {
-		capacity, err := s.getCapacity(ctx)
+	status := &s.scope.HCloudMachineTemplate.Status
+	if s.needsCapacityUpdate() {
+		serverType, err := s.getServerType(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to get capacity: %w", err)
 		}
 
-		s.scope.HCloudMachineTemplate.Status.Capacity = capacity
+		capacity, err := capacityFromServerType(serverType)
+		if err != nil {
+			return fmt.Errorf("failed to get capacity: %w", err)
+		}
+
+		status.Capacity = capacity
+		status.NodeInfo = &infrav1.NodeInfo{
+			Architecture:    nodeArchitecture(serverType.Architecture),
+			OperatingSystem: "linux",
+		}
+		status.ServerType = s.scope.HCloudMachineTemplate.Spec.Template.Spec.Type
+		status.CPUType = string(serverType.CPUType)
+		now := metav1.Now()
+		status.LastCapacityUpdate = &now
 	}
+
+	// the annotations can change at any time and don't need HCloud
+	s.reconcileGPUs()
+	status.NodeLabels = s.nodeLabels()
+	status.NodeTaints = s.nodeTaints()
 	return nil
 }
 
-func (s *Service) getCapacity(ctx context.Context) (corev1.ResourceList, error) {
-	capacity := make(corev1.ResourceList)
+// needsCapacityUpdate returns whether the capacity is missing, has been evaluated for another server type or is outdated.
+func (s *Service) needsCapacityUpdate() bool {
+	template := s.scope.HCloudMachineTemplate
+	status := template.Status
+	return status.Capacity == nil ||
+		status.NodeInfo == nil ||
+		status.LastCapacityUpdate == nil ||
+		status.ServerType != template.Spec.Template.Spec.Type ||
+		time.Since(status.LastCapacityUpdate.Time) > CapacityRefreshInterval
+}
+
+func (s *Service) getServerType(ctx context.Context) (*hcloud.ServerType, error) {
 	// List all server types
 	serverTypes, err := s.scope.HCloudClient.ListServerTypes(ctx)
 	if err != nil {
@@ -66,28 +109,137 @@ func (s *Service) getCapacity(ctx context.Context) (corev1.ResourceList, error)
 		return nil, fmt.Errorf("failed to list server types: %w", err)
 	}
 
-	// Find the correct server type and check number of CPU cores and GB of memory
-	var foundServerType bool
+	// Find the correct server type
 	for _, serverType := range serverTypes {
-		if serverType.Name != string(s.scope.HCloudMachineTemplate.Spec.Template.Spec.Type) {
-			continue
+		if serverType.Name == string(s.scope.HCloudMachineTemplate.Spec.Template.Spec.Type) {
+			return serverType, nil
 		}
+	}
+	return nil, fmt.Errorf("failed to find server type for %s", s.scope.HCloudMachineTemplate.Spec.Template.Spec.Type)
+}
 
-		foundServerType = true
-		cpu, err := GetCPUQuantityFromInt(serverType.Cores)
+// capacityFromServerType returns the number of CPU cores, the memory and the disk of the server type.
+func capacityFromServerType(serverType *hcloud.ServerType) (corev1.ResourceList, error) {
+	capacity := make(corev1.ResourceList)
+
+	cpu, err := GetCPUQuantityFromInt(serverType.Cores)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse quantity. CPU cores %v. Server type %+v: %w", serverType.Cores, serverType, err)
+	}
+	capacity[corev1.ResourceCPU] = cpu
+
+	memory, err := GetMemoryQuantityFromFloat32(serverType.Memory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse quantity. Memory %v. Server type %+v: %w", serverType.Memory, serverType, err)
+	}
+	capacity[corev1.ResourceMemory] = memory
+
+	if serverType.Disk > 0 {
+		disk, err := GetDiskQuantityFromInt(serverType.Disk)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse quantity. CPU cores %v. Server type %+v: %w", serverType.Cores, serverType, err)
+			return nil, fmt.Errorf("failed to parse quantity. Disk %v. Server type %+v: %w", serverType.Disk, serverType, err)
 		}
-		capacity[corev1.ResourceCPU] = cpu
-		memory, err := GetMemoryQuantityFromFloat32(serverType.Memory)
+		capacity[corev1.ResourceEphemeralStorage] = disk
+	}
+
+	return capacity, nil
+}
+
+// reconcileGPUs sets the GPUs of the annotation in the capacity, as HCloud does not report GPUs.
+func (s *Service) reconcileGPUs() {
+	template := s.scope.HCloudMachineTemplate
+	value, found := template.Annotations[infrav1.GPUCountAnnotation]
+	if !found {
+		delete(template.Status.Capacity, gpuResourceName)
+		return
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		record.Warnf(template, "InvalidGPUCount", "Ignoring invalid GPU count %q of annotation %s", value, infrav1.GPUCountAnnotation)
+		delete(template.Status.Capacity, gpuResourceName)
+		return
+	}
+	template.Status.Capacity[gpuResourceName] = *resource.NewQuantity(int64(count), resource.DecimalSI)
+}
+
+// nodeLabels returns the well-known labels of the server type together with the labels of the annotation.
+func (s *Service) nodeLabels() map[string]string {
+	template := s.scope.HCloudMachineTemplate
+	labels := map[string]string{
+		corev1.LabelInstanceTypeStable: string(template.Status.ServerType),
+		corev1.LabelOSStable:           template.Status.NodeInfo.OperatingSystem,
+	}
+	if arch := template.Status.NodeInfo.Architecture; arch != "" {
+		labels[corev1.LabelArchStable] = arch
+	}
+
+	value := template.Annotations[infrav1.NodeLabelsAnnotation]
+	for _, label := range splitList(value) {
+		key, val, found := strings.Cut(label, "=")
+		if !found || key == "" {
+			record.Warnf(template, "InvalidNodeLabel", "Ignoring invalid label %q of annotation %s", label, infrav1.NodeLabelsAnnotation)
+			continue
+		}
+		labels[key] = val
+	}
+	return labels
+}
+
+// nodeTaints returns the taints of the annotation.
+func (s *Service) nodeTaints() []corev1.Taint {
+	template := s.scope.HCloudMachineTemplate
+
+	var taints []corev1.Taint
+	for _, value := range splitList(template.Annotations[infrav1.NodeTaintsAnnotation]) {
+		taint, err := parseTaint(value)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse quantity. Memory %v. Server type %+v: %w", serverType.Memory, serverType, err)
+			record.Warnf(template, "InvalidNodeTaint", "Ignoring invalid taint %q of annotation %s: %s", value, infrav1.NodeTaintsAnnotation, err)
+			continue
 		}
-		capacity[corev1.ResourceMemory] = memory
+		taints = append(taints, taint)
 	}
-	if !foundServerType {
-		return nil, fmt.Errorf("failed to find server type for %s", s.scope.HCloudMachineTemplate.Spec.Template.Spec.Type)
+	return taints
+}
+
+// parseTaint parses a taint in the format key=value:Effect or key:Effect.
+func parseTaint(value string) (corev1.Taint, error) {
+	keyValue, effect, found := strings.Cut(value, ":")
+	if !found {
+		return corev1.Taint{}, fmt.Errorf("missing effect")
 	}
 
-	return capacity, nil
+	switch corev1.TaintEffect(effect) {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		return corev1.Taint{}, fmt.Errorf("unknown effect %q", effect)
+	}
+
+	key, val, _ := strings.Cut(keyValue, "=")
+	if key == "" {
+		return corev1.Taint{}, fmt.Errorf("missing key")
+	}
+	return corev1.Taint{Key: key, Value: val, Effect: corev1.TaintEffect(effect)}, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// nodeArchitecture returns the architecture of Kubernetes nodes for the architecture of HCloud.
+func nodeArchitecture(architecture hcloud.Architecture) string {
+	switch architecture {
+	case hcloud.ArchitectureX86:
+		return "amd64"
+	case hcloud.ArchitectureARM:
+		return "arm64"
+	default:
+		return ""
+	}
 }
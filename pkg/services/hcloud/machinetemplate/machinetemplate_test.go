@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinetemplate
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+)
+
+var _ = Describe("Reconcile", func() {
+	var (
+		template *infrav1.HCloudMachineTemplate
+		service  *Service
+	)
+
+	BeforeEach(func() {
+		template = &infrav1.HCloudMachineTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: "default"},
+			Spec: infrav1.HCloudMachineTemplateSpec{
+				Template: infrav1.HCloudMachineTemplateResource{
+					Spec: infrav1.HCloudMachineSpec{Type: "cpx31"},
+				},
+			},
+		}
+		service = NewService(&scope.HCloudMachineTemplateScope{
+			HCloudMachineTemplate: template,
+			HCloudClient:          fakeclient.NewHCloudClientFactory().NewClient(""),
+		})
+	})
+
+	It("sets the capacity, node info and well-known labels of the server type", func() {
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+
+		status := template.Status
+		Expect(status.Capacity.Cpu().String()).To(Equal("1"))
+		Expect(status.Capacity.Memory().String()).To(Equal("4G"))
+		Expect(status.Capacity.StorageEphemeral().String()).To(Equal("40G"))
+		Expect(status.NodeInfo).To(Equal(&infrav1.NodeInfo{Architecture: "amd64", OperatingSystem: "linux"}))
+		Expect(status.ServerType).To(Equal(infrav1.HCloudMachineType("cpx31")))
+		Expect(status.CPUType).To(Equal("shared"))
+		Expect(status.LastCapacityUpdate).ToNot(BeNil())
+		Expect(status.NodeLabels).To(Equal(map[string]string{
+			corev1.LabelInstanceTypeStable: "cpx31",
+			corev1.LabelOSStable:           "linux",
+			corev1.LabelArchStable:         "amd64",
+		}))
+		Expect(status.NodeTaints).To(BeEmpty())
+	})
+
+	It("evaluates the capacity again if the server type changed", func() {
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+		lastUpdate := template.Status.LastCapacityUpdate
+
+		template.Spec.Template.Spec.Type = "cpx21"
+		Expect(service.needsCapacityUpdate()).To(BeTrue())
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+		Expect(template.Status.ServerType).To(Equal(infrav1.HCloudMachineType("cpx21")))
+		Expect(template.Status.NodeLabels).To(HaveKeyWithValue(corev1.LabelInstanceTypeStable, "cpx21"))
+		Expect(template.Status.LastCapacityUpdate).ToNot(BeIdenticalTo(lastUpdate))
+	})
+
+	It("evaluates the capacity again after the refresh interval", func() {
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+		Expect(service.needsCapacityUpdate()).To(BeFalse())
+
+		template.Status.LastCapacityUpdate = &metav1.Time{Time: time.Now().Add(-CapacityRefreshInterval - time.Minute)}
+		Expect(service.needsCapacityUpdate()).To(BeTrue())
+	})
+
+	It("fails for an unknown server type", func() {
+		template.Spec.Template.Spec.Type = "unknown"
+		Expect(service.Reconcile(context.Background())).ToNot(Succeed())
+	})
+
+	It("sets labels, taints and GPUs of the annotations and ignores invalid entries", func() {
+		template.Annotations = map[string]string{
+			infrav1.NodeLabelsAnnotation: "pool=gpu, invalid ,zone=",
+			infrav1.NodeTaintsAnnotation: "dedicated=gpu:NoSchedule,gpu:NoExecute,invalid,key=value:Unknown",
+			infrav1.GPUCountAnnotation:   "2",
+		}
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+
+		status := template.Status
+		Expect(status.NodeLabels).To(HaveKeyWithValue("pool", "gpu"))
+		Expect(status.NodeLabels).To(HaveKeyWithValue("zone", ""))
+		Expect(status.NodeLabels).ToNot(HaveKey("invalid"))
+		Expect(status.NodeTaints).To(Equal([]corev1.Taint{
+			{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "gpu", Effect: corev1.TaintEffectNoExecute},
+		}))
+		gpus := status.Capacity[gpuResourceName]
+		Expect(gpus.String()).To(Equal("2"))
+
+		delete(template.Annotations, infrav1.GPUCountAnnotation)
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+		Expect(status.Capacity).ToNot(HaveKey(gpuResourceName))
+	})
+
+	It("ignores an invalid GPU count", func() {
+		template.Annotations = map[string]string{infrav1.GPUCountAnnotation: "-1"}
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+		Expect(template.Status.Capacity).ToNot(HaveKey(gpuResourceName))
+	})
+})
@@ -31,3 +31,8 @@ func GetCPUQuantityFromInt(cores int) (resource.Quantity, error) {
 func GetMemoryQuantityFromFloat32(memory float32) (resource.Quantity, error) {
 	return resource.ParseQuantity(fmt.Sprintf("%vG", memory))
 }
+
+// GetDiskQuantityFromInt returns a resource quantity for disk space in GB from an integer.
+func GetDiskQuantityFromInt(disk int) (resource.Quantity, error) {
+	return resource.ParseQuantity(fmt.Sprintf("%vG", disk))
+}
@@ -23,7 +23,9 @@ import (
 
 var _ = DescribeTable("GetCPUQuantityFromInt",
 	func(cpuCores int, expectedOutput string) {
-		Expect(GetCPUQuantityFromInt(cpuCores)).To(Equal(expectedOutput))
+		quantity, err := GetCPUQuantityFromInt(cpuCores)
+		Expect(err).To(Succeed())
+		Expect(quantity.String()).To(Equal(expectedOutput))
 	},
 	Entry("1", 1, "1"),
 	Entry("2", 2, "2"),
@@ -31,8 +33,20 @@ var _ = DescribeTable("GetCPUQuantityFromInt",
 
 var _ = DescribeTable("GetMemoryQuantityFromFloat32",
 	func(memory float32, expectedOutput string) {
-		Expect(GetMemoryQuantityFromFloat32(memory)).To(Equal(expectedOutput))
+		quantity, err := GetMemoryQuantityFromFloat32(memory)
+		Expect(err).To(Succeed())
+		Expect(quantity.String()).To(Equal(expectedOutput))
 	},
 	Entry("1", float32(1), "1G"),
 	Entry("2", float32(2), "2G"),
 )
+
+var _ = DescribeTable("GetDiskQuantityFromInt",
+	func(disk int, expectedOutput string) {
+		quantity, err := GetDiskQuantityFromInt(disk)
+		Expect(err).To(Succeed())
+		Expect(quantity.String()).To(Equal(expectedOutput))
+	},
+	Entry("40", 40, "40G"),
+	Entry("160", 160, "160G"),
+)
@@ -0,0 +1,390 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package natgateway implements the lifecycle of the NAT gateway of the HCloud network.
+package natgateway
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudutil "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/util"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+// notReadyRequeueAfter is the interval after which a NAT gateway without IP address in the network is checked again.
+const notReadyRequeueAfter = 10 * time.Second
+
+// defaultRoute is the destination of the route via the NAT gateway.
+var defaultRoute = &net.IPNet{IP: net.IPv4zero, Mask: net.CIDRMask(0, 32)}
+
+// userDataTemplate enables IP forwarding and masquerades the traffic of the network on the public interface.
+// The commands that add and remove the masquerading rules are inserted by userData.
+const userDataTemplate = `#cloud-config
+write_files:
+  - path: /etc/sysctl.d/99-nat-gateway.conf
+    content: |
+      net.ipv4.ip_forward=1
+  - path: /etc/systemd/system/nat-gateway.service
+    content: |
+      [Unit]
+      Description=Masquerade the traffic of the private network
+      After=network-online.target
+      Wants=network-online.target
+
+      [Service]
+      Type=oneshot
+      RemainAfterExit=true
+%[1]s
+
+      [Install]
+      WantedBy=multi-user.target
+runcmd:
+  - sysctl --system
+  - systemctl daemon-reload
+  - systemctl enable --now nat-gateway.service
+`
+
+// Service struct contains cluster scope to reconcile the NAT gateway.
+type Service struct {
+	scope *scope.ClusterScope
+}
+
+// NewService creates new service object.
+func NewService(scope *scope.ClusterScope) *Service {
+	return &Service{
+		scope: scope,
+	}
+}
+
+// Reconcile implements the life cycle of the NAT gateway. It creates the server of the NAT gateway in the network
+// and routes all traffic that leaves the network through it.
+func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err error) {
+	hetznerCluster := s.scope.HetznerCluster
+	spec := hetznerCluster.Spec.HCloudNetwork.NATGateway
+
+	if spec == nil {
+		// delete the NAT gateway if it has been removed from the spec
+		if err := s.Delete(ctx); err != nil {
+			return reconcile.Result{}, err
+		}
+		conditions.Delete(hetznerCluster, infrav1.NATGatewayReadyCondition)
+		return reconcile.Result{}, nil
+	}
+
+	defer func() {
+		if err != nil {
+			conditions.MarkFalse(
+				hetznerCluster,
+				infrav1.NATGatewayReadyCondition,
+				infrav1.NATGatewayReconcileFailedReason,
+				clusterv1.ConditionSeverityWarning,
+				"%s",
+				err.Error(),
+			)
+		}
+	}()
+
+	if hetznerCluster.Status.Network == nil {
+		return reconcile.Result{}, fmt.Errorf("network does not exist yet")
+	}
+
+	server, err := s.findServer(ctx)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to find server: %w", err)
+	}
+
+	if server == nil {
+		server, err = s.createServer(ctx, spec)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to create server: %w", err)
+		}
+	}
+
+	status := &infrav1.NATGatewayStatus{ServerID: server.ID}
+	if ip := server.PublicNet.IPv4.IP; ip != nil {
+		status.PublicIPv4 = ip.String()
+	}
+	for _, privateNet := range server.PrivateNet {
+		if privateNet.Network != nil && privateNet.Network.ID != hetznerCluster.Status.Network.ID {
+			continue
+		}
+		status.PrivateIP = privateNet.IP.String()
+	}
+	hetznerCluster.Status.NATGateway = status
+
+	if status.PrivateIP == "" {
+		conditions.MarkFalse(
+			hetznerCluster,
+			infrav1.NATGatewayReadyCondition,
+			infrav1.NATGatewayNotReadyReason,
+			clusterv1.ConditionSeverityInfo,
+			"NAT gateway has no IP address in the network yet",
+		)
+		return reconcile.Result{RequeueAfter: notReadyRequeueAfter}, nil
+	}
+
+	if err := s.reconcileRoute(ctx, net.ParseIP(status.PrivateIP)); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile route: %w", err)
+	}
+
+	conditions.MarkTrue(hetznerCluster, infrav1.NATGatewayReadyCondition)
+	return reconcile.Result{}, nil
+}
+
+// reconcileRoute makes sure that the default route of the network points to the NAT gateway.
+func (s *Service) reconcileRoute(ctx context.Context, gateway net.IP) error {
+	network, err := s.scope.HCloudClient.GetNetwork(ctx, s.scope.HetznerCluster.Status.Network.ID)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "GetNetwork")
+		return fmt.Errorf("failed to get network: %w", err)
+	}
+	if network == nil {
+		return fmt.Errorf("network with ID %d not found", s.scope.HetznerCluster.Status.Network.ID)
+	}
+
+	var found bool
+	for _, route := range network.Routes {
+		if !isDefaultRoute(route) {
+			continue
+		}
+		if route.Gateway.Equal(gateway) {
+			found = true
+			continue
+		}
+		// the default route points to an old NAT gateway
+		if err := s.deleteRoute(ctx, network, route); err != nil {
+			return err
+		}
+	}
+	if found {
+		return nil
+	}
+
+	route := hcloud.NetworkRoute{Destination: defaultRoute, Gateway: gateway}
+	if err := s.scope.HCloudClient.AddRouteToNetwork(ctx, network, hcloud.NetworkAddRouteOpts{Route: route}); err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "AddRouteToNetwork")
+		return fmt.Errorf("failed to add route to %s via %s: %w", route.Destination, route.Gateway, err)
+	}
+	record.Eventf(s.scope.HetznerCluster, "NetworkRouteAdded", "Added route to %s via NAT gateway %s to network with ID %v", route.Destination, route.Gateway, network.ID)
+	return nil
+}
+
+// Delete implements the deletion of the NAT gateway. The default route is removed before the server is deleted.
+func (s *Service) Delete(ctx context.Context) error {
+	status := s.scope.HetznerCluster.Status.NATGateway
+	if status == nil {
+		return nil
+	}
+
+	if err := s.deleteDefaultRoute(ctx, net.ParseIP(status.PrivateIP)); err != nil {
+		return fmt.Errorf("failed to delete route: %w", err)
+	}
+
+	server, err := s.findServer(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find server: %w", err)
+	}
+
+	if server != nil {
+		if err := s.scope.HCloudClient.DeleteServer(ctx, server); err != nil {
+			// if the server has been deleted already then do nothing
+			if !hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+				hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteServer")
+				record.Warnf(s.scope.HetznerCluster, "NATGatewayDeleteFailed", "Failed to delete NAT gateway %s: %s", server.Name, err)
+				return fmt.Errorf("failed to delete server %s: %w", server.Name, err)
+			}
+		}
+		record.Eventf(s.scope.HetznerCluster, "NATGatewayDeleted", "Deleted NAT gateway %s with ID %d", server.Name, server.ID)
+	}
+
+	s.scope.HetznerCluster.Status.NATGateway = nil
+	return nil
+}
+
+func (s *Service) deleteDefaultRoute(ctx context.Context, gateway net.IP) error {
+	networkStatus := s.scope.HetznerCluster.Status.Network
+	if networkStatus == nil || gateway == nil {
+		return nil
+	}
+
+	network, err := s.scope.HCloudClient.GetNetwork(ctx, networkStatus.ID)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "GetNetwork")
+		return fmt.Errorf("failed to get network: %w", err)
+	}
+	if network == nil {
+		return nil
+	}
+
+	i := slices.IndexFunc(network.Routes, func(route hcloud.NetworkRoute) bool {
+		return isDefaultRoute(route) && route.Gateway.Equal(gateway)
+	})
+	if i < 0 {
+		return nil
+	}
+	return s.deleteRoute(ctx, network, network.Routes[i])
+}
+
+func (s *Service) deleteRoute(ctx context.Context, network *hcloud.Network, route hcloud.NetworkRoute) error {
+	if err := s.scope.HCloudClient.DeleteRouteFromNetwork(ctx, network, hcloud.NetworkDeleteRouteOpts{Route: route}); err != nil {
+		// if the route has been deleted already then do nothing
+		if hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+			return nil
+		}
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteRouteFromNetwork")
+		return fmt.Errorf("failed to delete route to %s via %s: %w", route.Destination, route.Gateway, err)
+	}
+	record.Eventf(s.scope.HetznerCluster, "NetworkRouteDeleted", "Deleted route to %s via %s from network with ID %v", route.Destination, route.Gateway, network.ID)
+	return nil
+}
+
+func (s *Service) createServer(ctx context.Context, spec *infrav1.NATGatewaySpec) (*hcloud.Server, error) {
+	hetznerCluster := s.scope.HetznerCluster
+
+	location := spec.Location
+	if location == "" && len(hetznerCluster.Spec.ControlPlaneRegions) > 0 {
+		location = hetznerCluster.Spec.ControlPlaneRegions[0]
+	}
+
+	sshKeys, err := s.sshKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	startAfterCreate := true
+	opts := hcloud.ServerCreateOpts{
+		Name:             fmt.Sprintf("%s-nat-gateway", hetznerCluster.Name),
+		ServerType:       &hcloud.ServerType{Name: string(spec.Type)},
+		Image:            &hcloud.Image{Name: spec.ImageName},
+		Location:         &hcloud.Location{Name: string(location)},
+		Labels:           s.labels(),
+		SSHKeys:          sshKeys,
+		StartAfterCreate: &startAfterCreate,
+		UserData:         userData(hetznerCluster.Spec.HCloudNetwork),
+		Networks:         []*hcloud.Network{{ID: hetznerCluster.Status.Network.ID}},
+		PublicNet: &hcloud.ServerCreatePublicNet{
+			EnableIPv4: true,
+			EnableIPv6: true,
+		},
+	}
+
+	server, err := s.scope.HCloudClient.CreateServer(ctx, opts)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "CreateServer")
+		record.Warnf(hetznerCluster, "NATGatewayCreateFailed", "Failed to create NAT gateway %s: %s", opts.Name, err)
+		return nil, fmt.Errorf("failed to create server %s: %w", opts.Name, err)
+	}
+
+	record.Eventf(hetznerCluster, "NATGatewayCreated", "Created NAT gateway %s with ID %d in location %s", server.Name, server.ID, location)
+	return server, nil
+}
+
+// sshKeys returns the HCloud SSH keys of the cluster, so that HCloud does not send a root password via email.
+func (s *Service) sshKeys(ctx context.Context) ([]*hcloud.SSHKey, error) {
+	specs := s.scope.HetznerCluster.Spec.SSHKeys.HCloud
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	sshKeys, err := s.scope.HCloudClient.ListSSHKeys(ctx, hcloud.SSHKeyListOpts{})
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ListSSHKeys")
+		return nil, fmt.Errorf("failed to list ssh keys: %w", err)
+	}
+
+	return slices.DeleteFunc(sshKeys, func(key *hcloud.SSHKey) bool {
+		return !slices.ContainsFunc(specs, func(spec infrav1.SSHKey) bool { return spec.Name == key.Name })
+	}), nil
+}
+
+func (s *Service) findServer(ctx context.Context) (*hcloud.Server, error) {
+	servers, err := s.scope.HCloudClient.ListServers(ctx, hcloud.ServerListOpts{
+		ListOpts: hcloud.ListOpts{LabelSelector: utils.LabelsToLabelSelector(s.labels())},
+	})
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ListServers")
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	if len(servers) > 1 {
+		record.Warnf(s.scope.HetznerCluster, "MultipleNATGateways", "Found %d NAT gateways of the cluster", len(servers))
+		return nil, fmt.Errorf("found %d NAT gateways of the cluster", len(servers))
+	}
+	if len(servers) == 0 {
+		return nil, nil
+	}
+	return servers[0], nil
+}
+
+func (s *Service) labels() map[string]string {
+	return map[string]string{
+		s.scope.HetznerCluster.ClusterTagKey(): string(infrav1.ResourceLifecycleOwned),
+		infrav1.NATGatewayTagKey:               "true",
+	}
+}
+
+func isDefaultRoute(route hcloud.NetworkRoute) bool {
+	return route.Destination != nil && route.Destination.String() == defaultRoute.String()
+}
+
+// userData returns the user data of the NAT gateway. It masquerades the traffic of the network and of all
+// subnets that are not part of the IP range of the network.
+func userData(spec infrav1.HCloudNetworkSpec) string {
+	ipRanges := []string{spec.CIDRBlock}
+	for _, subnet := range spec.SubnetSpecs() {
+		if subnet.IPRange == "" || containsIPRange(spec.CIDRBlock, subnet.IPRange) {
+			continue
+		}
+		ipRanges = append(ipRanges, subnet.IPRange)
+	}
+
+	var commands []string
+	for _, ipRange := range ipRanges {
+		commands = append(commands, fmt.Sprintf("      ExecStart=/usr/sbin/iptables -t nat -A POSTROUTING -s %s -o eth0 -j MASQUERADE", ipRange))
+	}
+	for _, ipRange := range ipRanges {
+		commands = append(commands, fmt.Sprintf("      ExecStop=/usr/sbin/iptables -t nat -D POSTROUTING -s %s -o eth0 -j MASQUERADE", ipRange))
+	}
+	return fmt.Sprintf(userDataTemplate, strings.Join(commands, "\n"))
+}
+
+// containsIPRange returns true if the IP range is part of the IP range of the network.
+func containsIPRange(networkIPRange, ipRange string) bool {
+	_, network, err := net.ParseCIDR(networkIPRange)
+	if err != nil {
+		return false
+	}
+	_, subnet, err := net.ParseCIDR(ipRange)
+	if err != nil {
+		return false
+	}
+	networkSize, _ := network.Mask.Size()
+	subnetSize, _ := subnet.Mask.Size()
+	return network.Contains(subnet.IP) && subnetSize >= networkSize
+}
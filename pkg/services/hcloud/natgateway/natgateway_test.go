@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package natgateway
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+)
+
+func TestNATGateway(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "NAT Gateway Suite")
+}
+
+var _ = Describe("Test Reconcile", func() {
+	var (
+		ctx            context.Context
+		hcloudClient   hcloudclient.Client
+		hetznerCluster *infrav1.HetznerCluster
+		network        *hcloud.Network
+		service        *Service
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		hcloudClient = fakeclient.NewHCloudClientFactory().NewClient("")
+		hcloudClient.Reset()
+
+		_, ipRange, err := net.ParseCIDR("10.0.0.0/16")
+		Expect(err).To(BeNil())
+		network, err = hcloudClient.CreateNetwork(ctx, hcloud.NetworkCreateOpts{Name: "hetzner-cluster", IPRange: ipRange})
+		Expect(err).To(BeNil())
+
+		hetznerCluster = &infrav1.HetznerCluster{}
+		hetznerCluster.Name = "hetzner-cluster"
+		hetznerCluster.Spec.ControlPlaneRegions = []infrav1.Region{"fsn1"}
+		hetznerCluster.Spec.HCloudNetwork = infrav1.HCloudNetworkSpec{
+			Enabled:    true,
+			CIDRBlock:  "10.0.0.0/16",
+			NATGateway: &infrav1.NATGatewaySpec{Type: "cx22", ImageName: "ubuntu-24.04"},
+		}
+		hetznerCluster.Status.Network = &infrav1.NetworkStatus{ID: network.ID}
+
+		service = NewService(&scope.ClusterScope{HCloudClient: hcloudClient, HetznerCluster: hetznerCluster})
+	})
+
+	defaultRoutes := func() []hcloud.NetworkRoute {
+		n, err := hcloudClient.GetNetwork(ctx, network.ID)
+		Expect(err).To(BeNil())
+
+		var routes []hcloud.NetworkRoute
+		for _, route := range n.Routes {
+			if isDefaultRoute(route) {
+				routes = append(routes, route)
+			}
+		}
+		return routes
+	}
+
+	It("creates the server and routes the traffic of the network through it", func() {
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+
+		servers, err := hcloudClient.ListServers(ctx, hcloud.ServerListOpts{})
+		Expect(err).To(BeNil())
+		Expect(servers).To(HaveLen(1))
+		Expect(servers[0].Name).To(Equal("hetzner-cluster-nat-gateway"))
+		Expect(servers[0].Labels).To(HaveKeyWithValue(infrav1.NATGatewayTagKey, "true"))
+
+		Expect(hetznerCluster.Status.NATGateway).ToNot(BeNil())
+		Expect(hetznerCluster.Status.NATGateway.ServerID).To(Equal(servers[0].ID))
+		Expect(hetznerCluster.Status.NATGateway.PrivateIP).To(Equal(servers[0].PrivateNet[0].IP.String()))
+
+		routes := defaultRoutes()
+		Expect(routes).To(HaveLen(1))
+		Expect(routes[0].Gateway.String()).To(Equal(hetznerCluster.Status.NATGateway.PrivateIP))
+		Expect(conditions.IsTrue(hetznerCluster, infrav1.NATGatewayReadyCondition)).To(BeTrue())
+
+		// reconciling again does not change anything
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(defaultRoutes()).To(HaveLen(1))
+		servers, err = hcloudClient.ListServers(ctx, hcloud.ServerListOpts{})
+		Expect(err).To(BeNil())
+		Expect(servers).To(HaveLen(1))
+	})
+
+	It("replaces a default route via another gateway", func() {
+		Expect(hcloudClient.AddRouteToNetwork(ctx, network, hcloud.NetworkAddRouteOpts{
+			Route: hcloud.NetworkRoute{Destination: defaultRoute, Gateway: net.ParseIP("10.0.0.99")},
+		})).To(Succeed())
+
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+
+		routes := defaultRoutes()
+		Expect(routes).To(HaveLen(1))
+		Expect(routes[0].Gateway.String()).To(Equal(hetznerCluster.Status.NATGateway.PrivateIP))
+	})
+
+	It("deletes the route and the server if the NAT gateway is removed from the spec", func() {
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+
+		hetznerCluster.Spec.HCloudNetwork.NATGateway = nil
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+
+		Expect(defaultRoutes()).To(BeEmpty())
+		servers, err := hcloudClient.ListServers(ctx, hcloud.ServerListOpts{})
+		Expect(err).To(BeNil())
+		Expect(servers).To(BeEmpty())
+		Expect(hetznerCluster.Status.NATGateway).To(BeNil())
+		Expect(conditions.Has(hetznerCluster, infrav1.NATGatewayReadyCondition)).To(BeFalse())
+	})
+
+	It("fails if the network does not exist yet", func() {
+		hetznerCluster.Status.Network = nil
+
+		_, err := service.Reconcile(ctx)
+		Expect(err).ToNot(Succeed())
+		Expect(conditions.IsFalse(hetznerCluster, infrav1.NATGatewayReadyCondition)).To(BeTrue())
+	})
+})
+
+var _ = Describe("userData", func() {
+	It("masquerades the network and the subnets outside of its IP range", func() {
+		data := userData(infrav1.HCloudNetworkSpec{
+			CIDRBlock: "10.0.0.0/16",
+			Subnets: []infrav1.HCloudNetworkSubnetSpec{
+				{IPRange: "10.0.1.0/24"},
+				{IPRange: "10.1.0.0/24"},
+			},
+		})
+		Expect(data).To(ContainSubstring("ExecStart=/usr/sbin/iptables -t nat -A POSTROUTING -s 10.0.0.0/16 -o eth0 -j MASQUERADE\n"))
+		Expect(data).To(ContainSubstring("ExecStart=/usr/sbin/iptables -t nat -A POSTROUTING -s 10.1.0.0/24 -o eth0 -j MASQUERADE\n"))
+		Expect(data).To(ContainSubstring("ExecStop=/usr/sbin/iptables -t nat -D POSTROUTING -s 10.1.0.0/24 -o eth0 -j MASQUERADE\n"))
+		Expect(data).ToNot(ContainSubstring("10.0.1.0/24"))
+	})
+})
@@ -68,6 +68,18 @@ func (s *Service) Reconcile(ctx context.Context) (err error) {
 		}
 	}()
 
+	if s.scope.HetznerCluster.Spec.HCloudNetwork.IsAdopted() {
+		network, err := s.findAdoptedNetwork(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to find existing network: %w", err)
+		}
+
+		conditions.MarkTrue(s.scope.HetznerCluster, infrav1.NetworkReadyCondition)
+		s.scope.HetznerCluster.Status.Network = statusFromHCloudNetwork(network)
+		s.scope.HetznerCluster.Status.Network.Unmanaged = true
+		return nil
+	}
+
 	network, err := s.findNetwork(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to find network: %w", err)
@@ -80,12 +92,77 @@ func (s *Service) Reconcile(ctx context.Context) (err error) {
 		}
 	}
 
+	updated, err := s.reconcileSubnetsAndRoutes(ctx, network)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile subnets and routes: %w", err)
+	}
+
+	if updated {
+		network, err = s.findNetwork(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to find network: %w", err)
+		}
+		if network == nil {
+			return fmt.Errorf("network not found after adding subnets and routes")
+		}
+	}
+
 	conditions.MarkTrue(s.scope.HetznerCluster, infrav1.NetworkReadyCondition)
 	s.scope.HetznerCluster.Status.Network = statusFromHCloudNetwork(network)
 
 	return nil
 }
 
+// reconcileSubnetsAndRoutes adds missing subnets and routes to an existing network. Subnets and routes
+// that are not specified are left untouched. It returns true if the network was updated.
+func (s *Service) reconcileSubnetsAndRoutes(ctx context.Context, network *hcloud.Network) (bool, error) {
+	spec := s.scope.HetznerCluster.Spec.HCloudNetwork
+
+	subnets, err := subnetsFromSpec(spec.SubnetSpecs())
+	if err != nil {
+		return false, err
+	}
+
+	routes, err := routesFromSpec(spec.Routes)
+	if err != nil {
+		return false, err
+	}
+
+	var updated bool
+	for _, subnet := range subnets {
+		if slices.ContainsFunc(network.Subnets, func(existing hcloud.NetworkSubnet) bool {
+			return existing.IPRange != nil && existing.IPRange.String() == subnet.IPRange.String()
+		}) {
+			continue
+		}
+
+		if err := s.scope.HCloudClient.AddSubnetToNetwork(ctx, network, hcloud.NetworkAddSubnetOpts{Subnet: subnet}); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "AddSubnetToNetwork")
+			return updated, fmt.Errorf("failed to add subnet %s: %w", subnet.IPRange, err)
+		}
+		record.Eventf(s.scope.HetznerCluster, "NetworkSubnetAdded", "Added subnet %s to network with ID %v", subnet.IPRange, network.ID)
+		updated = true
+	}
+
+	for _, route := range routes {
+		if slices.ContainsFunc(network.Routes, func(existing hcloud.NetworkRoute) bool {
+			return existing.Destination != nil && existing.Destination.String() == route.Destination.String() &&
+				existing.Gateway.Equal(route.Gateway)
+		}) {
+			continue
+		}
+
+		if err := s.scope.HCloudClient.AddRouteToNetwork(ctx, network, hcloud.NetworkAddRouteOpts{Route: route}); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "AddRouteToNetwork")
+			return updated, fmt.Errorf("failed to add route to %s via %s: %w", route.Destination, route.Gateway, err)
+		}
+		record.Eventf(s.scope.HetznerCluster, "NetworkRouteAdded", "Added route to %s via %s to network with ID %v", route.Destination, route.Gateway, network.ID)
+		updated = true
+	}
+
+	return updated, nil
+}
+
 func (s *Service) createNetwork(ctx context.Context) (*hcloud.Network, error) {
 	opts, err := s.createOpts()
 	if err != nil {
@@ -111,25 +188,72 @@ func (s *Service) createOpts() (hcloud.NetworkCreateOpts, error) {
 		return hcloud.NetworkCreateOpts{}, fmt.Errorf("invalid network %q: %w", spec.CIDRBlock, err)
 	}
 
-	_, subnet, err := net.ParseCIDR(spec.SubnetCIDRBlock)
+	subnets, err := subnetsFromSpec(spec.SubnetSpecs())
+	if err != nil {
+		return hcloud.NetworkCreateOpts{}, err
+	}
+
+	routes, err := routesFromSpec(spec.Routes)
 	if err != nil {
-		return hcloud.NetworkCreateOpts{}, fmt.Errorf("invalid network %q: %w", spec.SubnetCIDRBlock, err)
+		return hcloud.NetworkCreateOpts{}, err
 	}
 
 	return hcloud.NetworkCreateOpts{
 		Name:    s.scope.HetznerCluster.Name,
 		IPRange: network,
 		Labels:  s.labels(),
-		Subnets: []hcloud.NetworkSubnet{
-			{
-				IPRange:     subnet,
-				NetworkZone: hcloud.NetworkZone(spec.NetworkZone),
-				Type:        hcloud.NetworkSubnetTypeCloud,
-			},
-		},
+		Subnets: subnets,
+		Routes:  routes,
 	}, nil
 }
 
+func subnetsFromSpec(specs []infrav1.HCloudNetworkSubnetSpec) ([]hcloud.NetworkSubnet, error) {
+	subnets := make([]hcloud.NetworkSubnet, 0, len(specs))
+	for _, spec := range specs {
+		_, ipRange, err := net.ParseCIDR(spec.IPRange)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q: %w", spec.IPRange, err)
+		}
+
+		subnetType := spec.Type
+		if subnetType == "" {
+			subnetType = infrav1.HCloudNetworkSubnetTypeCloud
+		}
+
+		subnet := hcloud.NetworkSubnet{
+			IPRange:     ipRange,
+			NetworkZone: hcloud.NetworkZone(spec.NetworkZone),
+			Type:        hcloud.NetworkSubnetType(subnetType),
+		}
+		if spec.VSwitchID != nil {
+			subnet.VSwitchID = *spec.VSwitchID
+		}
+		subnets = append(subnets, subnet)
+	}
+	return subnets, nil
+}
+
+func routesFromSpec(specs []infrav1.HCloudNetworkRouteSpec) ([]hcloud.NetworkRoute, error) {
+	var routes []hcloud.NetworkRoute
+	for _, spec := range specs {
+		_, destination, err := net.ParseCIDR(spec.Destination)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route destination %q: %w", spec.Destination, err)
+		}
+
+		gateway := net.ParseIP(spec.Gateway)
+		if gateway == nil {
+			return nil, fmt.Errorf("invalid route gateway %q", spec.Gateway)
+		}
+
+		routes = append(routes, hcloud.NetworkRoute{
+			Destination: destination,
+			Gateway:     gateway,
+		})
+	}
+	return routes, nil
+}
+
 // Delete implements deletion of the network.
 func (s *Service) Delete(ctx context.Context) error {
 	if s.scope.HetznerCluster.Status.Network == nil {
@@ -139,6 +263,12 @@ func (s *Service) Delete(ctx context.Context) error {
 
 	id := s.scope.HetznerCluster.Status.Network.ID
 
+	// an adopted network is not owned by the cluster
+	if s.scope.HetznerCluster.Status.Network.Unmanaged {
+		s.scope.V(1).Info("not deleting unmanaged network", "id", id)
+		return nil
+	}
+
 	if err := s.scope.HCloudClient.DeleteNetwork(ctx, &hcloud.Network{ID: id}); err != nil {
 		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteNetwork")
 		// if resource has been deleted already then do nothing
@@ -172,8 +302,36 @@ func (s *Service) findNetwork(ctx context.Context) (*hcloud.Network, error) {
 		return nil, nil
 	}
 
-	if len(networks[0].Subnets) > 1 {
-		return nil, fmt.Errorf("multiple subnets not allowed")
+	return networks[0], nil
+}
+
+// findAdoptedNetwork finds the existing network that is specified by ID or label selector.
+func (s *Service) findAdoptedNetwork(ctx context.Context) (*hcloud.Network, error) {
+	spec := s.scope.HetznerCluster.Spec.HCloudNetwork
+
+	if spec.ID != nil {
+		network, err := s.scope.HCloudClient.GetNetwork(ctx, *spec.ID)
+		if err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "GetNetwork")
+			return nil, fmt.Errorf("failed to get network with ID %d: %w", *spec.ID, err)
+		}
+		if network == nil {
+			return nil, fmt.Errorf("network with ID %d not found", *spec.ID)
+		}
+		return network, nil
+	}
+
+	opts := hcloud.NetworkListOpts{}
+	opts.LabelSelector = utils.LabelsToLabelSelector(spec.LabelSelector)
+
+	networks, err := s.scope.HCloudClient.ListNetworks(ctx, opts)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ListNetworks")
+		return nil, fmt.Errorf("failed to list networks: %w", err)
+	}
+
+	if len(networks) != 1 {
+		return nil, fmt.Errorf("found %d networks with label selector %q - exactly one is required", len(networks), opts.LabelSelector)
 	}
 
 	return networks[0], nil
@@ -188,10 +346,39 @@ func statusFromHCloudNetwork(network *hcloud.Network) *infrav1.NetworkStatus {
 	// deterministic order to avoid unnecessary updates to the HetznerCluster resource.
 	slices.Sort(attachedServerIDs)
 
+	var subnets []infrav1.NetworkSubnetStatus
+	for _, subnet := range network.Subnets {
+		status := infrav1.NetworkSubnetStatus{
+			Type:        infrav1.HCloudNetworkSubnetType(subnet.Type),
+			NetworkZone: infrav1.HCloudNetworkZone(subnet.NetworkZone),
+			VSwitchID:   subnet.VSwitchID,
+		}
+		if subnet.IPRange != nil {
+			status.IPRange = subnet.IPRange.String()
+		}
+		if subnet.Gateway != nil {
+			status.Gateway = subnet.Gateway.String()
+		}
+		subnets = append(subnets, status)
+	}
+
+	var routes []infrav1.HCloudNetworkRouteSpec
+	for _, route := range network.Routes {
+		if route.Destination == nil {
+			continue
+		}
+		routes = append(routes, infrav1.HCloudNetworkRouteSpec{
+			Destination: route.Destination.String(),
+			Gateway:     route.Gateway.String(),
+		})
+	}
+
 	return &infrav1.NetworkStatus{
 		ID:              network.ID,
 		Labels:          network.Labels,
 		AttachedServers: attachedServerIDs,
+		Subnets:         subnets,
+		Routes:          routes,
 	}
 }
 
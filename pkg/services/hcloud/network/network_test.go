@@ -17,6 +17,7 @@ limitations under the License.
 package network
 
 import (
+	"context"
 	"net"
 	"testing"
 
@@ -24,8 +25,12 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"k8s.io/utils/ptr"
+
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
 )
 
 func TestNetwork(t *testing.T) {
@@ -84,3 +89,156 @@ var _ = Describe("Test createOpts", func() {
 		Expect(err).ToNot(BeNil())
 	})
 })
+
+var _ = Describe("Test createOpts with multiple subnets and routes", func() {
+	var hetznerCluster infrav1.HetznerCluster
+	var service Service
+	BeforeEach(func() {
+		hetznerCluster.Spec.HCloudNetwork = infrav1.HCloudNetworkSpec{
+			Enabled:         true,
+			CIDRBlock:       "10.0.0.0/16",
+			SubnetCIDRBlock: "10.0.0.0/24",
+			NetworkZone:     "eu-central",
+			Subnets: []infrav1.HCloudNetworkSubnetSpec{
+				{NetworkZone: "eu-central", IPRange: "10.0.1.0/24"},
+				{Type: "cloud", NetworkZone: "us-east", IPRange: "10.0.2.0/24"},
+				{Type: "vswitch", NetworkZone: "eu-central", IPRange: "10.0.3.0/24", VSwitchID: ptr.To[int64](42)},
+			},
+			Routes: []infrav1.HCloudNetworkRouteSpec{
+				{Destination: "10.100.0.0/16", Gateway: "10.0.1.1"},
+			},
+		}
+		hetznerCluster.Name = "hetzner-cluster"
+
+		service = Service{&scope.ClusterScope{HetznerCluster: &hetznerCluster}}
+	})
+
+	It("uses the subnets instead of SubnetCIDRBlock", func() {
+		opts, err := service.createOpts()
+		Expect(err).To(BeNil())
+
+		Expect(opts.Subnets).To(HaveLen(3))
+		Expect(opts.Subnets[0].IPRange.String()).To(Equal("10.0.1.0/24"))
+		Expect(opts.Subnets[0].Type).To(Equal(hcloud.NetworkSubnetTypeCloud))
+		Expect(opts.Subnets[1].NetworkZone).To(Equal(hcloud.NetworkZoneUSEast))
+		Expect(opts.Subnets[2].Type).To(Equal(hcloud.NetworkSubnetTypeVSwitch))
+		Expect(opts.Subnets[2].VSwitchID).To(Equal(int64(42)))
+
+		Expect(opts.Routes).To(HaveLen(1))
+		Expect(opts.Routes[0].Destination.String()).To(Equal("10.100.0.0/16"))
+		Expect(opts.Routes[0].Gateway.String()).To(Equal("10.0.1.1"))
+	})
+
+	It("gives an error with wrong route gateway", func() {
+		hetznerCluster.Spec.HCloudNetwork.Routes[0].Gateway = "invalid-ip"
+		_, err := service.createOpts()
+		Expect(err).ToNot(BeNil())
+	})
+})
+
+var _ = Describe("Test Reconcile", func() {
+	var hetznerCluster *infrav1.HetznerCluster
+	var service *Service
+	ctx := context.Background()
+
+	var hcloudClient hcloudclient.Client
+
+	BeforeEach(func() {
+		hcloudClient = fakeclient.NewHCloudClientFactory().NewClient("")
+		hcloudClient.Reset()
+
+		hetznerCluster = &infrav1.HetznerCluster{}
+		hetznerCluster.Name = "hetzner-cluster"
+		hetznerCluster.Spec.HCloudNetwork = infrav1.HCloudNetworkSpec{
+			Enabled:         true,
+			CIDRBlock:       "10.0.0.0/16",
+			SubnetCIDRBlock: "10.0.0.0/24",
+			NetworkZone:     "eu-central",
+		}
+
+		service = NewService(&scope.ClusterScope{HCloudClient: hcloudClient, HetznerCluster: hetznerCluster})
+	})
+
+	It("adds subnets and routes to an existing network", func() {
+		Expect(service.Reconcile(ctx)).To(Succeed())
+		Expect(hetznerCluster.Status.Network.Subnets).To(HaveLen(1))
+		Expect(hetznerCluster.Status.Network.Routes).To(BeEmpty())
+
+		hetznerCluster.Spec.HCloudNetwork.Subnets = []infrav1.HCloudNetworkSubnetSpec{
+			{NetworkZone: "eu-central", IPRange: "10.0.0.0/24"},
+			{NetworkZone: "us-east", IPRange: "10.0.1.0/24"},
+		}
+		hetznerCluster.Spec.HCloudNetwork.Routes = []infrav1.HCloudNetworkRouteSpec{
+			{Destination: "10.100.0.0/16", Gateway: "10.0.0.2"},
+		}
+		Expect(service.Reconcile(ctx)).To(Succeed())
+
+		Expect(hetznerCluster.Status.Network.Subnets).To(Equal([]infrav1.NetworkSubnetStatus{
+			{Type: "cloud", NetworkZone: "eu-central", IPRange: "10.0.0.0/24"},
+			{Type: "cloud", NetworkZone: "us-east", IPRange: "10.0.1.0/24"},
+		}))
+		Expect(hetznerCluster.Status.Network.Routes).To(Equal([]infrav1.HCloudNetworkRouteSpec{
+			{Destination: "10.100.0.0/16", Gateway: "10.0.0.2"},
+		}))
+
+		// reconciling again does not change anything
+		Expect(service.Reconcile(ctx)).To(Succeed())
+		Expect(hetznerCluster.Status.Network.Subnets).To(HaveLen(2))
+		Expect(hetznerCluster.Status.Network.Routes).To(HaveLen(1))
+	})
+
+	Context("existing network", func() {
+		var existing *hcloud.Network
+
+		BeforeEach(func() {
+			_, ipRange, err := net.ParseCIDR("10.0.0.0/16")
+			Expect(err).To(BeNil())
+
+			existing, err = hcloudClient.CreateNetwork(ctx, hcloud.NetworkCreateOpts{
+				Name:    "shared",
+				IPRange: ipRange,
+				Labels:  map[string]string{"network": "shared"},
+			})
+			Expect(err).To(BeNil())
+		})
+
+		It("adopts the network by ID and does not delete it", func() {
+			hetznerCluster.Spec.HCloudNetwork.ID = ptr.To(existing.ID)
+
+			Expect(service.Reconcile(ctx)).To(Succeed())
+			Expect(hetznerCluster.Status.Network.ID).To(Equal(existing.ID))
+			Expect(hetznerCluster.Status.Network.Unmanaged).To(BeTrue())
+
+			// no network has been created for the cluster
+			networks, err := hcloudClient.ListNetworks(ctx, hcloud.NetworkListOpts{})
+			Expect(err).To(BeNil())
+			Expect(networks).To(HaveLen(1))
+
+			Expect(service.Delete(ctx)).To(Succeed())
+			network, err := hcloudClient.GetNetwork(ctx, existing.ID)
+			Expect(err).To(BeNil())
+			Expect(network).ToNot(BeNil())
+		})
+
+		It("adopts the network by label selector", func() {
+			hetznerCluster.Spec.HCloudNetwork.LabelSelector = map[string]string{"network": "shared"}
+
+			Expect(service.Reconcile(ctx)).To(Succeed())
+			Expect(hetznerCluster.Status.Network.ID).To(Equal(existing.ID))
+			Expect(hetznerCluster.Status.Network.Unmanaged).To(BeTrue())
+		})
+
+		It("fails if the network does not exist", func() {
+			hetznerCluster.Spec.HCloudNetwork.ID = ptr.To(existing.ID + 1)
+
+			Expect(service.Reconcile(ctx)).ToNot(Succeed())
+			Expect(hetznerCluster.Status.Network).To(BeNil())
+		})
+
+		It("fails if no network matches the label selector", func() {
+			hetznerCluster.Spec.HCloudNetwork.LabelSelector = map[string]string{"network": "other"}
+
+			Expect(service.Reconcile(ctx)).ToNot(Succeed())
+		})
+	})
+})
@@ -21,6 +21,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
@@ -71,18 +73,25 @@ func (s *Service) Reconcile(ctx context.Context) (err error) {
 	placementGroupsStatus := statusFromHCloudPlacementGroups(placementGroups, s.scope.HetznerCluster.Name)
 
 	// Create arrays and maps to make diff
-	placementGroupNamesExisting := make([]string, len(placementGroupsStatus))
-	placementGroupNamesDesired := make([]string, len(placementGroupsSpec))
+	placementGroupNamesExisting := make([]string, 0, len(placementGroupsStatus))
+	placementGroupNamesDesired := make([]string, 0, len(placementGroupsSpec))
 	placementGroupExistingMap := make(map[string]infrav1.HCloudPlacementGroupStatus)
 	placementGroupDesiredMap := make(map[string]infrav1.HCloudPlacementGroupSpec)
 
+	// shards of sharded placement groups are reconciled separately
 	for i, pgSpec := range placementGroupsSpec {
-		placementGroupNamesDesired[i] = pgSpec.Name
+		if pgSpec.Sharded {
+			continue
+		}
+		placementGroupNamesDesired = append(placementGroupNamesDesired, pgSpec.Name)
 		placementGroupDesiredMap[pgSpec.Name] = placementGroupsSpec[i]
 	}
 
 	for i, pgSts := range placementGroupsStatus {
-		placementGroupNamesExisting[i] = pgSts.Name
+		if pgSts.ShardOf != "" {
+			continue
+		}
+		placementGroupNamesExisting = append(placementGroupNamesExisting, pgSts.Name)
 		placementGroupExistingMap[pgSts.Name] = placementGroupsStatus[i]
 	}
 
@@ -116,12 +125,49 @@ func (s *Service) Reconcile(ctx context.Context) (err error) {
 		}
 	}
 
+	inCreation, err := s.countHCloudMachinesInCreation(ctx, placementGroupsSpec, placementGroupsStatus)
+	if err != nil {
+		return fmt.Errorf("failed to count HCloudMachines in creation: %w", err)
+	}
+
+	shardsToCreate, shardsToDelete := shardChanges(placementGroupsSpec, placementGroupsStatus, inCreation)
+
+	// create new shards of sharded placement groups
+	for _, shard := range shardsToCreate {
+		name := fmt.Sprintf("%s-%s", s.scope.HetznerCluster.Name, shard.Name)
+		opts := hcloud.PlacementGroupCreateOpts{
+			Name: name,
+			Type: hcloud.PlacementGroupType(shard.Type),
+			Labels: map[string]string{
+				s.scope.HetznerCluster.ClusterTagKey(): string(infrav1.ResourceLifecycleOwned),
+				infrav1.PlacementGroupShardTagKey:      strconv.Itoa(shard.index),
+			},
+		}
+
+		if _, err := s.scope.HCloudClient.CreatePlacementGroup(ctx, opts); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "CreatePlacementGroup")
+			multierr = errors.Join(multierr, fmt.Errorf("failed to create placement group %q: %w", shard.Name, err))
+			continue
+		}
+		record.Eventf(s.scope.HetznerCluster, "PlacementGroupShardCreated", "Created placement group %s as shard of %s", name, shard.ShardOf)
+	}
+
+	// delete empty shards
+	for _, shard := range shardsToDelete {
+		if err := s.scope.HCloudClient.DeletePlacementGroup(ctx, shard.ID); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeletePlacementGroup")
+			if !hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+				multierr = errors.Join(multierr, fmt.Errorf("failed to delete placement group %v: %w", shard.ID, err))
+			}
+		}
+	}
+
 	if multierr != nil {
 		return fmt.Errorf("aggregate error - creating/deleting placement groups: %w", multierr)
 	}
 
 	// Update status
-	if len(toCreate) > 0 || len(toDelete) > 0 {
+	if len(toCreate) > 0 || len(toDelete) > 0 || len(shardsToCreate) > 0 || len(shardsToDelete) > 0 {
 		// No need to update status if nothing changed
 		placementGroups, err = s.findPlacementGroups(ctx)
 	}
@@ -171,6 +217,35 @@ func (s *Service) findPlacementGroups(ctx context.Context) ([]*hcloud.PlacementG
 	return placementGroups, nil
 }
 
+// countHCloudMachinesInCreation returns the number of HCloudMachines without server per sharded placement group.
+// These machines might have chosen a shard already, so shards are not deleted as long as they exist.
+func (s *Service) countHCloudMachinesInCreation(
+	ctx context.Context,
+	specs []infrav1.HCloudPlacementGroupSpec,
+	statuses []infrav1.HCloudPlacementGroupStatus,
+) (map[string]int, error) {
+	inCreation := make(map[string]int)
+	for _, spec := range specs {
+		if spec.Sharded {
+			inCreation[spec.Name] = 0
+		}
+	}
+	for _, pgSts := range statuses {
+		if pgSts.ShardOf != "" {
+			inCreation[pgSts.ShardOf] = 0
+		}
+	}
+
+	for name := range inCreation {
+		hcloudMachines, err := s.scope.ListHCloudMachinesInCreation(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		inCreation[name] = len(hcloudMachines)
+	}
+	return inCreation, nil
+}
+
 // statusFromHCloudPlacementGroups gets the information of the Hetzner placement groups and returns it in our status object.
 func statusFromHCloudPlacementGroups(placementGroups []*hcloud.PlacementGroup, clusterName string) []infrav1.HCloudPlacementGroupStatus {
 	status := make([]infrav1.HCloudPlacementGroupStatus, len(placementGroups))
@@ -181,6 +256,100 @@ func statusFromHCloudPlacementGroups(placementGroups []*hcloud.PlacementGroup, c
 			Name:   strings.TrimPrefix(pg.Name, clusterName+"-"),
 			Type:   string(pg.Type),
 		}
+		if index, ok := pg.Labels[infrav1.PlacementGroupShardTagKey]; ok {
+			status[i].ShardOf = strings.TrimSuffix(status[i].Name, "-"+index)
+		}
 	}
 	return status
 }
+
+// shard is a shard of a sharded placement group together with its index.
+type shard struct {
+	infrav1.HCloudPlacementGroupStatus
+	index int
+}
+
+// shardChanges returns the shards that have to be created and deleted, so that every sharded placement group
+// has exactly one shard with free capacity, unless several shards are only partially filled or more machines
+// are in creation than servers fit into the shards. Shards of placement groups that are not in the spec anymore
+// are deleted if they are empty. Empty shards are kept as long as machines of the placement group are in creation.
+func shardChanges(
+	specs []infrav1.HCloudPlacementGroupSpec,
+	statuses []infrav1.HCloudPlacementGroupStatus,
+	inCreation map[string]int,
+) (toCreate []shard, toDelete []infrav1.HCloudPlacementGroupStatus) {
+	shardsByName := make(map[string][]shard)
+	for _, pgSts := range statuses {
+		if pgSts.ShardOf == "" {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimPrefix(pgSts.Name, pgSts.ShardOf+"-"))
+		if err != nil {
+			continue
+		}
+		shardsByName[pgSts.ShardOf] = append(shardsByName[pgSts.ShardOf], shard{HCloudPlacementGroupStatus: pgSts, index: index})
+	}
+
+	for name, shards := range shardsByName {
+		if slices.ContainsFunc(specs, func(spec infrav1.HCloudPlacementGroupSpec) bool {
+			return spec.Sharded && spec.Name == name
+		}) || inCreation[name] > 0 {
+			continue
+		}
+		for _, shard := range shards {
+			if len(shard.Server) == 0 {
+				toDelete = append(toDelete, shard.HCloudPlacementGroupStatus)
+			}
+		}
+	}
+
+	for _, spec := range specs {
+		if !spec.Sharded {
+			continue
+		}
+
+		shards := shardsByName[spec.Name]
+		slices.SortFunc(shards, func(a, b shard) int { return a.index - b.index })
+
+		var maxIndex, free int
+		var notFull, empty []shard
+		for _, shard := range shards {
+			maxIndex = max(maxIndex, shard.index)
+			if shard.IsFull() {
+				continue
+			}
+			free += infrav1.MaxServersPerPlacementGroup - len(shard.Server)
+			notFull = append(notFull, shard)
+			if len(shard.Server) == 0 {
+				empty = append(empty, shard)
+			}
+		}
+
+		if len(notFull) == 0 || free < inCreation[spec.Name] {
+			toCreate = append(toCreate, shard{
+				HCloudPlacementGroupStatus: infrav1.HCloudPlacementGroupStatus{
+					Name:    fmt.Sprintf("%s-%d", spec.Name, maxIndex+1),
+					Type:    spec.Type,
+					ShardOf: spec.Name,
+				},
+				index: maxIndex + 1,
+			})
+			continue
+		}
+
+		// machines in creation might have chosen an empty shard already
+		if inCreation[spec.Name] > 0 {
+			continue
+		}
+
+		// keep one empty shard if all other shards are full
+		if len(empty) == len(notFull) {
+			empty = empty[1:]
+		}
+		for _, shard := range empty {
+			toDelete = append(toDelete, shard.HCloudPlacementGroupStatus)
+		}
+	}
+
+	return toCreate, toDelete
+}
@@ -17,10 +17,25 @@ limitations under the License.
 package placementgroup
 
 import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
 )
 
 var _ = Describe("statusFromHCloudPlacementGroups", func() {
@@ -52,3 +67,175 @@ var _ = Describe("statusFromHCloudPlacementGroups", func() {
 		}
 	})
 })
+
+var _ = Describe("shardChanges", func() {
+	full := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	specs := []infrav1.HCloudPlacementGroupSpec{{Name: "workers", Type: "spread", Sharded: true}}
+
+	It("creates the first shard", func() {
+		toCreate, toDelete := shardChanges(specs, nil, nil)
+		Expect(toDelete).To(BeEmpty())
+		Expect(toCreate).To(HaveLen(1))
+		Expect(toCreate[0].Name).To(Equal("workers-1"))
+		Expect(toCreate[0].index).To(Equal(1))
+	})
+
+	It("creates a new shard if all shards are full", func() {
+		toCreate, toDelete := shardChanges(specs, []infrav1.HCloudPlacementGroupStatus{
+			{ID: 1, Name: "workers-1", ShardOf: "workers", Server: full},
+			{ID: 3, Name: "workers-3", ShardOf: "workers", Server: full},
+		}, nil)
+		Expect(toDelete).To(BeEmpty())
+		Expect(toCreate).To(HaveLen(1))
+		Expect(toCreate[0].Name).To(Equal("workers-4"))
+	})
+
+	It("keeps one empty shard if the other shards are full", func() {
+		toCreate, toDelete := shardChanges(specs, []infrav1.HCloudPlacementGroupStatus{
+			{ID: 1, Name: "workers-1", ShardOf: "workers", Server: full},
+			{ID: 2, Name: "workers-2", ShardOf: "workers"},
+			{ID: 3, Name: "workers-3", ShardOf: "workers"},
+		}, nil)
+		Expect(toCreate).To(BeEmpty())
+		Expect(toDelete).To(HaveLen(1))
+		Expect(toDelete[0].ID).To(Equal(int64(3)))
+	})
+
+	It("deletes empty shards if another shard has free capacity", func() {
+		toCreate, toDelete := shardChanges(specs, []infrav1.HCloudPlacementGroupStatus{
+			{ID: 1, Name: "workers-1", ShardOf: "workers", Server: []int64{1}},
+			{ID: 2, Name: "workers-2", ShardOf: "workers"},
+		}, nil)
+		Expect(toCreate).To(BeEmpty())
+		Expect(toDelete).To(HaveLen(1))
+		Expect(toDelete[0].ID).To(Equal(int64(2)))
+	})
+
+	It("keeps empty shards while machines are in creation", func() {
+		toCreate, toDelete := shardChanges(specs, []infrav1.HCloudPlacementGroupStatus{
+			{ID: 1, Name: "workers-1", ShardOf: "workers", Server: []int64{1}},
+			{ID: 2, Name: "workers-2", ShardOf: "workers"},
+		}, map[string]int{"workers": 1})
+		Expect(toCreate).To(BeEmpty())
+		Expect(toDelete).To(BeEmpty())
+	})
+
+	It("creates a new shard if more machines are in creation than servers fit into the shards", func() {
+		toCreate, toDelete := shardChanges(specs, []infrav1.HCloudPlacementGroupStatus{
+			{ID: 1, Name: "workers-1", ShardOf: "workers", Server: full[:8]},
+		}, map[string]int{"workers": 3})
+		Expect(toDelete).To(BeEmpty())
+		Expect(toCreate).To(HaveLen(1))
+		Expect(toCreate[0].Name).To(Equal("workers-2"))
+	})
+
+	It("deletes empty shards of placement groups that are removed from the spec", func() {
+		toCreate, toDelete := shardChanges(nil, []infrav1.HCloudPlacementGroupStatus{
+			{ID: 1, Name: "workers-1", ShardOf: "workers", Server: []int64{1}},
+			{ID: 2, Name: "workers-2", ShardOf: "workers"},
+			{ID: 3, Name: "control-plane"},
+		}, nil)
+		Expect(toCreate).To(BeEmpty())
+		Expect(toDelete).To(HaveLen(1))
+		Expect(toDelete[0].ID).To(Equal(int64(2)))
+	})
+})
+
+var _ = Describe("Reconcile sharded placement groups", func() {
+	var (
+		ctx            context.Context
+		c              client.Client
+		hcloudClient   hcloudclient.Client
+		hetznerCluster *infrav1.HetznerCluster
+		service        *Service
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		hcloudClient = fakeclient.NewHCloudClientFactory().NewClient("")
+		hcloudClient.Reset()
+
+		hetznerCluster = &infrav1.HetznerCluster{
+			Spec: infrav1.HetznerClusterSpec{
+				HCloudPlacementGroups: []infrav1.HCloudPlacementGroupSpec{
+					{Name: "control-plane", Type: "spread"},
+					{Name: "workers", Type: "spread", Sharded: true},
+				},
+			},
+		}
+		hetznerCluster.Name = "hetzner-cluster"
+
+		scheme := runtime.NewScheme()
+		utilruntime.Must(infrav1.AddToScheme(scheme))
+		c = fakectrlclient.NewClientBuilder().WithScheme(scheme).Build()
+
+		service = NewService(&scope.ClusterScope{
+			Client:         c,
+			HCloudClient:   hcloudClient,
+			Cluster:        &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+			HetznerCluster: hetznerCluster,
+		})
+	})
+
+	It("reports the shards and their servers in the status", func() {
+		Expect(service.Reconcile(ctx)).To(Succeed())
+
+		statuses := hetznerCluster.Status.HCloudPlacementGroups
+		Expect(statuses).To(HaveLen(2))
+		shard := statuses[slices.IndexFunc(statuses, func(pg infrav1.HCloudPlacementGroupStatus) bool { return pg.ShardOf == "workers" })]
+		Expect(shard.Name).To(Equal("workers-1"))
+
+		// fill the shard
+		for i := range infrav1.MaxServersPerPlacementGroup {
+			_, err := hcloudClient.CreateServer(ctx, hcloud.ServerCreateOpts{
+				Name:           fmt.Sprintf("worker-%d", i),
+				PlacementGroup: &hcloud.PlacementGroup{ID: shard.ID},
+			})
+			Expect(err).To(BeNil())
+		}
+
+		Expect(service.Reconcile(ctx)).To(Succeed())
+
+		statuses = hetznerCluster.Status.HCloudPlacementGroups
+		Expect(statuses).To(HaveLen(3))
+		for _, pg := range statuses {
+			switch pg.Name {
+			case "workers-1":
+				Expect(pg.ShardOf).To(Equal("workers"))
+				Expect(pg.Server).To(HaveLen(infrav1.MaxServersPerPlacementGroup))
+			case "workers-2":
+				Expect(pg.ShardOf).To(Equal("workers"))
+				Expect(pg.Server).To(BeEmpty())
+			default:
+				Expect(pg.Name).To(Equal("control-plane"))
+				Expect(pg.ShardOf).To(BeEmpty())
+			}
+		}
+	})
+	It("keeps an empty shard while a machine of the placement group is in creation", func() {
+		Expect(service.Reconcile(ctx)).To(Succeed())
+		Expect(hetznerCluster.Status.HCloudPlacementGroups).To(HaveLen(2))
+
+		Expect(c.Create(ctx, &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "worker",
+				Labels: map[string]string{clusterv1.ClusterNameLabel: "cluster"},
+			},
+			Spec: infrav1.HCloudMachineSpec{PlacementGroupName: ptr.To("workers")},
+		})).To(Succeed())
+
+		// the machine might have chosen the shard already
+		hetznerCluster.Spec.HCloudPlacementGroups = hetznerCluster.Spec.HCloudPlacementGroups[:1]
+		Expect(service.Reconcile(ctx)).To(Succeed())
+		Expect(hetznerCluster.Status.HCloudPlacementGroups).To(HaveLen(2))
+
+		// the empty shard is deleted once the machine has a server
+		hcloudMachine := &infrav1.HCloudMachine{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: "worker"}, hcloudMachine)).To(Succeed())
+		hcloudMachine.Spec.ProviderID = ptr.To("hcloud://1")
+		Expect(c.Update(ctx, hcloudMachine)).To(Succeed())
+
+		Expect(service.Reconcile(ctx)).To(Succeed())
+		Expect(hetznerCluster.Status.HCloudPlacementGroups).To(HaveLen(1))
+	})
+})
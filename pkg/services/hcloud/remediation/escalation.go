@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	hcloudutil "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/util"
+)
+
+// powerCycleRequeueAfter is the interval in which a power cycle is checked until the server is powered on again.
+const powerCycleRequeueAfter = 10 * time.Second
+
+// handleEscalation executes the steps of an escalating remediation one after another. Each step is retried until
+// its retry limit is reached before the next step is executed. If the machine is still unhealthy after the last
+// step, it is handed back to CAPI for replacement. Once the machine is healthy again, CAPI deletes the remediation.
+func (s *Service) handleEscalation(ctx context.Context, server *hcloud.Server) (reconcile.Result, error) {
+	remediation := s.scope.HCloudRemediation
+	strategy := remediation.Spec.Strategy
+	steps := strategy.EscalationSteps(infrav1.DefaultHCloudRemediationSteps)
+
+	if remediation.Status.CurrentStep >= len(steps) {
+		return reconcile.Result{}, s.replaceMachine(ctx, "all remediation steps have been executed")
+	}
+
+	step := steps[remediation.Status.CurrentStep]
+	now := time.Now()
+
+	if remediation.Status.LastRemediated != nil {
+		if step.Type == infrav1.RemediationStepTypePowerCycle {
+			finished, err := s.completePowerCycle(ctx, server)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			if !finished {
+				return reconcile.Result{RequeueAfter: powerCycleRequeueAfter}, nil
+			}
+		}
+
+		// give the machine the time of the step to become healthy
+		if wait := remediation.Status.LastRemediated.Add(strategy.StepTimeout(step)).Sub(now); wait > 0 {
+			return reconcile.Result{RequeueAfter: wait + time.Second}, nil
+		}
+	}
+
+	// escalate to the next step if the current one has been executed often enough
+	if remediation.Status.RetryCount >= step.Attempts() {
+		remediation.Status.CurrentStep++
+		remediation.Status.RetryCount = 0
+		if remediation.Status.CurrentStep >= len(steps) {
+			return reconcile.Result{}, s.replaceMachine(ctx, "machine is still unhealthy after all remediation steps")
+		}
+		step = steps[remediation.Status.CurrentStep]
+	}
+
+	// a rebuilt server boots with the user data it has been created with, which must not be replayed in some cases
+	if reason := s.scope.HCloudMachine.BootstrapDataNotReplayableReason(); step.Type == infrav1.RemediationStepTypeRebuild && reason != "" {
+		record.Warnf(s.scope.HCloudRemediation, "SkippedRebuildServer", "Cannot rebuild server %s, because %s", server.Name, reason)
+		return reconcile.Result{}, s.replaceMachine(ctx, "the server cannot be rebuilt")
+	}
+
+	if err := s.executeStep(ctx, server, step.Type); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	remediation.Status.RetryCount++
+	remediation.Status.LastRemediated = &metav1.Time{Time: now}
+
+	stepStatus := infrav1.RemediationStepStatus{
+		Type:    step.Type,
+		Attempt: remediation.Status.RetryCount,
+		Started: metav1.Time{Time: now},
+	}
+	if step.Type != infrav1.RemediationStepTypePowerCycle {
+		stepStatus.Finished = &stepStatus.Started
+	}
+	remediation.Status.Steps = append(remediation.Status.Steps, stepStatus)
+
+	if step.Type == infrav1.RemediationStepTypePowerCycle {
+		return reconcile.Result{RequeueAfter: powerCycleRequeueAfter}, nil
+	}
+	return reconcile.Result{RequeueAfter: strategy.StepTimeout(step) + time.Second}, nil
+}
+
+// executeStep executes the action of a remediation step on the server.
+func (s *Service) executeStep(ctx context.Context, server *hcloud.Server, stepType infrav1.RemediationStepType) error {
+	switch stepType {
+	case infrav1.RemediationStepTypeReboot:
+		if err := s.scope.HCloudClient.RebootServer(ctx, server); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HCloudMachine, err, "RebootServer")
+			record.Warn(s.scope.HCloudRemediation, "FailedRebootServer", err.Error())
+			return fmt.Errorf("failed to reboot server %s with ID %d: %w", server.Name, server.ID, err)
+		}
+		record.Event(s.scope.HCloudRemediation, "ServerRebooted", "Server has been rebooted")
+
+	case infrav1.RemediationStepTypePowerCycle:
+		if err := s.scope.HCloudClient.ShutdownServer(ctx, server); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HCloudMachine, err, "ShutdownServer")
+			record.Warn(s.scope.HCloudRemediation, "FailedShutdownServer", err.Error())
+			return fmt.Errorf("failed to shut down server %s with ID %d: %w", server.Name, server.ID, err)
+		}
+		record.Event(s.scope.HCloudRemediation, "ServerShutdown", "Server is shut down for a power cycle")
+
+	case infrav1.RemediationStepTypeRebuild:
+		image := server.Image
+		if image == nil && s.scope.HCloudMachine.Status.ImageID != 0 {
+			image = &hcloud.Image{ID: s.scope.HCloudMachine.Status.ImageID}
+		}
+		if image == nil {
+			record.Warnf(s.scope.HCloudRemediation, "SkippedRebuildServer", "Cannot rebuild server %s as its image is unknown", server.Name)
+			return nil
+		}
+
+		if err := s.scope.HCloudClient.RebuildServer(ctx, server, hcloud.ServerRebuildOpts{Image: image}); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HCloudMachine, err, "RebuildServer")
+			record.Warn(s.scope.HCloudRemediation, "FailedRebuildServer", err.Error())
+			return fmt.Errorf("failed to rebuild server %s with ID %d: %w", server.Name, server.ID, err)
+		}
+		record.Eventf(s.scope.HCloudRemediation, "ServerRebuilt", "Server has been rebuilt with image %d", image.ID)
+
+	default:
+		return fmt.Errorf("unsupported remediation step %q", stepType)
+	}
+	return nil
+}
+
+// completePowerCycle powers the server on once it is off. If the operating system does not shut down within the
+// graceful shutdown timeout of the machine, the server is powered off. It returns whether the power cycle is finished.
+func (s *Service) completePowerCycle(ctx context.Context, server *hcloud.Server) (bool, error) {
+	remediation := s.scope.HCloudRemediation
+	if len(remediation.Status.Steps) == 0 {
+		return true, nil
+	}
+	stepStatus := &remediation.Status.Steps[len(remediation.Status.Steps)-1]
+	if stepStatus.Finished != nil {
+		return true, nil
+	}
+
+	switch server.Status {
+	case hcloud.ServerStatusOff:
+		if err := s.scope.HCloudClient.PowerOnServer(ctx, server); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HCloudMachine, err, "PowerOnServer")
+			record.Warn(s.scope.HCloudRemediation, "FailedPowerOnServer", err.Error())
+			return false, fmt.Errorf("failed to power on server %s with ID %d: %w", server.Name, server.ID, err)
+		}
+		record.Event(s.scope.HCloudRemediation, "ServerPoweredOn", "Server has been powered on after a power cycle")
+
+		now := metav1.Now()
+		stepStatus.Finished = &now
+		// the timeout of the step starts once the server is on again
+		remediation.Status.LastRemediated = &now
+		return true, nil
+
+	case hcloud.ServerStatusRunning:
+		if time.Since(stepStatus.Started.Time) < s.scope.HCloudMachine.Spec.GracefulShutdownTimeout() {
+			return false, nil
+		}
+		if err := s.scope.HCloudClient.PowerOffServer(ctx, server); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HCloudMachine, err, "PowerOffServer")
+			record.Warn(s.scope.HCloudRemediation, "FailedPowerOffServer", err.Error())
+			return false, fmt.Errorf("failed to power off server %s with ID %d: %w", server.Name, server.ID, err)
+		}
+		record.Event(s.scope.HCloudRemediation, "ServerPoweredOff", "Server has been powered off as it did not shut down in time")
+		return false, nil
+
+	default:
+		// the server is stopping or starting
+		return false, nil
+	}
+}
+
+// replaceMachine hands the machine back to CAPI, which replaces it.
+func (s *Service) replaceMachine(ctx context.Context, reason string) error {
+	s.scope.HCloudRemediation.Status.Phase = infrav1.PhaseDeleting
+
+	if err := s.setOwnerRemediatedCondition(ctx); err != nil {
+		record.Warn(s.scope.HCloudRemediation, "FailedSettingConditionOnMachine", err.Error())
+		return fmt.Errorf("failed to set conditions on CAPI machine: %w", err)
+	}
+	record.Eventf(s.scope.HCloudRemediation, "SetOwnerRemediatedCondition", "exit remediation because %s", reason)
+	return nil
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remediation
+
+import (
+	"context"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+)
+
+var _ = Describe("Escalating remediation", func() {
+	var (
+		ctx           context.Context
+		client        hcloudclient.Client
+		remediation   *infrav1.HCloudRemediation
+		machine       *clusterv1.Machine
+		hcloudMachine *infrav1.HCloudMachine
+		server        *hcloud.Server
+		service       *Service
+	)
+
+	// expireStep lets the timeout of the last executed step pass.
+	expireStep := func() {
+		remediation.Status.LastRemediated = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	}
+
+	getServer := func() *hcloud.Server {
+		s, err := client.GetServer(ctx, server.ID)
+		Expect(err).To(Succeed())
+		return s
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "remediation-machine", Namespace: "default"},
+		}
+		remediation = &infrav1.HCloudRemediation{
+			ObjectMeta: metav1.ObjectMeta{Name: "remediation-machine", Namespace: "default"},
+			Spec: infrav1.HCloudRemediationSpec{
+				Strategy: &infrav1.RemediationStrategy{
+					Type:    infrav1.RemediationTypeEscalate,
+					Timeout: &metav1.Duration{Duration: 5 * time.Minute},
+				},
+			},
+		}
+
+		service, machine, server = newTestService(hcloudMachine, remediation, hcloud.ServerCreateOpts{
+			Image: &hcloud.Image{ID: 42, Name: "my-image"},
+		})
+		client = service.scope.HCloudClient
+	})
+
+	It("escalates from reboot to power cycle and hands the machine back to CAPI", func() {
+		res, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(BeNumerically(">", 5*time.Minute))
+		Expect(remediation.Status.Phase).To(Equal(infrav1.PhaseRunning))
+		Expect(remediation.Status.Steps).To(HaveLen(1))
+		Expect(remediation.Status.Steps[0].Type).To(Equal(infrav1.RemediationStepTypeReboot))
+
+		// nothing happens before the step timed out
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.Steps).To(HaveLen(1))
+
+		// power cycle
+		expireStep()
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.CurrentStep).To(Equal(1))
+		Expect(remediation.Status.Steps).To(HaveLen(2))
+		Expect(remediation.Status.Steps[1].Type).To(Equal(infrav1.RemediationStepTypePowerCycle))
+		Expect(remediation.Status.Steps[1].Finished).To(BeNil())
+		Expect(getServer().Status).To(Equal(hcloud.ServerStatusOff))
+
+		res, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(getServer().Status).To(Equal(hcloud.ServerStatusRunning))
+		Expect(remediation.Status.Steps[1].Finished).ToNot(BeNil())
+		Expect(res.RequeueAfter).To(BeNumerically(">", 4*time.Minute))
+
+		// replace
+		expireStep()
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.Phase).To(Equal(infrav1.PhaseDeleting))
+		Expect(conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition)).To(BeTrue())
+	})
+
+	It("rebuilds the server with its image", func() {
+		remediation.Spec.Strategy.Steps = []infrav1.RemediationStep{{Type: infrav1.RemediationStepTypeRebuild}}
+		hcloudMachine.Spec.ReplayableBootstrapData = true
+
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.Steps).To(HaveLen(1))
+		Expect(remediation.Status.Steps[0].Type).To(Equal(infrav1.RemediationStepTypeRebuild))
+		Expect(getServer().Status).To(Equal(hcloud.ServerStatusRebuilding))
+		Expect(getServer().Image.ID).To(Equal(int64(42)))
+	})
+
+	It("replaces the machine instead of rebuilding a server whose bootstrap data cannot be run again", func() {
+		remediation.Spec.Strategy.Steps = []infrav1.RemediationStep{
+			{Type: infrav1.RemediationStepTypeReboot},
+			{Type: infrav1.RemediationStepTypeRebuild},
+		}
+		hcloudMachine.Spec.ReplayableBootstrapData = true
+		hcloudMachine.Status.BootstrapDataNotReplayable = "the bootstrap data initializes the cluster"
+
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.Steps).To(HaveLen(1))
+
+		expireStep()
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.Steps).To(HaveLen(1))
+		Expect(remediation.Status.Phase).To(Equal(infrav1.PhaseDeleting))
+		Expect(conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition)).To(BeTrue())
+		Expect(getServer().Status).To(Equal(hcloud.ServerStatusRunning))
+	})
+
+	It("replaces the machine instead of rebuilding a server whose bootstrap data has not been declared replayable", func() {
+		remediation.Spec.Strategy.Steps = []infrav1.RemediationStep{{Type: infrav1.RemediationStepTypeRebuild}}
+
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.Steps).To(BeEmpty())
+		Expect(remediation.Status.Phase).To(Equal(infrav1.PhaseDeleting))
+		Expect(getServer().Status).To(Equal(hcloud.ServerStatusRunning))
+	})
+
+	It("retries a step up to its retry limit", func() {
+		remediation.Spec.Strategy.Steps = []infrav1.RemediationStep{
+			{Type: infrav1.RemediationStepTypeReboot, RetryLimit: 2, Timeout: &metav1.Duration{Duration: time.Minute}},
+		}
+
+		res, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(BeNumerically("<=", time.Minute+time.Second))
+
+		expireStep()
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.Steps).To(HaveLen(2))
+		Expect(remediation.Status.Steps[1].Attempt).To(Equal(2))
+		Expect(remediation.Status.Phase).To(Equal(infrav1.PhaseRunning))
+
+		expireStep()
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.Steps).To(HaveLen(2))
+		Expect(remediation.Status.Phase).To(Equal(infrav1.PhaseDeleting))
+	})
+
+	It("powers the server off if it does not shut down in time", func() {
+		remediation.Spec.Strategy.Steps = []infrav1.RemediationStep{{Type: infrav1.RemediationStepTypePowerCycle}}
+
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+
+		// the operating system ignored the shutdown request
+		Expect(client.PowerOnServer(ctx, server)).To(Succeed())
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(getServer().Status).To(Equal(hcloud.ServerStatusRunning))
+
+		remediation.Status.Steps[0].Started = metav1.Time{Time: time.Now().Add(-time.Hour)}
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(getServer().Status).To(Equal(hcloud.ServerStatusOff))
+		Expect(remediation.Status.Steps[0].Finished).To(BeNil())
+
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(getServer().Status).To(Equal(hcloud.ServerStatusRunning))
+		Expect(remediation.Status.Steps[0].Finished).ToNot(BeNil())
+	})
+
+	It("hands the machine back to CAPI right away with the strategy Replace", func() {
+		remediation.Spec.Strategy.Type = infrav1.RemediationTypeReplace
+
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.Phase).To(Equal(infrav1.PhaseDeleting))
+		Expect(remediation.Status.Steps).To(BeEmpty())
+		Expect(conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition)).To(BeTrue())
+
+		By("not handing the machine back again")
+		conditions.Delete(machine, clusterv1.MachineOwnerRemediatedCondition)
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(conditions.Has(machine, clusterv1.MachineOwnerRemediatedCondition)).To(BeFalse())
+	})
+})
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+// skipRemediation runs the health probe of the remediation strategy before any action of the remediation. If the
+// server is running and all probed ports are reachable, the machine is only unreachable from the management cluster,
+// so the remediation is annotated and the action is skipped.
+func (s *Service) skipRemediation(ctx context.Context, server *hcloud.Server) bool {
+	remediation := s.scope.HCloudRemediation
+	probe := remediation.Spec.Strategy.HealthProbe
+	if probe == nil || remediation.Status.Phase == infrav1.PhaseDeleting || s.stepInProgress() {
+		return false
+	}
+
+	reachable, msg := s.probeServer(ctx, server, probe)
+	if !reachable {
+		if _, found := remediation.Annotations[infrav1.RemediationSkippedAnnotation]; found {
+			delete(remediation.Annotations, infrav1.RemediationSkippedAnnotation)
+			record.Eventf(remediation, "HealthProbeFailed", "Continue remediation because %s", msg)
+		}
+		return false
+	}
+
+	if remediation.Annotations == nil {
+		remediation.Annotations = make(map[string]string)
+	}
+	remediation.Annotations[infrav1.RemediationSkippedAnnotation] = fmt.Sprintf("%s: %s", time.Now().UTC().Format(time.RFC3339), msg)
+	record.Eventf(remediation, "RemediationSkipped", "Skipped remediation because %s", msg)
+	return true
+}
+
+// probeServer returns whether the server is reachable from the provider side and a description of the result.
+func (s *Service) probeServer(ctx context.Context, server *hcloud.Server, probe *infrav1.RemediationHealthProbe) (bool, string) {
+	if server.Status != hcloud.ServerStatusRunning {
+		return false, fmt.Sprintf("server has status %s", server.Status)
+	}
+	if len(probe.TCPPorts) == 0 {
+		// a running server alone does not show that the node works
+		return false, "health probe has no ports"
+	}
+
+	address := machineAddress(s.scope.HCloudMachine.Status.Addresses)
+	if address == "" {
+		return false, "server has no address to probe"
+	}
+
+	if unreachable := utils.UnreachableTCPPorts(ctx, address, probe.TCPPorts, probe.ConnectTimeout()); len(unreachable) > 0 {
+		return false, fmt.Sprintf("ports %v of %s are unreachable", unreachable, address)
+	}
+	return true, fmt.Sprintf("server is running and ports %v of %s are reachable", probe.TCPPorts, address)
+}
+
+// stepInProgress returns whether a step of an escalating remediation, e.g. a power cycle, has not been completed.
+func (s *Service) stepInProgress() bool {
+	steps := s.scope.HCloudRemediation.Status.Steps
+	return len(steps) > 0 && steps[len(steps)-1].Finished == nil
+}
+
+// machineAddress returns the first external address of the machine or, if there is none, the first internal one.
+func machineAddress(addresses []clusterv1.MachineAddress) string {
+	for _, addressType := range []clusterv1.MachineAddressType{clusterv1.MachineExternalIP, clusterv1.MachineInternalIP} {
+		for _, address := range addresses {
+			if address.Type == addressType && address.Address != "" {
+				return address.Address
+			}
+		}
+	}
+	return ""
+}
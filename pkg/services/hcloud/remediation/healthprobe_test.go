@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remediation
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+)
+
+var _ = Describe("Health probe", func() {
+	var (
+		ctx         context.Context
+		client      hcloudclient.Client
+		remediation *infrav1.HCloudRemediation
+		server      *hcloud.Server
+		listener    net.Listener
+		port        int
+		service     *Service
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		var err error
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(Succeed())
+		port = listener.Addr().(*net.TCPAddr).Port
+
+		hcloudMachine := &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "probed-machine", Namespace: "default"},
+			Status: infrav1.HCloudMachineStatus{Addresses: []clusterv1.MachineAddress{
+				{Type: clusterv1.MachineInternalIP, Address: "127.0.0.1"},
+			}},
+		}
+		remediation = &infrav1.HCloudRemediation{
+			ObjectMeta: metav1.ObjectMeta{Name: "probed-machine", Namespace: "default"},
+			Spec: infrav1.HCloudRemediationSpec{
+				Strategy: &infrav1.RemediationStrategy{
+					Type:        infrav1.RemediationTypeReboot,
+					Timeout:     &metav1.Duration{Duration: 5 * time.Minute},
+					HealthProbe: &infrav1.RemediationHealthProbe{TCPPorts: []int{port}},
+				},
+			},
+		}
+
+		service, _, server = newTestService(hcloudMachine, remediation, hcloud.ServerCreateOpts{})
+		client = service.scope.HCloudClient
+	})
+
+	AfterEach(func() {
+		_ = listener.Close()
+	})
+
+	It("skips the remediation of a running server with reachable ports", func() {
+		res, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(Equal(5 * time.Minute))
+		Expect(remediation.Status.LastRemediated).To(BeNil())
+		Expect(remediation.Status.RetryCount).To(Equal(0))
+		Expect(remediation.Annotations[infrav1.RemediationSkippedAnnotation]).To(ContainSubstring("server is running"))
+	})
+
+	It("skips the replacement of a running server with reachable ports", func() {
+		remediation.Spec.Strategy.Type = infrav1.RemediationTypeReplace
+
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.Phase).To(Equal(infrav1.PhaseRunning))
+		Expect(remediation.Annotations).To(HaveKey(infrav1.RemediationSkippedAnnotation))
+	})
+
+	It("remediates once a port is unreachable", func() {
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Annotations).To(HaveKey(infrav1.RemediationSkippedAnnotation))
+
+		Expect(listener.Close()).To(Succeed())
+		_, err = service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.LastRemediated).ToNot(BeNil())
+		Expect(remediation.Status.RetryCount).To(Equal(1))
+		Expect(remediation.Annotations).ToNot(HaveKey(infrav1.RemediationSkippedAnnotation))
+	})
+
+	It("remediates a server that is not running", func() {
+		Expect(client.PowerOffServer(ctx, server)).To(Succeed())
+
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.LastRemediated).ToNot(BeNil())
+		Expect(remediation.Annotations).ToNot(HaveKey(infrav1.RemediationSkippedAnnotation))
+	})
+
+	It("does not skip the remediation of a running server without probed ports", func() {
+		remediation.Spec.Strategy.HealthProbe.TCPPorts = nil
+
+		_, err := service.Reconcile(ctx)
+		Expect(err).To(Succeed())
+		Expect(remediation.Status.LastRemediated).ToNot(BeNil())
+		Expect(remediation.Annotations).ToNot(HaveKey(infrav1.RemediationSkippedAnnotation))
+	})
+})
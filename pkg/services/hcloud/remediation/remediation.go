@@ -67,7 +67,9 @@ func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err erro
 
 	remediationType := s.scope.HCloudRemediation.Spec.Strategy.Type
 
-	if remediationType != infrav1.RemediationTypeReboot {
+	switch remediationType {
+	case infrav1.RemediationTypeReboot, infrav1.RemediationTypeEscalate, infrav1.RemediationTypeReplace:
+	default:
 		s.scope.Info("unsupported remediation strategy")
 		record.Warnf(s.scope.HCloudRemediation, "UnsupportedRemdiationStrategy", "remediation strategy %q is unsupported", remediationType)
 		return res, nil
@@ -78,8 +80,24 @@ func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err erro
 		s.scope.HCloudRemediation.Status.Phase = infrav1.PhaseRunning
 	}
 
+	// the owner Machine has already been marked for deletion by Cluster API
+	if s.scope.HCloudRemediation.Status.Phase == infrav1.PhaseDeleting {
+		return res, nil
+	}
+
+	if s.skipRemediation(ctx, server) {
+		return reconcile.Result{RequeueAfter: s.scope.HCloudRemediation.Spec.Strategy.Timeout.Duration}, nil
+	}
+
+	if remediationType == infrav1.RemediationTypeReplace {
+		return res, s.replaceMachine(ctx, "remediation strategy is Replace")
+	}
+
 	switch s.scope.HCloudRemediation.Status.Phase {
 	case infrav1.PhaseRunning:
+		if remediationType == infrav1.RemediationTypeEscalate {
+			return s.handleEscalation(ctx, server)
+		}
 		return s.handlePhaseRunning(ctx, server)
 	case infrav1.PhaseWaiting:
 		return s.handlePhaseWaiting(ctx)
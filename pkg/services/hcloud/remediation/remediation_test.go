@@ -17,15 +17,24 @@ limitations under the License.
 package remediation
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	fakehcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
 )
 
 func TestHCloudRemediation(t *testing.T) {
@@ -33,6 +42,35 @@ func TestHCloudRemediation(t *testing.T) {
 	RunSpecs(t, "HCloudRemediation Suite")
 }
 
+// newTestService returns a service for the remediation of the machine with its own fake HCloud client, in which the
+// server of the machine has been created with the name of the machine. It returns the CAPI Machine and the server.
+func newTestService(hcloudMachine *infrav1.HCloudMachine, remediation *infrav1.HCloudRemediation, opts hcloud.ServerCreateOpts) (*Service, *clusterv1.Machine, *hcloud.Server) {
+	hcloudClient := fakehcloudclient.NewHCloudClient()
+
+	opts.Name = hcloudMachine.Name
+	server, err := hcloudClient.CreateServer(context.Background(), opts)
+	Expect(err).To(Succeed())
+	hcloudMachine.Spec.ProviderID = ptr.To(fmt.Sprintf("hcloud://%d", server.ID))
+
+	machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: hcloudMachine.Name, Namespace: hcloudMachine.Namespace}}
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(infrav1.AddToScheme(scheme))
+	utilruntime.Must(clusterv1.AddToScheme(scheme))
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(machine, remediation).WithStatusSubresource(machine, remediation).Build()
+
+	remediationScope, err := scope.NewHCloudRemediationScope(scope.HCloudRemediationScopeParams{
+		Logger:            GinkgoLogr,
+		Client:            c,
+		HCloudClient:      hcloudClient,
+		Machine:           machine,
+		HCloudMachine:     hcloudMachine,
+		HCloudRemediation: remediation,
+	})
+	Expect(err).To(Succeed())
+	return NewService(remediationScope), machine, server
+}
+
 var _ = Describe("Test TimeUntilNextRemediation", func() {
 	type testCaseTimeUntilNextRemediation struct {
 		lastRemediated                 time.Time
@@ -0,0 +1,191 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/objectstorage"
+)
+
+// maxUserDataSize is the maximum size of the user data that HCloud accepts.
+const maxUserDataSize = 32 * 1024
+
+// userData returns the user data of the server for the bootstrap data. Bootstrap data that exceeds the limit of
+// HCloud is compressed with gzip and encoded with base64, which cloud-init decodes on Hetzner. If it is still too
+// large, it is uploaded to the bootstrap data storage of the cluster and the user data only includes its URL.
+func (s *Service) userData(ctx context.Context, bootstrapData []byte) (string, error) {
+	s.scope.HCloudMachine.Status.BootstrapDataNotReplayable = ""
+	if s.scope.IsControlPlane() &&
+		(s.scope.Cluster == nil || !conditions.IsTrue(s.scope.Cluster, clusterv1.ControlPlaneInitializedCondition)) {
+		s.scope.HCloudMachine.Status.BootstrapDataNotReplayable = "the bootstrap data initializes the cluster"
+	}
+
+	if len(bootstrapData) <= maxUserDataSize {
+		return string(bootstrapData), nil
+	}
+
+	compressed, err := compressUserData(bootstrapData)
+	if err != nil {
+		return "", fmt.Errorf("failed to compress bootstrap data: %w", err)
+	}
+	if len(compressed) <= maxUserDataSize {
+		return compressed, nil
+	}
+
+	if s.scope.HetznerCluster.Spec.BootstrapDataStorage == nil {
+		msg := fmt.Sprintf("bootstrap data of %d bytes exceeds the user data limit of %d bytes even after compression "+
+			"and no bootstrapDataStorage is configured in the HetznerCluster", len(bootstrapData), maxUserDataSize)
+		conditions.MarkFalse(s.scope.HCloudMachine,
+			infrav1.ServerCreateSucceededCondition,
+			infrav1.BootstrapDataTooLargeReason,
+			clusterv1.ConditionSeverityError,
+			"%s",
+			msg,
+		)
+		record.Warnf(s.scope.HCloudMachine, "BootstrapDataTooLarge", msg)
+		return "", errServerCreateNotPossible
+	}
+
+	url, err := s.uploadBootstrapData(ctx, bootstrapData)
+	if err != nil {
+		conditions.MarkFalse(s.scope.HCloudMachine,
+			infrav1.ServerCreateSucceededCondition,
+			infrav1.BootstrapDataUploadFailedReason,
+			clusterv1.ConditionSeverityWarning,
+			"%s",
+			err.Error(),
+		)
+		record.Warnf(s.scope.HCloudMachine, "FailedUploadBootstrapData", "Failed to upload bootstrap data: %s", err)
+		return "", err
+	}
+
+	// cloud-init downloads the URL and processes its content as user data
+	s.scope.HCloudMachine.Status.BootstrapDataNotReplayable = "the bootstrap data has been served from the bootstrap data storage with an expiring URL"
+	return "#include\n" + url + "\n", nil
+}
+
+// uploadBootstrapData uploads the bootstrap data to the bootstrap data storage and returns a presigned URL of it.
+// The object of a machine has always the same key, so that attempts to create the server again overwrite it.
+func (s *Service) uploadBootstrapData(ctx context.Context, bootstrapData []byte) (string, error) {
+	client, err := s.objectStorageClient()
+	if err != nil {
+		return "", err
+	}
+
+	key := bootstrapDataObjectKey(s.scope.HCloudMachine)
+	if err := client.PutObject(ctx, key, bootstrapData); err != nil {
+		return "", err
+	}
+
+	expiry := s.scope.HetznerCluster.Spec.BootstrapDataStorage.URLExpiry.Duration
+	if expiry == 0 {
+		expiry = infrav1.DefaultBootstrapDataURLExpiry
+	}
+	url, err := client.PresignGetObject(key, expiry)
+	if err != nil {
+		return "", err
+	}
+
+	s.scope.HCloudMachine.Status.BootstrapDataObject = &infrav1.BootstrapDataObjectStatus{
+		Key:       key,
+		ExpiresAt: metav1.NewTime(time.Now().Add(expiry)),
+	}
+	record.Eventf(s.scope.HCloudMachine, "BootstrapDataUploaded", "Uploaded bootstrap data of %d bytes to object %s", len(bootstrapData), key)
+	return url, nil
+}
+
+// deleteBootstrapDataObject deletes the object with the bootstrap data once its URL has expired or if force is set.
+func (s *Service) deleteBootstrapDataObject(ctx context.Context, force bool) error {
+	object := s.scope.HCloudMachine.Status.BootstrapDataObject
+	if object == nil || !force && time.Now().Before(object.ExpiresAt.Time) {
+		return nil
+	}
+
+	// the object cannot be deleted without storage, it has to be removed manually
+	if s.scope.HetznerCluster.Spec.BootstrapDataStorage == nil {
+		record.Warnf(s.scope.HCloudMachine, "BootstrapDataObjectOrphaned",
+			"Cannot delete bootstrap data object %s, because no bootstrapDataStorage is configured anymore", object.Key)
+		s.scope.HCloudMachine.Status.BootstrapDataObject = nil
+		return nil
+	}
+
+	client, err := s.objectStorageClient()
+	if err != nil {
+		return err
+	}
+	if err := client.DeleteObject(ctx, object.Key); err != nil {
+		record.Warnf(s.scope.HCloudMachine, "FailedDeleteBootstrapData", "Failed to delete bootstrap data: %s", err)
+		return err
+	}
+
+	record.Eventf(s.scope.HCloudMachine, "BootstrapDataDeleted", "Deleted bootstrap data object %s", object.Key)
+	s.scope.HCloudMachine.Status.BootstrapDataObject = nil
+	return nil
+}
+
+func (s *Service) objectStorageClient() (*objectstorage.Client, error) {
+	spec := s.scope.HetznerCluster.Spec.BootstrapDataStorage
+	keys := s.scope.HetznerCluster.Spec.HetznerSecret.Key
+
+	secret := s.scope.HetznerSecret()
+	if secret == nil || len(secret.Data[keys.ObjectStorageAccessKey]) == 0 || len(secret.Data[keys.ObjectStorageSecretKey]) == 0 {
+		return nil, fmt.Errorf("object storage credentials not found in keys %q and %q of the Hetzner secret",
+			keys.ObjectStorageAccessKey, keys.ObjectStorageSecretKey)
+	}
+
+	return objectstorage.NewClient(objectstorage.Config{
+		Endpoint:  spec.Endpoint,
+		Region:    spec.Region,
+		Bucket:    spec.Bucket,
+		AccessKey: string(secret.Data[keys.ObjectStorageAccessKey]),
+		SecretKey: string(secret.Data[keys.ObjectStorageSecretKey]),
+	})
+}
+
+func bootstrapDataObjectKey(hcloudMachine *infrav1.HCloudMachine) string {
+	return path.Join(hcloudMachine.Namespace, hcloudMachine.Name, "bootstrap-data")
+}
+
+// compressUserData compresses the data with gzip and encodes it with base64, because HCloud does not accept
+// binary user data.
+func compressUserData(data []byte) (string, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(data); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	fakestorage "github.com/syself/cluster-api-provider-hetzner/pkg/services/objectstorage/fake"
+)
+
+var _ = Describe("Bootstrap data", func() {
+	var (
+		ctx            context.Context
+		storage        *fakestorage.Server
+		hetznerCluster *infrav1.HetznerCluster
+		hcloudMachine  *infrav1.HCloudMachine
+		cluster        *clusterv1.Cluster
+		machine        *clusterv1.Machine
+		service        *Service
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		storage = fakestorage.NewServer("access")
+		DeferCleanup(storage.Close)
+
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-data-machine", Namespace: "default"},
+		}
+		hetznerCluster = &infrav1.HetznerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "hetzner-cluster", Namespace: "default"},
+			Spec: infrav1.HetznerClusterSpec{
+				HetznerSecret: infrav1.HetznerSecretRef{Key: infrav1.HetznerSecretKeyRef{
+					ObjectStorageAccessKey: "object-storage-access-key",
+					ObjectStorageSecretKey: "object-storage-secret-key",
+				}},
+			},
+		}
+
+		cluster = &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+		machine = &clusterv1.Machine{}
+
+		service, _ = newTestServiceWithServer(scope.MachineScopeParams{
+			ClusterScopeParams: scope.ClusterScopeParams{
+				HetznerSecret: &corev1.Secret{Data: map[string][]byte{
+					"object-storage-access-key": []byte("access"),
+					"object-storage-secret-key": []byte("secret"),
+				}},
+				Cluster:        cluster,
+				HetznerCluster: hetznerCluster,
+			},
+			Machine:       machine,
+			HCloudMachine: hcloudMachine,
+		}, hcloud.ServerCreateOpts{})
+	})
+
+	It("passes small bootstrap data unchanged", func() {
+		Expect(service.userData(ctx, []byte("#cloud-config\n"))).To(Equal("#cloud-config\n"))
+		Expect(hcloudMachine.Status.BootstrapDataNotReplayable).To(BeEmpty())
+	})
+
+	It("marks the bootstrap data of a control plane machine as not replayable until the control plane is initialized", func() {
+		machine.Labels = map[string]string{clusterv1.MachineControlPlaneLabel: ""}
+		Expect(service.userData(ctx, []byte("#cloud-config\n"))).ToNot(BeEmpty())
+		Expect(hcloudMachine.Status.BootstrapDataNotReplayable).ToNot(BeEmpty())
+
+		conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+		Expect(service.userData(ctx, []byte("#cloud-config\n"))).ToNot(BeEmpty())
+		Expect(hcloudMachine.Status.BootstrapDataNotReplayable).To(BeEmpty())
+	})
+
+	It("compresses bootstrap data that exceeds the limit", func() {
+		bootstrapData := []byte("#cloud-config\n" + strings.Repeat("write_files: []\n", 4*1024))
+
+		userData, err := service.userData(ctx, bootstrapData)
+		Expect(err).To(Succeed())
+		Expect(len(userData)).To(BeNumerically("<=", maxUserDataSize))
+
+		compressed, err := base64.StdEncoding.DecodeString(userData)
+		Expect(err).To(Succeed())
+		r, err := gzip.NewReader(bytes.NewReader(compressed))
+		Expect(err).To(Succeed())
+		Expect(io.ReadAll(r)).To(Equal(bootstrapData))
+	})
+
+	It("does not create the server if the compressed bootstrap data exceeds the limit and no storage is configured", func() {
+		bootstrapData := make([]byte, 2*maxUserDataSize)
+		_, err := rand.Read(bootstrapData)
+		Expect(err).To(Succeed())
+
+		_, err = service.userData(ctx, bootstrapData)
+		Expect(err).To(MatchError(errServerCreateNotPossible))
+
+		c := conditions.Get(hcloudMachine, infrav1.ServerCreateSucceededCondition)
+		Expect(c).ToNot(BeNil())
+		Expect(c.Reason).To(Equal(infrav1.BootstrapDataTooLargeReason))
+	})
+
+	It("serves bootstrap data from the storage and deletes it after the URL expired", func() {
+		hetznerCluster.Spec.BootstrapDataStorage = &infrav1.BootstrapDataStorageSpec{
+			Endpoint:  storage.URL,
+			Region:    "fsn1",
+			Bucket:    "bootstrap",
+			URLExpiry: metav1.Duration{Duration: time.Minute},
+		}
+		bootstrapData := make([]byte, 2*maxUserDataSize)
+		_, err := rand.Read(bootstrapData)
+		Expect(err).To(Succeed())
+
+		By("uploading the bootstrap data")
+		userData, err := service.userData(ctx, bootstrapData)
+		Expect(err).To(Succeed())
+		Expect(userData).To(HavePrefix("#include\n"))
+		Expect(hcloudMachine.Status.BootstrapDataObject).ToNot(BeNil())
+		Expect(hcloudMachine.Status.BootstrapDataObject.Key).To(Equal("default/bootstrap-data-machine/bootstrap-data"))
+		Expect(hcloudMachine.Status.BootstrapDataNotReplayable).ToNot(BeEmpty())
+
+		By("downloading the bootstrap data with the URL of the user data")
+		resp, err := http.Get(strings.TrimSpace(strings.TrimPrefix(userData, "#include\n"))) //nolint:noctx // test request
+		Expect(err).To(Succeed())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(io.ReadAll(resp.Body)).To(Equal(bootstrapData))
+
+		By("keeping the object until the URL expired")
+		Expect(service.deleteBootstrapDataObject(ctx, false)).To(Succeed())
+		Expect(storage.Paths()).To(HaveLen(1))
+
+		By("deleting the object after the URL expired")
+		hcloudMachine.Status.BootstrapDataObject.ExpiresAt = metav1.NewTime(time.Now().Add(-time.Second))
+		Expect(service.deleteBootstrapDataObject(ctx, false)).To(Succeed())
+		Expect(storage.Paths()).To(BeEmpty())
+		Expect(hcloudMachine.Status.BootstrapDataObject).To(BeNil())
+	})
+
+	It("reports a failed upload", func() {
+		hetznerCluster.Spec.BootstrapDataStorage = &infrav1.BootstrapDataStorageSpec{
+			Endpoint: storage.URL,
+			Region:   "fsn1",
+			Bucket:   "bootstrap",
+		}
+		service.scope.HetznerSecret().Data["object-storage-access-key"] = []byte("other")
+		bootstrapData := make([]byte, 2*maxUserDataSize)
+		_, err := rand.Read(bootstrapData)
+		Expect(err).To(Succeed())
+
+		_, err = service.userData(ctx, bootstrapData)
+		Expect(err).ToNot(Succeed())
+
+		c := conditions.Get(hcloudMachine, infrav1.ServerCreateSucceededCondition)
+		Expect(c).ToNot(BeNil())
+		Expect(c.Reason).To(Equal(infrav1.BootstrapDataUploadFailedReason))
+		Expect(hcloudMachine.Status.BootstrapDataObject).To(BeNil())
+	})
+})
@@ -0,0 +1,276 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	secretutil "github.com/syself/cluster-api-provider-hetzner/pkg/secrets"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+)
+
+const (
+	// diagnosticsTimeout is the time after which the collection of diagnostics fails if the rescue system cannot be
+	// reached via SSH.
+	diagnosticsTimeout = 10 * time.Minute
+
+	// maxDiagnosticsLogSize is the maximum size of a log in the ConfigMap. Older lines are cut off.
+	maxDiagnosticsLogSize = 256 * 1024
+
+	// diagnosticsSummaryLines is the number of lines of the cloud-init output that are shown in the event.
+	diagnosticsSummaryLines = 10
+
+	// keys of the ConfigMap with the diagnostics.
+	cloudInitOutputKey = "cloud-init-output.log"
+	journalKey         = "journal.log"
+)
+
+var errDiagnosticsNotPossible = errors.New("diagnostics not possible")
+
+// reconcileDiagnostics collects diagnostics of a server that has been annotated with the diagnostics annotation. The
+// server is rebooted into the rescue system, where the logs of cloud-init and the journal of the installed operating
+// system are read via SSH and stored in a ConfigMap. Afterwards, the server is rebooted into its operating system and
+// the annotation is removed. If the annotation is removed while the server is in the rescue system, the server is
+// rebooted into its operating system right away.
+func (s *Service) reconcileDiagnostics(ctx context.Context, server *hcloud.Server) (reconcile.Result, error) {
+	status := s.scope.HCloudMachine.Status.Diagnostics
+	if _, found := s.scope.HCloudMachine.Annotations[infrav1.DiagnosticsAnnotation]; !found {
+		return s.finishDiagnostics(ctx, server, errors.New("diagnostics annotation has been removed"), "", "")
+	}
+
+	if s.scope.SSHClientFactory == nil {
+		if status == nil {
+			s.scope.HCloudMachine.Status.Diagnostics = &infrav1.HCloudDiagnosticsStatus{}
+		}
+		return s.finishDiagnostics(ctx, server, fmt.Errorf("%w: no SSH client factory configured", errDiagnosticsNotPossible), "", "")
+	}
+
+	if !diagnosticsInProgress(s.scope.HCloudMachine) {
+		return s.startDiagnostics(ctx, server)
+	}
+
+	// the node is not available while the server is in the rescue system
+	s.scope.SetReady(false)
+
+	privateKey, err := s.rescuePrivateKey(ctx)
+	if err != nil {
+		return s.finishDiagnostics(ctx, server, err, "", "")
+	}
+
+	sshClient := s.scope.SSHClientFactory.NewClient(sshclient.Input{
+		IP:         server.PublicNet.IPv4.IP.String(),
+		PrivateKey: privateKey,
+		Port:       22,
+	})
+
+	cloudInitOutput := sshClient.GetCloudInitOutputOfInstalledOS()
+	if err := cloudInitOutput.Err; err != nil {
+		var exitErr *ssh.ExitError
+		if !errors.As(err, &exitErr) {
+			// the rescue system is not reachable yet
+			if time.Since(status.StartTime.Time) > diagnosticsTimeout {
+				return s.finishDiagnostics(ctx, server, fmt.Errorf("failed to reach rescue system via SSH: %w", err), "", "")
+			}
+			return reconcile.Result{RequeueAfter: 15 * time.Second}, nil
+		}
+	}
+
+	journal := sshClient.GetJournalOfInstalledOS()
+	journalLog := journal.String()
+	if errors.Is(journal.Err, sshclient.ErrNoPersistentJournal) {
+		// the volatile journal is lost with the reboot into the rescue system
+		journalLog = fmt.Sprintf("%s. The journal is only kept in memory and has been lost with the reboot into the rescue system. "+
+			"Configure Storage=persistent in journald.conf of the image to collect it.", sshclient.ErrNoPersistentJournal)
+	}
+
+	configMapName, err := s.writeDiagnostics(ctx, map[string]string{
+		cloudInitOutputKey: truncateLog(cloudInitOutput.String()),
+		journalKey:         truncateLog(journalLog),
+	})
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to store diagnostics: %w", err)
+	}
+
+	return s.finishDiagnostics(ctx, server, nil, configMapName, lastLines(cloudInitOutput.String(), diagnosticsSummaryLines))
+}
+
+// startDiagnostics enables the rescue system with the SSH keys of the cluster and reboots the server into it.
+func (s *Service) startDiagnostics(ctx context.Context, server *hcloud.Server) (reconcile.Result, error) {
+	hm := s.scope.HCloudMachine
+
+	if server.PublicNet.IPv4.IP == nil || server.PublicNet.IPv4.IP.IsUnspecified() {
+		hm.Status.Diagnostics = &infrav1.HCloudDiagnosticsStatus{}
+		return s.finishDiagnostics(ctx, server, fmt.Errorf("%w: server has no public IPv4 address", errDiagnosticsNotPossible), "", "")
+	}
+
+	sshKeysAPI, err := s.scope.HCloudClient.ListSSHKeys(ctx, hcloud.SSHKeyListOpts{})
+	if err != nil {
+		return reconcile.Result{}, handleRateLimit(hm, err, "ListSSHKeys", "failed listing ssh keys from hcloud")
+	}
+	sshKeySpecs := s.withSecretSSHKey(slices.Clone(s.scope.HetznerCluster.Spec.SSHKeys.HCloud))
+	sshKeys, err := filterHCloudSSHKeys(sshKeysAPI, sshKeySpecs)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to find ssh keys of the cluster: %w", err)
+	}
+
+	opts := hcloud.ServerEnableRescueOpts{
+		Type:    hcloud.ServerRescueTypeLinux64,
+		SSHKeys: sshKeys,
+	}
+	if err := s.scope.HCloudClient.EnableRescueSystem(ctx, server, opts); err != nil {
+		record.Warnf(hm, "FailedEnableRescueSystem", "Failed to enable rescue system of server %s: %s", server.Name, err)
+		return reconcile.Result{}, handleRateLimit(hm, err, "EnableRescueSystem", "failed to enable rescue system")
+	}
+
+	if err := s.scope.HCloudClient.ResetServer(ctx, server); err != nil {
+		record.Warnf(hm, "FailedResetServer", "Failed to reset server %s: %s", server.Name, err)
+		return reconcile.Result{}, handleRateLimit(hm, err, "ResetServer", "failed to reset server")
+	}
+
+	hm.Status.Diagnostics = &infrav1.HCloudDiagnosticsStatus{
+		Phase:     infrav1.HCloudDiagnosticsPhaseRescueBooting,
+		StartTime: &metav1.Time{Time: time.Now()},
+	}
+	s.scope.SetReady(false)
+	record.Eventf(hm, "DiagnosticsStarted", "Rebooting server %s into the rescue system to collect diagnostics", server.Name)
+	return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+// finishDiagnostics reboots the server from the rescue system into its operating system, records the result and
+// removes the annotation.
+func (s *Service) finishDiagnostics(ctx context.Context, server *hcloud.Server, diagErr error, configMapName, summary string) (reconcile.Result, error) {
+	hm := s.scope.HCloudMachine
+
+	// the rescue system is used only for one boot, so a reset boots the installed operating system again
+	if !errors.Is(diagErr, errDiagnosticsNotPossible) {
+		if err := s.scope.HCloudClient.ResetServer(ctx, server); err != nil {
+			record.Warnf(hm, "FailedResetServer", "Failed to reset server %s: %s", server.Name, err)
+			return reconcile.Result{}, handleRateLimit(hm, err, "ResetServer", "failed to reset server")
+		}
+	}
+
+	status := hm.Status.Diagnostics
+	status.ConfigMapName = configMapName
+	if diagErr != nil {
+		status.Phase = infrav1.HCloudDiagnosticsPhaseFailed
+		status.Message = diagErr.Error()
+		record.Warnf(hm, "DiagnosticsFailed", "Failed to collect diagnostics of server %s: %s", server.Name, diagErr)
+	} else {
+		status.Phase = infrav1.HCloudDiagnosticsPhaseCompleted
+		status.Message = fmt.Sprintf("diagnostics have been stored in ConfigMap %s", configMapName)
+		record.Eventf(hm, "DiagnosticsCollected", "Stored diagnostics of server %s in ConfigMap %s. Last lines of the cloud-init output:\n%s",
+			server.Name, configMapName, summary)
+	}
+
+	delete(hm.Annotations, infrav1.DiagnosticsAnnotation)
+	return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+}
+
+// diagnosticsInProgress returns whether the server of the machine has been rebooted into the rescue system to collect
+// diagnostics.
+func diagnosticsInProgress(hm *infrav1.HCloudMachine) bool {
+	return hm.Status.Diagnostics != nil && hm.Status.Diagnostics.Phase == infrav1.HCloudDiagnosticsPhaseRescueBooting
+}
+
+// rescuePrivateKey returns the private SSH key that is used to log into the rescue system.
+func (s *Service) rescuePrivateKey(ctx context.Context) (string, error) {
+	secretRef := s.scope.HetznerCluster.Spec.SSHKeys.HCloudRescueSecretRef
+	if secretRef == nil {
+		return "", errors.New("hcloudRescueSecretRef is not set in the SSH keys of the HetznerCluster")
+	}
+
+	secretManager := secretutil.NewSecretManager(s.scope.Logger, s.scope.Client, s.scope.APIReader)
+	secret, err := secretManager.ObtainSecret(ctx, types.NamespacedName{Namespace: s.scope.Namespace(), Name: secretRef.Name})
+	if err != nil {
+		return "", err
+	}
+
+	privateKey := secret.Data[secretRef.Key.PrivateKey]
+	if len(privateKey) == 0 {
+		return "", fmt.Errorf("private key not found in key %q of secret %s", secretRef.Key.PrivateKey, secretRef.Name)
+	}
+	return string(privateKey), nil
+}
+
+// writeDiagnostics stores the logs in a ConfigMap that is owned by the HCloudMachine and returns its name.
+func (s *Service) writeDiagnostics(ctx context.Context, data map[string]string) (string, error) {
+	hm := s.scope.HCloudMachine
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      hm.Name + "-diagnostics",
+			Namespace: hm.Namespace,
+		},
+		Data: data,
+	}
+	if err := controllerutil.SetOwnerReference(hm, configMap, s.scope.Client.Scheme()); err != nil {
+		return "", err
+	}
+
+	err := s.scope.Client.Create(ctx, configMap)
+	if apierrors.IsAlreadyExists(err) {
+		existing := &corev1.ConfigMap{}
+		if err := s.scope.APIReader.Get(ctx, client.ObjectKeyFromObject(configMap), existing); err != nil {
+			return "", err
+		}
+		existing.Data = data
+		err = s.scope.Client.Update(ctx, existing)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return configMap.Name, nil
+}
+
+// truncateLog cuts off the beginning of a log that does not fit into the ConfigMap.
+func truncateLog(log string) string {
+	if len(log) <= maxDiagnosticsLogSize {
+		return log
+	}
+	log = log[len(log)-maxDiagnosticsLogSize:]
+	if i := strings.IndexByte(log, '\n'); i >= 0 {
+		log = log[i+1:]
+	}
+	return log
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks"
+	sshmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks/ssh"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+)
+
+var _ = Describe("Diagnostics", func() {
+	var (
+		ctx           context.Context
+		c             client.Client
+		hcloudClient  hcloudclient.Client
+		sshClient     *sshmock.Client
+		hcloudMachine *infrav1.HCloudMachine
+		server        *hcloud.Server
+		service       *Service
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		sshClient = &sshmock.Client{}
+
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "diagnostics-machine",
+				Namespace:   "default",
+				Annotations: map[string]string{infrav1.DiagnosticsAnnotation: ""},
+			},
+		}
+		hetznerCluster := &infrav1.HetznerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "hetzner-cluster", Namespace: "default"},
+			Spec: infrav1.HetznerClusterSpec{
+				SSHKeys: infrav1.HetznerSSHKeys{
+					HCloud: []infrav1.SSHKey{{Name: "testsshkey"}},
+					HCloudRescueSecretRef: &infrav1.SSHSecretRef{
+						Name: "rescue-ssh",
+						Key:  infrav1.SSHSecretKeyRef{Name: "name", PublicKey: "public-key", PrivateKey: "private-key"},
+					},
+				},
+			},
+		}
+		rescueSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rescue-ssh", Namespace: "default"},
+			Data:       map[string][]byte{"private-key": []byte("rescue-ssh-secret-private-key")},
+		}
+
+		service, server = newTestServiceWithServer(scope.MachineScopeParams{
+			ClusterScopeParams: scope.ClusterScopeParams{HetznerCluster: hetznerCluster},
+			HCloudMachine:      hcloudMachine,
+			SSHClientFactory:   mocks.NewSSHFactory(sshClient, sshClient, sshClient),
+		}, hcloud.ServerCreateOpts{}, rescueSecret)
+		server.PublicNet.IPv4.IP = net.ParseIP("1.2.3.4")
+		c = service.scope.Client
+		hcloudClient = service.scope.HCloudClient
+	})
+
+	It("collects the logs in the rescue system and boots the operating system again", func() {
+		By("booting the rescue system")
+		res, err := service.reconcileDiagnostics(ctx, server)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).ToNot(BeZero())
+		Expect(server.RescueEnabled).To(BeTrue())
+		Expect(hcloudMachine.Status.Diagnostics.Phase).To(Equal(infrav1.HCloudDiagnosticsPhaseRescueBooting))
+		Expect(hcloudMachine.Status.Ready).To(BeFalse())
+
+		By("waiting for the rescue system")
+		sshClient.On("GetCloudInitOutputOfInstalledOS").Return(sshclient.Output{Err: errors.New("connection refused")}).Once()
+		res, err = service.reconcileDiagnostics(ctx, server)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).ToNot(BeZero())
+		Expect(hcloudMachine.Status.Diagnostics.Phase).To(Equal(infrav1.HCloudDiagnosticsPhaseRescueBooting))
+
+		By("collecting the logs")
+		sshClient.On("GetCloudInitOutputOfInstalledOS").Return(sshclient.Output{StdOut: "kubeadm join failed"}).Once()
+		sshClient.On("GetJournalOfInstalledOS").Return(sshclient.Output{StdOut: "kubelet crashed"}).Once()
+		_, err = service.reconcileDiagnostics(ctx, server)
+		Expect(err).To(Succeed())
+
+		Expect(hcloudMachine.Status.Diagnostics.Phase).To(Equal(infrav1.HCloudDiagnosticsPhaseCompleted))
+		Expect(hcloudMachine.Annotations).ToNot(HaveKey(infrav1.DiagnosticsAnnotation))
+
+		configMap := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: hcloudMachine.Status.Diagnostics.ConfigMapName}, configMap)).To(Succeed())
+		Expect(configMap.Data).To(Equal(map[string]string{
+			cloudInitOutputKey: "kubeadm join failed",
+			journalKey:         "kubelet crashed",
+		}))
+		Expect(configMap.OwnerReferences).To(HaveLen(1))
+	})
+
+	It("records that the installed operating system has no persistent journal", func() {
+		_, err := service.reconcileDiagnostics(ctx, server)
+		Expect(err).To(Succeed())
+
+		sshClient.On("GetCloudInitOutputOfInstalledOS").Return(sshclient.Output{StdOut: "kubeadm join failed"}).Once()
+		sshClient.On("GetJournalOfInstalledOS").Return(sshclient.Output{Err: sshclient.ErrNoPersistentJournal}).Once()
+		_, err = service.reconcileDiagnostics(ctx, server)
+		Expect(err).To(Succeed())
+
+		Expect(hcloudMachine.Status.Diagnostics.Phase).To(Equal(infrav1.HCloudDiagnosticsPhaseCompleted))
+
+		configMap := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: hcloudMachine.Status.Diagnostics.ConfigMapName}, configMap)).To(Succeed())
+		Expect(configMap.Data).To(HaveKeyWithValue(journalKey, ContainSubstring("no persistent journal found")))
+		Expect(configMap.Data).To(HaveKeyWithValue(journalKey, ContainSubstring("Storage=persistent")))
+	})
+
+	It("boots the operating system again if the annotation is removed in the rescue system", func() {
+		hcloudMachine.Status.Ready = true
+		_, err := service.reconcileDiagnostics(ctx, server)
+		Expect(err).To(Succeed())
+		Expect(hcloudMachine.Status.Ready).To(BeFalse())
+
+		delete(hcloudMachine.Annotations, infrav1.DiagnosticsAnnotation)
+		Expect(hcloudClient.ShutdownServer(ctx, server)).To(Succeed())
+		_, err = service.reconcileDiagnostics(ctx, server)
+		Expect(err).To(Succeed())
+
+		// the reset boots the operating system
+		current, err := hcloudClient.GetServer(ctx, server.ID)
+		Expect(err).To(Succeed())
+		Expect(current.Status).To(Equal(hcloud.ServerStatusRunning))
+		Expect(hcloudMachine.Status.Diagnostics.Phase).To(Equal(infrav1.HCloudDiagnosticsPhaseFailed))
+		Expect(hcloudMachine.Status.Diagnostics.Message).To(ContainSubstring("annotation has been removed"))
+	})
+
+	It("fails without a public IPv4 address", func() {
+		server.PublicNet.IPv4.IP = nil
+
+		_, err := service.reconcileDiagnostics(ctx, server)
+		Expect(err).To(Succeed())
+		Expect(server.RescueEnabled).To(BeFalse())
+		Expect(hcloudMachine.Status.Diagnostics.Phase).To(Equal(infrav1.HCloudDiagnosticsPhaseFailed))
+		Expect(hcloudMachine.Annotations).ToNot(HaveKey(infrav1.DiagnosticsAnnotation))
+	})
+
+	It("fails without SSH client factory", func() {
+		service.scope.SSHClientFactory = nil
+
+		_, err := service.reconcileDiagnostics(ctx, server)
+		Expect(err).To(Succeed())
+		Expect(server.RescueEnabled).To(BeFalse())
+		Expect(hcloudMachine.Status.Diagnostics.Phase).To(Equal(infrav1.HCloudDiagnosticsPhaseFailed))
+		Expect(hcloudMachine.Annotations).ToNot(HaveKey(infrav1.DiagnosticsAnnotation))
+	})
+})
+
+var _ = Describe("truncateLog", func() {
+	It("keeps the end of long logs", func() {
+		log := strings.Repeat("old line\n", maxDiagnosticsLogSize/9) + "last line"
+		truncated := truncateLog(log)
+		Expect(len(truncated)).To(BeNumerically("<=", maxDiagnosticsLogSize))
+		Expect(truncated).To(HavePrefix("old line\n"))
+		Expect(truncated).To(HaveSuffix("last line"))
+	})
+})
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/mocks"
+)
+
+var _ = Describe("serverCandidates", func() {
+	It("tries all server types in the failure domain before the fallback locations", func() {
+		spec := infrav1.HCloudMachineSpec{
+			Type:              "cpx31",
+			FallbackTypes:     []infrav1.HCloudMachineType{"cpx41"},
+			FallbackLocations: []infrav1.Region{"nbg1"},
+		}
+
+		Expect(serverCandidates(spec, "fsn1")).To(Equal([]serverCandidate{
+			{serverType: "cpx31", location: "fsn1"},
+			{serverType: "cpx41", location: "fsn1"},
+			{serverType: "cpx31", location: "nbg1"},
+			{serverType: "cpx41", location: "nbg1"},
+		}))
+	})
+
+	It("ignores the failure domain and locations in other network zones", func() {
+		spec := infrav1.HCloudMachineSpec{
+			Type:              "cpx31",
+			FallbackLocations: []infrav1.Region{"fsn1", "ash", "hel1"},
+		}
+
+		Expect(serverCandidates(spec, "fsn1")).To(Equal([]serverCandidate{
+			{serverType: "cpx31", location: "fsn1"},
+			{serverType: "cpx31", location: "hel1"},
+		}))
+	})
+})
+
+var _ = Describe("createServerWithFallback", func() {
+	var (
+		ctx           context.Context
+		client        *mocks.Client
+		hcloudMachine *infrav1.HCloudMachine
+		service       *Service
+		unavailable   = hcloud.Error{Code: hcloud.ErrorCodeResourceUnavailable, Message: "server type unavailable"}
+		x86Image      = &hcloud.Image{ID: 1, Name: "x86-image"}
+		armImage      = &hcloud.Image{ID: 2, Name: "arm-image"}
+	)
+
+	withType := func(serverType, location string) any {
+		return mock.MatchedBy(func(opts hcloud.ServerCreateOpts) bool {
+			return opts.ServerType.Name == serverType && opts.Location.Name == location
+		})
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		client = mocks.NewClient(GinkgoT())
+
+		client.On("GetServerType", mock.Anything, "cpx31").Return(&hcloud.ServerType{Name: "cpx31", Architecture: hcloud.ArchitectureX86}, nil).Maybe()
+		client.On("GetServerType", mock.Anything, "cax31").Return(&hcloud.ServerType{Name: "cax31", Architecture: hcloud.ArchitectureARM}, nil).Maybe()
+		client.On("ListImages", mock.Anything, mock.MatchedBy(func(opts hcloud.ImageListOpts) bool { return opts.Name != "" })).Return(nil, nil).Maybe()
+		client.On("ListImages", mock.Anything, mock.MatchedBy(func(opts hcloud.ImageListOpts) bool {
+			return opts.Name == "" && opts.Architecture[0] == hcloud.ArchitectureX86
+		})).Return([]*hcloud.Image{x86Image}, nil).Maybe()
+		client.On("ListImages", mock.Anything, mock.MatchedBy(func(opts hcloud.ImageListOpts) bool {
+			return opts.Name == "" && opts.Architecture[0] == hcloud.ArchitectureARM
+		})).Return([]*hcloud.Image{armImage}, nil).Maybe()
+
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "fallback-machine", Namespace: "default"},
+			Spec: infrav1.HCloudMachineSpec{
+				Type:              "cpx31",
+				ImageName:         "my-image",
+				FallbackTypes:     []infrav1.HCloudMachineType{"cax31"},
+				FallbackLocations: []infrav1.Region{"nbg1"},
+			},
+			Status: infrav1.HCloudMachineStatus{Region: "fsn1"},
+		}
+
+		service = NewService(&scope.MachineScope{
+			ClusterScope: scope.ClusterScope{
+				HCloudClient:   client,
+				HetznerCluster: &infrav1.HetznerCluster{ObjectMeta: metav1.ObjectMeta{Name: "hetzner-cluster"}},
+			},
+			Machine:       &clusterv1.Machine{},
+			HCloudMachine: hcloudMachine,
+		})
+	})
+
+	It("uses the server type of the spec if it is available", func() {
+		client.On("CreateServer", mock.Anything, withType("cpx31", "fsn1")).Return(&hcloud.Server{ID: 1}, nil).Once()
+
+		server, err := service.createServerWithFallback(ctx, hcloud.ServerCreateOpts{PublicNet: &hcloud.ServerCreatePublicNet{}})
+		Expect(err).To(Succeed())
+		Expect(server.ID).To(Equal(int64(1)))
+	})
+
+	It("falls back to the next server type with the image of its architecture", func() {
+		client.On("CreateServer", mock.Anything, withType("cpx31", "fsn1")).Return(nil, unavailable).Once()
+		client.On("CreateServer", mock.Anything, mock.MatchedBy(func(opts hcloud.ServerCreateOpts) bool {
+			return opts.ServerType.Name == "cax31" && opts.Location.Name == "fsn1" && opts.Image.ID == armImage.ID
+		})).Return(&hcloud.Server{ID: 2}, nil).Once()
+
+		server, err := service.createServerWithFallback(ctx, hcloud.ServerCreateOpts{PublicNet: &hcloud.ServerCreatePublicNet{}})
+		Expect(err).To(Succeed())
+		Expect(server.ID).To(Equal(int64(2)))
+	})
+
+	It("falls back to the next location if no server type is available", func() {
+		client.On("CreateServer", mock.Anything, withType("cpx31", "fsn1")).Return(nil, unavailable).Once()
+		client.On("CreateServer", mock.Anything, withType("cax31", "fsn1")).Return(nil, unavailable).Once()
+		client.On("CreateServer", mock.Anything, withType("cpx31", "nbg1")).Return(&hcloud.Server{ID: 3}, nil).Once()
+
+		server, err := service.createServerWithFallback(ctx, hcloud.ServerCreateOpts{PublicNet: &hcloud.ServerCreatePublicNet{}})
+		Expect(err).To(Succeed())
+		Expect(server.ID).To(Equal(int64(3)))
+	})
+
+	It("does not fall back on other errors", func() {
+		client.On("CreateServer", mock.Anything, withType("cpx31", "fsn1")).
+			Return(nil, hcloud.Error{Code: hcloud.ErrorCodeInvalidInput, Message: "invalid input"}).Once()
+
+		_, err := service.createServerWithFallback(ctx, hcloud.ServerCreateOpts{PublicNet: &hcloud.ServerCreatePublicNet{}})
+		Expect(hcloud.IsError(err, hcloud.ErrorCodeInvalidInput)).To(BeTrue())
+	})
+
+	It("marks the condition if no server type is available in any location", func() {
+		client.On("CreateServer", mock.Anything, mock.Anything).Return(nil, unavailable).Times(4)
+
+		_, err := service.createServerWithFallback(ctx, hcloud.ServerCreateOpts{PublicNet: &hcloud.ServerCreatePublicNet{}})
+		Expect(err).To(MatchError(errServerCreateNotPossible))
+
+		condition := conditions.Get(hcloudMachine, infrav1.ServerCreateSucceededCondition)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(infrav1.ServerTypeUnavailableReason))
+	})
+})
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+// imageNameLabelKey is the label of images that contains the name of the image. Snapshots don't have a name, so
+// they are found by this label.
+var imageNameLabelKey = infrav1.NameHetznerProviderPrefix + "image-name"
+
+// getServerImage returns the image of the spec with the architecture of the server type. The image that has been
+// recorded in the status is preferred, so that a server that is created again uses the same image.
+func (s *Service) getServerImage(ctx context.Context, architecture hcloud.Architecture) (*hcloud.Image, error) {
+	if id := s.scope.HCloudMachine.Status.ImageID; id != 0 {
+		image, err := s.scope.HCloudClient.GetImage(ctx, id)
+		if err != nil {
+			return nil, handleRateLimit(s.scope.HCloudMachine, err, "GetImage", fmt.Sprintf("failed to get image %d", id))
+		}
+		if image != nil && image.Architecture == architecture {
+			return image, nil
+		}
+	}
+
+	return s.getSpecImage(ctx, architecture)
+}
+
+// getSpecImage returns the image that the spec refers to with the architecture of the server type.
+func (s *Service) getSpecImage(ctx context.Context, architecture hcloud.Architecture) (*hcloud.Image, error) {
+	spec := s.scope.HCloudMachine.Spec
+	switch {
+	case spec.Image != nil && spec.Image.ID != nil:
+		return s.getServerImageByID(ctx, *spec.Image.ID, architecture)
+	case spec.Image != nil:
+		return s.getServerImageBySelector(ctx, spec.Image, architecture)
+	default:
+		return s.getServerImageByName(ctx, spec.ImageName, architecture)
+	}
+}
+
+func (s *Service) getServerImageByID(ctx context.Context, id int64, architecture hcloud.Architecture) (*hcloud.Image, error) {
+	image, err := s.scope.HCloudClient.GetImage(ctx, id)
+	if err != nil {
+		return nil, handleRateLimit(s.scope.HCloudMachine, err, "GetImage", fmt.Sprintf("failed to get image %d", id))
+	}
+	if image == nil {
+		return nil, s.imageNotFound(fmt.Errorf("no image found with ID %d", id))
+	}
+	if image.Architecture != architecture {
+		return nil, s.imageNotFound(fmt.Errorf("image %d has architecture %s, but the server type requires %s", id, image.Architecture, architecture))
+	}
+	return image, nil
+}
+
+func (s *Service) getServerImageBySelector(ctx context.Context, spec *infrav1.HCloudImageSpec, architecture hcloud.Architecture) (*hcloud.Image, error) {
+	selector := utils.LabelsToLabelSelector(spec.LabelSelector)
+
+	images, err := s.scope.HCloudClient.ListImages(ctx, hcloud.ImageListOpts{
+		ListOpts:     hcloud.ListOpts{LabelSelector: selector},
+		Architecture: []hcloud.Architecture{architecture},
+	})
+	if err != nil {
+		return nil, handleRateLimit(s.scope.HCloudMachine, err, "ListImages", "failed to list images by label selector in HCloud")
+	}
+
+	if len(images) > 1 && spec.SelectionPolicy == infrav1.ImageSelectionPolicyNewest {
+		sort.SliceStable(images, func(i, j int) bool { return images[i].Created.After(images[j].Created) })
+		images = images[:1]
+	}
+
+	return s.singleImage(images, fmt.Sprintf("labels %s", selector))
+}
+
+func (s *Service) getServerImageByName(ctx context.Context, name string, architecture hcloud.Architecture) (*hcloud.Image, error) {
+	// query for an existing image by label
+	// this is needed because snapshots don't have a name, only descriptions and labels
+	listOpts := hcloud.ImageListOpts{
+		ListOpts: hcloud.ListOpts{
+			LabelSelector: fmt.Sprintf("%s==%s", imageNameLabelKey, name),
+		},
+		Architecture: []hcloud.Architecture{architecture},
+	}
+
+	images, err := s.scope.HCloudClient.ListImages(ctx, listOpts)
+	if err != nil {
+		return nil, handleRateLimit(s.scope.HCloudMachine, err, "ListImages", "failed to list images by label in HCloud")
+	}
+
+	// query for an existing image by name.
+	listOpts = hcloud.ImageListOpts{
+		Name:         name,
+		Architecture: []hcloud.Architecture{architecture},
+	}
+	imagesByName, err := s.scope.HCloudClient.ListImages(ctx, listOpts)
+	if err != nil {
+		return nil, handleRateLimit(s.scope.HCloudMachine, err, "ListImages", "failed to list images by name in HCloud")
+	}
+
+	return s.singleImage(append(images, imagesByName...), fmt.Sprintf("name %s", name))
+}
+
+// singleImage returns the only image of the list. The description of the reference of the image is used in messages.
+func (s *Service) singleImage(images []*hcloud.Image, description string) (*hcloud.Image, error) {
+	if len(images) > 1 {
+		err := fmt.Errorf("image is ambiguous - %d images have %s", len(images), description)
+		record.Warnf(s.scope.HCloudMachine, "ImageNameAmbiguous", err.Error())
+		conditions.MarkFalse(s.scope.HCloudMachine,
+			infrav1.ServerCreateSucceededCondition,
+			infrav1.ImageAmbiguousReason,
+			clusterv1.ConditionSeverityError,
+			"%s",
+			err.Error(),
+		)
+		return nil, errServerCreateNotPossible
+	}
+	if len(images) == 0 {
+		return nil, s.imageNotFound(fmt.Errorf("no image found with %s", description))
+	}
+
+	return images[0], nil
+}
+
+func (s *Service) imageNotFound(err error) error {
+	record.Warnf(s.scope.HCloudMachine, "ImageNotFound", err.Error())
+	conditions.MarkFalse(s.scope.HCloudMachine,
+		infrav1.ServerCreateSucceededCondition,
+		infrav1.ImageNotFoundReason,
+		clusterv1.ConditionSeverityError,
+		"%s",
+		err.Error(),
+	)
+	return errServerCreateNotPossible
+}
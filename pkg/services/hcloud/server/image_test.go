@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/mocks"
+)
+
+var _ = Describe("getServerImage", func() {
+	var (
+		ctx           context.Context
+		client        *mocks.Client
+		hcloudMachine *infrav1.HCloudMachine
+		service       *Service
+		now           = time.Now()
+		oldImage      = &hcloud.Image{ID: 1, Architecture: hcloud.ArchitectureX86, Created: now.Add(-48 * time.Hour)}
+		newImage      = &hcloud.Image{ID: 2, Architecture: hcloud.ArchitectureX86, Created: now}
+		olderImage    = &hcloud.Image{ID: 3, Architecture: hcloud.ArchitectureX86, Created: now.Add(-72 * time.Hour)}
+		armImage      = &hcloud.Image{ID: 4, Architecture: hcloud.ArchitectureARM, Created: now}
+	)
+
+	withSelector := func(selector string) any {
+		return mock.MatchedBy(func(opts hcloud.ImageListOpts) bool { return opts.LabelSelector == selector })
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		client = mocks.NewClient(GinkgoT())
+
+		hcloudMachine = &infrav1.HCloudMachine{
+			Spec: infrav1.HCloudMachineSpec{
+				Image: &infrav1.HCloudImageSpec{
+					LabelSelector:   map[string]string{"release": "v1"},
+					SelectionPolicy: infrav1.ImageSelectionPolicyNewest,
+				},
+			},
+		}
+		service = newTestService(hcloudMachine, client)
+	})
+
+	It("uses the newest image that matches the label selector", func() {
+		client.On("ListImages", mock.Anything, withSelector("release==v1")).Return([]*hcloud.Image{oldImage, newImage, olderImage}, nil).Once()
+
+		image, err := service.getServerImage(ctx, hcloud.ArchitectureX86)
+		Expect(err).To(Succeed())
+		Expect(image.ID).To(Equal(newImage.ID))
+	})
+
+	It("fails if several images match the label selector without selection policy", func() {
+		hcloudMachine.Spec.Image.SelectionPolicy = ""
+		client.On("ListImages", mock.Anything, withSelector("release==v1")).Return([]*hcloud.Image{oldImage, newImage}, nil).Once()
+
+		_, err := service.getServerImage(ctx, hcloud.ArchitectureX86)
+		Expect(err).To(MatchError(errServerCreateNotPossible))
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerCreateSucceededCondition)).To(Equal(infrav1.ImageAmbiguousReason))
+	})
+
+	It("uses the image with the ID of the spec", func() {
+		hcloudMachine.Spec.Image = &infrav1.HCloudImageSpec{ID: ptr.To(oldImage.ID)}
+		client.On("GetImage", mock.Anything, oldImage.ID).Return(oldImage, nil).Once()
+
+		image, err := service.getServerImage(ctx, hcloud.ArchitectureX86)
+		Expect(err).To(Succeed())
+		Expect(image.ID).To(Equal(oldImage.ID))
+	})
+
+	It("fails if the image with the ID of the spec has another architecture", func() {
+		hcloudMachine.Spec.Image = &infrav1.HCloudImageSpec{ID: ptr.To(armImage.ID)}
+		client.On("GetImage", mock.Anything, armImage.ID).Return(armImage, nil).Once()
+
+		_, err := service.getServerImage(ctx, hcloud.ArchitectureX86)
+		Expect(err).To(MatchError(errServerCreateNotPossible))
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerCreateSucceededCondition)).To(Equal(infrav1.ImageNotFoundReason))
+	})
+
+	It("prefers the image of the status", func() {
+		hcloudMachine.Status.ImageID = oldImage.ID
+		client.On("GetImage", mock.Anything, oldImage.ID).Return(oldImage, nil).Once()
+
+		image, err := service.getServerImage(ctx, hcloud.ArchitectureX86)
+		Expect(err).To(Succeed())
+		Expect(image.ID).To(Equal(oldImage.ID))
+	})
+
+	It("resolves the image again if the image of the status does not exist anymore", func() {
+		hcloudMachine.Status.ImageID = 99
+		client.On("GetImage", mock.Anything, int64(99)).Return(nil, nil).Once()
+		client.On("ListImages", mock.Anything, withSelector("release==v1")).Return([]*hcloud.Image{oldImage, newImage}, nil).Once()
+
+		image, err := service.getServerImage(ctx, hcloud.ArchitectureX86)
+		Expect(err).To(Succeed())
+		Expect(image.ID).To(Equal(newImage.ID))
+	})
+})
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"sigs.k8s.io/cluster-api/util/record"
+)
+
+// reconcileLabels applies the labels of the spec and the propagated labels of the Machine to the server. Labels that
+// have been applied before but are not desired anymore are removed. Labels that CAPH manages and labels that others
+// have added to the server are not touched.
+func (s *Service) reconcileLabels(ctx context.Context, server *hcloud.Server) error {
+	desired := s.userLabels()
+
+	labels := make(map[string]string, len(server.Labels)+len(desired))
+	maps.Copy(labels, server.Labels)
+	for _, key := range s.scope.HCloudMachine.Status.AppliedLabels {
+		if _, found := desired[key]; !found {
+			delete(labels, key)
+		}
+	}
+	maps.Copy(labels, desired)
+
+	if !maps.Equal(labels, server.Labels) {
+		if _, err := s.scope.HCloudClient.UpdateServer(ctx, server, hcloud.ServerUpdateOpts{Labels: labels}); err != nil {
+			record.Warnf(s.scope.HCloudMachine, "FailedUpdateServerLabels", "Failed to update labels of server %s: %s", server.Name, err)
+			return handleRateLimit(s.scope.HCloudMachine, err, "UpdateServer", "failed to update labels of server")
+		}
+		server.Labels = labels
+		record.Eventf(s.scope.HCloudMachine, "ServerLabelsUpdated", "Updated labels of server %s", server.Name)
+	}
+
+	s.scope.HCloudMachine.Status.AppliedLabels = slices.Sorted(maps.Keys(desired))
+	return nil
+}
+
+// userLabels returns the labels of the spec together with the propagated labels of the Machine. The labels of the
+// spec take precedence.
+func (s *Service) userLabels() map[string]string {
+	spec := s.scope.HCloudMachine.Spec
+	labels := make(map[string]string, len(spec.Labels)+len(spec.PropagateMachineLabels))
+
+	if s.scope.Machine != nil {
+		for _, key := range spec.PropagateMachineLabels {
+			if value, found := s.scope.Machine.Labels[key]; found {
+				labels[key] = value
+			}
+		}
+	}
+	maps.Copy(labels, spec.Labels)
+
+	return labels
+}
+
+// serverLabels returns the labels of a new server.
+func (s *Service) serverLabels() map[string]string {
+	labels := s.userLabels()
+	maps.Copy(labels, s.createLabels())
+	return labels
+}
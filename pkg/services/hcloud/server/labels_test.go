@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+)
+
+var _ = Describe("Labels", func() {
+	var (
+		ctx           context.Context
+		client        hcloudclient.Client
+		hcloudMachine *infrav1.HCloudMachine
+		machine       *clusterv1.Machine
+		server        *hcloud.Server
+		service       *Service
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "labels-machine", Namespace: "default"},
+			Spec: infrav1.HCloudMachineSpec{
+				Labels:                 map[string]string{"team": "platform"},
+				PropagateMachineLabels: []string{clusterv1.MachineDeploymentNameLabel, "missing"},
+			},
+		}
+		machine = &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+				clusterv1.MachineDeploymentNameLabel: "md-0",
+				"not-propagated":                     "true",
+			}},
+		}
+
+		service, server = newTestServiceWithServer(scope.MachineScopeParams{Machine: machine, HCloudMachine: hcloudMachine}, hcloud.ServerCreateOpts{})
+		client = service.scope.HCloudClient
+	})
+
+	It("creates the server with the labels of the spec and the Machine", func() {
+		Expect(server.Labels).To(Equal(map[string]string{
+			"caph-cluster-hetzner-cluster":       "owned",
+			infrav1.MachineNameTagKey:            hcloudMachine.Name,
+			infrav1.MachineTypeTagKey:            infrav1.MachineTypeWorker,
+			"team":                               "platform",
+			clusterv1.MachineDeploymentNameLabel: "md-0",
+		}))
+	})
+
+	It("updates, adds and removes labels without touching other labels", func() {
+		Expect(service.reconcileLabels(ctx, server)).To(Succeed())
+		Expect(hcloudMachine.Status.AppliedLabels).To(Equal([]string{clusterv1.MachineDeploymentNameLabel, "team"}))
+
+		// a label that has been added by someone else
+		server.Labels["external"] = "true"
+
+		hcloudMachine.Spec.Labels = map[string]string{"cost-center": "42"}
+		machine.Labels[clusterv1.MachineDeploymentNameLabel] = "md-1"
+		Expect(service.reconcileLabels(ctx, server)).To(Succeed())
+
+		updated, err := client.GetServer(ctx, server.ID)
+		Expect(err).To(Succeed())
+		Expect(updated.Labels).To(Equal(map[string]string{
+			"caph-cluster-hetzner-cluster":       "owned",
+			infrav1.MachineNameTagKey:            hcloudMachine.Name,
+			infrav1.MachineTypeTagKey:            infrav1.MachineTypeWorker,
+			"external":                           "true",
+			"cost-center":                        "42",
+			clusterv1.MachineDeploymentNameLabel: "md-1",
+		}))
+		Expect(hcloudMachine.Status.AppliedLabels).To(Equal([]string{clusterv1.MachineDeploymentNameLabel, "cost-center"}))
+	})
+})
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+// rebuildRequeueAfter is the interval after which a server is checked again after the rebuild has been requested.
+const rebuildRequeueAfter = 30 * time.Second
+
+// reconcileRebuild rebuilds the running server in place with the image of the spec, if the rebuild has been approved
+// with the annotation. The server keeps its ID, IPs and volumes. It returns done once the server runs the image of
+// the spec, so that the remaining reconciliation continues.
+func (s *Service) reconcileRebuild(ctx context.Context, server *hcloud.Server) (res reconcile.Result, done bool, err error) {
+	image, err := s.getRebuildImage(ctx, server)
+	if err != nil {
+		if errors.Is(err, errServerCreateNotPossible) {
+			return reconcile.Result{RequeueAfter: 5 * time.Minute}, false, nil
+		}
+		return reconcile.Result{}, false, err
+	}
+
+	if server.Image != nil && server.Image.ID == image.ID {
+		// The annotation is consumed only by a rebuild that has been issued. Otherwise, the image of the spec has not
+		// been changed yet and the approval is kept for the update that changes it.
+		if conditions.GetReason(s.scope.HCloudMachine, infrav1.ServerRebuildCondition) == infrav1.RebuildInProgressReason {
+			record.Eventf(s.scope.HCloudMachine, "ServerRebuilt", "Server %s runs image %s", server.Name, imageDescription(image))
+			delete(s.scope.HCloudMachine.Annotations, infrav1.RebuildApprovedAnnotation)
+			conditions.MarkTrue(s.scope.HCloudMachine, infrav1.ServerRebuildCondition)
+		}
+		return reconcile.Result{}, true, nil
+	}
+
+	// The rebuild API of HCloud does not accept user data, so fresh bootstrap data cannot be passed. cloud-init runs the
+	// user data of the creation of the server again, which has to be usable for a rejoin, see the node image docs.
+	if reason := s.scope.HCloudMachine.BootstrapDataNotReplayableReason(); reason != "" {
+		if conditions.GetReason(s.scope.HCloudMachine, infrav1.ServerRebuildCondition) != infrav1.RebuildBlockedReason {
+			record.Warnf(s.scope.HCloudMachine, "RebuildBlocked", "Server %s is not rebuilt: %s", server.Name, reason)
+		}
+		conditions.MarkFalse(s.scope.HCloudMachine,
+			infrav1.ServerRebuildCondition,
+			infrav1.RebuildBlockedReason,
+			clusterv1.ConditionSeverityError,
+			"server cannot be rebuilt, because %s. Delete the machine to replace the server",
+			reason,
+		)
+		return reconcile.Result{}, true, nil
+	}
+
+	if err := s.scope.HCloudClient.RebuildServer(ctx, server, hcloud.ServerRebuildOpts{Image: image}); err != nil {
+		record.Warnf(s.scope.HCloudMachine, "FailedRebuildServer", "Failed to rebuild server %s with image %s: %s", server.Name, imageDescription(image), err)
+		err = handleRateLimit(s.scope.HCloudMachine, err, "RebuildServer", "failed to rebuild server")
+		conditions.MarkFalse(s.scope.HCloudMachine,
+			infrav1.ServerRebuildCondition,
+			infrav1.RebuildFailedReason,
+			clusterv1.ConditionSeverityWarning,
+			"%s",
+			err.Error(),
+		)
+		return reconcile.Result{}, false, err
+	}
+
+	record.Eventf(s.scope.HCloudMachine, "ServerRebuildStarted", "Rebuilding server %s with image %s", server.Name, imageDescription(image))
+	conditions.MarkFalse(s.scope.HCloudMachine,
+		infrav1.ServerRebuildCondition,
+		infrav1.RebuildInProgressReason,
+		clusterv1.ConditionSeverityInfo,
+		"server is rebuilt with image %s",
+		imageDescription(image),
+	)
+	s.scope.SetReady(false)
+	return reconcile.Result{RequeueAfter: rebuildRequeueAfter}, false, nil
+}
+
+// getRebuildImage returns the image of the spec. In contrast to the creation of a server, the image of the status is
+// ignored, as it is the image of the current server.
+func (s *Service) getRebuildImage(ctx context.Context, server *hcloud.Server) (*hcloud.Image, error) {
+	var architecture hcloud.Architecture
+	if server.ServerType != nil {
+		architecture = server.ServerType.Architecture
+	}
+
+	// the helpers report on the create condition, which does not apply to an existing server
+	createCondition := conditions.Get(s.scope.HCloudMachine, infrav1.ServerCreateSucceededCondition)
+
+	image, err := s.getSpecImage(ctx, architecture)
+
+	if errors.Is(err, errServerCreateNotPossible) {
+		msg := conditions.GetMessage(s.scope.HCloudMachine, infrav1.ServerCreateSucceededCondition)
+		if createCondition != nil {
+			conditions.Set(s.scope.HCloudMachine, createCondition)
+		} else {
+			conditions.Delete(s.scope.HCloudMachine, infrav1.ServerCreateSucceededCondition)
+		}
+		conditions.MarkFalse(s.scope.HCloudMachine,
+			infrav1.ServerRebuildCondition,
+			infrav1.RebuildFailedReason,
+			clusterv1.ConditionSeverityError,
+			"%s",
+			msg,
+		)
+	}
+	return image, err
+}
+
+func imageDescription(image *hcloud.Image) string {
+	if image.Name != "" {
+		return fmt.Sprintf("%s (%d)", image.Name, image.ID)
+	}
+	return fmt.Sprintf("%d", image.ID)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/mocks"
+)
+
+var _ = Describe("reconcileRebuild", func() {
+	var (
+		ctx           context.Context
+		client        *mocks.Client
+		hcloudMachine *infrav1.HCloudMachine
+		server        *hcloud.Server
+		service       *Service
+		oldImage      = &hcloud.Image{ID: 1, Name: "ubuntu-22.04", Architecture: hcloud.ArchitectureX86}
+		newImage      = &hcloud.Image{ID: 2, Name: "ubuntu-24.04", Architecture: hcloud.ArchitectureX86}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		client = mocks.NewClient(GinkgoT())
+
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "rebuild-machine",
+				Annotations: map[string]string{infrav1.RebuildApprovedAnnotation: ""},
+			},
+			Spec: infrav1.HCloudMachineSpec{
+				Image:                   &infrav1.HCloudImageSpec{ID: ptr.To(newImage.ID)},
+				ReplayableBootstrapData: true,
+			},
+			Status: infrav1.HCloudMachineStatus{Ready: true, ImageID: oldImage.ID},
+		}
+		server = &hcloud.Server{
+			ID:         42,
+			Name:       "rebuild-machine",
+			Image:      oldImage,
+			ServerType: &hcloud.ServerType{Architecture: hcloud.ArchitectureX86},
+			Status:     hcloud.ServerStatusRunning,
+		}
+		service = newTestService(hcloudMachine, client)
+	})
+
+	It("rebuilds the server with the image of the spec", func() {
+		client.On("GetImage", mock.Anything, newImage.ID).Return(newImage, nil).Once()
+		client.On("RebuildServer", mock.Anything, server, hcloud.ServerRebuildOpts{Image: newImage}).Return(nil).Once()
+
+		res, done, err := service.reconcileRebuild(ctx, server)
+		Expect(err).To(Succeed())
+		Expect(done).To(BeFalse())
+		Expect(res.RequeueAfter).To(Equal(rebuildRequeueAfter))
+		Expect(hcloudMachine.Status.Ready).To(BeFalse())
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerRebuildCondition)).To(Equal(infrav1.RebuildInProgressReason))
+		Expect(hcloudMachine.Annotations).To(HaveKey(infrav1.RebuildApprovedAnnotation))
+	})
+
+	It("finishes once the server runs the image of the spec", func() {
+		conditions.MarkFalse(hcloudMachine, infrav1.ServerRebuildCondition, infrav1.RebuildInProgressReason, clusterv1.ConditionSeverityInfo, "")
+		server.Image = newImage
+		client.On("GetImage", mock.Anything, newImage.ID).Return(newImage, nil).Once()
+
+		_, done, err := service.reconcileRebuild(ctx, server)
+		Expect(err).To(Succeed())
+		Expect(done).To(BeTrue())
+		Expect(conditions.IsTrue(hcloudMachine, infrav1.ServerRebuildCondition)).To(BeTrue())
+		Expect(hcloudMachine.Annotations).ToNot(HaveKey(infrav1.RebuildApprovedAnnotation))
+	})
+
+	It("keeps the approval until the image of the spec has been changed", func() {
+		server.Image = newImage
+		client.On("GetImage", mock.Anything, newImage.ID).Return(newImage, nil).Once()
+
+		_, done, err := service.reconcileRebuild(ctx, server)
+		Expect(err).To(Succeed())
+		Expect(done).To(BeTrue())
+		Expect(conditions.Has(hcloudMachine, infrav1.ServerRebuildCondition)).To(BeFalse())
+		Expect(hcloudMachine.Annotations).To(HaveKey(infrav1.RebuildApprovedAnnotation))
+	})
+
+	It("does not rebuild the server if its user data cannot be run again", func() {
+		hcloudMachine.Status.BootstrapDataNotReplayable = "the bootstrap data initializes the cluster"
+		client.On("GetImage", mock.Anything, newImage.ID).Return(newImage, nil).Once()
+
+		res, done, err := service.reconcileRebuild(ctx, server)
+		Expect(err).To(Succeed())
+		Expect(done).To(BeTrue())
+		Expect(res.IsZero()).To(BeTrue())
+		Expect(hcloudMachine.Status.Ready).To(BeTrue())
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerRebuildCondition)).To(Equal(infrav1.RebuildBlockedReason))
+		Expect(conditions.GetSeverity(hcloudMachine, infrav1.ServerRebuildCondition)).To(Equal(ptr.To(clusterv1.ConditionSeverityError)))
+	})
+
+	It("does not rebuild the server if its bootstrap data has not been declared replayable", func() {
+		hcloudMachine.Spec.ReplayableBootstrapData = false
+		client.On("GetImage", mock.Anything, newImage.ID).Return(newImage, nil).Once()
+
+		_, done, err := service.reconcileRebuild(ctx, server)
+		Expect(err).To(Succeed())
+		Expect(done).To(BeTrue())
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerRebuildCondition)).To(Equal(infrav1.RebuildBlockedReason))
+	})
+
+	It("reports a missing image on the rebuild condition", func() {
+		conditions.MarkTrue(hcloudMachine, infrav1.ServerCreateSucceededCondition)
+		client.On("GetImage", mock.Anything, newImage.ID).Return(nil, nil).Once()
+
+		res, done, err := service.reconcileRebuild(ctx, server)
+		Expect(err).To(Succeed())
+		Expect(done).To(BeFalse())
+		Expect(res.RequeueAfter).ToNot(BeZero())
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerRebuildCondition)).To(Equal(infrav1.RebuildFailedReason))
+		Expect(conditions.IsTrue(hcloudMachine, infrav1.ServerCreateSucceededCondition)).To(BeTrue())
+	})
+
+	It("reports a failed rebuild", func() {
+		client.On("GetImage", mock.Anything, newImage.ID).Return(newImage, nil).Once()
+		client.On("RebuildServer", mock.Anything, server, mock.Anything).Return(errors.New("boom")).Once()
+
+		_, done, err := service.reconcileRebuild(ctx, server)
+		Expect(err).ToNot(Succeed())
+		Expect(done).To(BeFalse())
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerRebuildCondition)).To(Equal(infrav1.RebuildFailedReason))
+	})
+})
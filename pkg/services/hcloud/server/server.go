@@ -21,6 +21,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -41,12 +43,17 @@ import (
 
 const (
 	serverOffTimeout = 10 * time.Minute
+
+	// shutdownRequeueAfter is the interval in which the graceful shutdown of a server is checked.
+	shutdownRequeueAfter = 10 * time.Second
 )
 
 var (
 	errWrongLabel              = fmt.Errorf("label is wrong")
 	errMissingLabel            = fmt.Errorf("label is missing")
 	errServerCreateNotPossible = fmt.Errorf("server create not possible - need action")
+	errPlacementGroupFull      = fmt.Errorf("placement group is full")
+	errNetworkNotReady         = fmt.Errorf("network is not ready")
 )
 
 // Service defines struct with machine scope to reconcile HCloudMachines.
@@ -104,6 +111,9 @@ func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err erro
 			if errors.Is(err, errServerCreateNotPossible) {
 				return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
 			}
+			if errors.Is(err, errNetworkNotReady) {
+				return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+			}
 			return reconcile.Result{}, fmt.Errorf("failed to create server: %w", err)
 		}
 	}
@@ -113,18 +123,52 @@ func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err erro
 	// update HCloudMachineStatus
 	c := s.scope.HCloudMachine.Status.Conditions.DeepCopy()
 	sshKeys := s.scope.HCloudMachine.Status.SSHKeys
+	appliedLabels := s.scope.HCloudMachine.Status.AppliedLabels
+	diagnostics := s.scope.HCloudMachine.Status.Diagnostics
+	bootstrapDataObject := s.scope.HCloudMachine.Status.BootstrapDataObject
+	bootstrapDataNotReplayable := s.scope.HCloudMachine.Status.BootstrapDataNotReplayable
+	snapshots := s.scope.HCloudMachine.Status.Snapshots
+	volumes := s.scope.HCloudMachine.Status.Volumes
+	imageID := s.scope.HCloudMachine.Status.ImageID
 	s.scope.HCloudMachine.Status = statusFromHCloudServer(server)
-	s.scope.SetRegion(failureDomain)
+	if s.scope.HCloudMachine.Status.ImageID == 0 {
+		// HCloud does not return the image anymore if it has been deleted
+		s.scope.HCloudMachine.Status.ImageID = imageID
+	}
+	if s.scope.HCloudMachine.Status.Region == "" {
+		s.scope.SetRegion(failureDomain)
+	}
 	s.scope.HCloudMachine.Status.Conditions = c
 	s.scope.HCloudMachine.Status.SSHKeys = sshKeys
+	s.scope.HCloudMachine.Status.AppliedLabels = appliedLabels
+	s.scope.HCloudMachine.Status.Diagnostics = diagnostics
+	s.scope.HCloudMachine.Status.BootstrapDataObject = bootstrapDataObject
+	s.scope.HCloudMachine.Status.BootstrapDataNotReplayable = bootstrapDataNotReplayable
+	s.scope.HCloudMachine.Status.Snapshots = snapshots
+	s.scope.HCloudMachine.Status.Volumes = volumes
 
 	// validate labels
-	if err := validateLabels(server, s.createLabels()); err != nil {
+	if err := validateLabels(server, s.identifyingLabels()); err != nil {
 		err := fmt.Errorf("could not validate labels of HCloud server: %w", err)
 		s.scope.SetError(err.Error(), capierrors.CreateMachineError)
 		return res, nil
 	}
 
+	// apply the labels of the spec and the Machine
+	if err := s.reconcileLabels(ctx, server); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile labels: %w", err)
+	}
+
+	// enable or disable the automatic backups
+	if err := s.reconcileBackups(ctx, server); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile backups: %w", err)
+	}
+
+	// the bootstrap data is not needed anymore once the server had the time to download it
+	if err := s.deleteBootstrapDataObject(ctx, false); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to delete bootstrap data object: %w", err)
+	}
+
 	// analyze status of server
 	switch server.Status {
 	case hcloud.ServerStatusOff:
@@ -149,6 +193,24 @@ func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err erro
 		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
+	// collect diagnostics in the rescue system if requested, and boot the operating system again once they are done
+	if _, found := s.scope.HCloudMachine.Annotations[infrav1.DiagnosticsAnnotation]; found || diagnosticsInProgress(s.scope.HCloudMachine) {
+		return s.reconcileDiagnostics(ctx, server)
+	}
+
+	// rebuild the server in place with a new image if it has been approved
+	if _, found := s.scope.HCloudMachine.Annotations[infrav1.RebuildApprovedAnnotation]; found {
+		res, done, err := s.reconcileRebuild(ctx, server)
+		if !done {
+			return res, err
+		}
+	}
+
+	// take a snapshot of the server if it has been requested
+	if err := s.reconcileSnapshot(ctx, server); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile snapshot: %w", err)
+	}
+
 	// check whether server is attached to the network
 	if err := s.reconcileNetworkAttachment(ctx, server); err != nil {
 		reterr := fmt.Errorf("failed to reconcile network attachment: %w", err)
@@ -163,6 +225,20 @@ func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err erro
 		return res, reterr
 	}
 
+	// create the volumes of the machine and attach them to the server
+	if err := s.reconcileVolumes(ctx, server); err != nil {
+		reterr := fmt.Errorf("failed to reconcile volumes: %w", err)
+		conditions.MarkFalse(
+			s.scope.HCloudMachine,
+			infrav1.ServerAvailableCondition,
+			infrav1.VolumeAttachFailedReason,
+			clusterv1.ConditionSeverityError,
+			"%s",
+			reterr.Error(),
+		)
+		return res, reterr
+	}
+
 	// nothing to do any more for worker nodes
 	if !s.scope.IsControlPlane() {
 		conditions.MarkTrue(s.scope.HCloudMachine, infrav1.ServerAvailableCondition)
@@ -215,6 +291,15 @@ func (s *Service) Delete(ctx context.Context) (res reconcile.Result, err error)
 		return reconcile.Result{}, fmt.Errorf("failed to find server: %w", err)
 	}
 
+	if err := s.deleteBootstrapDataObject(ctx, true); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to delete bootstrap data object: %w", err)
+	}
+
+	// volumes are deleted after the server, because attached volumes cannot be deleted
+	if server == nil && s.hasVolumes() {
+		return s.deleteVolumes(ctx)
+	}
+
 	// if no server has been found, then nothing can be deleted
 	if server == nil {
 		msg := fmt.Sprintf("Unable to delete HCloud server. Could not find matching server for %s", s.scope.Name())
@@ -235,6 +320,13 @@ func (s *Service) Delete(ctx context.Context) (res reconcile.Result, err error)
 		}
 	}
 
+	// retained volumes must not be owned by the machine anymore once they are detached from the deleted server
+	if s.hasVolumes() {
+		if err := s.releaseRetainedVolumes(ctx); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to release retained volumes: %w", err)
+		}
+	}
+
 	// first shut the server down, then delete it
 	switch server.Status {
 	case hcloud.ServerStatusRunning:
@@ -276,18 +368,34 @@ func (s *Service) reconcileNetworkAttachment(ctx context.Context, server *hcloud
 }
 
 func (s *Service) reconcileLoadBalancerAttachment(ctx context.Context, server *hcloud.Server) (reconcile.Result, error) {
+	deleting := conditions.Has(s.scope.Machine, clusterv1.PreDrainDeleteHookSucceededCondition)
+
+	// the additional load balancers target the servers only with label selectors
+	if deleting && (s.scope.HetznerCluster.Spec.ControlPlaneLoadBalancer.UseLabelSelectorTarget ||
+		len(s.scope.HetznerCluster.Spec.AdditionalLoadBalancers) > 0) {
+		if err := s.deleteMachineTypeLabel(ctx, server); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to remove server %s with ID %d from label selector target: %w", server.Name, server.ID, err)
+		}
+	}
+
 	if s.scope.HetznerCluster.Status.ControlPlaneLoadBalancer == nil {
 		return reconcile.Result{}, nil
 	}
 
 	// remove server from load balancer if it's being deleted
-	if conditions.Has(s.scope.Machine, clusterv1.PreDrainDeleteHookSucceededCondition) {
+	if deleting {
+		// the server can still be a single target while the label selector target is enabled or disabled
 		if err := s.deleteServerOfLoadBalancer(ctx, server); err != nil {
 			return reconcile.Result{}, fmt.Errorf("failed to delete server %s with ID %d from loadbalancer: %w", server.Name, server.ID, err)
 		}
 		return reconcile.Result{}, nil
 	}
 
+	// servers are targeted by their labels and added by HCloud
+	if s.scope.HetznerCluster.Spec.ControlPlaneLoadBalancer.UseLabelSelectorTarget {
+		return reconcile.Result{}, nil
+	}
+
 	// if already attached do nothing
 	for _, target := range s.scope.HetznerCluster.Status.ControlPlaneLoadBalancer.Target {
 		if target.Type == infrav1.LoadBalancerTargetTypeServer && target.ServerID == server.ID {
@@ -308,6 +416,10 @@ func (s *Service) reconcileLoadBalancerAttachment(ctx context.Context, server *h
 
 	// attach only if server has private IP or public IPv4, otherwise Hetzner cannot handle it
 	if server.PublicNet.IPv4.IP == nil && !hasPrivateIP {
+		// private-only servers get their private IP with the network attachment
+		if s.scope.HCloudMachine.Spec.IsPrivateOnly() {
+			return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+		}
 		return reconcile.Result{}, nil
 	}
 
@@ -343,8 +455,15 @@ func (s *Service) reconcileLoadBalancerAttachment(ctx context.Context, server *h
 }
 
 func (s *Service) createServer(ctx context.Context) (*hcloud.Server, error) {
+	// private-only servers are reachable only through the network, so they have to be attached to it on creation
+	if s.scope.HCloudMachine.Spec.IsPrivateOnly() {
+		if err := s.checkNetworkForPrivateOnlyServer(); err != nil {
+			return nil, err
+		}
+	}
+
 	// get userData
-	userData, err := s.scope.GetRawBootstrapData(ctx)
+	bootstrapData, err := s.scope.GetRawBootstrapData(ctx)
 	if err != nil {
 		record.Warnf(
 			s.scope.HCloudMachine,
@@ -354,26 +473,19 @@ func (s *Service) createServer(ctx context.Context) (*hcloud.Server, error) {
 		return nil, fmt.Errorf("failed to get raw bootstrap data: %s", err)
 	}
 
-	image, err := s.getServerImage(ctx)
+	userData, err := s.userData(ctx, bootstrapData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get server image: %w", err)
+		return nil, err
 	}
 
 	automount := false
 	startAfterCreate := true
 	opts := hcloud.ServerCreateOpts{
-		Name:   s.scope.Name(),
-		Labels: s.createLabels(),
-		Image:  image,
-		Location: &hcloud.Location{
-			Name: string(s.scope.HCloudMachine.Status.Region),
-		},
-		ServerType: &hcloud.ServerType{
-			Name: string(s.scope.HCloudMachine.Spec.Type),
-		},
+		Name:             s.scope.Name(),
+		Labels:           s.serverLabels(),
 		Automount:        &automount,
 		StartAfterCreate: &startAfterCreate,
-		UserData:         string(userData),
+		UserData:         userData,
 		PublicNet: &hcloud.ServerCreatePublicNet{
 			EnableIPv4: s.scope.HCloudMachine.Spec.PublicNetwork.EnableIPv4,
 			EnableIPv6: s.scope.HCloudMachine.Spec.PublicNetwork.EnableIPv6,
@@ -381,28 +493,32 @@ func (s *Service) createServer(ctx context.Context) (*hcloud.Server, error) {
 	}
 
 	// set placement group if necessary
-	if s.scope.HCloudMachine.Spec.PlacementGroupName != nil {
-		var foundPlacementGroupInStatus bool
-		for _, pgSts := range s.scope.HetznerCluster.Status.HCloudPlacementGroups {
-			if *s.scope.HCloudMachine.Spec.PlacementGroupName == pgSts.Name {
-				foundPlacementGroupInStatus = true
-				opts.PlacementGroup = &hcloud.PlacementGroup{
-					ID:   pgSts.ID,
-					Name: pgSts.Name,
-					Type: hcloud.PlacementGroupType(pgSts.Type),
-				}
-			}
+	if name := s.scope.HCloudMachine.Spec.PlacementGroupName; name != nil {
+		placementGroups, inCreationBefore, err := s.placementGroupsForCreation(ctx, *name)
+		if err != nil {
+			return nil, err
 		}
-		if !foundPlacementGroupInStatus {
+		pgSts, err := findPlacementGroup(placementGroups, *name, inCreationBefore)
+		if err != nil {
+			reason, severity := infrav1.InstanceHasNonExistingPlacementGroupReason, clusterv1.ConditionSeverityError
+			if errors.Is(err, errPlacementGroupFull) {
+				// a new shard is created by the cluster controller
+				reason, severity = infrav1.InstanceHasFullPlacementGroupReason, clusterv1.ConditionSeverityWarning
+			}
 			conditions.MarkFalse(s.scope.HCloudMachine,
 				infrav1.ServerCreateSucceededCondition,
-				infrav1.InstanceHasNonExistingPlacementGroupReason,
-				clusterv1.ConditionSeverityError,
-				"Placement group %q does not exist in cluster",
-				*s.scope.HCloudMachine.Spec.PlacementGroupName,
+				reason,
+				severity,
+				"%s",
+				err.Error(),
 			)
 			return nil, errServerCreateNotPossible
 		}
+		opts.PlacementGroup = &hcloud.PlacementGroup{
+			ID:   pgSts.ID,
+			Name: pgSts.Name,
+			Type: hcloud.PlacementGroupType(pgSts.Type),
+		}
 	}
 
 	sshKeySpecs := s.scope.HCloudMachine.Spec.SSHKeys
@@ -413,25 +529,7 @@ func (s *Service) createServer(ctx context.Context) (*hcloud.Server, error) {
 	}
 
 	// always add ssh key from secret if one is found
-
-	// this code is redundant with a similar one on cluster level but is necessary if ClusterClass is used
-	// as in ClusterClass we cannot store anything in HetznerCluster object
-	sshKeyName := s.scope.HetznerSecret().Data[s.scope.HetznerCluster.Spec.HetznerSecret.Key.SSHKey]
-	if len(sshKeyName) > 0 {
-		// Check if the SSH key name already exists
-		keyExists := false
-		for _, key := range sshKeySpecs {
-			if string(sshKeyName) == key.Name {
-				keyExists = true
-				break
-			}
-		}
-
-		// If the SSH key name doesn't exist, append it
-		if !keyExists {
-			sshKeySpecs = append(sshKeySpecs, infrav1.SSHKey{Name: string(sshKeyName)})
-		}
-	}
+	sshKeySpecs = s.withSecretSSHKey(sshKeySpecs)
 
 	// get all ssh keys that are stored in HCloud API
 	sshKeysAPI, err := s.scope.HCloudClient.ListSSHKeys(ctx, hcloud.SSHKeyListOpts{})
@@ -466,8 +564,11 @@ func (s *Service) createServer(ctx context.Context) (*hcloud.Server, error) {
 	}
 
 	// Create the server
-	server, err := s.scope.HCloudClient.CreateServer(ctx, opts)
+	server, err := s.createServerWithFallback(ctx, opts)
 	if err != nil {
+		if errors.Is(err, errServerCreateNotPossible) {
+			return nil, err
+		}
 		if hcloudutil.HandleRateLimitExceeded(s.scope.HCloudMachine, err, "CreateServer") {
 			// RateLimit was reached. Condition and Event got already created.
 			return nil, fmt.Errorf("failed to create HCloud server %s: %w", s.scope.HCloudMachine.Name, err)
@@ -499,77 +600,146 @@ func (s *Service) createServer(ctx context.Context) (*hcloud.Server, error) {
 	return server, nil
 }
 
-func (s *Service) getServerImage(ctx context.Context) (*hcloud.Image, error) {
-	key := fmt.Sprintf("%s%s", infrav1.NameHetznerProviderPrefix, "image-name")
+// serverCandidate is a combination of server type and location in which the server can be created.
+type serverCandidate struct {
+	serverType infrav1.HCloudMachineType
+	location   infrav1.Region
+}
 
-	// Get server type so we can filter for images with correct architecture
-	serverType, err := s.scope.HCloudClient.GetServerType(ctx, string(s.scope.HCloudMachine.Spec.Type))
-	if err != nil {
-		return nil, handleRateLimit(s.scope.HCloudMachine, err, "GetServerType", "failed to get server type in HCloud")
+// serverCandidates returns the combinations of server type and location in the order in which they are tried.
+// All server types are tried in the location of the failure domain before the fallback locations are tried.
+func serverCandidates(spec infrav1.HCloudMachineSpec, failureDomain infrav1.Region) []serverCandidate {
+	serverTypes := append([]infrav1.HCloudMachineType{spec.Type}, spec.FallbackTypes...)
+
+	locations := []infrav1.Region{failureDomain}
+	for _, location := range spec.FallbackLocations {
+		// servers in other network zones cannot be attached to the network of the cluster
+		if location == failureDomain || location.NetworkZone() != failureDomain.NetworkZone() {
+			continue
+		}
+		locations = append(locations, location)
 	}
-	if serverType == nil {
+
+	candidates := make([]serverCandidate, 0, len(serverTypes)*len(locations))
+	for _, location := range locations {
+		for _, serverType := range serverTypes {
+			candidates = append(candidates, serverCandidate{serverType: serverType, location: location})
+		}
+	}
+	return candidates
+}
+
+// isServerUnavailableError returns whether HCloud has no capacity for the server type in the location.
+func isServerUnavailableError(err error) bool {
+	return hcloud.IsError(err, hcloud.ErrorCodeResourceUnavailable) || hcloud.IsError(err, hcloud.ErrorCodePlacementError)
+}
+
+// createServerWithFallback creates the server with the first combination of server type and location that HCloud
+// has capacity for. Other errors than capacity errors are returned immediately.
+func (s *Service) createServerWithFallback(ctx context.Context, opts hcloud.ServerCreateOpts) (*hcloud.Server, error) {
+	images := make(map[hcloud.Architecture]*hcloud.Image)
+	var unavailable []string
+
+	for _, candidate := range serverCandidates(s.scope.HCloudMachine.Spec, s.scope.HCloudMachine.Status.Region) {
+		serverType, err := s.getServerType(ctx, candidate.serverType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get server type: %w", err)
+		}
+
+		image, found := images[serverType.Architecture]
+		if !found {
+			image, err = s.getServerImage(ctx, serverType.Architecture)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get server image: %w", err)
+			}
+			images[serverType.Architecture] = image
+		}
+
+		opts.Image = image
+		opts.ServerType = &hcloud.ServerType{Name: string(candidate.serverType)}
+		opts.Location = &hcloud.Location{Name: string(candidate.location)}
+
+		server, err := s.scope.HCloudClient.CreateServer(ctx, opts)
+		if err == nil {
+			if len(unavailable) > 0 {
+				record.Eventf(s.scope.HCloudMachine,
+					"ServerTypeFallbackUsed",
+					"Created server with server type %s in location %s because HCloud had no capacity for %s",
+					candidate.serverType,
+					candidate.location,
+					strings.Join(unavailable, ", "),
+				)
+			}
+			return server, nil
+		}
+		if !isServerUnavailableError(err) {
+			return nil, err
+		}
+
+		record.Warnf(s.scope.HCloudMachine,
+			"ServerTypeUnavailable",
+			"Server type %s is not available in location %s: %s",
+			candidate.serverType,
+			candidate.location,
+			err,
+		)
+		unavailable = append(unavailable, fmt.Sprintf("%s in %s", candidate.serverType, candidate.location))
+	}
+
+	conditions.MarkFalse(s.scope.HCloudMachine,
+		infrav1.ServerCreateSucceededCondition,
+		infrav1.ServerTypeUnavailableReason,
+		clusterv1.ConditionSeverityWarning,
+		"no capacity for %s",
+		strings.Join(unavailable, ", "),
+	)
+	return nil, errServerCreateNotPossible
+}
+
+// checkNetworkForPrivateOnlyServer makes sure that a private-only server is created in the network of the cluster.
+func (s *Service) checkNetworkForPrivateOnlyServer() error {
+	if !s.scope.HetznerCluster.Spec.HCloudNetwork.Enabled {
 		conditions.MarkFalse(
 			s.scope.HCloudMachine,
 			infrav1.ServerCreateSucceededCondition,
-			infrav1.ServerTypeNotFoundReason,
+			infrav1.NetworkNotReadyReason,
 			clusterv1.ConditionSeverityError,
-			"failed to get server type - nil type",
+			"servers without public IPv4 and IPv6 require an enabled network",
 		)
-		return nil, errServerCreateNotPossible
+		return errServerCreateNotPossible
 	}
 
-	// query for an existing image by label
-	// this is needed because snapshots don't have a name, only descriptions and labels
-	listOpts := hcloud.ImageListOpts{
-		ListOpts: hcloud.ListOpts{
-			LabelSelector: fmt.Sprintf("%s==%s", key, s.scope.HCloudMachine.Spec.ImageName),
-		},
-		Architecture: []hcloud.Architecture{serverType.Architecture},
+	if s.scope.HetznerCluster.Status.Network == nil {
+		conditions.MarkFalse(
+			s.scope.HCloudMachine,
+			infrav1.ServerCreateSucceededCondition,
+			infrav1.NetworkNotReadyReason,
+			clusterv1.ConditionSeverityInfo,
+			"waiting for the network of the cluster",
+		)
+		return errNetworkNotReady
 	}
 
-	images, err := s.scope.HCloudClient.ListImages(ctx, listOpts)
-	if err != nil {
-		return nil, handleRateLimit(s.scope.HCloudMachine, err, "ListImages", "failed to list images by label in HCloud")
-	}
+	return nil
+}
 
-	// query for an existing image by name.
-	listOpts = hcloud.ImageListOpts{
-		Name:         s.scope.HCloudMachine.Spec.ImageName,
-		Architecture: []hcloud.Architecture{serverType.Architecture},
-	}
-	imagesByName, err := s.scope.HCloudClient.ListImages(ctx, listOpts)
+func (s *Service) getServerType(ctx context.Context, name infrav1.HCloudMachineType) (*hcloud.ServerType, error) {
+	serverType, err := s.scope.HCloudClient.GetServerType(ctx, string(name))
 	if err != nil {
-		return nil, handleRateLimit(s.scope.HCloudMachine, err, "ListImages", "failed to list images by name in HCloud")
-	}
-
-	images = append(images, imagesByName...)
-
-	if len(images) > 1 {
-		err := fmt.Errorf("image is ambiguous - %d images have name %s", len(images), s.scope.HCloudMachine.Spec.ImageName)
-		record.Warnf(s.scope.HCloudMachine, "ImageNameAmbiguous", err.Error())
-		conditions.MarkFalse(s.scope.HCloudMachine,
-			infrav1.ServerCreateSucceededCondition,
-			infrav1.ImageAmbiguousReason,
-			clusterv1.ConditionSeverityError,
-			"%s",
-			err.Error(),
-		)
-		return nil, errServerCreateNotPossible
+		return nil, handleRateLimit(s.scope.HCloudMachine, err, "GetServerType", "failed to get server type in HCloud")
 	}
-	if len(images) == 0 {
-		err := fmt.Errorf("no image found with name %s", s.scope.HCloudMachine.Spec.ImageName)
-		record.Warnf(s.scope.HCloudMachine, "ImageNotFound", err.Error())
-		conditions.MarkFalse(s.scope.HCloudMachine,
+	if serverType == nil {
+		conditions.MarkFalse(
+			s.scope.HCloudMachine,
 			infrav1.ServerCreateSucceededCondition,
-			infrav1.ImageNotFoundReason,
+			infrav1.ServerTypeNotFoundReason,
 			clusterv1.ConditionSeverityError,
-			"%s",
-			err.Error(),
+			"failed to get server type %s - nil type",
+			name,
 		)
 		return nil, errServerCreateNotPossible
 	}
-
-	return images[0], nil
+	return serverType, nil
 }
 
 func (s *Service) handleServerStatusOff(ctx context.Context, server *hcloud.Server) (res reconcile.Result, err error) {
@@ -617,37 +787,70 @@ func (s *Service) handleServerStatusOff(ctx context.Context, server *hcloud.Serv
 	return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 }
 
+// handleDeleteServerStatusRunning asks the operating system of the server to shut down. If it does not shut down
+// within the graceful shutdown timeout, the server is powered off.
 func (s *Service) handleDeleteServerStatusRunning(ctx context.Context, server *hcloud.Server) (res reconcile.Result, err error) {
-	// Shut down the server if one of the two conditions apply:
-	// 1. The server has not yet been tried to shut down and still is marked as "ready".
-	// 2. The server has been tried to shut down without an effect and the timeout is not reached yet.
+	hm := s.scope.HCloudMachine
+	timeout := hm.Spec.GracefulShutdownTimeout()
 
-	if s.scope.HasServerAvailableCondition() {
+	// request the graceful shutdown once
+	if !conditions.Has(hm, infrav1.ServerShutdownCondition) && timeout > 0 {
 		if err := s.scope.HCloudClient.ShutdownServer(ctx, server); err != nil {
-			return reconcile.Result{}, handleRateLimit(s.scope.HCloudMachine, err, "ShutdownServer", "failed to shutdown server")
+			return reconcile.Result{}, handleRateLimit(hm, err, "ShutdownServer", "failed to shutdown server")
 		}
 
-		conditions.MarkFalse(s.scope.HCloudMachine,
+		conditions.MarkFalse(hm,
 			infrav1.ServerAvailableCondition,
 			infrav1.ServerTerminatingReason,
 			clusterv1.ConditionSeverityInfo,
 			"Instance has been shut down",
 		)
+		conditions.MarkFalse(hm,
+			infrav1.ServerShutdownCondition,
+			infrav1.GracefulShutdownRequestedReason,
+			clusterv1.ConditionSeverityInfo,
+			"waiting up to %s for the graceful shutdown",
+			timeout,
+		)
 
-		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+		return reconcile.Result{RequeueAfter: min(timeout, shutdownRequeueAfter)}, nil
 	}
 
-	// timeout for shutdown has been reached - delete server
-	if err := s.scope.HCloudClient.DeleteServer(ctx, server); err != nil {
-		record.Warnf(s.scope.HCloudMachine, "FailedDeleteHCloudServer", "Failed to delete HCloud server %s", s.scope.Name())
-		return reconcile.Result{}, handleRateLimit(s.scope.HCloudMachine, err, "DeleteServer", "failed to delete server")
+	// wait for the graceful shutdown until the timeout has been reached. The condition must not be updated while
+	// waiting, as its lastTransitionTime marks the time of the shutdown request.
+	if conditions.GetReason(hm, infrav1.ServerShutdownCondition) == infrav1.GracefulShutdownRequestedReason {
+		waited := time.Since(conditions.GetLastTransitionTime(hm, infrav1.ServerShutdownCondition).Time)
+		if waited < timeout {
+			return reconcile.Result{RequeueAfter: min(timeout-waited, shutdownRequeueAfter)}, nil
+		}
 	}
 
-	record.Eventf(s.scope.HCloudMachine, "HCloudServerDeleted", "HCloud server %s deleted", s.scope.Name())
-	return res, nil
+	// the graceful shutdown timed out or is disabled - power the server off
+	if err := s.scope.HCloudClient.PowerOffServer(ctx, server); err != nil {
+		return reconcile.Result{}, handleRateLimit(hm, err, "PowerOffServer", "failed to power off server")
+	}
+
+	conditions.MarkFalse(hm,
+		infrav1.ServerAvailableCondition,
+		infrav1.ServerTerminatingReason,
+		clusterv1.ConditionSeverityInfo,
+		"Instance has been powered off",
+	)
+	conditions.MarkFalse(hm,
+		infrav1.ServerShutdownCondition,
+		infrav1.ServerPoweredOffReason,
+		clusterv1.ConditionSeverityWarning,
+		"server has been powered off after a graceful shutdown timeout of %s",
+		timeout,
+	)
+	record.Warnf(hm, "ServerPoweredOff", "Powered off HCloud server %s after a graceful shutdown timeout of %s", server.Name, timeout)
+
+	return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
 }
 
 func (s *Service) handleDeleteServerStatusOff(ctx context.Context, server *hcloud.Server) (res reconcile.Result, err error) {
+	conditions.MarkTrue(s.scope.HCloudMachine, infrav1.ServerShutdownCondition)
+
 	// server is off and can be deleted
 	if err := s.scope.HCloudClient.DeleteServer(ctx, server); err != nil {
 		record.Warnf(s.scope.HCloudMachine, "FailedDeleteHCloudServer", "Failed to delete HCloud server %s", s.scope.Name())
@@ -655,9 +858,37 @@ func (s *Service) handleDeleteServerStatusOff(ctx context.Context, server *hclou
 	}
 
 	record.Eventf(s.scope.HCloudMachine, "HCloudServerDeleted", "HCloud server %s deleted", s.scope.Name())
+
+	// the volumes are deleted in the next reconcile loop
+	if s.hasVolumes() {
+		return reconcile.Result{RequeueAfter: volumeDetachRequeueAfter}, nil
+	}
 	return res, nil
 }
 
+// deleteMachineTypeLabel removes the label that the label selector targets of the load balancers select, so that the
+// server does not get traffic anymore while it is deleted.
+func (s *Service) deleteMachineTypeLabel(ctx context.Context, server *hcloud.Server) error {
+	if _, found := server.Labels[infrav1.MachineTypeTagKey]; !found {
+		return nil
+	}
+
+	labels := maps.Clone(server.Labels)
+	delete(labels, infrav1.MachineTypeTagKey)
+	if _, err := s.scope.HCloudClient.UpdateServer(ctx, server, hcloud.ServerUpdateOpts{Labels: labels}); err != nil {
+		return handleRateLimit(s.scope.HCloudMachine, err, "UpdateServer", "failed to update labels of server")
+	}
+	server.Labels = labels
+
+	record.Eventf(
+		s.scope.HetznerCluster,
+		"DeletedTargetOfLoadBalancer",
+		"Removed label %s of server %s with ID %d to remove it from the label selector targets of the load balancers",
+		infrav1.MachineTypeTagKey, server.Name, server.ID,
+	)
+	return nil
+}
+
 func (s *Service) deleteServerOfLoadBalancer(ctx context.Context, server *hcloud.Server) error {
 	lb := &hcloud.LoadBalancer{ID: s.scope.HetznerCluster.Status.ControlPlaneLoadBalancer.ID}
 
@@ -700,7 +931,7 @@ func (s *Service) findServer(ctx context.Context) (*hcloud.Server, error) {
 	// server has not been found via id - try to find the server based on its labels
 	opts := hcloud.ServerListOpts{}
 
-	opts.LabelSelector = utils.LabelsToLabelSelector(s.createLabels())
+	opts.LabelSelector = utils.LabelsToLabelSelector(s.identifyingLabels())
 
 	servers, err := s.scope.HCloudClient.ListServers(ctx, opts)
 	if err != nil {
@@ -771,25 +1002,162 @@ func statusFromHCloudServer(server *hcloud.Server) infrav1.HCloudMachineStatus {
 		)
 	}
 
-	return infrav1.HCloudMachineStatus{
+	status := infrav1.HCloudMachineStatus{
 		InstanceState: &instanceState,
 		Addresses:     addresses,
 	}
+
+	// record the server type and location, which differ from the spec if a fallback has been used
+	if server.ServerType != nil {
+		status.ServerType = infrav1.HCloudMachineType(server.ServerType.Name)
+	}
+	if server.Datacenter != nil && server.Datacenter.Location != nil {
+		status.Region = infrav1.Region(server.Datacenter.Location.Name)
+	}
+	if server.Image != nil {
+		status.ImageID = server.Image.ID
+	}
+
+	return status
 }
 
 func (s *Service) createLabels() map[string]string {
 	var machineType string
 	if s.scope.IsControlPlane() {
-		machineType = "control_plane"
+		machineType = infrav1.MachineTypeControlPlane
 	} else {
-		machineType = "worker"
+		machineType = infrav1.MachineTypeWorker
 	}
 
 	return map[string]string{
 		infrav1.NameHetznerProviderOwned + s.scope.HetznerCluster.Name: string(infrav1.ResourceLifecycleOwned),
 		infrav1.MachineNameTagKey:                                      s.scope.Name(),
-		"machine_type":                                                 machineType,
+		infrav1.MachineTypeTagKey:                                      machineType,
+	}
+}
+
+// identifyingLabels returns the labels that the server of the machine is expected to have. The machine type label is
+// removed from servers that are being deleted to take them out of the label selector target of the load balancer.
+func (s *Service) identifyingLabels() map[string]string {
+	labels := s.createLabels()
+	if conditions.Has(s.scope.Machine, clusterv1.PreDrainDeleteHookSucceededCondition) {
+		delete(labels, infrav1.MachineTypeTagKey)
+	}
+	return labels
+}
+
+// placementGroupsForCreation returns the placement groups of the cluster. For a sharded placement group, the
+// servers of the shards are read from HCloud, because the status of the HetznerCluster might be outdated. It
+// also returns the number of machines of the placement group that started the creation before this machine.
+func (s *Service) placementGroupsForCreation(ctx context.Context, name string) ([]infrav1.HCloudPlacementGroupStatus, int, error) {
+	placementGroups := s.scope.HetznerCluster.Status.HCloudPlacementGroups
+	if !slices.ContainsFunc(placementGroups, func(pgSts infrav1.HCloudPlacementGroupStatus) bool {
+		return pgSts.ShardOf == name
+	}) {
+		return placementGroups, 0, nil
+	}
+
+	opts := hcloud.PlacementGroupListOpts{}
+	opts.LabelSelector = utils.LabelsToLabelSelector(map[string]string{
+		s.scope.HetznerCluster.ClusterTagKey(): string(infrav1.ResourceLifecycleOwned),
+	})
+	hcloudPlacementGroups, err := s.scope.HCloudClient.ListPlacementGroups(ctx, opts)
+	if err != nil {
+		return nil, 0, handleRateLimit(s.scope.HCloudMachine, err, "ListPlacementGroups", "failed to list placement groups")
+	}
+	servers := make(map[int64][]int64, len(hcloudPlacementGroups))
+	for _, pg := range hcloudPlacementGroups {
+		servers[pg.ID] = pg.Servers
+	}
+
+	current := make([]infrav1.HCloudPlacementGroupStatus, 0, len(placementGroups))
+	for _, pgSts := range placementGroups {
+		if pgSts.ShardOf == name {
+			pgServers, ok := servers[pgSts.ID]
+			if !ok {
+				// the shard has been deleted
+				continue
+			}
+			pgSts.Server = pgServers
+		}
+		current = append(current, pgSts)
+	}
+
+	hcloudMachines, err := s.scope.ListHCloudMachinesInCreation(ctx, name)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list HCloudMachines in creation: %w", err)
+	}
+	var inCreationBefore int
+	for _, hm := range hcloudMachines {
+		if hm.CreationTimestamp.Before(&s.scope.HCloudMachine.CreationTimestamp) ||
+			(hm.CreationTimestamp.Equal(&s.scope.HCloudMachine.CreationTimestamp) && hm.Name < s.scope.HCloudMachine.Name) {
+			inCreationBefore++
+		}
+	}
+
+	return current, inCreationBefore, nil
+}
+
+// findPlacementGroup returns the placement group with the given name. For a sharded placement group,
+// the least-filled shard is returned. The given number of machines in creation gets a shard first, as
+// they might not have a server in the shard yet.
+func findPlacementGroup(placementGroups []infrav1.HCloudPlacementGroupStatus, name string, inCreation int) (*infrav1.HCloudPlacementGroupStatus, error) {
+	var shards []int
+	for i, pgSts := range placementGroups {
+		if pgSts.ShardOf == "" && pgSts.Name == name {
+			return &placementGroups[i], nil
+		}
+		if pgSts.ShardOf == name {
+			shards = append(shards, i)
+		}
+	}
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("placement group %q does not exist in cluster", name)
+	}
+
+	servers := make(map[int]int, len(shards))
+	for _, i := range shards {
+		servers[i] = len(placementGroups[i].Server)
+	}
+
+	found := -1
+	for range inCreation + 1 {
+		found = -1
+		for _, i := range shards {
+			if servers[i] >= infrav1.MaxServersPerPlacementGroup {
+				continue
+			}
+			if found == -1 || servers[i] < servers[found] ||
+				(servers[i] == servers[found] && placementGroups[i].ID < placementGroups[found].ID) {
+				found = i
+			}
+		}
+		if found == -1 {
+			return nil, fmt.Errorf("%w: all shards of placement group %q are full", errPlacementGroupFull, name)
+		}
+		servers[found]++
 	}
+	return &placementGroups[found], nil
+}
+
+// withSecretSSHKey adds the ssh key whose name is stored in the Hetzner secret to the ssh keys if it is missing.
+//
+// This is redundant with a similar code on cluster level but is necessary if ClusterClass is used
+// as in ClusterClass we cannot store anything in HetznerCluster object.
+func (s *Service) withSecretSSHKey(sshKeySpecs []infrav1.SSHKey) []infrav1.SSHKey {
+	sshKeyName := s.scope.HetznerSecret().Data[s.scope.HetznerCluster.Spec.HetznerSecret.Key.SSHKey]
+	if len(sshKeyName) == 0 {
+		return sshKeySpecs
+	}
+
+	// Check if the SSH key name already exists
+	for _, key := range sshKeySpecs {
+		if string(sshKeyName) == key.Name {
+			return sshKeySpecs
+		}
+	}
+
+	return append(sshKeySpecs, infrav1.SSHKey{Name: string(sshKeyName)})
 }
 
 func filterHCloudSSHKeys(sshKeysAPI []*hcloud.SSHKey, sshKeysSpec []infrav1.SSHKey) ([]*hcloud.SSHKey, error) {
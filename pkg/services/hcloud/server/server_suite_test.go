@@ -18,6 +18,7 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -25,11 +26,20 @@ import (
 	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2/textlogger"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
 	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
 )
 
 const serverJSON = `
@@ -220,3 +230,42 @@ func newTestService(hcloudMachine *infrav1.HCloudMachine, hcloudClient hcloudcli
 		},
 	}
 }
+
+// newTestServiceWithServer returns a service with a machine scope like the one of the controller and its own fake
+// HCloud client, in which the server of the machine has been created with the name of the machine and the labels of
+// the controller. The client of the scope contains the HCloudMachine and the given objects.
+func newTestServiceWithServer(params scope.MachineScopeParams, opts hcloud.ServerCreateOpts, objects ...client.Object) (*Service, *hcloud.Server) {
+	if params.Cluster == nil {
+		params.Cluster = &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+	}
+	if params.HetznerCluster == nil {
+		params.HetznerCluster = &infrav1.HetznerCluster{ObjectMeta: metav1.ObjectMeta{Name: "hetzner-cluster", Namespace: "default"}}
+	}
+	if params.HetznerSecret == nil {
+		params.HetznerSecret = &corev1.Secret{}
+	}
+	if params.Machine == nil {
+		params.Machine = &clusterv1.Machine{}
+	}
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(infrav1.AddToScheme(scheme))
+	utilruntime.Must(clusterv1.AddToScheme(scheme))
+	c := fakectrlclient.NewClientBuilder().WithScheme(scheme).WithObjects(append(objects, params.HCloudMachine)...).Build()
+
+	params.Client = c
+	params.APIReader = c
+	params.Logger = textlogger.NewLogger(textlogger.NewConfig())
+	params.HCloudClient = fakeclient.NewHCloudClient()
+
+	machineScope, err := scope.NewMachineScope(params)
+	Expect(err).To(Succeed())
+	service := NewService(machineScope)
+
+	opts.Name = params.HCloudMachine.Name
+	opts.Labels = service.serverLabels()
+	server, err := service.scope.HCloudClient.CreateServer(context.Background(), opts)
+	Expect(err).To(Succeed())
+	return service, server
+}
@@ -24,16 +24,21 @@ import (
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	fakectrlclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
 	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/mocks"
 )
 
 var _ = Describe("statusFromHCloudServer", func() {
@@ -57,6 +62,13 @@ var _ = Describe("statusFromHCloudServer", func() {
 			Expect(addr.Type).To(Equal(addressTypes[i]))
 		}
 	})
+	It("should have the server type and location of the server", func() {
+		Expect(sts.ServerType).To(Equal(infrav1.HCloudMachineType("cx11")))
+		Expect(sts.Region).To(Equal(infrav1.Region("fsn1")))
+	})
+	It("should have the ID of the image of the server", func() {
+		Expect(sts.ImageID).To(Equal(int64(42)))
+	})
 })
 
 type testCaseStatusFromHCloudServer struct {
@@ -302,6 +314,177 @@ var _ = Describe("handleServerStatusOff", func() {
 	})
 })
 
+var _ = Describe("handleDeleteServerStatusRunning", func() {
+	var hcloudMachine *infrav1.HCloudMachine
+	client := fakeclient.NewHCloudClientFactory().NewClient("")
+
+	server, err := client.CreateServer(context.Background(), hcloud.ServerCreateOpts{Name: "deleteServerName"})
+	Expect(err).To(Succeed())
+
+	BeforeEach(func() {
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hcloudMachineName",
+				Namespace: "default",
+			},
+			Spec: infrav1.HCloudMachineSpec{
+				ImageName: "my-control-plane",
+				Type:      "cpx31",
+			},
+		}
+
+		server.Status = hcloud.ServerStatusRunning
+	})
+
+	It("requests a graceful shutdown", func() {
+		service := newTestService(hcloudMachine, client)
+
+		res, err := service.handleDeleteServerStatusRunning(context.Background(), server)
+		Expect(err).To(Succeed())
+		Expect(res).Should(Equal(reconcile.Result{RequeueAfter: shutdownRequeueAfter}))
+
+		Expect(server.Status).To(Equal(hcloud.ServerStatusOff))
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerShutdownCondition)).To(Equal(infrav1.GracefulShutdownRequestedReason))
+	})
+
+	It("powers the server off once the graceful shutdown timed out", func() {
+		hcloudMachine.Spec.DeletionPolicy = &infrav1.HCloudDeletionPolicy{GracefulShutdownTimeout: &metav1.Duration{Duration: 2 * time.Second}}
+
+		service := newTestService(hcloudMachine, client)
+
+		_, err := service.handleDeleteServerStatusRunning(context.Background(), server)
+		Expect(err).To(Succeed())
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerShutdownCondition)).To(Equal(infrav1.GracefulShutdownRequestedReason))
+
+		// the operating system ignores the shutdown request
+		server.Status = hcloud.ServerStatusRunning
+
+		res, err := service.handleDeleteServerStatusRunning(context.Background(), server)
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(server.Status).To(Equal(hcloud.ServerStatusRunning))
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerShutdownCondition)).To(Equal(infrav1.GracefulShutdownRequestedReason))
+
+		Eventually(func() hcloud.ServerStatus {
+			_, err := service.handleDeleteServerStatusRunning(context.Background(), server)
+			Expect(err).To(Succeed())
+			return server.Status
+		}, 5*time.Second, 200*time.Millisecond).Should(Equal(hcloud.ServerStatusOff))
+
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerShutdownCondition)).To(Equal(infrav1.ServerPoweredOffReason))
+	})
+
+	It("powers the server off right away if the graceful shutdown is disabled", func() {
+		hcloudMachine.Spec.DeletionPolicy = &infrav1.HCloudDeletionPolicy{GracefulShutdownTimeout: &metav1.Duration{}}
+
+		service := newTestService(hcloudMachine, client)
+
+		_, err := service.handleDeleteServerStatusRunning(context.Background(), server)
+		Expect(err).To(Succeed())
+
+		Expect(server.Status).To(Equal(hcloud.ServerStatusOff))
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerShutdownCondition)).To(Equal(infrav1.ServerPoweredOffReason))
+	})
+})
+
+var _ = Describe("reconcileLoadBalancerAttachment", func() {
+	It("removes a deleted server from the label selector target of the load balancer", func() {
+		client := mocks.NewClient(GinkgoT())
+		server := &hcloud.Server{
+			ID:   42,
+			Name: "control-plane-server",
+			Labels: map[string]string{
+				"caph-cluster-hetzner-cluster": "owned",
+				infrav1.MachineTypeTagKey:      infrav1.MachineTypeControlPlane,
+			},
+		}
+
+		hetznerCluster := &infrav1.HetznerCluster{}
+		hetznerCluster.Name = "hetzner-cluster"
+		hetznerCluster.Spec.ControlPlaneLoadBalancer.UseLabelSelectorTarget = true
+		hetznerCluster.Status.ControlPlaneLoadBalancer = &infrav1.LoadBalancerStatus{ID: 1}
+
+		machine := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{clusterv1.MachineControlPlaneLabel: ""}},
+			Spec: clusterv1.MachineSpec{
+				FailureDomain: ptr.To("fsn1"),
+				Bootstrap:     clusterv1.Bootstrap{DataSecretName: ptr.To("bootstrap-secret")},
+			},
+		}
+		conditions.MarkTrue(machine, clusterv1.PreDrainDeleteHookSucceededCondition)
+
+		hcloudMachine := &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "control-plane-server"},
+			Spec:       infrav1.HCloudMachineSpec{ProviderID: ptr.To("hcloud://42")},
+		}
+		server.Labels[infrav1.MachineNameTagKey] = hcloudMachine.Name
+
+		service := newTestService(hcloudMachine, client)
+		service.scope.HetznerCluster = hetznerCluster
+		service.scope.Machine = machine
+
+		client.On("UpdateServer", mock.Anything, server, hcloud.ServerUpdateOpts{
+			Labels: map[string]string{
+				"caph-cluster-hetzner-cluster": "owned",
+				infrav1.MachineNameTagKey:      hcloudMachine.Name,
+			},
+		}).Return(server, nil).Once()
+		client.On("DeleteTargetServerOfLoadBalancer", mock.Anything, mock.Anything, server).Return(
+			hcloud.Error{Code: "load_balancer_target_not_found", Message: "target not found"},
+		).Twice()
+
+		_, err := service.reconcileLoadBalancerAttachment(context.Background(), server)
+		Expect(err).To(Succeed())
+		Expect(server.Labels).ToNot(HaveKey(infrav1.MachineTypeTagKey))
+
+		// the missing machine type label does not let the next reconcile fail the machine
+		server.Status = hcloud.ServerStatusRunning
+		client.On("GetServer", mock.Anything, server.ID).Return(server, nil).Once()
+
+		_, err = service.Reconcile(context.Background())
+		Expect(err).To(Succeed())
+		Expect(hcloudMachine.Status.FailureReason).To(BeNil())
+		Expect(hcloudMachine.Status.FailureMessage).To(BeNil())
+	})
+
+	It("removes a deleted server from the label selector targets of the additional load balancers", func() {
+		client := mocks.NewClient(GinkgoT())
+		server := &hcloud.Server{
+			ID:   42,
+			Name: "worker-server",
+			Labels: map[string]string{
+				"caph-cluster-hetzner-cluster": "owned",
+				infrav1.MachineNameTagKey:      "worker-server",
+				infrav1.MachineTypeTagKey:      infrav1.MachineTypeWorker,
+			},
+		}
+
+		hetznerCluster := &infrav1.HetznerCluster{}
+		hetznerCluster.Name = "hetzner-cluster"
+		hetznerCluster.Spec.AdditionalLoadBalancers = []infrav1.AdditionalLoadBalancerSpec{{Name: "ingress"}}
+
+		machine := &clusterv1.Machine{}
+		conditions.MarkTrue(machine, clusterv1.PreDrainDeleteHookSucceededCondition)
+
+		hcloudMachine := &infrav1.HCloudMachine{ObjectMeta: metav1.ObjectMeta{Name: "worker-server"}}
+
+		service := newTestService(hcloudMachine, client)
+		service.scope.HetznerCluster = hetznerCluster
+		service.scope.Machine = machine
+
+		client.On("UpdateServer", mock.Anything, server, hcloud.ServerUpdateOpts{
+			Labels: map[string]string{
+				"caph-cluster-hetzner-cluster": "owned",
+				infrav1.MachineNameTagKey:      "worker-server",
+			},
+		}).Return(server, nil).Once()
+
+		_, err := service.reconcileLoadBalancerAttachment(context.Background(), server)
+		Expect(err).To(Succeed())
+		Expect(server.Labels).ToNot(HaveKey(infrav1.MachineTypeTagKey))
+	})
+})
+
 var _ = Describe("Test ValidateLabels", func() {
 	type testCaseValidateLabels struct {
 		gotLabels   map[string]string
@@ -443,3 +626,151 @@ func isPresentAndFalseWithReason(getter conditions.Getter, condition clusterv1.C
 	return objectCondition.Status == corev1.ConditionFalse &&
 		objectCondition.Reason == reason
 }
+
+var _ = Describe("findPlacementGroup", func() {
+	full := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	It("finds a placement group by name", func() {
+		pg, err := findPlacementGroup([]infrav1.HCloudPlacementGroupStatus{
+			{ID: 1, Name: "md-0"},
+			{ID: 2, Name: "control-plane"},
+		}, "control-plane", 0)
+		Expect(err).To(BeNil())
+		Expect(pg.ID).To(Equal(int64(2)))
+	})
+
+	It("assigns the least-filled shard that is not full", func() {
+		pg, err := findPlacementGroup([]infrav1.HCloudPlacementGroupStatus{
+			{ID: 1, Name: "workers-1", ShardOf: "workers", Server: full},
+			{ID: 2, Name: "workers-2", ShardOf: "workers", Server: []int64{11, 12}},
+			{ID: 3, Name: "workers-3", ShardOf: "workers", Server: []int64{13}},
+			{ID: 4, Name: "other-1", ShardOf: "other"},
+		}, "workers", 0)
+		Expect(err).To(BeNil())
+		Expect(pg.ID).To(Equal(int64(3)))
+	})
+
+	It("leaves the free capacity to machines that are in creation already", func() {
+		pg, err := findPlacementGroup([]infrav1.HCloudPlacementGroupStatus{
+			{ID: 1, Name: "workers-1", ShardOf: "workers", Server: full[:8]},
+			{ID: 2, Name: "workers-2", ShardOf: "workers", Server: full[:9]},
+			{ID: 3, Name: "workers-3", ShardOf: "workers", Server: full[:8]},
+		}, "workers", 3)
+		Expect(err).To(BeNil())
+		Expect(pg.ID).To(Equal(int64(2)))
+
+		_, err = findPlacementGroup([]infrav1.HCloudPlacementGroupStatus{
+			{ID: 1, Name: "workers-1", ShardOf: "workers", Server: full[:9]},
+		}, "workers", 1)
+		Expect(err).To(MatchError(errPlacementGroupFull))
+	})
+
+	It("fails if all shards are full", func() {
+		_, err := findPlacementGroup([]infrav1.HCloudPlacementGroupStatus{
+			{ID: 1, Name: "workers-1", ShardOf: "workers", Server: full},
+		}, "workers", 0)
+		Expect(err).To(MatchError(errPlacementGroupFull))
+	})
+
+	It("fails if the placement group does not exist", func() {
+		_, err := findPlacementGroup([]infrav1.HCloudPlacementGroupStatus{
+			{ID: 1, Name: "workers-1", ShardOf: "workers"},
+		}, "workers-2", 0)
+		Expect(err).ToNot(BeNil())
+		Expect(err).ToNot(MatchError(errPlacementGroupFull))
+	})
+})
+
+var _ = Describe("placementGroupsForCreation", func() {
+	It("reads the servers of the shards from HCloud and counts the machines in creation before", func() {
+		client := mocks.NewClient(GinkgoT())
+		client.On("ListPlacementGroups", mock.Anything, mock.Anything).Return([]*hcloud.PlacementGroup{
+			{ID: 1, Servers: []int64{1, 2}},
+		}, nil).Once()
+
+		now := time.Now().Truncate(time.Second)
+		hcloudMachine := func(name string, created time.Time) *infrav1.HCloudMachine {
+			return &infrav1.HCloudMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              name,
+					Namespace:         "default",
+					CreationTimestamp: metav1.NewTime(created),
+					Labels:            map[string]string{clusterv1.ClusterNameLabel: "cluster"},
+				},
+				Spec: infrav1.HCloudMachineSpec{PlacementGroupName: ptr.To("workers")},
+			}
+		}
+		self := hcloudMachine("worker-b", now)
+
+		scheme := runtime.NewScheme()
+		utilruntime.Must(infrav1.AddToScheme(scheme))
+		c := fakectrlclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+			self,
+			hcloudMachine("worker-a", now),
+			hcloudMachine("worker-c", now.Add(-time.Minute)),
+			hcloudMachine("worker-d", now.Add(time.Minute)),
+		).Build()
+
+		hetznerCluster := &infrav1.HetznerCluster{}
+		hetznerCluster.Name = "hetzner-cluster"
+		hetznerCluster.Namespace = "default"
+		hetznerCluster.Status.HCloudPlacementGroups = []infrav1.HCloudPlacementGroupStatus{
+			{ID: 1, Name: "workers-1", ShardOf: "workers"},
+			{ID: 2, Name: "workers-2", ShardOf: "workers"},
+			{ID: 3, Name: "control-plane"},
+		}
+
+		service := newTestService(self, client)
+		service.scope.Client = c
+		service.scope.Cluster = &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+		service.scope.HetznerCluster = hetznerCluster
+
+		placementGroups, inCreationBefore, err := service.placementGroupsForCreation(context.Background(), "workers")
+		Expect(err).To(Succeed())
+		Expect(inCreationBefore).To(Equal(2))
+		Expect(placementGroups).To(Equal([]infrav1.HCloudPlacementGroupStatus{
+			{ID: 1, Name: "workers-1", ShardOf: "workers", Server: []int64{1, 2}},
+			{ID: 3, Name: "control-plane"},
+		}))
+	})
+})
+
+var _ = Describe("checkNetworkForPrivateOnlyServer", func() {
+	var (
+		hcloudMachine  *infrav1.HCloudMachine
+		hetznerCluster *infrav1.HetznerCluster
+		service        *Service
+	)
+
+	BeforeEach(func() {
+		hcloudMachine = &infrav1.HCloudMachine{
+			Spec: infrav1.HCloudMachineSpec{
+				PublicNetwork: &infrav1.PublicNetworkSpec{EnableIPv4: false, EnableIPv6: false},
+			},
+		}
+		hetznerCluster = &infrav1.HetznerCluster{}
+		hetznerCluster.Spec.HCloudNetwork.Enabled = true
+
+		service = newTestService(hcloudMachine, nil)
+		service.scope.HetznerCluster = hetznerCluster
+	})
+
+	It("fails permanently if the network is disabled", func() {
+		hetznerCluster.Spec.HCloudNetwork.Enabled = false
+		Expect(service.checkNetworkForPrivateOnlyServer()).To(MatchError(errServerCreateNotPossible))
+
+		c := conditions.Get(hcloudMachine, infrav1.ServerCreateSucceededCondition)
+		Expect(c).ToNot(BeNil())
+		Expect(c.Reason).To(Equal(infrav1.NetworkNotReadyReason))
+		Expect(c.Severity).To(Equal(clusterv1.ConditionSeverityError))
+	})
+
+	It("waits for the network", func() {
+		Expect(service.checkNetworkForPrivateOnlyServer()).To(MatchError(errNetworkNotReady))
+	})
+
+	It("succeeds if the network exists", func() {
+		hetznerCluster.Status.Network = &infrav1.NetworkStatus{ID: 1}
+		Expect(service.checkNetworkForPrivateOnlyServer()).To(Succeed())
+	})
+})
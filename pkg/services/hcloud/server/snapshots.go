@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+// snapshotTimeFormat is the format of the time in the default name of a snapshot.
+const snapshotTimeFormat = "20060102150405"
+
+// reconcileBackups enables or disables the automatic backups of the server according to the spec.
+func (s *Service) reconcileBackups(ctx context.Context, server *hcloud.Server) error {
+	enabled := server.BackupWindow != ""
+	if s.scope.HCloudMachine.Spec.Backups == enabled {
+		return nil
+	}
+
+	if s.scope.HCloudMachine.Spec.Backups {
+		if err := s.scope.HCloudClient.EnableServerBackup(ctx, server); err != nil {
+			record.Warnf(s.scope.HCloudMachine, "FailedEnableBackups", "Failed to enable backups of server %s: %s", server.Name, err)
+			return handleRateLimit(s.scope.HCloudMachine, err, "EnableServerBackup", "failed to enable backups")
+		}
+		record.Eventf(s.scope.HCloudMachine, "BackupsEnabled", "Enabled backups of server %s", server.Name)
+		return nil
+	}
+
+	if err := s.scope.HCloudClient.DisableServerBackup(ctx, server); err != nil {
+		record.Warnf(s.scope.HCloudMachine, "FailedDisableBackups", "Failed to disable backups of server %s: %s", server.Name, err)
+		return handleRateLimit(s.scope.HCloudMachine, err, "DisableServerBackup", "failed to disable backups")
+	}
+	record.Eventf(s.scope.HCloudMachine, "BackupsDisabled", "Disabled backups of server %s", server.Name)
+	return nil
+}
+
+// reconcileSnapshot creates the snapshot that has been requested with the snapshot annotation. Snapshots of the
+// machine with the same name are deleted afterwards, so that the name refers to a single image. A snapshot that has
+// been created for the request before, but could not be stored in the status, is adopted instead of creating another
+// one.
+func (s *Service) reconcileSnapshot(ctx context.Context, server *hcloud.Server) error {
+	hm := s.scope.HCloudMachine
+	name, found := hm.Annotations[infrav1.SnapshotAnnotation]
+	if !found {
+		return nil
+	}
+
+	defaultName := name == ""
+	if defaultName {
+		name = defaultSnapshotName(s.scope.Name(), time.Now())
+	}
+	if errs := validation.IsValidLabelValue(name); len(errs) > 0 {
+		record.Warnf(hm, "InvalidSnapshotName", "Cannot take snapshot %q: %s", name, errs[0])
+		delete(hm.Annotations, infrav1.SnapshotAnnotation)
+		return nil
+	}
+
+	images, err := s.scope.HCloudClient.ListImages(ctx, hcloud.ImageListOpts{
+		ListOpts: hcloud.ListOpts{LabelSelector: fmt.Sprintf("%s==%s", infrav1.MachineNameTagKey, s.scope.Name())},
+	})
+	if err != nil {
+		return handleRateLimit(hm, err, "ListImages", "failed to list snapshots")
+	}
+
+	image := s.untrackedSnapshot(images, name, defaultName)
+	if image != nil {
+		name = image.Labels[imageNameLabelKey]
+		record.Eventf(hm, "SnapshotAdopted", "Adopted snapshot %s with ID %d of server %s", name, image.ID, server.Name)
+	} else {
+		// snapshots outlive the machine and the cluster, so they don't get the label of resources owned by the cluster
+		image, err = s.scope.HCloudClient.CreateImage(ctx, server, hcloud.ServerCreateImageOpts{
+			Type:        hcloud.ImageTypeSnapshot,
+			Description: hcloud.Ptr(name),
+			Labels: map[string]string{
+				infrav1.MachineNameTagKey: s.scope.Name(),
+				imageNameLabelKey:         name,
+			},
+		})
+		if err != nil {
+			record.Warnf(hm, "FailedCreateSnapshot", "Failed to create snapshot %s of server %s: %s", name, server.Name, err)
+			return handleRateLimit(hm, err, "CreateImage", fmt.Sprintf("failed to create snapshot %s", name))
+		}
+		record.Eventf(hm, "SnapshotCreated", "Created snapshot %s with ID %d of server %s", name, image.ID, server.Name)
+	}
+
+	// snapshots of the same name that are not in the status would make the name ambiguous
+	for _, other := range images {
+		if other.ID == image.ID || other.Labels[imageNameLabelKey] != name || isTrackedSnapshot(hm.Status.Snapshots, other.ID) {
+			continue
+		}
+		if err := s.scope.HCloudClient.DeleteImage(ctx, other.ID); err != nil && !hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+			record.Warnf(hm, "FailedDeleteSnapshot", "Failed to delete duplicate snapshot %s with ID %d: %s", name, other.ID, err)
+			return handleRateLimit(hm, err, "DeleteImage", fmt.Sprintf("failed to delete duplicate snapshot %s", name))
+		}
+		record.Eventf(hm, "SnapshotDeleted", "Deleted duplicate snapshot %s with ID %d", name, other.ID)
+	}
+
+	snapshots := []infrav1.HCloudSnapshotStatus{{Name: name, ID: image.ID, Created: metav1.NewTime(image.Created)}}
+	for _, snapshot := range hm.Status.Snapshots {
+		if snapshot.Name != name {
+			snapshots = append(snapshots, snapshot)
+			continue
+		}
+		if err := s.scope.HCloudClient.DeleteImage(ctx, snapshot.ID); err != nil && !hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+			// keep the old snapshot in the status, so that it is deleted with the next snapshot of the same name
+			record.Warnf(hm, "FailedDeleteSnapshot", "Failed to delete replaced snapshot %s with ID %d: %s", name, snapshot.ID, err)
+			snapshots = append(snapshots, snapshot)
+			continue
+		}
+		record.Eventf(hm, "SnapshotDeleted", "Deleted replaced snapshot %s with ID %d", name, snapshot.ID)
+	}
+
+	hm.Status.Snapshots = snapshots
+	delete(hm.Annotations, infrav1.SnapshotAnnotation)
+	return nil
+}
+
+// untrackedSnapshot returns the newest snapshot of the machine with the given name that is not in the status. If the
+// name is a default name, any snapshot with a default name is returned, as the time in the name differs between
+// attempts.
+func (s *Service) untrackedSnapshot(images []*hcloud.Image, name string, defaultName bool) *hcloud.Image {
+	var newest *hcloud.Image
+	for _, image := range images {
+		imageName := image.Labels[imageNameLabelKey]
+		if imageName == "" || isTrackedSnapshot(s.scope.HCloudMachine.Status.Snapshots, image.ID) {
+			continue
+		}
+		if imageName != name && (!defaultName || !isDefaultSnapshotName(s.scope.Name(), imageName)) {
+			continue
+		}
+		if newest == nil || image.Created.After(newest.Created) {
+			newest = image
+		}
+	}
+	return newest
+}
+
+func isTrackedSnapshot(snapshots []infrav1.HCloudSnapshotStatus, id int64) bool {
+	for _, snapshot := range snapshots {
+		if snapshot.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultSnapshotName returns the name of the machine with the time. The name of the machine is shortened, so that
+// the name is a valid label value.
+func defaultSnapshotName(machineName string, now time.Time) string {
+	suffix := "-" + now.UTC().Format(snapshotTimeFormat)
+	if maxLen := validation.LabelValueMaxLength - len(suffix); len(machineName) > maxLen {
+		machineName = machineName[:maxLen]
+	}
+	return machineName + suffix
+}
+
+// isDefaultSnapshotName returns whether the name is a default name of snapshots of the machine.
+func isDefaultSnapshotName(machineName, name string) bool {
+	i := strings.LastIndexByte(name, '-')
+	if i < 0 {
+		return false
+	}
+	t, err := time.Parse(snapshotTimeFormat, name[i+1:])
+	return err == nil && defaultSnapshotName(machineName, t) == name
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+)
+
+var _ = Describe("Backups and snapshots", func() {
+	var (
+		ctx           context.Context
+		client        hcloudclient.Client
+		hcloudMachine *infrav1.HCloudMachine
+		server        *hcloud.Server
+		service       *Service
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "snapshot-machine", Namespace: "default"},
+		}
+		service, server = newTestServiceWithServer(scope.MachineScopeParams{HCloudMachine: hcloudMachine}, hcloud.ServerCreateOpts{
+			ServerType: &hcloud.ServerType{Architecture: hcloud.ArchitectureX86},
+		})
+		client = service.scope.HCloudClient
+	})
+
+	It("enables and disables backups", func() {
+		hcloudMachine.Spec.Backups = true
+		Expect(service.reconcileBackups(ctx, server)).To(Succeed())
+		Expect(server.BackupWindow).ToNot(BeEmpty())
+
+		hcloudMachine.Spec.Backups = false
+		Expect(service.reconcileBackups(ctx, server)).To(Succeed())
+		Expect(server.BackupWindow).To(BeEmpty())
+	})
+
+	It("does nothing without the snapshot annotation", func() {
+		Expect(service.reconcileSnapshot(ctx, server)).To(Succeed())
+		Expect(hcloudMachine.Status.Snapshots).To(BeEmpty())
+	})
+
+	It("creates a labelled snapshot that can be used as image name", func() {
+		snapshotName := fmt.Sprintf("%s-before-upgrade", server.Name)
+		hcloudMachine.Annotations = map[string]string{infrav1.SnapshotAnnotation: snapshotName}
+
+		Expect(service.reconcileSnapshot(ctx, server)).To(Succeed())
+		Expect(hcloudMachine.Annotations).ToNot(HaveKey(infrav1.SnapshotAnnotation))
+		Expect(hcloudMachine.Status.Snapshots).To(ConsistOf(HaveField("Name", snapshotName)))
+
+		image, err := service.getServerImageByName(ctx, snapshotName, hcloud.ArchitectureX86)
+		Expect(err).To(Succeed())
+		Expect(image.ID).To(Equal(hcloudMachine.Status.Snapshots[0].ID))
+		Expect(image.Type).To(Equal(hcloud.ImageTypeSnapshot))
+		Expect(image.Labels).To(HaveKeyWithValue(infrav1.MachineNameTagKey, hcloudMachine.Name))
+		Expect(image.Labels).ToNot(HaveKey(infrav1.NameHetznerProviderOwned + "hetzner-cluster"))
+	})
+
+	It("adopts a snapshot whose status has not been stored", func() {
+		snapshotName := fmt.Sprintf("%s-before-upgrade", server.Name)
+		hcloudMachine.Annotations = map[string]string{infrav1.SnapshotAnnotation: snapshotName}
+		Expect(service.reconcileSnapshot(ctx, server)).To(Succeed())
+		id := hcloudMachine.Status.Snapshots[0].ID
+
+		// the status and the annotation could not be patched
+		hcloudMachine.Status.Snapshots = nil
+		hcloudMachine.Annotations = map[string]string{infrav1.SnapshotAnnotation: snapshotName}
+		Expect(service.reconcileSnapshot(ctx, server)).To(Succeed())
+		Expect(hcloudMachine.Status.Snapshots).To(ConsistOf(HaveField("ID", id)))
+
+		image, err := service.getServerImageByName(ctx, snapshotName, hcloud.ArchitectureX86)
+		Expect(err).To(Succeed())
+		Expect(image.ID).To(Equal(id))
+	})
+
+	It("adopts a snapshot with a default name whose status has not been stored", func() {
+		hcloudMachine.Annotations = map[string]string{infrav1.SnapshotAnnotation: ""}
+		Expect(service.reconcileSnapshot(ctx, server)).To(Succeed())
+		snapshot := hcloudMachine.Status.Snapshots[0]
+
+		hcloudMachine.Status.Snapshots = nil
+		hcloudMachine.Annotations = map[string]string{infrav1.SnapshotAnnotation: ""}
+		Expect(service.reconcileSnapshot(ctx, server)).To(Succeed())
+		Expect(hcloudMachine.Status.Snapshots).To(ConsistOf(And(HaveField("ID", snapshot.ID), HaveField("Name", snapshot.Name))))
+	})
+
+	It("deletes duplicates of the snapshot that are not in the status", func() {
+		snapshotName := fmt.Sprintf("%s-nightly", server.Name)
+		hcloudMachine.Annotations = map[string]string{infrav1.SnapshotAnnotation: snapshotName}
+		Expect(service.reconcileSnapshot(ctx, server)).To(Succeed())
+		oldID := hcloudMachine.Status.Snapshots[0].ID
+
+		// two attempts created a snapshot without storing it in the status
+		var duplicates []*hcloud.Image
+		for _, created := range []time.Time{time.Now().Add(-time.Hour), time.Now()} {
+			duplicate, err := client.CreateImage(ctx, server, hcloud.ServerCreateImageOpts{
+				Type: hcloud.ImageTypeSnapshot,
+				Labels: map[string]string{
+					infrav1.MachineNameTagKey: hcloudMachine.Name,
+					imageNameLabelKey:         snapshotName,
+				},
+			})
+			Expect(err).To(Succeed())
+			duplicate.Created = created
+			duplicates = append(duplicates, duplicate)
+		}
+
+		hcloudMachine.Annotations = map[string]string{infrav1.SnapshotAnnotation: snapshotName}
+		Expect(service.reconcileSnapshot(ctx, server)).To(Succeed())
+		Expect(hcloudMachine.Status.Snapshots).To(ConsistOf(HaveField("ID", duplicates[1].ID)))
+		Expect(client.GetImage(ctx, oldID)).To(BeNil())
+		Expect(client.GetImage(ctx, duplicates[0].ID)).To(BeNil())
+
+		_, err := service.getServerImageByName(ctx, snapshotName, hcloud.ArchitectureX86)
+		Expect(err).To(Succeed())
+	})
+
+	It("names the snapshot after the machine if the annotation is empty", func() {
+		hcloudMachine.Annotations = map[string]string{infrav1.SnapshotAnnotation: ""}
+
+		Expect(service.reconcileSnapshot(ctx, server)).To(Succeed())
+		Expect(hcloudMachine.Status.Snapshots).To(HaveLen(1))
+		Expect(hcloudMachine.Status.Snapshots[0].Name).To(HavePrefix(hcloudMachine.Name + "-"))
+	})
+
+	It("shortens long machine names in the default name of snapshots", func() {
+		name := defaultSnapshotName(strings.Repeat("a", 70), time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+		Expect(name).To(HaveSuffix("-20240102030405"))
+		Expect(validation.IsValidLabelValue(name)).To(BeEmpty())
+		Expect(isDefaultSnapshotName(strings.Repeat("a", 70), name)).To(BeTrue())
+		Expect(isDefaultSnapshotName(strings.Repeat("a", 70), "nightly")).To(BeFalse())
+	})
+})
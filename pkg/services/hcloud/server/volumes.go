@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+// volumeDetachRequeueAfter is the interval after which volumes that are still attached to a deleted server are checked again.
+const volumeDetachRequeueAfter = 5 * time.Second
+
+// reconcileVolumes creates the volumes of the machine in the location of the server and attaches them to it.
+func (s *Service) reconcileVolumes(ctx context.Context, server *hcloud.Server) error {
+	specs := s.scope.HCloudMachine.Spec.Volumes
+	if len(specs) == 0 {
+		return nil
+	}
+
+	volumes, err := s.findVolumes(ctx)
+	if err != nil {
+		return err
+	}
+
+	volumesByName := make(map[string]*hcloud.Volume, len(volumes))
+	for _, volume := range volumes {
+		volumesByName[volume.Labels[infrav1.VolumeNameTagKey]] = volume
+	}
+
+	statuses := make([]infrav1.HCloudVolumeStatus, 0, len(specs))
+	for _, spec := range specs {
+		volume, found := volumesByName[spec.Name]
+		if !found {
+			volume, err = s.createVolume(ctx, server, spec)
+			if err != nil {
+				return err
+			}
+		}
+
+		if volume.Server == nil {
+			if err := s.attachVolume(ctx, volume, server, spec); err != nil {
+				return err
+			}
+			volume.Server = &hcloud.Server{ID: server.ID}
+		}
+
+		if volume.Server.ID != server.ID {
+			return fmt.Errorf("volume %s is attached to server %d instead of %d", volume.Name, volume.Server.ID, server.ID)
+		}
+
+		statuses = append(statuses, infrav1.HCloudVolumeStatus{
+			Name:        spec.Name,
+			ID:          volume.ID,
+			LinuxDevice: volume.LinuxDevice,
+			Attached:    true,
+		})
+	}
+
+	s.scope.HCloudMachine.Status.Volumes = statuses
+	return nil
+}
+
+func (s *Service) createVolume(ctx context.Context, server *hcloud.Server, spec infrav1.HCloudVolumeSpec) (*hcloud.Volume, error) {
+	location := string(s.scope.HCloudMachine.Status.Region)
+	if server.Datacenter != nil && server.Datacenter.Location != nil {
+		location = server.Datacenter.Location.Name
+	}
+
+	labels := make(map[string]string, len(spec.Labels)+5)
+	for key, value := range spec.Labels {
+		labels[key] = value
+	}
+	for key, value := range s.createLabels() {
+		labels[key] = value
+	}
+	labels[infrav1.VolumeNameTagKey] = spec.Name
+	if spec.ReclaimPolicy != "" {
+		labels[infrav1.VolumeReclaimPolicyTagKey] = string(spec.ReclaimPolicy)
+	}
+
+	opts := hcloud.VolumeCreateOpts{
+		Name:     fmt.Sprintf("%s-%s", s.scope.Name(), spec.Name),
+		Size:     spec.Size,
+		Location: &hcloud.Location{Name: location},
+		Labels:   labels,
+	}
+	if spec.Format != "" {
+		opts.Format = hcloud.Ptr(spec.Format)
+	}
+
+	volume, err := s.scope.HCloudClient.CreateVolume(ctx, opts)
+	if err != nil {
+		record.Warnf(s.scope.HCloudMachine, "FailedCreateVolume", "Failed to create volume %s: %s", opts.Name, err)
+		return nil, handleRateLimit(s.scope.HCloudMachine, err, "CreateVolume", fmt.Sprintf("failed to create volume %s", opts.Name))
+	}
+
+	record.Eventf(s.scope.HCloudMachine, "VolumeCreated", "Created volume %s with ID %d in location %s", volume.Name, volume.ID, location)
+	return volume, nil
+}
+
+func (s *Service) attachVolume(ctx context.Context, volume *hcloud.Volume, server *hcloud.Server, spec infrav1.HCloudVolumeSpec) error {
+	opts := hcloud.VolumeAttachOpts{
+		Server:    server,
+		Automount: hcloud.Ptr(spec.Automount),
+	}
+
+	if err := s.scope.HCloudClient.AttachVolume(ctx, volume, opts); err != nil {
+		record.Warnf(s.scope.HCloudMachine, "FailedAttachVolume", "Failed to attach volume %s to server %s: %s", volume.Name, server.Name, err)
+		return handleRateLimit(s.scope.HCloudMachine, err, "AttachVolume", fmt.Sprintf("failed to attach volume %s", volume.Name))
+	}
+
+	record.Eventf(s.scope.HCloudMachine, "VolumeAttached", "Attached volume %s to server %s", volume.Name, server.Name)
+	return nil
+}
+
+// hasVolumes checks whether the machine has or had volumes. The volumes of the status are kept if the volumes are
+// removed from the spec, so that they are still cleaned up together with the machine.
+func (s *Service) hasVolumes() bool {
+	return len(s.scope.HCloudMachine.Spec.Volumes) > 0 || len(s.scope.HCloudMachine.Status.Volumes) > 0
+}
+
+// releaseRetainedVolumes removes the ownership labels of the volumes that are retained, before they are detached
+// together with the deleted server. Afterwards, they are not found as volumes of the machine or cluster anymore.
+func (s *Service) releaseRetainedVolumes(ctx context.Context) error {
+	volumes, err := s.findVolumes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, volume := range volumes {
+		if s.reclaimPolicy(volume) != infrav1.VolumeReclaimPolicyRetain {
+			continue
+		}
+		if err := s.releaseVolume(ctx, volume); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) releaseVolume(ctx context.Context, volume *hcloud.Volume) error {
+	labels := maps.Clone(volume.Labels)
+	for key := range s.createLabels() {
+		delete(labels, key)
+	}
+
+	if _, err := s.scope.HCloudClient.UpdateVolume(ctx, volume, hcloud.VolumeUpdateOpts{Labels: labels}); err != nil {
+		record.Warnf(s.scope.HCloudMachine, "FailedReleaseVolume", "Failed to remove the ownership labels of volume %s: %s", volume.Name, err)
+		return handleRateLimit(s.scope.HCloudMachine, err, "UpdateVolume", fmt.Sprintf("failed to update labels of volume %s", volume.Name))
+	}
+	volume.Labels = labels
+
+	record.Eventf(s.scope.HCloudMachine, "VolumeRetained", "Retained volume %s with ID %d and removed its ownership labels", volume.Name, volume.ID)
+	return nil
+}
+
+// deleteVolumes deletes the volumes of the machine according to their reclaim policy. Volumes cannot be deleted
+// while they are attached, so it requeues until the deleted server has released them. Retained volumes are
+// released if this has not been done before the server was deleted.
+func (s *Service) deleteVolumes(ctx context.Context) (reconcile.Result, error) {
+	volumes, err := s.findVolumes(ctx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	var attached bool
+	for _, volume := range volumes {
+		if s.reclaimPolicy(volume) == infrav1.VolumeReclaimPolicyRetain {
+			if err := s.releaseVolume(ctx, volume); err != nil {
+				return reconcile.Result{}, err
+			}
+			continue
+		}
+
+		if volume.Server != nil {
+			attached = true
+			continue
+		}
+
+		if err := s.scope.HCloudClient.DeleteVolume(ctx, volume.ID); err != nil {
+			// if the volume has been deleted already then do nothing
+			if hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+				continue
+			}
+			record.Warnf(s.scope.HCloudMachine, "FailedDeleteVolume", "Failed to delete volume %s: %s", volume.Name, err)
+			return reconcile.Result{}, handleRateLimit(s.scope.HCloudMachine, err, "DeleteVolume", fmt.Sprintf("failed to delete volume %s", volume.Name))
+		}
+		record.Eventf(s.scope.HCloudMachine, "VolumeDeleted", "Deleted volume %s with ID %d", volume.Name, volume.ID)
+	}
+
+	if attached {
+		return reconcile.Result{RequeueAfter: volumeDetachRequeueAfter}, nil
+	}
+
+	s.scope.HCloudMachine.Status.Volumes = nil
+	return reconcile.Result{}, nil
+}
+
+// reclaimPolicy returns the reclaim policy of a volume. It is read from the label that is set at the creation of the
+// volume, so that it does not depend on the spec anymore. Volumes that have been created without the label fall back
+// to the reclaim policy of their spec. Volumes without either of them are deleted.
+func (s *Service) reclaimPolicy(volume *hcloud.Volume) infrav1.VolumeReclaimPolicy {
+	switch policy := infrav1.VolumeReclaimPolicy(volume.Labels[infrav1.VolumeReclaimPolicyTagKey]); policy {
+	case infrav1.VolumeReclaimPolicyDelete, infrav1.VolumeReclaimPolicyRetain:
+		return policy
+	}
+
+	for _, spec := range s.scope.HCloudMachine.Spec.Volumes {
+		if spec.Name == volume.Labels[infrav1.VolumeNameTagKey] && spec.ReclaimPolicy != "" {
+			return spec.ReclaimPolicy
+		}
+	}
+	return infrav1.VolumeReclaimPolicyDelete
+}
+
+func (s *Service) findVolumes(ctx context.Context) ([]*hcloud.Volume, error) {
+	labels := map[string]string{
+		infrav1.NameHetznerProviderOwned + s.scope.HetznerCluster.Name: string(infrav1.ResourceLifecycleOwned),
+		infrav1.MachineNameTagKey:                                      s.scope.Name(),
+	}
+
+	volumes, err := s.scope.HCloudClient.ListVolumes(ctx, hcloud.VolumeListOpts{
+		ListOpts: hcloud.ListOpts{LabelSelector: utils.LabelsToLabelSelector(labels)},
+	})
+	if err != nil {
+		return nil, handleRateLimit(s.scope.HCloudMachine, err, "ListVolumes", "failed to list volumes")
+	}
+
+	return volumes, nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+var _ = Describe("Volumes", func() {
+	var (
+		ctx           context.Context
+		client        hcloudclient.Client
+		hcloudMachine *infrav1.HCloudMachine
+		server        *hcloud.Server
+		service       *Service
+		name          string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		name = "volumes-machine"
+
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: infrav1.HCloudMachineSpec{
+				Volumes: []infrav1.HCloudVolumeSpec{
+					{Name: "data", Size: 10, Format: "ext4", Automount: true, Labels: map[string]string{"role": "data"}},
+					{Name: "etcd", Size: 20, ReclaimPolicy: infrav1.VolumeReclaimPolicyRetain},
+				},
+			},
+			Status: infrav1.HCloudMachineStatus{Region: "fsn1"},
+		}
+
+		service, server = newTestServiceWithServer(scope.MachineScopeParams{HCloudMachine: hcloudMachine}, hcloud.ServerCreateOpts{})
+		client = service.scope.HCloudClient
+	})
+
+	It("creates and attaches the volumes", func() {
+		Expect(service.reconcileVolumes(ctx, server)).To(Succeed())
+
+		volumes, err := service.findVolumes(ctx)
+		Expect(err).To(Succeed())
+		Expect(volumes).To(HaveLen(2))
+		for _, volume := range volumes {
+			Expect(volume.Server).ToNot(BeNil())
+			Expect(volume.Server.ID).To(Equal(server.ID))
+			Expect(volume.Location.Name).To(Equal("fsn1"))
+			if volume.Name == name+"-data" {
+				Expect(volume.Labels).To(HaveKeyWithValue("role", "data"))
+				Expect(volume.Labels).To(HaveKeyWithValue(infrav1.VolumeNameTagKey, "data"))
+				Expect(*volume.Format).To(Equal("ext4"))
+			}
+		}
+
+		Expect(hcloudMachine.Status.Volumes).To(HaveLen(2))
+		Expect(hcloudMachine.Status.Volumes[0].Name).To(Equal("data"))
+		Expect(hcloudMachine.Status.Volumes[0].LinuxDevice).ToNot(BeEmpty())
+		Expect(hcloudMachine.Status.Volumes[1].Attached).To(BeTrue())
+
+		By("being idempotent")
+		Expect(service.reconcileVolumes(ctx, server)).To(Succeed())
+		volumes, err = service.findVolumes(ctx)
+		Expect(err).To(Succeed())
+		Expect(volumes).To(HaveLen(2))
+	})
+
+	It("attaches existing volumes that are not attached", func() {
+		Expect(service.reconcileVolumes(ctx, server)).To(Succeed())
+		volumes, err := service.findVolumes(ctx)
+		Expect(err).To(Succeed())
+		Expect(client.DetachVolume(ctx, volumes[0])).To(Succeed())
+
+		Expect(service.reconcileVolumes(ctx, server)).To(Succeed())
+		Expect(volumes[0].Server).ToNot(BeNil())
+	})
+
+	It("fails if a volume is attached to another server", func() {
+		other, err := client.CreateServer(ctx, hcloud.ServerCreateOpts{Name: name + "-other"})
+		Expect(err).To(Succeed())
+		Expect(service.reconcileVolumes(ctx, other)).To(Succeed())
+
+		Expect(service.reconcileVolumes(ctx, server)).ToNot(Succeed())
+	})
+
+	It("deletes the volumes after the server according to their reclaim policy", func() {
+		Expect(service.reconcileVolumes(ctx, server)).To(Succeed())
+
+		By("waiting for the volumes to be detached")
+		res, err := service.deleteVolumes(ctx)
+		Expect(err).To(Succeed())
+		Expect(res).To(Equal(reconcile.Result{RequeueAfter: volumeDetachRequeueAfter}))
+
+		Expect(client.DeleteServer(ctx, server)).To(Succeed())
+		res, err = service.deleteVolumes(ctx)
+		Expect(err).To(Succeed())
+		Expect(res).To(Equal(reconcile.Result{}))
+
+		volumes, err := service.findVolumes(ctx)
+		Expect(err).To(Succeed())
+		Expect(volumes).To(BeEmpty())
+		Expect(hcloudMachine.Status.Volumes).To(BeEmpty())
+
+		By("keeping the retained volume without ownership labels")
+		volumes, err = client.ListVolumes(ctx, hcloud.VolumeListOpts{})
+		Expect(err).To(Succeed())
+		Expect(volumes).To(ConsistOf(HaveField("Name", name+"-etcd")))
+		Expect(volumes[0].Labels).To(Equal(map[string]string{
+			infrav1.VolumeNameTagKey:          "etcd",
+			infrav1.VolumeReclaimPolicyTagKey: string(infrav1.VolumeReclaimPolicyRetain),
+		}))
+	})
+
+	It("removes the ownership labels of retained volumes before the server is deleted", func() {
+		Expect(service.reconcileVolumes(ctx, server)).To(Succeed())
+
+		Expect(service.releaseRetainedVolumes(ctx)).To(Succeed())
+
+		volumes, err := service.findVolumes(ctx)
+		Expect(err).To(Succeed())
+		Expect(volumes).To(ConsistOf(HaveField("Name", name+"-data")))
+
+		volumes, err = client.ListVolumes(ctx, hcloud.VolumeListOpts{
+			ListOpts: hcloud.ListOpts{LabelSelector: utils.LabelsToLabelSelector(map[string]string{infrav1.VolumeNameTagKey: "etcd"})},
+		})
+		Expect(err).To(Succeed())
+		Expect(volumes).To(HaveLen(1))
+		Expect(volumes[0].Server).ToNot(BeNil())
+		Expect(volumes[0].Labels).ToNot(HaveKey(infrav1.MachineNameTagKey))
+	})
+
+	It("keeps the reclaim policy of the volumes after they have been removed from the spec", func() {
+		Expect(service.reconcileVolumes(ctx, server)).To(Succeed())
+
+		hcloudMachine.Spec.Volumes = nil
+		Expect(service.reconcileVolumes(ctx, server)).To(Succeed())
+		Expect(hcloudMachine.Status.Volumes).To(HaveLen(2))
+
+		Expect(client.DeleteServer(ctx, server)).To(Succeed())
+		hcloudMachine.Spec.ProviderID = nil
+
+		res, err := service.Delete(ctx)
+		Expect(err).To(Succeed())
+		Expect(res).To(Equal(reconcile.Result{}))
+
+		volumes, err := client.ListVolumes(ctx, hcloud.VolumeListOpts{})
+		Expect(err).To(Succeed())
+		Expect(volumes).To(ConsistOf(HaveField("Name", name+"-etcd")))
+		Expect(volumes[0].Labels).ToNot(HaveKey(infrav1.MachineNameTagKey))
+		Expect(hcloudMachine.Status.Volumes).To(BeEmpty())
+	})
+
+	It("falls back to the reclaim policy of the spec for volumes without label", func() {
+		volume := &hcloud.Volume{Labels: map[string]string{infrav1.VolumeNameTagKey: "etcd"}}
+		Expect(service.reclaimPolicy(volume)).To(Equal(infrav1.VolumeReclaimPolicyRetain))
+
+		volume.Labels[infrav1.VolumeNameTagKey] = "removed"
+		Expect(service.reclaimPolicy(volume)).To(Equal(infrav1.VolumeReclaimPolicyDelete))
+	})
+})
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake implements an in-process fake of an S3 compatible object storage for tests.
+package fake
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is an in-process fake of the object endpoints of an S3 compatible object storage. It checks that requests
+// are made with the access key, but does not verify signatures.
+type Server struct {
+	*httptest.Server
+
+	accessKey string
+
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+// NewServer starts a fake server that accepts requests signed with the given access key.
+// The caller has to close the server.
+func NewServer(accessKey string) *Server {
+	s := &Server{
+		accessKey: accessKey,
+		objects:   make(map[string][]byte),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Object returns the content of the object of the path, i.e. "/<bucket>/<key>".
+func (s *Server) Object(path string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.objects[path]
+	return data, ok
+}
+
+// Paths returns the paths of all stored objects.
+func (s *Server) Paths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths := make([]string, 0, len(s.objects))
+	for path := range s.objects {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	credential := r.URL.Query().Get("X-Amz-Credential")
+	presigned := credential != ""
+	if !presigned {
+		credential = strings.TrimPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=")
+	}
+	if !strings.HasPrefix(credential, s.accessKey+"/") {
+		http.Error(w, "InvalidAccessKeyId", http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && presigned:
+		if expired(r) {
+			http.Error(w, "AccessDenied: Request has expired", http.StatusForbidden)
+			return
+		}
+		data, ok := s.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	case r.Method == http.MethodPut && !presigned:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.objects[r.URL.Path] = data
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodDelete && !presigned:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func expired(r *http.Request) bool {
+	date, err := time.Parse("20060102T150405Z", r.URL.Query().Get("X-Amz-Date"))
+	if err != nil {
+		return true
+	}
+	seconds, err := strconv.Atoi(r.URL.Query().Get("X-Amz-Expires"))
+	if err != nil {
+		return true
+	}
+	return time.Now().After(date.Add(time.Duration(seconds) * time.Second))
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package objectstorage implements a minimal client for S3 compatible object storages like Hetzner Object Storage.
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+const (
+	requestTimeout  = 30 * time.Second
+	signAlgorithm   = "AWS4-HMAC-SHA256"
+	amzDateFormat   = "20060102T150405Z"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	service         = "s3"
+)
+
+// Config contains the location of a bucket and the credentials to access it.
+type Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// Client uploads, deletes and presigns objects of a bucket. Requests are signed with AWS Signature Version 4 and
+// the bucket is addressed in path style, which all S3 compatible storages support.
+type Client struct {
+	cfg        Config
+	endpoint   *url.URL
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewClient creates a client for the bucket of the config.
+func NewClient(cfg Config) (*Client, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse endpoint %q: %w", cfg.Endpoint, err)
+	}
+	if endpoint.Scheme != "https" && endpoint.Scheme != "http" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q: expected http(s)://host", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" || cfg.Region == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("bucket, region and credentials of the object storage must be set")
+	}
+
+	return &Client{
+		cfg:        cfg,
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: requestTimeout},
+		now:        time.Now,
+	}, nil
+}
+
+// PutObject stores data under the key. An existing object of the same key is replaced.
+func (c *Client) PutObject(ctx context.Context, key string, data []byte) error {
+	if err := c.do(ctx, http.MethodPut, key, data); err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+	return nil
+}
+
+// DeleteObject deletes the object of the key. Deleting an object that does not exist is not an error.
+func (c *Client) DeleteObject(ctx context.Context, key string) error {
+	if err := c.do(ctx, http.MethodDelete, key, nil); err != nil {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+	return nil
+}
+
+// PresignGetObject returns a URL that allows to download the object of the key without further credentials
+// until the expiry has passed.
+func (c *Client) PresignGetObject(key string, expiry time.Duration) (string, error) {
+	if expiry <= 0 || expiry > infrav1.MaxBootstrapDataURLExpiry {
+		return "", fmt.Errorf("expiry %s of presigned URL is not between 1s and %s", expiry, infrav1.MaxBootstrapDataURLExpiry)
+	}
+	return c.presign(c.objectURL(key), c.now(), expiry), nil
+}
+
+func (c *Client) do(ctx context.Context, method, key string, body []byte) error {
+	u := c.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	c.sign(req, hashHex(body), c.now())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (c *Client) objectURL(key string) *url.URL {
+	u := *c.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + c.cfg.Bucket + "/" + key
+	u.RawPath = encodePath(u.Path)
+	u.RawQuery = ""
+	return &u
+}
+
+// sign adds the headers of AWS Signature Version 4 to the request.
+func (c *Client) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format(amzDateFormat)
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+
+	query := make(map[string]string)
+	for key := range req.URL.Query() {
+		query[key] = req.URL.Query().Get(key)
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		encodePath(req.URL.Path),
+		encodeQuery(query),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := c.scope(amzDate)
+	signature := c.signature(amzDate, scope, canonicalRequest)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signAlgorithm, c.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+// presign returns the URL with the query parameters of AWS Signature Version 4.
+func (c *Client) presign(u *url.URL, now time.Time, expiry time.Duration) string {
+	amzDate := now.UTC().Format(amzDateFormat)
+	scope := c.scope(amzDate)
+
+	query := map[string]string{
+		"X-Amz-Algorithm":     signAlgorithm,
+		"X-Amz-Credential":    c.cfg.AccessKey + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(expiry.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	canonicalQuery := encodeQuery(query)
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		encodePath(u.Path),
+		canonicalQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	presigned := *u
+	presigned.RawPath = encodePath(u.Path)
+	presigned.RawQuery = canonicalQuery + "&X-Amz-Signature=" + c.signature(amzDate, scope, canonicalRequest)
+	return presigned.String()
+}
+
+func (c *Client) scope(amzDate string) string {
+	return amzDate[:8] + "/" + c.cfg.Region + "/" + service + "/aws4_request"
+}
+
+func (c *Client) signature(amzDate, scope, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{signAlgorithm, amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.cfg.SecretKey), amzDate[:8])
+	key = hmacSHA256(key, c.cfg.Region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// encodePath encodes each segment of the path as required for the canonical request.
+func encodePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func encodeQuery(query map[string]string) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	params := make([]string, 0, len(keys))
+	for _, key := range keys {
+		params = append(params, uriEncode(key)+"="+uriEncode(query[key]))
+	}
+	return strings.Join(params, "&")
+}
+
+// uriEncode encodes all characters except the unreserved ones of RFC 3986.
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch >= 'A' && ch <= 'Z' || ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' ||
+			ch == '-' || ch == '_' || ch == '.' || ch == '~' {
+			b.WriteByte(ch)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", ch)
+	}
+	return b.String()
+}
@@ -18,10 +18,15 @@ limitations under the License.
 package utils
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
@@ -42,6 +47,8 @@ func LabelsToLabelSelector(labels map[string]string) string {
 			fmt.Sprintf("%s==%s", key, val),
 		)
 	}
+	// sort the parts to get a deterministic label selector
+	slices.Sort(parts)
 	return strings.Join(parts, ",")
 }
 
@@ -180,6 +187,21 @@ func GenerateName(name *string, fallback string) string {
 	return names.SimpleNameGenerator.GenerateName(fallback)
 }
 
+// UnreachableTCPPorts returns the ports of the host that do not accept a TCP connection within the timeout.
+func UnreachableTCPPorts(ctx context.Context, host string, ports []int, timeout time.Duration) []int {
+	var unreachable []int
+	dialer := net.Dialer{Timeout: timeout}
+	for _, port := range ports {
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+		if err != nil {
+			unreachable = append(unreachable, port)
+			continue
+		}
+		_ = conn.Close()
+	}
+	return unreachable
+}
+
 // GetDefaultLogger returns a default zapr logger.
 func GetDefaultLogger(logLevel string) logr.Logger {
 	cfg := zap.Config{
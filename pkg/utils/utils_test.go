@@ -17,6 +17,10 @@ limitations under the License.
 package utils_test
 
 import (
+	"context"
+	"net"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -321,3 +325,25 @@ var _ = Describe("Test FindOwnerRefFromList", func() {
 		}),
 	)
 })
+
+var _ = Describe("UnreachableTCPPorts", func() {
+	It("returns the ports that do not accept connections", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(Succeed())
+		defer listener.Close()
+		openPort := listener.Addr().(*net.TCPAddr).Port
+
+		// a port that has just been released is not reachable anymore
+		closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(Succeed())
+		closedPort := closedListener.Addr().(*net.TCPAddr).Port
+		Expect(closedListener.Close()).To(Succeed())
+
+		unreachable := utils.UnreachableTCPPorts(context.Background(), "127.0.0.1", []int{openPort, closedPort}, time.Second)
+		Expect(unreachable).To(Equal([]int{closedPort}))
+	})
+
+	It("returns nothing without ports", func() {
+		Expect(utils.UnreachableTCPPorts(context.Background(), "127.0.0.1", nil, time.Second)).To(BeEmpty())
+	})
+})
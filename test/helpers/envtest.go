@@ -155,7 +155,7 @@ func NewTestEnvironment() *TestEnvironment {
 	if err := (&infrav1.HetznerClusterTemplate{}).SetupWebhookWithManager(mgr); err != nil {
 		klog.Fatalf("failed to set up webhook with manager for HetznerClusterTemplate: %s", err)
 	}
-	if err := (&infrav1.HCloudMachine{}).SetupWebhookWithManager(mgr); err != nil {
+	if err := (&infrav1.HCloudMachineWebhook{}).SetupWebhookWithManager(mgr); err != nil {
 		klog.Fatalf("failed to set up webhook with manager for HCloudMachine: %s", err)
 	}
 	if err := (&infrav1.HCloudMachineTemplateWebhook{}).SetupWebhookWithManager(mgr); err != nil {